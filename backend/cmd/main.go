@@ -9,13 +9,16 @@ import (
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
 	"postgresus-backend/internal/config"
 	"postgresus-backend/internal/downdetect"
+	"postgresus-backend/internal/features/audit_logs"
 	"postgresus-backend/internal/features/backups/backups"
 	backups_config "postgresus-backend/internal/features/backups/config"
+	backups_transfers "postgresus-backend/internal/features/backups/transfers"
 	"postgresus-backend/internal/features/databases"
 	"postgresus-backend/internal/features/disk"
 	healthcheck_attempt "postgresus-backend/internal/features/healthcheck/attempt"
@@ -24,10 +27,14 @@ import (
 	postgres_monitoring_metrics "postgresus-backend/internal/features/monitoring/postgres/metrics"
 	postgres_monitoring_settings "postgresus-backend/internal/features/monitoring/postgres/settings"
 	"postgresus-backend/internal/features/notifiers"
+	notifier_digests "postgresus-backend/internal/features/notifiers/digests"
+	notification_rules "postgresus-backend/internal/features/notifiers/rules"
 	"postgresus-backend/internal/features/restores"
+	"postgresus-backend/internal/features/secrets"
 	"postgresus-backend/internal/features/storages"
 	system_healthcheck "postgresus-backend/internal/features/system/healthcheck"
 	"postgresus-backend/internal/features/users"
+	"postgresus-backend/internal/features/workspaces"
 	env_utils "postgresus-backend/internal/util/env"
 	files_utils "postgresus-backend/internal/util/files"
 	"postgresus-backend/internal/util/logger"
@@ -66,9 +73,26 @@ func main() {
 
 	// Handle password reset if flag is provided
 	newPassword := flag.String("new-password", "", "Set a new password for the user")
+	email := flag.String("email", "", "Email of the user to reset password for")
+	isRotateEncryptionKey := flag.Bool(
+		"rotate-encryption-key",
+		false,
+		"Re-encrypt stored credentials by a new encryption key",
+	)
 	flag.Parse()
 	if *newPassword != "" {
-		resetPassword(*newPassword, log)
+		resetPassword(*email, *newPassword, log)
+	}
+
+	if *isRotateEncryptionKey {
+		rotateEncryptionKey(log)
+	}
+
+	// credentials saved by previous versions are kept in plain text
+	// until they are encrypted here
+	if err := secrets.GetSecretService().EncryptPlainValues(); err != nil {
+		log.Error("Failed to encrypt credentials", "error", err)
+		os.Exit(1)
 	}
 
 	go generateSwaggerDocs(log)
@@ -76,6 +100,11 @@ func main() {
 	gin.SetMode(gin.ReleaseMode)
 	ginApp := gin.Default()
 
+	if err := ginApp.SetTrustedProxies(getTrustedProxies()); err != nil {
+		log.Error("TRUSTED_PROXIES is invalid", "error", err)
+		os.Exit(1)
+	}
+
 	// Add GZIP compression middleware
 	ginApp.Use(gzip.Gzip(
 		gzip.DefaultCompression,
@@ -94,11 +123,29 @@ func main() {
 	startServerWithGracefulShutdown(log, ginApp)
 }
 
-func resetPassword(newPassword string, log *slog.Logger) {
+// getTrustedProxies returns nil if no proxy is configured, so client IP
+// is always the address of the connection
+func getTrustedProxies() []string {
+	var trustedProxies []string
+
+	for _, proxy := range strings.Split(config.GetEnv().TrustedProxies, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+
+	return trustedProxies
+}
+
+func resetPassword(email string, newPassword string, log *slog.Logger) {
 	log.Info("Resetting password...")
 
+	// password reset is written into audit log as a system action
+	audit_logs.SetupDependencies()
+
 	userService := users.GetUserService()
-	err := userService.ChangePassword(newPassword)
+	err := userService.ResetPassword(email, newPassword)
 	if err != nil {
 		log.Error("Failed to reset password", "error", err)
 		os.Exit(1)
@@ -108,6 +155,18 @@ func resetPassword(newPassword string, log *slog.Logger) {
 	os.Exit(0)
 }
 
+func rotateEncryptionKey(log *slog.Logger) {
+	log.Info("Rotating encryption key...")
+
+	if err := secrets.GetSecretService().RotateEncryptionKey(); err != nil {
+		log.Error("Failed to rotate encryption key", "error", err)
+		os.Exit(1)
+	}
+
+	log.Info("Encryption key rotated successfully")
+	os.Exit(0)
+}
+
 func startServerWithGracefulShutdown(log *slog.Logger, app *gin.Engine) {
 	host := ""
 	if config.GetEnv().EnvMode == env_utils.EnvModeDevelopment {
@@ -145,6 +204,7 @@ func startServerWithGracefulShutdown(log *slog.Logger, app *gin.Engine) {
 
 func setUpRoutes(r *gin.Engine) {
 	v1 := r.Group("/api/v1")
+	v1.Use(users.GetUserController().CheckApiKeyScope)
 
 	// Mount Swagger UI
 	v1.GET("/docs/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -152,10 +212,12 @@ func setUpRoutes(r *gin.Engine) {
 	downdetectContoller := downdetect.GetDowndetectController()
 	userController := users.GetUserController()
 	notifierController := notifiers.GetNotifierController()
+	notificationRuleController := notification_rules.GetNotificationRuleController()
 	storageController := storages.GetStorageController()
 	databaseController := databases.GetDatabaseController()
 	backupController := backups.GetBackupController()
 	restoreController := restores.GetRestoreController()
+	backupTransferController := backups_transfers.GetBackupTransferController()
 	healthcheckController := system_healthcheck.GetHealthcheckController()
 	healthcheckConfigController := healthcheck_config.GetHealthcheckConfigController()
 	healthcheckAttemptController := healthcheck_attempt.GetHealthcheckAttemptController()
@@ -163,14 +225,18 @@ func setUpRoutes(r *gin.Engine) {
 	backupConfigController := backups_config.GetBackupConfigController()
 	postgresMonitoringSettingsController := postgres_monitoring_settings.GetPostgresMonitoringSettingsController()
 	postgresMonitoringMetricsController := postgres_monitoring_metrics.GetPostgresMonitoringMetricsController()
+	auditLogController := audit_logs.GetAuditLogController()
+	workspaceController := workspaces.GetWorkspaceController()
 
 	downdetectContoller.RegisterRoutes(v1)
 	userController.RegisterRoutes(v1)
 	notifierController.RegisterRoutes(v1)
+	notificationRuleController.RegisterRoutes(v1)
 	storageController.RegisterRoutes(v1)
 	databaseController.RegisterRoutes(v1)
 	backupController.RegisterRoutes(v1)
 	restoreController.RegisterRoutes(v1)
+	backupTransferController.RegisterRoutes(v1)
 	healthcheckController.RegisterRoutes(v1)
 	diskController.RegisterRoutes(v1)
 	healthcheckConfigController.RegisterRoutes(v1)
@@ -178,11 +244,19 @@ func setUpRoutes(r *gin.Engine) {
 	backupConfigController.RegisterRoutes(v1)
 	postgresMonitoringSettingsController.RegisterRoutes(v1)
 	postgresMonitoringMetricsController.RegisterRoutes(v1)
+	auditLogController.RegisterRoutes(v1)
+	workspaceController.RegisterRoutes(v1)
 }
 
 func setUpDependencies() {
+	audit_logs.SetupDependencies()
+	storages.SetupDependencies()
+	notifiers.SetupDependencies()
+	databases.SetupDependencies()
+	notification_rules.SetupDependencies()
 	backups.SetupDependencies()
 	restores.SetupDependencies()
+	backups_transfers.SetupDependencies()
 	healthcheck_config.SetupDependencies()
 	postgres_monitoring_settings.SetupDependencies()
 }
@@ -203,6 +277,18 @@ func runBackgroundTasks(log *slog.Logger) {
 		restores.GetRestoreBackgroundService().Run()
 	})
 
+	go runWithPanicLogging(log, "backup transfer background service", func() {
+		backups_transfers.GetBackupTransferBackgroundService().Run()
+	})
+
+	go runWithPanicLogging(log, "notification delivery background service", func() {
+		notifiers.GetNotificationDeliveryBackgroundService().Run()
+	})
+
+	go runWithPanicLogging(log, "notifier digest background service", func() {
+		notifier_digests.GetDigestBackgroundService().Run()
+	})
+
 	go runWithPanicLogging(log, "healthcheck attempt background service", func() {
 		healthcheck_attempt.GetHealthcheckAttemptBackgroundService().Run()
 	})
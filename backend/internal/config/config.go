@@ -29,6 +29,48 @@ type EnvVariables struct {
 	DataFolder string
 	TempFolder string
 
+	// OpenID Connect sign in is enabled when issuer is set. Role
+	// mapping has "claimValue=ROLE,claimValue=ROLE" format, users
+	// without mapped role get default role or are rejected if it
+	// is empty
+	OidcIssuerURL    string `env:"OIDC_ISSUER_URL"`
+	OidcClientID     string `env:"OIDC_CLIENT_ID"`
+	OidcClientSecret string `env:"OIDC_CLIENT_SECRET"`
+	OidcRedirectURL  string `env:"OIDC_REDIRECT_URL"`
+	OidcProviderName string `env:"OIDC_PROVIDER_NAME" env-default:"SSO"`
+	OidcRolesClaim   string `env:"OIDC_ROLES_CLAIM"   env-default:"groups"`
+	OidcRoleMapping  string `env:"OIDC_ROLE_MAPPING"`
+	OidcDefaultRole  string `env:"OIDC_DEFAULT_ROLE"`
+	// comma separated Google Workspace domains checked by "hd" claim,
+	// required for Google, otherwise any Google account can sign in
+	OidcAllowedDomains string `env:"OIDC_ALLOWED_DOMAINS"`
+
+	IsPasswordLoginDisabled bool `env:"DISABLE_PASSWORD_LOGIN"`
+
+	// comma separated IPs or CIDRs of reverse proxies. Client IP is taken
+	// from X-Forwarded-For only if the request comes from them, otherwise
+	// anybody could change the IP to bypass sign in limits
+	TrustedProxies string `env:"TRUSTED_PROXIES"`
+
+	// public URL of Postgresus, notifications link to it if it is set
+	AppURL string `env:"APP_URL"`
+
+	// credentials are encrypted by data keys, which are encrypted by
+	// the master key. If master key is not set, it is generated into
+	// the file of data folder. Previous master key is needed once to
+	// re-encrypt data keys after the master key is changed
+	EncryptionMasterKey         string `env:"ENCRYPTION_MASTER_KEY"`
+	EncryptionPreviousMasterKey string `env:"ENCRYPTION_PREVIOUS_MASTER_KEY"`
+	EncryptionMasterKeyPath     string
+
+	// credentials can reference external secrets instead of keeping
+	// them: "vault://mount/path#key" (KV v2), "env://NAME" or
+	// "file://path" (relative to or inside secret files folder)
+	VaultAddr      string `env:"VAULT_ADDR"`
+	VaultToken     string `env:"VAULT_TOKEN"`
+	VaultNamespace string `env:"VAULT_NAMESPACE"`
+	SecretFilesDir string `env:"SECRET_FILES_DIR" env-default:"/run/secrets"`
+
 	TestGoogleDriveClientID     string `env:"TEST_GOOGLE_DRIVE_CLIENT_ID"`
 	TestGoogleDriveClientSecret string `env:"TEST_GOOGLE_DRIVE_CLIENT_SECRET"`
 	TestGoogleDriveTokenJSON    string `env:"TEST_GOOGLE_DRIVE_TOKEN_JSON"`
@@ -126,6 +168,24 @@ func loadEnvVariables() {
 	}
 	log.Info("ENV_MODE loaded", "mode", env.EnvMode)
 
+	if env.OidcIssuerURL != "" {
+		if env.OidcClientID == "" || env.OidcRedirectURL == "" {
+			log.Error("OIDC_CLIENT_ID and OIDC_REDIRECT_URL are required for OIDC")
+			os.Exit(1)
+		}
+
+		isGoogleIssuer := strings.Contains(env.OidcIssuerURL, "accounts.google.com")
+		if isGoogleIssuer && env.OidcAllowedDomains == "" {
+			log.Error("OIDC_ALLOWED_DOMAINS is required for Google, otherwise any account can sign in")
+			os.Exit(1)
+		}
+	}
+
+	if env.IsPasswordLoginDisabled && env.OidcIssuerURL == "" {
+		log.Error("DISABLE_PASSWORD_LOGIN requires OIDC_ISSUER_URL, otherwise nobody can sign in")
+		os.Exit(1)
+	}
+
 	env.PostgresesInstallDir = filepath.Join(backendRoot, "tools", "postgresql")
 	tools.VerifyPostgresesInstallation(log, env.EnvMode, env.PostgresesInstallDir)
 
@@ -133,6 +193,12 @@ func loadEnvVariables() {
 	// (projectRoot/postgresus-data -> /postgresus-data)
 	env.DataFolder = filepath.Join(filepath.Dir(backendRoot), "postgresus-data", "backups")
 	env.TempFolder = filepath.Join(filepath.Dir(backendRoot), "postgresus-data", "temp")
+	env.EncryptionMasterKeyPath = filepath.Join(
+		filepath.Dir(backendRoot),
+		"postgresus-data",
+		"secrets",
+		"encryption_master_key",
+	)
 
 	if env.IsTesting {
 		if env.TestPostgres13Port == "" {
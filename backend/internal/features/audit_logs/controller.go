@@ -0,0 +1,212 @@
+package audit_logs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"postgresus-backend/internal/features/users"
+	user_enums "postgresus-backend/internal/features/users/enums"
+	users_models "postgresus-backend/internal/features/users/models"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AuditLogController struct {
+	auditLogService *AuditLogService
+	userService     *users.UserService
+}
+
+func (c *AuditLogController) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/audit-logs", c.GetAuditLogs)
+	router.GET("/audit-logs/export", c.ExportAuditLogs)
+}
+
+// GetAuditLogs
+// @Summary Get audit logs
+// @Description Get actions made by users and by the system, newest first. Available for admins only
+// @Tags audit-logs
+// @Produce json
+// @Param user_id query string false "Filter by user ID"
+// @Param actor_type query string false "Filter by actor type: USER or SYSTEM"
+// @Param action query string false "Filter by action, e.g. DELETE"
+// @Param target_type query string false "Filter by target type, e.g. DATABASE"
+// @Param target_id query string false "Filter by target ID"
+// @Param from query string false "Logs created at or after the date (RFC3339)"
+// @Param to query string false "Logs created before the date (RFC3339)"
+// @Param limit query int false "Limit, 100 by default"
+// @Param offset query int false "Offset"
+// @Success 200 {object} GetAuditLogsResponse
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /audit-logs [get]
+func (c *AuditLogController) GetAuditLogs(ctx *gin.Context) {
+	if _, ok := c.getAdmin(ctx); !ok {
+		return
+	}
+
+	request, err := c.parseGetAuditLogsRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := c.auditLogService.GetAuditLogs(request)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ExportAuditLogs
+// @Summary Export audit logs
+// @Description Download audit logs matching the filters as CSV or JSON file. Available for admins only
+// @Tags audit-logs
+// @Produce octet-stream
+// @Param format query string false "csv (default) or json"
+// @Param user_id query string false "Filter by user ID"
+// @Param actor_type query string false "Filter by actor type: USER or SYSTEM"
+// @Param action query string false "Filter by action, e.g. DELETE"
+// @Param target_type query string false "Filter by target type, e.g. DATABASE"
+// @Param target_id query string false "Filter by target ID"
+// @Param from query string false "Logs created at or after the date (RFC3339)"
+// @Param to query string false "Logs created before the date (RFC3339)"
+// @Success 200 {file} file
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /audit-logs/export [get]
+func (c *AuditLogController) ExportAuditLogs(ctx *gin.Context) {
+	if _, ok := c.getAdmin(ctx); !ok {
+		return
+	}
+
+	request, err := c.parseGetAuditLogsRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	format := AuditLogExportFormat(ctx.DefaultQuery("format", string(AuditLogExportFormatCsv)))
+
+	switch format {
+	case AuditLogExportFormatCsv:
+		ctx.Header("Content-Type", "text/csv")
+	case AuditLogExportFormatJson:
+		ctx.Header("Content-Type", "application/json")
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid format"})
+		return
+	}
+
+	ctx.Header(
+		"Content-Disposition",
+		fmt.Sprintf(
+			"attachment; filename=\"audit_logs_%s.%s\"",
+			time.Now().UTC().Format("20060102_150405"),
+			format,
+		),
+	)
+
+	if err := c.auditLogService.ExportAuditLogs(request, format, ctx.Writer); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+}
+
+func (c *AuditLogController) getAdmin(ctx *gin.Context) (*users_models.User, bool) {
+	authorizationHeader := ctx.GetHeader("Authorization")
+	if authorizationHeader == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+		return nil, false
+	}
+
+	user, err := c.userService.GetUserFromToken(authorizationHeader)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return nil, false
+	}
+
+	if !user.Role.IsAtLeast(user_enums.UserRoleAdmin) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+		return nil, false
+	}
+
+	return user, true
+}
+
+func (c *AuditLogController) parseGetAuditLogsRequest(
+	ctx *gin.Context,
+) (*GetAuditLogsRequest, error) {
+	request := &GetAuditLogsRequest{}
+
+	if userIDStr := ctx.Query("user_id"); userIDStr != "" {
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return nil, errors.New("invalid user_id")
+		}
+		request.UserID = &userID
+	}
+
+	if actorTypeStr := ctx.Query("actor_type"); actorTypeStr != "" {
+		actorType := AuditLogActorType(actorTypeStr)
+		request.ActorType = &actorType
+	}
+
+	if actionStr := ctx.Query("action"); actionStr != "" {
+		action := AuditLogAction(actionStr)
+		request.Action = &action
+	}
+
+	if targetTypeStr := ctx.Query("target_type"); targetTypeStr != "" {
+		targetType := AuditLogTargetType(targetTypeStr)
+		request.TargetType = &targetType
+	}
+
+	if targetIDStr := ctx.Query("target_id"); targetIDStr != "" {
+		targetID, err := uuid.Parse(targetIDStr)
+		if err != nil {
+			return nil, errors.New("invalid target_id")
+		}
+		request.TargetID = &targetID
+	}
+
+	if fromStr := ctx.Query("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return nil, errors.New("invalid from, RFC3339 date is expected")
+		}
+		request.From = &from
+	}
+
+	if toStr := ctx.Query("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return nil, errors.New("invalid to, RFC3339 date is expected")
+		}
+		request.To = &to
+	}
+
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			return nil, errors.New("invalid limit")
+		}
+		request.Limit = limit
+	}
+
+	if offsetStr := ctx.Query("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil {
+			return nil, errors.New("invalid offset")
+		}
+		request.Offset = offset
+	}
+
+	return request, nil
+}
@@ -0,0 +1,28 @@
+package audit_logs
+
+import (
+	"postgresus-backend/internal/features/users"
+	"postgresus-backend/internal/util/logger"
+)
+
+var auditLogRepository = &AuditLogRepository{}
+var auditLogService = &AuditLogService{
+	auditLogRepository,
+	logger.GetLogger(),
+}
+var auditLogController = &AuditLogController{
+	auditLogService,
+	users.GetUserService(),
+}
+
+func GetAuditLogService() *AuditLogService {
+	return auditLogService
+}
+
+func GetAuditLogController() *AuditLogController {
+	return auditLogController
+}
+
+func SetupDependencies() {
+	users.GetUserService().SetAuditLogWriter(auditLogService)
+}
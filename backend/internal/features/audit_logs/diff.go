@@ -0,0 +1,137 @@
+package audit_logs
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const maskedValue = "******"
+
+// sensitiveFieldParts are parts of JSON field names which values
+// should never be written into audit log: credentials of databases,
+// storages and notifiers
+var sensitiveFieldParts = []string{
+	"password",
+	"secret",
+	"token",
+	"accesskey",
+	"apikey",
+	"routingkey",
+	"webhookurl",
+	"powerautomateurl",
+}
+
+// CalculateDiff returns fields changed between JSON representations
+// of the objects. Any of them can be nil, e.g. for created or deleted
+// targets. Values of sensitive fields are masked, so only the fact
+// of the change is kept
+func CalculateDiff(before any, after any) AuditLogDiff {
+	beforeFields := flattenToFields(before)
+	afterFields := flattenToFields(after)
+
+	diff := AuditLogDiff{}
+
+	for name, newValue := range afterFields {
+		oldValue, isExisted := beforeFields[name]
+		if isExisted && reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+
+		diff[name] = newFieldChange(name, oldValue, newValue)
+	}
+
+	for name, oldValue := range beforeFields {
+		if _, isExists := afterFields[name]; !isExists {
+			diff[name] = newFieldChange(name, oldValue, nil)
+		}
+	}
+
+	return diff
+}
+
+func newFieldChange(name string, oldValue any, newValue any) AuditLogFieldChange {
+	if isSensitiveField(name) {
+		if oldValue != nil {
+			oldValue = maskedValue
+		}
+
+		if newValue != nil {
+			newValue = maskedValue
+		}
+	}
+
+	return AuditLogFieldChange{Old: oldValue, New: newValue}
+}
+
+func isSensitiveField(name string) bool {
+	if isHeaderValueField(name) {
+		return true
+	}
+
+	fieldName := strings.ToLower(name[strings.LastIndex(name, ".")+1:])
+
+	for _, part := range sensitiveFieldParts {
+		if strings.Contains(fieldName, part) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isHeaderValueField checks values of HTTP headers like
+// "webhookNotifier.headers.0.value", they usually hold Authorization
+// or API keys while names of the headers are safe to keep
+func isHeaderValueField(name string) bool {
+	parts := strings.Split(strings.ToLower(name), ".")
+	if len(parts) < 3 {
+		return false
+	}
+
+	return parts[len(parts)-1] == "value" && parts[len(parts)-3] == "headers"
+}
+
+func flattenToFields(value any) map[string]any {
+	fields := map[string]any{}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fields
+	}
+
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fields
+	}
+
+	flattenValue("", decoded, fields)
+
+	return fields
+}
+
+func flattenValue(prefix string, value any, fields map[string]any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, nested := range v {
+			flattenValue(joinFieldName(prefix, key), nested, fields)
+		}
+	case []any:
+		for index, nested := range v {
+			flattenValue(joinFieldName(prefix, strconv.Itoa(index)), nested, fields)
+		}
+	default:
+		if prefix != "" {
+			fields[prefix] = v
+		}
+	}
+}
+
+func joinFieldName(prefix string, name string) string {
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + "." + name
+}
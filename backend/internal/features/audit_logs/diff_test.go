@@ -0,0 +1,111 @@
+package audit_logs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testTarget struct {
+	Name     string           `json:"name"`
+	Port     int              `json:"port"`
+	Password string           `json:"password"`
+	Nested   *testTargetInner `json:"nested,omitempty"`
+}
+
+type testTargetInner struct {
+	Host       string `json:"host"`
+	WebhookURL string `json:"webhookUrl"`
+	RoutingKey string `json:"routingKey,omitempty"`
+	APIKey     string `json:"apiKey,omitempty"`
+
+	Headers []testTargetHeader `json:"headers,omitempty"`
+}
+
+type testTargetHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func Test_CalculateDiffWhenFieldsChanged_OnlyChangedFieldsReturned(t *testing.T) {
+	before := &testTarget{Name: "old", Port: 5432, Nested: &testTargetInner{Host: "localhost"}}
+	after := &testTarget{Name: "new", Port: 5432, Nested: &testTargetInner{Host: "remote"}}
+
+	diff := CalculateDiff(before, after)
+
+	assert.Len(t, diff, 2)
+	assert.Equal(t, AuditLogFieldChange{Old: "old", New: "new"}, diff["name"])
+	assert.Equal(t, AuditLogFieldChange{Old: "localhost", New: "remote"}, diff["nested.host"])
+}
+
+func Test_CalculateDiffWhenSensitiveFieldsChanged_ValuesMasked(t *testing.T) {
+	before := &testTarget{Password: "old-password", Nested: &testTargetInner{WebhookURL: "old"}}
+	after := &testTarget{Password: "new-password", Nested: &testTargetInner{WebhookURL: "new"}}
+
+	diff := CalculateDiff(before, after)
+
+	assert.Len(t, diff, 2)
+	assert.Equal(t, AuditLogFieldChange{Old: maskedValue, New: maskedValue}, diff["password"])
+	assert.Equal(
+		t,
+		AuditLogFieldChange{Old: maskedValue, New: maskedValue},
+		diff["nested.webhookUrl"],
+	)
+}
+
+func Test_CalculateDiffWhenNotifierKeysChanged_ValuesMasked(t *testing.T) {
+	before := &testTarget{Nested: &testTargetInner{RoutingKey: "old-key", APIKey: "old-key"}}
+	after := &testTarget{Nested: &testTargetInner{RoutingKey: "new-key", APIKey: "new-key"}}
+
+	diff := CalculateDiff(before, after)
+
+	assert.Len(t, diff, 2)
+	assert.Equal(
+		t,
+		AuditLogFieldChange{Old: maskedValue, New: maskedValue},
+		diff["nested.routingKey"],
+	)
+	assert.Equal(
+		t,
+		AuditLogFieldChange{Old: maskedValue, New: maskedValue},
+		diff["nested.apiKey"],
+	)
+}
+
+func Test_CalculateDiffWhenHeaderAdded_HeaderValueMasked(t *testing.T) {
+	after := &testTarget{Nested: &testTargetInner{
+		Headers: []testTargetHeader{{Name: "Authorization", Value: "Bearer token"}},
+	}}
+
+	diff := CalculateDiff(nil, after)
+
+	assert.Equal(
+		t,
+		AuditLogFieldChange{Old: nil, New: "Authorization"},
+		diff["nested.headers.0.name"],
+	)
+	assert.Equal(
+		t,
+		AuditLogFieldChange{Old: nil, New: maskedValue},
+		diff["nested.headers.0.value"],
+	)
+}
+
+func Test_CalculateDiffWhenTargetCreated_AllFieldsReturnedAsNew(t *testing.T) {
+	after := &testTarget{Name: "new", Port: 5432, Password: "password"}
+
+	diff := CalculateDiff(nil, after)
+
+	assert.Len(t, diff, 3)
+	assert.Equal(t, AuditLogFieldChange{Old: nil, New: "new"}, diff["name"])
+	assert.Equal(t, AuditLogFieldChange{Old: nil, New: float64(5432)}, diff["port"])
+	assert.Equal(t, AuditLogFieldChange{Old: nil, New: maskedValue}, diff["password"])
+}
+
+func Test_CalculateDiffWhenNothingChanged_EmptyDiffReturned(t *testing.T) {
+	target := &testTarget{Name: "name", Nested: &testTargetInner{Host: "localhost"}}
+
+	diff := CalculateDiff(target, target)
+
+	assert.Empty(t, diff)
+}
@@ -0,0 +1,36 @@
+package audit_logs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLogEntry describes the action to write into audit log
+type AuditLogEntry struct {
+	Action     AuditLogAction
+	TargetType AuditLogTargetType
+	TargetID   uuid.UUID
+	TargetName string
+	Message    string
+	Diff       AuditLogDiff
+}
+
+type GetAuditLogsRequest struct {
+	UserID     *uuid.UUID
+	ActorType  *AuditLogActorType
+	Action     *AuditLogAction
+	TargetType *AuditLogTargetType
+	TargetID   *uuid.UUID
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
+}
+
+type GetAuditLogsResponse struct {
+	AuditLogs []*AuditLog `json:"auditLogs"`
+	Total     int64       `json:"total"`
+	Limit     int         `json:"limit"`
+	Offset    int         `json:"offset"`
+}
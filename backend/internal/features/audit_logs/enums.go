@@ -0,0 +1,55 @@
+package audit_logs
+
+type AuditLogActorType string
+
+const (
+	AuditLogActorTypeUser   AuditLogActorType = "USER"
+	AuditLogActorTypeSystem AuditLogActorType = "SYSTEM"
+)
+
+type AuditLogAction string
+
+const (
+	// AuditLogActionUnknown is set for logs written before
+	// actions and targets were recorded
+	AuditLogActionUnknown             AuditLogAction = "UNKNOWN"
+	AuditLogActionCreate              AuditLogAction = "CREATE"
+	AuditLogActionUpdate              AuditLogAction = "UPDATE"
+	AuditLogActionDelete              AuditLogAction = "DELETE"
+	AuditLogActionCopy                AuditLogAction = "COPY"
+	AuditLogActionBackup              AuditLogAction = "BACKUP"
+	AuditLogActionRestore             AuditLogAction = "RESTORE"
+	AuditLogActionImport              AuditLogAction = "IMPORT"
+	AuditLogActionTransfer            AuditLogAction = "TRANSFER"
+	AuditLogActionReconcile           AuditLogAction = "RECONCILE"
+	AuditLogActionChangePassword      AuditLogAction = "CHANGE_PASSWORD"
+	AuditLogActionResetPassword       AuditLogAction = "RESET_PASSWORD"
+	AuditLogActionRevokeSessions      AuditLogAction = "REVOKE_SESSIONS"
+	AuditLogActionRotateSecretKey     AuditLogAction = "ROTATE_SECRET_KEY"
+	AuditLogActionRotateEncryptionKey AuditLogAction = "ROTATE_ENCRYPTION_KEY"
+	AuditLogActionFailedSignin        AuditLogAction = "FAILED_SIGNIN"
+	AuditLogActionLockAccount         AuditLogAction = "LOCK_ACCOUNT"
+)
+
+type AuditLogTargetType string
+
+const (
+	AuditLogTargetTypeUnknown          AuditLogTargetType = "UNKNOWN"
+	AuditLogTargetTypeDatabase         AuditLogTargetType = "DATABASE"
+	AuditLogTargetTypeStorage          AuditLogTargetType = "STORAGE"
+	AuditLogTargetTypeNotifier         AuditLogTargetType = "NOTIFIER"
+	AuditLogTargetTypeNotificationRule AuditLogTargetType = "NOTIFICATION_RULE"
+	AuditLogTargetTypeBackup           AuditLogTargetType = "BACKUP"
+	AuditLogTargetTypeBackupConfig     AuditLogTargetType = "BACKUP_CONFIG"
+	AuditLogTargetTypeUser             AuditLogTargetType = "USER"
+	AuditLogTargetTypeWorkspace        AuditLogTargetType = "WORKSPACE"
+	AuditLogTargetTypeWorkspaceMember  AuditLogTargetType = "WORKSPACE_MEMBER"
+	AuditLogTargetTypeSystem           AuditLogTargetType = "SYSTEM"
+)
+
+type AuditLogExportFormat string
+
+const (
+	AuditLogExportFormatCsv  AuditLogExportFormat = "csv"
+	AuditLogExportFormatJson AuditLogExportFormat = "json"
+)
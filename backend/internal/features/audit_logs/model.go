@@ -0,0 +1,70 @@
+package audit_logs
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditLog struct {
+	ID uuid.UUID `json:"id" gorm:"column:id;type:uuid;primaryKey"`
+
+	// UserID is cleared when the user is deleted, the email
+	// is kept to know who made the action. Both are empty for
+	// actions made by the system itself
+	ActorType AuditLogActorType `json:"actorType" gorm:"column:actor_type;type:text;not null"`
+	UserID    *uuid.UUID        `json:"userId"    gorm:"column:user_id;type:uuid"`
+	UserEmail string            `json:"userEmail" gorm:"column:user_email;type:text;not null"`
+
+	Action     AuditLogAction     `json:"action"     gorm:"column:action;type:text;not null"`
+	TargetType AuditLogTargetType `json:"targetType" gorm:"column:target_type;type:text;not null"`
+	TargetID   *uuid.UUID         `json:"targetId"   gorm:"column:target_id;type:uuid"`
+	TargetName string             `json:"targetName" gorm:"column:target_name;type:text;not null"`
+
+	Message   string       `json:"message"   gorm:"column:message;type:text;not null"`
+	Diff      AuditLogDiff `json:"diff"      gorm:"column:diff;type:jsonb"`
+	CreatedAt time.Time    `json:"createdAt" gorm:"column:created_at;not null"`
+}
+
+func (l *AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+type AuditLogFieldChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// AuditLogDiff contains changed fields of the target. Nested fields
+// are joined with dots, e.g. "postgresql.host"
+type AuditLogDiff map[string]AuditLogFieldChange
+
+func (d AuditLogDiff) Value() (driver.Value, error) {
+	if len(d) == 0 {
+		return nil, nil
+	}
+
+	return json.Marshal(d)
+}
+
+func (d *AuditLogDiff) Scan(value any) error {
+	if value == nil {
+		*d = nil
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("failed to scan audit log diff")
+	}
+
+	return json.Unmarshal(data, d)
+}
@@ -0,0 +1,79 @@
+package audit_logs
+
+import (
+	"postgresus-backend/internal/storage"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository struct{}
+
+func (r *AuditLogRepository) Save(auditLog *AuditLog) error {
+	if auditLog.ID == uuid.Nil {
+		auditLog.ID = uuid.New()
+	}
+
+	return storage.GetDb().Create(auditLog).Error
+}
+
+func (r *AuditLogRepository) Find(
+	request *GetAuditLogsRequest,
+	limit int,
+	offset int,
+) ([]*AuditLog, error) {
+	var auditLogs []*AuditLog
+
+	if err := r.applyFilters(storage.GetDb(), request).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&auditLogs).Error; err != nil {
+		return nil, err
+	}
+
+	return auditLogs, nil
+}
+
+func (r *AuditLogRepository) Count(request *GetAuditLogsRequest) (int64, error) {
+	var count int64
+
+	if err := r.applyFilters(storage.GetDb().Model(&AuditLog{}), request).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *AuditLogRepository) applyFilters(query *gorm.DB, request *GetAuditLogsRequest) *gorm.DB {
+	if request.UserID != nil {
+		query = query.Where("user_id = ?", *request.UserID)
+	}
+
+	if request.ActorType != nil {
+		query = query.Where("actor_type = ?", *request.ActorType)
+	}
+
+	if request.Action != nil {
+		query = query.Where("action = ?", *request.Action)
+	}
+
+	if request.TargetType != nil {
+		query = query.Where("target_type = ?", *request.TargetType)
+	}
+
+	if request.TargetID != nil {
+		query = query.Where("target_id = ?", *request.TargetID)
+	}
+
+	if request.From != nil {
+		query = query.Where("created_at >= ?", *request.From)
+	}
+
+	if request.To != nil {
+		query = query.Where("created_at < ?", *request.To)
+	}
+
+	return query
+}
@@ -0,0 +1,250 @@
+package audit_logs
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	users_models "postgresus-backend/internal/features/users/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const defaultAuditLogsLimit = 100
+const maxAuditLogsLimit = 1000
+const maxAuditLogsExportLimit = 100_000
+
+type AuditLogService struct {
+	auditLogRepository *AuditLogRepository
+	logger             *slog.Logger
+}
+
+// WriteAuditLog records the action made by the user. Failure to
+// write the log is only logged, so it does not break the action itself
+func (s *AuditLogService) WriteAuditLog(user *users_models.User, entry *AuditLogEntry) {
+	auditLog := s.newAuditLog(entry)
+	auditLog.ActorType = AuditLogActorTypeUser
+	auditLog.UserID = &user.ID
+	auditLog.UserEmail = user.Email
+
+	s.saveAuditLog(auditLog)
+}
+
+// WriteSystemAuditLog records the action made by Postgresus itself,
+// e.g. scheduled backups or removal of backups by store period
+func (s *AuditLogService) WriteSystemAuditLog(entry *AuditLogEntry) {
+	auditLog := s.newAuditLog(entry)
+	auditLog.ActorType = AuditLogActorTypeSystem
+
+	s.saveAuditLog(auditLog)
+}
+
+// WriteUserAuditLog records changes of users. The actor is nil
+// when the action is made via CLI
+func (s *AuditLogService) WriteUserAuditLog(
+	actor *users_models.User,
+	action string,
+	before *users_models.User,
+	after *users_models.User,
+	message string,
+) {
+	target := after
+	if target == nil {
+		target = before
+	}
+
+	entry := &AuditLogEntry{
+		Action:     AuditLogAction(action),
+		TargetType: AuditLogTargetTypeUser,
+		TargetID:   target.ID,
+		TargetName: target.Email,
+		Message:    message,
+		Diff:       CalculateDiff(before, after),
+	}
+
+	if actor == nil {
+		s.WriteSystemAuditLog(entry)
+		return
+	}
+
+	s.WriteAuditLog(actor, entry)
+}
+
+// WriteSigninAuditLog records sign in attempts. They are made by not
+// authenticated users, so the system is the actor. User is nil when
+// nobody has the email
+func (s *AuditLogService) WriteSigninAuditLog(
+	action string,
+	user *users_models.User,
+	email string,
+	message string,
+) {
+	entry := &AuditLogEntry{
+		Action:     AuditLogAction(action),
+		TargetType: AuditLogTargetTypeUser,
+		TargetName: email,
+		Message:    message,
+	}
+
+	if user != nil {
+		entry.TargetID = user.ID
+	}
+
+	s.WriteSystemAuditLog(entry)
+}
+
+func (s *AuditLogService) GetAuditLogs(
+	request *GetAuditLogsRequest,
+) (*GetAuditLogsResponse, error) {
+	limit := request.Limit
+	if limit <= 0 {
+		limit = defaultAuditLogsLimit
+	}
+
+	limit = min(limit, maxAuditLogsLimit)
+	offset := max(request.Offset, 0)
+
+	auditLogs, err := s.auditLogRepository.Find(request, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.auditLogRepository.Count(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetAuditLogsResponse{
+		AuditLogs: auditLogs,
+		Total:     total,
+		Limit:     limit,
+		Offset:    offset,
+	}, nil
+}
+
+// ExportAuditLogs writes logs matching the filters, newest first.
+// Pagination of the request is ignored, at most 100 000 logs
+// are exported
+func (s *AuditLogService) ExportAuditLogs(
+	request *GetAuditLogsRequest,
+	format AuditLogExportFormat,
+	writer io.Writer,
+) error {
+	if format != AuditLogExportFormatCsv && format != AuditLogExportFormatJson {
+		return errors.New("invalid export format")
+	}
+
+	auditLogs, err := s.auditLogRepository.Find(request, maxAuditLogsExportLimit, 0)
+	if err != nil {
+		return err
+	}
+
+	if format == AuditLogExportFormatJson {
+		return json.NewEncoder(writer).Encode(auditLogs)
+	}
+
+	return s.writeCsv(auditLogs, writer)
+}
+
+func (s *AuditLogService) newAuditLog(entry *AuditLogEntry) *AuditLog {
+	auditLog := &AuditLog{
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetName: entry.TargetName,
+		Message:    entry.Message,
+		Diff:       entry.Diff,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	if entry.TargetID != uuid.Nil {
+		targetID := entry.TargetID
+		auditLog.TargetID = &targetID
+	}
+
+	return auditLog
+}
+
+func (s *AuditLogService) saveAuditLog(auditLog *AuditLog) {
+	if err := s.auditLogRepository.Save(auditLog); err != nil {
+		s.logger.Error(
+			"Failed to write audit log",
+			"action",
+			auditLog.Action,
+			"targetId",
+			auditLog.TargetID,
+			"error",
+			err,
+		)
+	}
+}
+
+func (s *AuditLogService) writeCsv(auditLogs []*AuditLog, writer io.Writer) error {
+	csvWriter := csv.NewWriter(writer)
+
+	if err := csvWriter.Write([]string{
+		"created_at",
+		"actor_type",
+		"user_email",
+		"action",
+		"target_type",
+		"target_id",
+		"target_name",
+		"message",
+		"diff",
+	}); err != nil {
+		return err
+	}
+
+	for _, auditLog := range auditLogs {
+		targetID := ""
+		if auditLog.TargetID != nil {
+			targetID = auditLog.TargetID.String()
+		}
+
+		diff := ""
+		if len(auditLog.Diff) > 0 {
+			diffJson, err := json.Marshal(auditLog.Diff)
+			if err != nil {
+				return err
+			}
+			diff = string(diffJson)
+		}
+
+		row := []string{
+			auditLog.CreatedAt.Format(time.RFC3339),
+			string(auditLog.ActorType),
+			auditLog.UserEmail,
+			string(auditLog.Action),
+			string(auditLog.TargetType),
+			targetID,
+			auditLog.TargetName,
+			auditLog.Message,
+			diff,
+		}
+		for i, cell := range row {
+			row[i] = escapeCsvCell(cell)
+		}
+
+		if err := csvWriter.Write(row); err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
+
+// escapeCsvCell prevents formula injection: names and messages are set
+// by users, so a cell like "=HYPERLINK(...)" would be executed by the
+// spreadsheet opening the export
+func escapeCsvCell(cell string) string {
+	if cell != "" && strings.ContainsAny(cell[:1], "=+-@\t\r") {
+		return "'" + cell
+	}
+
+	return cell
+}
@@ -0,0 +1,54 @@
+package audit_logs
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WriteCsvWhenCellsStartWithFormulaCharacters_CellsEscaped(t *testing.T) {
+	auditLogs := []*AuditLog{
+		{
+			ActorType:  AuditLogActorTypeUser,
+			UserEmail:  "admin@example.com",
+			Action:     AuditLogActionCreate,
+			TargetType: AuditLogTargetTypeDatabase,
+			TargetName: "=HYPERLINK(\"https://example.com\")",
+			Message:    "@SUM(1+1)",
+			CreatedAt:  time.Date(2025, 10, 20, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			ActorType:  AuditLogActorTypeUser,
+			UserEmail:  "admin@example.com",
+			Action:     AuditLogActionUpdate,
+			TargetType: AuditLogTargetTypeDatabase,
+			TargetName: "-1+2",
+			Message:    "\tcmd",
+			CreatedAt:  time.Date(2025, 10, 20, 12, 0, 0, 0, time.UTC),
+		},
+	}
+
+	var buffer bytes.Buffer
+	require.NoError(t, (&AuditLogService{}).writeCsv(auditLogs, &buffer))
+
+	rows, err := csv.NewReader(&buffer).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	assert.Equal(t, "admin@example.com", rows[1][2])
+	assert.Equal(t, "'=HYPERLINK(\"https://example.com\")", rows[1][6])
+	assert.Equal(t, "'@SUM(1+1)", rows[1][7])
+	assert.Equal(t, "'-1+2", rows[2][6])
+	assert.Equal(t, "'\tcmd", rows[2][7])
+}
+
+func Test_EscapeCsvCellWhenCellStartsWithCarriageReturn_CellEscaped(t *testing.T) {
+	assert.Equal(t, "'\rvalue", escapeCsvCell("\rvalue"))
+	assert.Equal(t, "'+value", escapeCsvCell("+value"))
+	assert.Equal(t, "value", escapeCsvCell("value"))
+	assert.Equal(t, "", escapeCsvCell(""))
+}
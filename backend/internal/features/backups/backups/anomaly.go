@@ -0,0 +1,107 @@
+package backups
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const anomalyHistorySize = 10
+const anomalyMinHistorySize = 3
+
+// detectBackupAnomaly compares size and duration of the backup with
+// medians of previous completed backups. Returns description of the
+// anomaly or nil if the backup looks as usual
+func detectBackupAnomaly(
+	backup *Backup,
+	previousBackups []*Backup,
+	sizeThresholdPercent int,
+	durationThresholdPercent int,
+) *string {
+	sizes := make([]float64, 0, anomalyHistorySize)
+	durations := make([]float64, 0, anomalyHistorySize)
+
+	for _, previousBackup := range previousBackups {
+		if len(sizes) >= anomalyHistorySize {
+			break
+		}
+
+		if previousBackup.ID == backup.ID ||
+			previousBackup.Status != BackupStatusCompleted ||
+			previousBackup.IsImported {
+			continue
+		}
+
+		sizes = append(sizes, previousBackup.BackupSizeMb)
+		durations = append(durations, float64(previousBackup.BackupDurationMs))
+	}
+
+	if len(sizes) < anomalyMinHistorySize {
+		return nil
+	}
+
+	anomalies := make([]string, 0)
+
+	if sizeThresholdPercent > 0 {
+		medianSizeMb := getMedian(sizes)
+		deviationPercent := getDeviationPercent(backup.BackupSizeMb, medianSizeMb)
+
+		if deviationPercent > float64(sizeThresholdPercent) {
+			anomalies = append(anomalies, fmt.Sprintf(
+				"size %.2f MB differs by %.0f%% from usual %.2f MB",
+				backup.BackupSizeMb,
+				deviationPercent,
+				medianSizeMb,
+			))
+		}
+	}
+
+	if durationThresholdPercent > 0 {
+		medianDurationMs := getMedian(durations)
+		deviationPercent := getDeviationPercent(
+			float64(backup.BackupDurationMs),
+			medianDurationMs,
+		)
+
+		if deviationPercent > float64(durationThresholdPercent) {
+			anomalies = append(anomalies, fmt.Sprintf(
+				"duration %.1fs differs by %.0f%% from usual %.1fs",
+				float64(backup.BackupDurationMs)/1000,
+				deviationPercent,
+				medianDurationMs/1000,
+			))
+		}
+	}
+
+	if len(anomalies) == 0 {
+		return nil
+	}
+
+	message := "Backup " + strings.Join(anomalies, ", ")
+	return &message
+}
+
+func getMedian(values []float64) float64 {
+	sortedValues := slices.Clone(values)
+	slices.Sort(sortedValues)
+
+	middle := len(sortedValues) / 2
+	if len(sortedValues)%2 == 0 {
+		return (sortedValues[middle-1] + sortedValues[middle]) / 2
+	}
+
+	return sortedValues[middle]
+}
+
+func getDeviationPercent(value float64, median float64) float64 {
+	if median == 0 {
+		return 0
+	}
+
+	deviation := (value - median) / median * 100
+	if deviation < 0 {
+		return -deviation
+	}
+
+	return deviation
+}
@@ -0,0 +1,48 @@
+package backups
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DetectBackupAnomalyForMuchSmallerBackup_AnomalyReturned(t *testing.T) {
+	backup := &Backup{ID: uuid.New(), BackupSizeMb: 10, BackupDurationMs: 60_000}
+
+	anomaly := detectBackupAnomaly(backup, createCompletedBackups(100, 60_000, 5), 50, 100)
+
+	assert.NotNil(t, anomaly)
+	assert.Contains(t, *anomaly, "size")
+	assert.NotContains(t, *anomaly, "duration")
+}
+
+func Test_DetectBackupAnomalyForUsualBackup_NilReturned(t *testing.T) {
+	backup := &Backup{ID: uuid.New(), BackupSizeMb: 110, BackupDurationMs: 70_000}
+
+	anomaly := detectBackupAnomaly(backup, createCompletedBackups(100, 60_000, 5), 50, 100)
+
+	assert.Nil(t, anomaly)
+}
+
+func Test_DetectBackupAnomalyWithShortHistory_NilReturned(t *testing.T) {
+	backup := &Backup{ID: uuid.New(), BackupSizeMb: 1, BackupDurationMs: 1_000}
+
+	anomaly := detectBackupAnomaly(backup, createCompletedBackups(100, 60_000, 2), 50, 100)
+
+	assert.Nil(t, anomaly)
+}
+
+func createCompletedBackups(sizeMb float64, durationMs int64, count int) []*Backup {
+	backups := make([]*Backup, 0, count)
+	for range count {
+		backups = append(backups, &Backup{
+			ID:               uuid.New(),
+			Status:           BackupStatusCompleted,
+			BackupSizeMb:     sizeMb,
+			BackupDurationMs: durationMs,
+		})
+	}
+
+	return backups
+}
@@ -1,12 +1,16 @@
 package backups
 
 import (
+	"fmt"
 	"log/slog"
 	"postgresus-backend/internal/config"
+	"postgresus-backend/internal/features/audit_logs"
 	backups_config "postgresus-backend/internal/features/backups/config"
 	"postgresus-backend/internal/features/storages"
 	"postgresus-backend/internal/util/period"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type BackupBackgroundService struct {
@@ -15,8 +19,17 @@ type BackupBackgroundService struct {
 	backupConfigService *backups_config.BackupConfigService
 	storageService      *storages.StorageService
 
-	lastBackupTime time.Time
-	logger         *slog.Logger
+	reconciliationService *BackupReconciliationService
+	storageUsageService   *StorageUsageService
+	auditLogService       *audit_logs.AuditLogService
+
+	// last completed backup of the database which overdue notification
+	// is sent for, so it is sent once until the next backup
+	overdueNotifiedBackupIDs map[uuid.UUID]uuid.UUID
+
+	lastBackupTime         time.Time
+	lastReconciliationTime time.Time
+	logger                 *slog.Logger
 }
 
 func (s *BackupBackgroundService) Run() {
@@ -44,6 +57,16 @@ func (s *BackupBackgroundService) Run() {
 			s.logger.Error("Failed to run pending backups", "error", err)
 		}
 
+		if err := s.checkOverdueBackups(); err != nil {
+			s.logger.Error("Failed to check overdue backups", "error", err)
+		}
+
+		if time.Since(s.lastReconciliationTime) > 24*time.Hour {
+			s.reconciliationService.ReconcileAllStorages()
+			s.storageUsageService.SaveUsageSnapshots()
+			s.lastReconciliationTime = time.Now().UTC()
+		}
+
 		s.lastBackupTime = time.Now().UTC()
 		time.Sleep(1 * time.Minute)
 	}
@@ -131,9 +154,12 @@ func (s *BackupBackgroundService) cleanOldBackups() error {
 				continue
 			}
 
+			// the backup is kept until its file is deleted, so it is retried on
+			// the next cleanup instead of leaving the file in the storage forever
 			err = storage.DeleteFile(backup.ID)
 			if err != nil {
 				s.logger.Error("Failed to delete backup file", "backupId", backup.ID, "error", err)
+				continue
 			}
 
 			if err := s.backupRepository.DeleteByID(backup.ID); err != nil {
@@ -148,6 +174,19 @@ func (s *BackupBackgroundService) cleanOldBackups() error {
 				"databaseId",
 				backupConfig.DatabaseID,
 			)
+
+			s.auditLogService.WriteSystemAuditLog(&audit_logs.AuditLogEntry{
+				Action:     audit_logs.AuditLogActionDelete,
+				TargetType: audit_logs.AuditLogTargetTypeBackup,
+				TargetID:   backup.ID,
+				TargetName: backup.Database.Name,
+				Message: fmt.Sprintf(
+					"Deleted backup of database \"%s\" made at %s by store period %s",
+					backup.Database.Name,
+					backup.CreatedAt.Format(time.RFC3339),
+					backupStorePeriod,
+				),
+			})
 		}
 	}
 
@@ -195,6 +234,13 @@ func (s *BackupBackgroundService) runPendingBackups() error {
 			)
 
 			go s.backupService.MakeBackup(backupConfig.DatabaseID, remainedBackupTryCount == 1)
+
+			s.auditLogService.WriteSystemAuditLog(&audit_logs.AuditLogEntry{
+				Action:     audit_logs.AuditLogActionBackup,
+				TargetType: audit_logs.AuditLogTargetTypeDatabase,
+				TargetID:   backupConfig.DatabaseID,
+				Message:    "Started scheduled backup",
+			})
 			s.logger.Info(
 				"Successfully triggered scheduled backup",
 				"databaseId",
@@ -206,6 +252,77 @@ func (s *BackupBackgroundService) runPendingBackups() error {
 	return nil
 }
 
+// checkOverdueBackups notifies about databases whose last completed
+// backup is older than the interval plus the grace period. It catches
+// schedules which silently stopped, when nothing fails
+func (s *BackupBackgroundService) checkOverdueBackups() error {
+	enabledBackupConfigs, err := s.backupConfigService.GetBackupConfigsWithEnabledBackups()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	for _, backupConfig := range enabledBackupConfigs {
+		if backupConfig.BackupInterval == nil {
+			continue
+		}
+
+		lastCompletedBackup, err := s.backupRepository.FindLastCompletedBeforeDate(
+			backupConfig.DatabaseID,
+			now,
+		)
+		if err != nil {
+			s.logger.Error(
+				"Failed to get last completed backup for database",
+				"databaseId",
+				backupConfig.DatabaseID,
+				"error",
+				err,
+			)
+			continue
+		}
+
+		// databases without completed backups get failure notifications
+		if lastCompletedBackup == nil {
+			continue
+		}
+
+		overdueMessage := detectBackupOverdue(
+			lastCompletedBackup,
+			backupConfig.BackupInterval,
+			time.Duration(backupConfig.BackupOverdueGraceMinutes)*time.Minute,
+			now,
+		)
+		if overdueMessage == nil {
+			delete(s.overdueNotifiedBackupIDs, backupConfig.DatabaseID)
+			continue
+		}
+
+		if s.overdueNotifiedBackupIDs[backupConfig.DatabaseID] == lastCompletedBackup.ID {
+			continue
+		}
+
+		s.logger.Warn(
+			"Backup is overdue",
+			"databaseId",
+			backupConfig.DatabaseID,
+			"lastBackupId",
+			lastCompletedBackup.ID,
+		)
+
+		s.backupService.SendBackupNotification(
+			backupConfig,
+			lastCompletedBackup,
+			backups_config.NotificationBackupOverdue,
+			overdueMessage,
+		)
+		s.overdueNotifiedBackupIDs[backupConfig.DatabaseID] = lastCompletedBackup.ID
+	}
+
+	return nil
+}
+
 // GetRemainedBackupTryCount returns the number of remaining backup tries for a given backup.
 // If the backup is not failed or the backup config does not allow retries, it returns 0.
 // If the backup is failed and the backup config allows retries, it returns the number of remaining tries.
@@ -1,6 +1,7 @@
 package backups
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,9 +11,16 @@ import (
 	"github.com/google/uuid"
 )
 
+// maxImportFileSize limits uploaded dumps, larger ones should be put
+// into the storage and imported from there
+const maxImportFileSize = 10 * 1024 * 1024 * 1024
+
 type BackupController struct {
-	backupService *BackupService
-	userService   *users.UserService
+	backupService               *BackupService
+	backupReconciliationService *BackupReconciliationService
+	backupImportService         *BackupImportService
+	storageUsageService         *StorageUsageService
+	userService                 *users.UserService
 }
 
 func (c *BackupController) RegisterRoutes(router *gin.RouterGroup) {
@@ -20,6 +28,22 @@ func (c *BackupController) RegisterRoutes(router *gin.RouterGroup) {
 	router.POST("/backups", c.MakeBackup)
 	router.GET("/backups/:id/file", c.GetFile)
 	router.DELETE("/backups/:id", c.DeleteBackup)
+
+	router.POST("/backups/import", c.ImportBackupFromStorage)
+	router.POST("/backups/import/upload", c.ImportBackupFromFile)
+
+	router.GET("/backups/storages/:id/usage", c.GetStorageUsage)
+	router.GET("/backups/storages/:id/forecast", c.GetStorageForecast)
+
+	router.GET("/backups/storages/:id/reconciliation", c.GetStorageReconciliation)
+	router.POST(
+		"/backups/storages/:id/reconciliation/delete-orphans",
+		c.DeleteStorageOrphanFiles,
+	)
+	router.POST(
+		"/backups/storages/:id/reconciliation/mark-lost",
+		c.MarkStorageMissingBackupsAsLost,
+	)
 }
 
 // GetBackups
@@ -199,6 +223,312 @@ func (c *BackupController) GetFile(ctx *gin.Context) {
 	}
 }
 
+// ImportBackupFromStorage
+// @Summary Import a dump from storage
+// @Description Register an existing pg_dump custom format archive from the storage as a backup of the database. The archive is copied, the original file is kept
+// @Tags backups
+// @Accept json
+// @Produce json
+// @Param request body ImportBackupFromStorageRequest true "Import data"
+// @Success 200 {object} Backup
+// @Failure 400
+// @Failure 401
+// @Router /backups/import [post]
+func (c *BackupController) ImportBackupFromStorage(ctx *gin.Context) {
+	var request ImportBackupFromStorageRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authorizationHeader := ctx.GetHeader("Authorization")
+	if authorizationHeader == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+		return
+	}
+
+	user, err := c.userService.GetUserFromToken(authorizationHeader)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	backup, err := c.backupImportService.ImportFromStorage(user, &request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, backup)
+}
+
+// ImportBackupFromFile
+// @Summary Import an uploaded dump
+// @Description Upload pg_dump custom format archive and register it as a backup of the database
+// @Tags backups
+// @Accept multipart/form-data
+// @Produce json
+// @Param database_id formData string true "Database ID"
+// @Param storage_id formData string true "Storage ID"
+// @Param file formData file true "Dump file, up to 10 GB"
+// @Success 200 {object} Backup
+// @Failure 400
+// @Failure 401
+// @Failure 413
+// @Router /backups/import/upload [post]
+func (c *BackupController) ImportBackupFromFile(ctx *gin.Context) {
+	// the user is checked before the form is read, otherwise anyone
+	// could make the server parse and store huge multipart bodies
+	authorizationHeader := ctx.GetHeader("Authorization")
+	if authorizationHeader == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+		return
+	}
+
+	user, err := c.userService.GetUserFromToken(authorizationHeader)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportFileSize)
+	if err := ctx.Request.ParseMultipartForm(32 << 20); err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+			return
+		}
+
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid multipart form"})
+		return
+	}
+
+	databaseID, err := uuid.Parse(ctx.PostForm("database_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid database_id"})
+		return
+	}
+
+	storageID, err := uuid.Parse(ctx.PostForm("storage_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid storage_id"})
+		return
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	backup, err := c.backupImportService.ImportFromFile(user, databaseID, storageID, file)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, backup)
+}
+
+// GetStorageUsage
+// @Summary Get storage usage
+// @Description Get space used by backups in the storage: sum of backups sizes and actual size of files in the storage
+// @Tags backups
+// @Produce json
+// @Param id path string true "Storage ID"
+// @Success 200 {object} StorageUsage
+// @Failure 400
+// @Failure 401
+// @Router /backups/storages/{id}/usage [get]
+func (c *BackupController) GetStorageUsage(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid storage ID"})
+		return
+	}
+
+	authorizationHeader := ctx.GetHeader("Authorization")
+	if authorizationHeader == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+		return
+	}
+
+	user, err := c.userService.GetUserFromToken(authorizationHeader)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	usage, err := c.storageUsageService.GetUsageWithAuth(user, id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, usage)
+}
+
+// GetStorageForecast
+// @Summary Get storage capacity forecast
+// @Description Forecast when the storage quota will be reached based on storage usage growth for the last 30 days
+// @Tags backups
+// @Produce json
+// @Param id path string true "Storage ID"
+// @Success 200 {object} StorageForecast
+// @Failure 400
+// @Failure 401
+// @Router /backups/storages/{id}/forecast [get]
+func (c *BackupController) GetStorageForecast(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid storage ID"})
+		return
+	}
+
+	authorizationHeader := ctx.GetHeader("Authorization")
+	if authorizationHeader == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+		return
+	}
+
+	user, err := c.userService.GetUserFromToken(authorizationHeader)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	forecast, err := c.storageUsageService.GetForecastWithAuth(user, id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, forecast)
+}
+
+// GetStorageReconciliation
+// @Summary Reconcile storage with backups
+// @Description Compare files in the storage with backups. Returns completed backups which files are missing and files which do not belong to any backup
+// @Tags backups
+// @Produce json
+// @Param id path string true "Storage ID"
+// @Success 200 {object} StorageReconciliationReport
+// @Failure 400
+// @Failure 401
+// @Router /backups/storages/{id}/reconciliation [get]
+func (c *BackupController) GetStorageReconciliation(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid storage ID"})
+		return
+	}
+
+	authorizationHeader := ctx.GetHeader("Authorization")
+	if authorizationHeader == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+		return
+	}
+
+	user, err := c.userService.GetUserFromToken(authorizationHeader)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	report, err := c.backupReconciliationService.GetReconciliationReport(user, id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+// DeleteStorageOrphanFiles
+// @Summary Delete orphan files from storage
+// @Description Delete files from the storage which do not belong to any backup
+// @Tags backups
+// @Produce json
+// @Param id path string true "Storage ID"
+// @Success 200 {object} map[string]int
+// @Failure 400
+// @Failure 401
+// @Router /backups/storages/{id}/reconciliation/delete-orphans [post]
+func (c *BackupController) DeleteStorageOrphanFiles(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid storage ID"})
+		return
+	}
+
+	authorizationHeader := ctx.GetHeader("Authorization")
+	if authorizationHeader == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+		return
+	}
+
+	user, err := c.userService.GetUserFromToken(authorizationHeader)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	deletedCount, err := c.backupReconciliationService.DeleteOrphanFiles(user, id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"deletedCount": deletedCount})
+}
+
+// MarkStorageMissingBackupsAsLost
+// @Summary Mark missing backups as lost
+// @Description Mark completed backups which files are missing in the storage as lost
+// @Tags backups
+// @Produce json
+// @Param id path string true "Storage ID"
+// @Success 200 {object} map[string]int
+// @Failure 400
+// @Failure 401
+// @Router /backups/storages/{id}/reconciliation/mark-lost [post]
+func (c *BackupController) MarkStorageMissingBackupsAsLost(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid storage ID"})
+		return
+	}
+
+	authorizationHeader := ctx.GetHeader("Authorization")
+	if authorizationHeader == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+		return
+	}
+
+	user, err := c.userService.GetUserFromToken(authorizationHeader)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	markedCount, err := c.backupReconciliationService.MarkMissingBackupsAsLost(user, id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"markedCount": markedCount})
+}
+
 type MakeBackupRequest struct {
 	DatabaseID uuid.UUID `json:"database_id" binding:"required"`
 }
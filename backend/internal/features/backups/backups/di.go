@@ -1,7 +1,9 @@
 package backups
 
 import (
+	"postgresus-backend/internal/features/audit_logs"
 	"postgresus-backend/internal/features/backups/backups/usecases"
+	usecases_postgresql "postgresus-backend/internal/features/backups/backups/usecases/postgresql"
 	backups_config "postgresus-backend/internal/features/backups/config"
 	"postgresus-backend/internal/features/databases"
 	"postgresus-backend/internal/features/notifiers"
@@ -9,9 +11,20 @@ import (
 	"postgresus-backend/internal/features/users"
 	"postgresus-backend/internal/util/logger"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 var backupRepository = &BackupRepository{}
+var storageUsageSnapshotRepository = &StorageUsageSnapshotRepository{}
+
+var storageUsageService = &StorageUsageService{
+	backupRepository,
+	storageUsageSnapshotRepository,
+	storages.GetStorageService(),
+	logger.GetLogger(),
+}
+
 var backupService = &BackupService{
 	databases.GetDatabaseService(),
 	storages.GetStorageService(),
@@ -19,22 +32,48 @@ var backupService = &BackupService{
 	notifiers.GetNotifierService(),
 	notifiers.GetNotifierService(),
 	backups_config.GetBackupConfigService(),
+	storageUsageService,
+	audit_logs.GetAuditLogService(),
 	usecases.GetCreateBackupUsecase(),
 	logger.GetLogger(),
 	[]BackupRemoveListener{},
 }
 
+var backupReconciliationService = &BackupReconciliationService{
+	backupRepository,
+	storages.GetStorageService(),
+	audit_logs.GetAuditLogService(),
+	logger.GetLogger(),
+}
+
+var backupImportService = &BackupImportService{
+	databases.GetDatabaseService(),
+	storages.GetStorageService(),
+	backupRepository,
+	usecases_postgresql.GetReadPostgresqlDumpInfoUsecase(),
+	audit_logs.GetAuditLogService(),
+	logger.GetLogger(),
+}
+
 var backupBackgroundService = &BackupBackgroundService{
 	backupService,
 	backupRepository,
 	backups_config.GetBackupConfigService(),
 	storages.GetStorageService(),
+	backupReconciliationService,
+	storageUsageService,
+	audit_logs.GetAuditLogService(),
+	map[uuid.UUID]uuid.UUID{},
+	time.Now().UTC(),
 	time.Now().UTC(),
 	logger.GetLogger(),
 }
 
 var backupController = &BackupController{
 	backupService,
+	backupReconciliationService,
+	backupImportService,
+	storageUsageService,
 	users.GetUserService(),
 }
 
@@ -51,6 +90,18 @@ func GetBackupService() *BackupService {
 	return backupService
 }
 
+func GetBackupReconciliationService() *BackupReconciliationService {
+	return backupReconciliationService
+}
+
+func GetBackupImportService() *BackupImportService {
+	return backupImportService
+}
+
+func GetStorageUsageService() *StorageUsageService {
+	return storageUsageService
+}
+
 func GetBackupController() *BackupController {
 	return backupController
 }
@@ -0,0 +1,46 @@
+package backups
+
+import (
+	storages_files "postgresus-backend/internal/features/storages/files"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StorageReconciliationReport struct {
+	StorageID      uuid.UUID                     `json:"storageId"`
+	CheckedAt      time.Time                     `json:"checkedAt"`
+	MissingBackups []*Backup                     `json:"missingBackups"`
+	OrphanFiles    []*storages_files.StorageFile `json:"orphanFiles"`
+}
+
+type ImportBackupFromStorageRequest struct {
+	DatabaseID uuid.UUID `json:"databaseId" binding:"required"`
+	StorageID  uuid.UUID `json:"storageId"  binding:"required"`
+	FileName   string    `json:"fileName"   binding:"required"`
+}
+
+type StorageUsage struct {
+	StorageID uuid.UUID `json:"storageId"`
+	// sum of backups sizes known by Postgresus
+	BackupsSizeMb float64 `json:"backupsSizeMb"`
+	// size of files of the storage backups found in the storage, empty
+	// if storage cannot be listed
+	ActualSizeMb    *float64 `json:"actualSizeMb"`
+	ActualSizeError *string  `json:"actualSizeError"`
+	UsedMb          float64  `json:"usedMb"`
+
+	QuotaMb          *float64 `json:"quotaMb"`
+	QuotaUsedPercent *float64 `json:"quotaUsedPercent"`
+}
+
+type StorageForecast struct {
+	StorageID      uuid.UUID `json:"storageId"`
+	UsedMb         float64   `json:"usedMb"`
+	QuotaMb        *float64  `json:"quotaMb"`
+	DailyGrowthMb  float64   `json:"dailyGrowthMb"`
+	SnapshotsCount int       `json:"snapshotsCount"`
+	// empty if storage has no quota or does not grow
+	DaysUntilFull *float64   `json:"daysUntilFull"`
+	FullAt        *time.Time `json:"fullAt"`
+}
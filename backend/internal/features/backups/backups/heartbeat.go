@@ -0,0 +1,29 @@
+package backups
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const heartbeatTimeout = 10 * time.Second
+
+// pingHeartbeat reports successful backup to external monitoring, e.g.
+// healthchecks.io, which alerts when pings stop coming
+func pingHeartbeat(heartbeatURL string) error {
+	client := &http.Client{Timeout: heartbeatTimeout}
+
+	resp, err := client.Get(heartbeatURL)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("heartbeat URL returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,283 @@
+package backups
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"postgresus-backend/internal/config"
+	"postgresus-backend/internal/features/audit_logs"
+	usecases_postgresql "postgresus-backend/internal/features/backups/backups/usecases/postgresql"
+	"postgresus-backend/internal/features/databases"
+	"postgresus-backend/internal/features/storages"
+	user_enums "postgresus-backend/internal/features/users/enums"
+	users_models "postgresus-backend/internal/features/users/models"
+	files_utils "postgresus-backend/internal/util/files"
+	"postgresus-backend/internal/util/tools"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BackupImportService registers dumps made outside of Postgresus as
+// backups, so they can be restored as usual. Only custom format
+// archives are accepted, because restore uses pg_restore -Fc
+type BackupImportService struct {
+	databaseService     *databases.DatabaseService
+	storageService      *storages.StorageService
+	backupRepository    *BackupRepository
+	readDumpInfoUsecase *usecases_postgresql.ReadPostgresqlDumpInfoUsecase
+	auditLogService     *audit_logs.AuditLogService
+	logger              *slog.Logger
+}
+
+// ImportFromStorage copies existing file of the storage into a new
+// backup. The original file is kept untouched
+func (s *BackupImportService) ImportFromStorage(
+	user *users_models.User,
+	request *ImportBackupFromStorageRequest,
+) (*Backup, error) {
+	database, storage, err := s.getDatabaseAndStorage(user, request.DatabaseID, request.StorageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.FileName == "" {
+		return nil, errors.New("file name is required")
+	}
+
+	file, err := storage.GetFileByName(request.FileName)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	backup, err := s.importBackup(database, storage, file)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+		Action:     audit_logs.AuditLogActionImport,
+		TargetType: audit_logs.AuditLogTargetTypeBackup,
+		TargetID:   backup.ID,
+		TargetName: database.Name,
+		Message: fmt.Sprintf(
+			"Imported backup of database \"%s\" from file \"%s\" of storage \"%s\"",
+			database.Name,
+			request.FileName,
+			storage.Name,
+		),
+	})
+
+	return backup, nil
+}
+
+func (s *BackupImportService) ImportFromFile(
+	user *users_models.User,
+	databaseID uuid.UUID,
+	storageID uuid.UUID,
+	file io.Reader,
+) (*Backup, error) {
+	database, storage, err := s.getDatabaseAndStorage(user, databaseID, storageID)
+	if err != nil {
+		return nil, err
+	}
+
+	backup, err := s.importBackup(database, storage, file)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+		Action:     audit_logs.AuditLogActionImport,
+		TargetType: audit_logs.AuditLogTargetTypeBackup,
+		TargetID:   backup.ID,
+		TargetName: database.Name,
+		Message: fmt.Sprintf(
+			"Imported uploaded backup of database \"%s\" to storage \"%s\"",
+			database.Name,
+			storage.Name,
+		),
+	})
+
+	return backup, nil
+}
+
+func (s *BackupImportService) importBackup(
+	database *databases.Database,
+	storage *storages.Storage,
+	file io.Reader,
+) (*Backup, error) {
+	tempFilePath, cleanup, err := s.saveToTempFile(file)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	dumpInfo, err := s.readDumpInfoUsecase.Execute(tempFilePath, database.Postgresql.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	if dumpInfo.Format != "CUSTOM" {
+		return nil, fmt.Errorf(
+			"only custom format dumps (pg_dump -Fc) are supported, got %s",
+			dumpInfo.Format,
+		)
+	}
+
+	if tools.IsBackupDbVersionHigherThanRestoreDbVersion(
+		tools.PostgresqlVersion(dumpInfo.GetServerMajorVersion()),
+		database.Postgresql.Version,
+	) {
+		return nil, fmt.Errorf(
+			"dump is made from PostgreSQL %s, which is higher than the database version %s",
+			dumpInfo.GetServerMajorVersion(),
+			database.Postgresql.Version,
+		)
+	}
+
+	fileInfo, err := os.Stat(tempFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dump file info: %w", err)
+	}
+
+	createdAt := time.Now().UTC()
+	if dumpInfo.CreatedAt != nil {
+		createdAt = *dumpInfo.CreatedAt
+	}
+
+	backup := &Backup{
+		DatabaseID: database.ID,
+		StorageID:  storage.ID,
+
+		Status: BackupStatusInProgress,
+
+		BackupSizeMb: float64(fileInfo.Size()) / (1024 * 1024),
+		IsImported:   true,
+
+		CreatedAt: createdAt,
+	}
+
+	if err := s.backupRepository.Save(backup); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveToStorage(storage, backup.ID, tempFilePath); err != nil {
+		if deleteErr := s.backupRepository.DeleteByID(backup.ID); deleteErr != nil {
+			s.logger.Error("Failed to delete imported backup", "error", deleteErr)
+		}
+
+		return nil, err
+	}
+
+	backup.Status = BackupStatusCompleted
+	if err := s.backupRepository.Save(backup); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info(
+		"Imported backup",
+		"backupId",
+		backup.ID,
+		"databaseId",
+		database.ID,
+		"storageId",
+		storage.ID,
+		"serverVersion",
+		dumpInfo.ServerVersion,
+	)
+
+	return backup, nil
+}
+
+func (s *BackupImportService) getDatabaseAndStorage(
+	user *users_models.User,
+	databaseID uuid.UUID,
+	storageID uuid.UUID,
+) (*databases.Database, *storages.Storage, error) {
+	database, err := s.databaseService.GetDatabaseWithRole(
+		user,
+		databaseID,
+		user_enums.UserRoleOperator,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if database.Type != databases.DatabaseTypePostgres || database.Postgresql == nil {
+		return nil, nil, errors.New("database type not supported")
+	}
+
+	storage, err := s.storageService.GetStorageWithRole(
+		user,
+		storageID,
+		user_enums.UserRoleOperator,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if storage.WorkspaceID != database.WorkspaceID {
+		return nil, nil, errors.New("storage belongs to another workspace")
+	}
+
+	return database, storage, nil
+}
+
+func (s *BackupImportService) saveToTempFile(file io.Reader) (string, func(), error) {
+	err := files_utils.EnsureDirectories([]string{
+		config.GetEnv().TempFolder,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to ensure directories: %w", err)
+	}
+
+	tempDir, err := os.MkdirTemp(config.GetEnv().TempFolder, "import_"+uuid.New().String())
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+
+	cleanup := func() {
+		_ = os.RemoveAll(tempDir)
+	}
+
+	tempFilePath := filepath.Join(tempDir, "backup.dump")
+
+	tempFile, err := os.Create(tempFilePath)
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		_ = tempFile.Close()
+	}()
+
+	if _, err := io.Copy(tempFile, file); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	return tempFilePath, cleanup, nil
+}
+
+func (s *BackupImportService) saveToStorage(
+	storage *storages.Storage,
+	backupID uuid.UUID,
+	filePath string,
+) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open dump file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return storage.SaveFile(s.logger, backupID, file)
+}
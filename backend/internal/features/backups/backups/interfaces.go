@@ -4,6 +4,7 @@ import (
 	backups_config "postgresus-backend/internal/features/backups/config"
 	"postgresus-backend/internal/features/databases"
 	"postgresus-backend/internal/features/notifiers"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
 	"postgresus-backend/internal/features/storages"
 
 	"github.com/google/uuid"
@@ -12,8 +13,12 @@ import (
 type NotificationSender interface {
 	SendNotification(
 		notifier *notifiers.Notifier,
-		title string,
-		message string,
+		event *notifier_events.NotificationEvent,
+	)
+
+	ResolveIncident(
+		notifier *notifiers.Notifier,
+		event *notifier_events.NotificationEvent,
 	)
 }
 
@@ -2,6 +2,7 @@ package backups
 
 import (
 	"postgresus-backend/internal/features/notifiers"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -12,8 +13,14 @@ type MockNotificationSender struct {
 
 func (m *MockNotificationSender) SendNotification(
 	notifier *notifiers.Notifier,
-	title string,
-	message string,
+	event *notifier_events.NotificationEvent,
 ) {
-	m.Called(notifier, title, message)
+	m.Called(notifier, event)
+}
+
+func (m *MockNotificationSender) ResolveIncident(
+	notifier *notifiers.Notifier,
+	event *notifier_events.NotificationEvent,
+) {
+	m.Called(notifier, event)
 }
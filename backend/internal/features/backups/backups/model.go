@@ -24,5 +24,23 @@ type Backup struct {
 
 	BackupDurationMs int64 `json:"backupDurationMs" gorm:"column:backup_duration_ms;default:0"`
 
+	// imported backups are not removed by store period, because
+	// they are usually older than the period
+	IsImported bool `json:"isImported" gorm:"column:is_imported;not null;default:false"`
+
+	IsAnomaly      bool    `json:"isAnomaly"      gorm:"column:is_anomaly;not null;default:false"`
+	AnomalyMessage *string `json:"anomalyMessage" gorm:"column:anomaly_message"`
+
 	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at"`
 }
+
+type StorageUsageSnapshot struct {
+	ID        uuid.UUID `json:"id"        gorm:"column:id;type:uuid;primaryKey"`
+	StorageID uuid.UUID `json:"storageId" gorm:"column:storage_id;type:uuid;not null"`
+	UsedMb    float64   `json:"usedMb"    gorm:"column:used_mb;not null"`
+	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at"`
+}
+
+func (s *StorageUsageSnapshot) TableName() string {
+	return "storage_usage_snapshots"
+}
@@ -0,0 +1,48 @@
+package backups
+
+import (
+	"fmt"
+	"postgresus-backend/internal/features/intervals"
+	"strings"
+	"time"
+)
+
+// detectBackupOverdue checks whether the last completed backup is older
+// than the interval plus the grace period. Returns description of the
+// delay or nil if backups are made on schedule
+func detectBackupOverdue(
+	lastCompletedBackup *Backup,
+	interval *intervals.Interval,
+	gracePeriod time.Duration,
+	now time.Time,
+) *string {
+	maxDuration := interval.GetMaxDuration()
+	if lastCompletedBackup == nil || maxDuration == 0 {
+		return nil
+	}
+
+	age := now.Sub(lastCompletedBackup.CreatedAt)
+	if age <= maxDuration+gracePeriod {
+		return nil
+	}
+
+	message := fmt.Sprintf(
+		"Last successful backup was made %s ago, %s backups are expected",
+		formatBackupAge(age),
+		strings.ToLower(string(interval.Interval)),
+	)
+	return &message
+}
+
+// formatBackupAge formats duration as "1d 2h" or "2h 5m"
+func formatBackupAge(age time.Duration) string {
+	days := int(age.Hours()) / 24
+	hours := int(age.Hours()) % 24
+	minutes := int(age.Minutes()) % 60
+
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh", days, hours)
+	}
+
+	return fmt.Sprintf("%dh %dm", hours, minutes)
+}
@@ -0,0 +1,33 @@
+package backups
+
+import (
+	"postgresus-backend/internal/features/intervals"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DetectBackupOverdueForDailyBackupTwoDaysAgo_OverdueReturned(t *testing.T) {
+	now := time.Date(2025, 10, 16, 12, 0, 0, 0, time.UTC)
+	lastBackup := &Backup{CreatedAt: now.Add(-50 * time.Hour)}
+	interval := &intervals.Interval{Interval: intervals.IntervalDaily}
+
+	overdue := detectBackupOverdue(lastBackup, interval, time.Hour, now)
+
+	assert.NotNil(t, overdue)
+	assert.Equal(
+		t,
+		"Last successful backup was made 2d 2h ago, daily backups are expected",
+		*overdue,
+	)
+}
+
+func Test_DetectBackupOverdueWithinGracePeriod_NilReturned(t *testing.T) {
+	now := time.Date(2025, 10, 16, 12, 0, 0, 0, time.UTC)
+	lastBackup := &Backup{CreatedAt: now.Add(-90 * time.Minute)}
+	interval := &intervals.Interval{Interval: intervals.IntervalHourly}
+
+	assert.Nil(t, detectBackupOverdue(lastBackup, interval, time.Hour, now))
+	assert.NotNil(t, detectBackupOverdue(lastBackup, interval, 15*time.Minute, now))
+}
@@ -0,0 +1,279 @@
+package backups
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"postgresus-backend/internal/features/audit_logs"
+	"postgresus-backend/internal/features/storages"
+	storages_files "postgresus-backend/internal/features/storages/files"
+	user_enums "postgresus-backend/internal/features/users/enums"
+	users_models "postgresus-backend/internal/features/users/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BackupReconciliationService compares backups known by Postgresus
+// with files that really exist in storages. It finds completed backups
+// without file (missing) and files without backup (orphans)
+type BackupReconciliationService struct {
+	backupRepository *BackupRepository
+	storageService   *storages.StorageService
+	auditLogService  *audit_logs.AuditLogService
+	logger           *slog.Logger
+}
+
+func (s *BackupReconciliationService) GetReconciliationReport(
+	user *users_models.User,
+	storageID uuid.UUID,
+) (*StorageReconciliationReport, error) {
+	storage, err := s.storageService.GetStorage(user, storageID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ReconcileStorage(storage)
+}
+
+func (s *BackupReconciliationService) DeleteOrphanFiles(
+	user *users_models.User,
+	storageID uuid.UUID,
+) (int, error) {
+	storage, err := s.storageService.GetStorageWithRole(
+		user,
+		storageID,
+		user_enums.UserRoleAdmin,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	report, err := s.ReconcileStorage(storage)
+	if err != nil {
+		return 0, err
+	}
+
+	deletedCount := 0
+	for _, file := range report.OrphanFiles {
+		fileID, _ := file.GetBackupID()
+
+		if err := storage.DeleteFile(fileID); err != nil {
+			return deletedCount, err
+		}
+
+		deletedCount++
+
+		s.logger.Info(
+			"Deleted orphan file from storage",
+			"storageId",
+			storage.ID,
+			"fileName",
+			file.Name,
+		)
+	}
+
+	s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+		Action:     audit_logs.AuditLogActionReconcile,
+		TargetType: audit_logs.AuditLogTargetTypeStorage,
+		TargetID:   storage.ID,
+		TargetName: storage.Name,
+		Message: fmt.Sprintf(
+			"Deleted %d orphan files from storage \"%s\"",
+			deletedCount,
+			storage.Name,
+		),
+	})
+
+	return deletedCount, nil
+}
+
+func (s *BackupReconciliationService) MarkMissingBackupsAsLost(
+	user *users_models.User,
+	storageID uuid.UUID,
+) (int, error) {
+	storage, err := s.storageService.GetStorageWithRole(
+		user,
+		storageID,
+		user_enums.UserRoleAdmin,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	report, err := s.ReconcileStorage(storage)
+	if err != nil {
+		return 0, err
+	}
+
+	markedCount := 0
+	for _, backup := range report.MissingBackups {
+		// the file may appear after the listing, e.g. it is uploaded
+		// by slow storage, so it is checked again right before marking
+		if s.isFileExists(storage, backup.ID) {
+			continue
+		}
+
+		failMessage := "Backup file is missing in storage"
+		backup.Status = BackupStatusLost
+		backup.FailMessage = &failMessage
+
+		if err := s.backupRepository.Save(backup); err != nil {
+			return markedCount, err
+		}
+
+		markedCount++
+
+		s.logger.Info(
+			"Marked backup as lost",
+			"storageId",
+			storage.ID,
+			"backupId",
+			backup.ID,
+		)
+	}
+
+	s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+		Action:     audit_logs.AuditLogActionReconcile,
+		TargetType: audit_logs.AuditLogTargetTypeStorage,
+		TargetID:   storage.ID,
+		TargetName: storage.Name,
+		Message: fmt.Sprintf(
+			"Marked %d backups as lost in storage \"%s\"",
+			markedCount,
+			storage.Name,
+		),
+	})
+
+	return markedCount, nil
+}
+
+func (s *BackupReconciliationService) ReconcileStorage(
+	storage *storages.Storage,
+) (*StorageReconciliationReport, error) {
+	if storage == nil {
+		return nil, errors.New("storage is required")
+	}
+
+	checkedAt := time.Now().UTC()
+
+	// backups are loaded before the listing: file of the backup is
+	// saved before the backup is completed, so each completed backup
+	// already has its file when the listing starts. Backups completed
+	// later are not loaded and are not considered as missing
+	storageBackups, err := s.backupRepository.FindByStorageID(storage.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := storage.ListFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	filesByBackupID := make(map[uuid.UUID]*storages_files.StorageFile)
+	for _, file := range files {
+		if backupID, isBackupFile := file.GetBackupID(); isBackupFile {
+			filesByBackupID[backupID] = file
+		}
+	}
+
+	missingBackups := make([]*Backup, 0)
+	for _, backup := range storageBackups {
+		if backup.Status != BackupStatusCompleted {
+			continue
+		}
+
+		if _, isFileExists := filesByBackupID[backup.ID]; !isFileExists {
+			missingBackups = append(missingBackups, backup)
+		}
+	}
+
+	fileBackupIDs := make([]uuid.UUID, 0, len(filesByBackupID))
+	for backupID := range filesByBackupID {
+		fileBackupIDs = append(fileBackupIDs, backupID)
+	}
+
+	fileBackups, err := s.backupRepository.FindByIDs(fileBackupIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	fileBackupsByID := make(map[uuid.UUID]*Backup, len(fileBackups))
+	for _, backup := range fileBackups {
+		fileBackupsByID[backup.ID] = backup
+	}
+
+	// file of a live backup of another storage is not an orphan:
+	// storages may share the same bucket or folder, deleting it would
+	// remove the backup of that storage. Files left by failed and lost
+	// backups are not used by anyone, so they are removable
+	orphanFiles := make([]*storages_files.StorageFile, 0)
+	for backupID, file := range filesByBackupID {
+		if backup, isBackupExists := fileBackupsByID[backupID]; isBackupExists &&
+			(backup.Status == BackupStatusCompleted || backup.Status == BackupStatusInProgress) {
+			continue
+		}
+
+		orphanFiles = append(orphanFiles, file)
+	}
+
+	return &StorageReconciliationReport{
+		StorageID:      storage.ID,
+		CheckedAt:      checkedAt,
+		MissingBackups: missingBackups,
+		OrphanFiles:    orphanFiles,
+	}, nil
+}
+
+func (s *BackupReconciliationService) isFileExists(
+	storage *storages.Storage,
+	backupID uuid.UUID,
+) bool {
+	file, err := storage.GetFile(backupID)
+	if err != nil {
+		return false
+	}
+
+	if err := file.Close(); err != nil {
+		s.logger.Error("Failed to close backup file", "backupId", backupID, "error", err)
+	}
+
+	return true
+}
+
+func (s *BackupReconciliationService) ReconcileAllStorages() {
+	allStorages, err := s.storageService.GetAllStorages()
+	if err != nil {
+		s.logger.Error("Failed to get storages for reconciliation", "error", err)
+		return
+	}
+
+	for _, storage := range allStorages {
+		report, err := s.ReconcileStorage(storage)
+		if err != nil {
+			s.logger.Error(
+				"Failed to reconcile storage",
+				"storageId",
+				storage.ID,
+				"error",
+				err,
+			)
+			continue
+		}
+
+		if len(report.MissingBackups) == 0 && len(report.OrphanFiles) == 0 {
+			continue
+		}
+
+		s.logger.Warn(
+			"Storage is out of sync with backups",
+			"storageId",
+			storage.ID,
+			"missingBackupsCount",
+			len(report.MissingBackups),
+			"orphanFilesCount",
+			len(report.OrphanFiles),
+		)
+	}
+}
@@ -0,0 +1,162 @@
+package backups
+
+import (
+	"bytes"
+	"postgresus-backend/internal/features/databases"
+	"postgresus-backend/internal/features/notifiers"
+	"postgresus-backend/internal/features/storages"
+	"postgresus-backend/internal/features/users"
+	"postgresus-backend/internal/util/logger"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ReconcileStorageWithMissingAndOrphanFiles_BothReported(t *testing.T) {
+	// setup data
+	user := users.GetTestUser()
+	storage := storages.CreateTestStorage(user.UserID)
+	notifier := notifiers.CreateTestNotifier(user.UserID)
+	database := databases.CreateTestDatabase(user.UserID, storage, notifier)
+
+	backupWithFile := &Backup{
+		DatabaseID: database.ID,
+		StorageID:  storage.ID,
+		Status:     BackupStatusCompleted,
+		CreatedAt:  time.Now().UTC().Add(-time.Hour),
+	}
+	assert.NoError(t, backupRepository.Save(backupWithFile))
+	assert.NoError(
+		t,
+		storage.SaveFile(logger.GetLogger(), backupWithFile.ID, bytes.NewReader([]byte("dump"))),
+	)
+
+	backupWithoutFile := &Backup{
+		DatabaseID: database.ID,
+		StorageID:  storage.ID,
+		Status:     BackupStatusCompleted,
+		CreatedAt:  time.Now().UTC().Add(-time.Hour),
+	}
+	assert.NoError(t, backupRepository.Save(backupWithoutFile))
+
+	orphanFileID := uuid.New()
+	assert.NoError(
+		t,
+		storage.SaveFile(logger.GetLogger(), orphanFileID, bytes.NewReader([]byte("orphan"))),
+	)
+
+	// act
+	report, err := GetBackupReconciliationService().ReconcileStorage(storage)
+	assert.NoError(t, err)
+
+	// assertions
+	missingBackupIDs := make([]uuid.UUID, 0)
+	for _, backup := range report.MissingBackups {
+		missingBackupIDs = append(missingBackupIDs, backup.ID)
+	}
+
+	orphanFileNames := make([]string, 0)
+	for _, file := range report.OrphanFiles {
+		orphanFileNames = append(orphanFileNames, file.Name)
+	}
+
+	assert.Contains(t, missingBackupIDs, backupWithoutFile.ID)
+	assert.NotContains(t, missingBackupIDs, backupWithFile.ID)
+	assert.Contains(t, orphanFileNames, orphanFileID.String())
+	assert.NotContains(t, orphanFileNames, backupWithFile.ID.String())
+
+	// cleanup
+	assert.NoError(t, storage.DeleteFile(orphanFileID))
+	assert.NoError(t, storage.DeleteFile(backupWithFile.ID))
+	assert.NoError(t, backupRepository.DeleteByID(backupWithFile.ID))
+	assert.NoError(t, backupRepository.DeleteByID(backupWithoutFile.ID))
+
+	databases.RemoveTestDatabase(database)
+	storages.RemoveTestStorage(storage.ID)
+	notifiers.RemoveTestNotifier(notifier)
+}
+
+func Test_ReconcileStorageWithFileOfBackupInAnotherStorage_FileNotReportedAsOrphan(t *testing.T) {
+	// setup data
+	user := users.GetTestUser()
+	storage := storages.CreateTestStorage(user.UserID)
+	anotherStorage := storages.CreateTestStorage(user.UserID)
+	notifier := notifiers.CreateTestNotifier(user.UserID)
+	database := databases.CreateTestDatabase(user.UserID, anotherStorage, notifier)
+
+	anotherStorageBackup := &Backup{
+		DatabaseID: database.ID,
+		StorageID:  anotherStorage.ID,
+		Status:     BackupStatusCompleted,
+		CreatedAt:  time.Now().UTC().Add(-time.Hour),
+	}
+	assert.NoError(t, backupRepository.Save(anotherStorageBackup))
+	assert.NoError(
+		t,
+		storage.SaveFile(
+			logger.GetLogger(),
+			anotherStorageBackup.ID,
+			bytes.NewReader([]byte("dump")),
+		),
+	)
+
+	// act
+	report, err := GetBackupReconciliationService().ReconcileStorage(storage)
+	assert.NoError(t, err)
+
+	// assertions
+	for _, file := range report.OrphanFiles {
+		assert.NotEqual(t, anotherStorageBackup.ID.String(), file.Name)
+	}
+
+	// cleanup
+	assert.NoError(t, storage.DeleteFile(anotherStorageBackup.ID))
+	assert.NoError(t, backupRepository.DeleteByID(anotherStorageBackup.ID))
+
+	databases.RemoveTestDatabase(database)
+	storages.RemoveTestStorage(anotherStorage.ID)
+	storages.RemoveTestStorage(storage.ID)
+	notifiers.RemoveTestNotifier(notifier)
+}
+
+func Test_ReconcileStorageWithFileOfFailedBackup_FileReportedAsOrphan(t *testing.T) {
+	// setup data
+	user := users.GetTestUser()
+	storage := storages.CreateTestStorage(user.UserID)
+	notifier := notifiers.CreateTestNotifier(user.UserID)
+	database := databases.CreateTestDatabase(user.UserID, storage, notifier)
+
+	failedBackup := &Backup{
+		DatabaseID: database.ID,
+		StorageID:  storage.ID,
+		Status:     BackupStatusFailed,
+		CreatedAt:  time.Now().UTC().Add(-time.Hour),
+	}
+	assert.NoError(t, backupRepository.Save(failedBackup))
+	assert.NoError(
+		t,
+		storage.SaveFile(logger.GetLogger(), failedBackup.ID, bytes.NewReader([]byte("partial"))),
+	)
+
+	// act
+	report, err := GetBackupReconciliationService().ReconcileStorage(storage)
+	assert.NoError(t, err)
+
+	// assertions
+	orphanFileNames := make([]string, 0)
+	for _, file := range report.OrphanFiles {
+		orphanFileNames = append(orphanFileNames, file.Name)
+	}
+
+	assert.Contains(t, orphanFileNames, failedBackup.ID.String())
+
+	// cleanup
+	assert.NoError(t, storage.DeleteFile(failedBackup.ID))
+	assert.NoError(t, backupRepository.DeleteByID(failedBackup.ID))
+
+	databases.RemoveTestDatabase(database)
+	storages.RemoveTestStorage(storage.ID)
+	notifiers.RemoveTestNotifier(notifier)
+}
@@ -173,6 +173,38 @@ func (r *BackupRepository) FindByDatabaseIdAndStatus(
 	return backups, nil
 }
 
+func (r *BackupRepository) FindByIDs(ids []uuid.UUID) ([]*Backup, error) {
+	var backups []*Backup
+
+	if len(ids) == 0 {
+		return backups, nil
+	}
+
+	if err := storage.
+		GetDb().
+		Where("id IN ?", ids).
+		Find(&backups).Error; err != nil {
+		return nil, err
+	}
+
+	return backups, nil
+}
+
+func (r *BackupRepository) GetTotalSizeMbByStorageID(storageID uuid.UUID) (float64, error) {
+	var totalSizeMb float64
+
+	if err := storage.
+		GetDb().
+		Model(&Backup{}).
+		Select("COALESCE(SUM(backup_size_mb), 0)").
+		Where("storage_id = ?", storageID).
+		Scan(&totalSizeMb).Error; err != nil {
+		return 0, err
+	}
+
+	return totalSizeMb, nil
+}
+
 func (r *BackupRepository) DeleteByID(id uuid.UUID) error {
 	return storage.GetDb().Delete(&Backup{}, "id = ?", id).Error
 }
@@ -187,7 +219,7 @@ func (r *BackupRepository) FindBackupsBeforeDate(
 		GetDb().
 		Preload("Database").
 		Preload("Storage").
-		Where("database_id = ? AND created_at < ?", databaseID, date).
+		Where("database_id = ? AND created_at < ? AND is_imported = ?", databaseID, date, false).
 		Order("created_at DESC").
 		Find(&backups).Error; err != nil {
 		return nil, err
@@ -195,3 +227,83 @@ func (r *BackupRepository) FindBackupsBeforeDate(
 
 	return backups, nil
 }
+
+// FindByDatabaseIDInPeriod returns backups created in [from, to), the
+// oldest first
+func (r *BackupRepository) FindByDatabaseIDInPeriod(
+	databaseID uuid.UUID,
+	from time.Time,
+	to time.Time,
+) ([]*Backup, error) {
+	var backups []*Backup
+
+	if err := storage.
+		GetDb().
+		Where("database_id = ? AND created_at >= ? AND created_at < ?", databaseID, from, to).
+		Order("created_at ASC").
+		Find(&backups).Error; err != nil {
+		return nil, err
+	}
+
+	return backups, nil
+}
+
+func (r *BackupRepository) FindLastCompletedBeforeDate(
+	databaseID uuid.UUID,
+	date time.Time,
+) (*Backup, error) {
+	var backup Backup
+
+	if err := storage.
+		GetDb().
+		Where(
+			"database_id = ? AND status = ? AND created_at < ?",
+			databaseID,
+			BackupStatusCompleted,
+			date,
+		).
+		Order("created_at DESC").
+		First(&backup).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &backup, nil
+}
+
+type StorageUsageSnapshotRepository struct{}
+
+func (r *StorageUsageSnapshotRepository) Save(snapshot *StorageUsageSnapshot) error {
+	if snapshot.ID == uuid.Nil {
+		snapshot.ID = uuid.New()
+	}
+
+	return storage.GetDb().Save(snapshot).Error
+}
+
+func (r *StorageUsageSnapshotRepository) FindByStorageIDAfterDate(
+	storageID uuid.UUID,
+	date time.Time,
+) ([]*StorageUsageSnapshot, error) {
+	var snapshots []*StorageUsageSnapshot
+
+	if err := storage.
+		GetDb().
+		Where("storage_id = ? AND created_at > ?", storageID, date).
+		Order("created_at ASC").
+		Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+func (r *StorageUsageSnapshotRepository) DeleteBeforeDate(date time.Time) error {
+	return storage.
+		GetDb().
+		Where("created_at < ?", date).
+		Delete(&StorageUsageSnapshot{}).Error
+}
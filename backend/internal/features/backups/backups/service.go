@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"postgresus-backend/internal/features/audit_logs"
 	backups_config "postgresus-backend/internal/features/backups/config"
 	"postgresus-backend/internal/features/databases"
 	"postgresus-backend/internal/features/notifiers"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
 	"postgresus-backend/internal/features/storages"
+	user_enums "postgresus-backend/internal/features/users/enums"
 	users_models "postgresus-backend/internal/features/users/models"
 	"slices"
 	"time"
@@ -23,6 +26,8 @@ type BackupService struct {
 	notifierService     *notifiers.NotifierService
 	notificationSender  NotificationSender
 	backupConfigService *backups_config.BackupConfigService
+	storageUsageService *StorageUsageService
+	auditLogService     *audit_logs.AuditLogService
 
 	createBackupUseCase CreateBackupUsecase
 
@@ -35,8 +40,13 @@ func (s *BackupService) AddBackupRemoveListener(listener BackupRemoveListener) {
 	s.backupRemoveListeners = append(s.backupRemoveListeners, listener)
 }
 
-func (s *BackupService) OnBeforeBackupsStorageChange(databaseID uuid.UUID) error {
-	err := s.deleteDbBackups(databaseID)
+// OnBeforeBackupsStorageChange removes backups of the database, except
+// backups already moved to the new storage
+func (s *BackupService) OnBeforeBackupsStorageChange(
+	databaseID uuid.UUID,
+	newStorageID *uuid.UUID,
+) error {
+	err := s.deleteDbBackups(databaseID, newStorageID)
 	if err != nil {
 		return err
 	}
@@ -45,7 +55,7 @@ func (s *BackupService) OnBeforeBackupsStorageChange(databaseID uuid.UUID) error
 }
 
 func (s *BackupService) OnBeforeDatabaseRemove(databaseID uuid.UUID) error {
-	err := s.deleteDbBackups(databaseID)
+	err := s.deleteDbBackups(databaseID, nil)
 	if err != nil {
 		return err
 	}
@@ -57,17 +67,25 @@ func (s *BackupService) MakeBackupWithAuth(
 	user *users_models.User,
 	databaseID uuid.UUID,
 ) error {
-	database, err := s.databaseService.GetDatabaseByID(databaseID)
+	database, err := s.databaseService.GetDatabaseWithRole(
+		user,
+		databaseID,
+		user_enums.UserRoleOperator,
+	)
 	if err != nil {
 		return err
 	}
 
-	if database.UserID != user.ID {
-		return errors.New("user does not have access to this database")
-	}
-
 	go s.MakeBackup(databaseID, true)
 
+	s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+		Action:     audit_logs.AuditLogActionBackup,
+		TargetType: audit_logs.AuditLogTargetTypeDatabase,
+		TargetID:   database.ID,
+		TargetName: database.Name,
+		Message:    fmt.Sprintf("Started backup of database \"%s\"", database.Name),
+	})
+
 	return nil
 }
 
@@ -75,15 +93,11 @@ func (s *BackupService) GetBackups(
 	user *users_models.User,
 	databaseID uuid.UUID,
 ) ([]*Backup, error) {
-	database, err := s.databaseService.GetDatabaseByID(databaseID)
+	_, err := s.databaseService.GetDatabase(user, databaseID)
 	if err != nil {
 		return nil, err
 	}
 
-	if database.UserID != user.ID {
-		return nil, errors.New("user does not have access to this database")
-	}
-
 	backups, err := s.backupRepository.FindByDatabaseID(databaseID)
 	if err != nil {
 		return nil, err
@@ -101,15 +115,36 @@ func (s *BackupService) DeleteBackup(
 		return err
 	}
 
-	if backup.Database.UserID != user.ID {
-		return errors.New("user does not have access to this backup")
+	_, err = s.databaseService.GetDatabaseWithRole(
+		user,
+		backup.DatabaseID,
+		user_enums.UserRoleAdmin,
+	)
+	if err != nil {
+		return err
 	}
 
 	if backup.Status == BackupStatusInProgress {
 		return errors.New("backup is in progress")
 	}
 
-	return s.deleteBackup(backup)
+	if err := s.deleteBackup(backup); err != nil {
+		return err
+	}
+
+	s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+		Action:     audit_logs.AuditLogActionDelete,
+		TargetType: audit_logs.AuditLogTargetTypeBackup,
+		TargetID:   backup.ID,
+		TargetName: backup.Database.Name,
+		Message: fmt.Sprintf(
+			"Deleted backup of database \"%s\" made at %s",
+			backup.Database.Name,
+			backup.CreatedAt.Format(time.RFC3339),
+		),
+	})
+
+	return nil
 }
 
 func (s *BackupService) MakeBackup(databaseID uuid.UUID, isLastTry bool) {
@@ -171,6 +206,21 @@ func (s *BackupService) MakeBackup(databaseID uuid.UUID, isLastTry bool) {
 		return
 	}
 
+	expectedBackupSizeMb, err := s.getExpectedBackupSizeMb(databaseID)
+	if err != nil {
+		s.logger.Error("Failed to get expected backup size", "error", err)
+	}
+
+	quotaWarning, quotaErr := s.storageUsageService.CheckQuota(storage, expectedBackupSizeMb)
+	if quotaWarning != nil {
+		s.SendBackupNotification(
+			backupConfig,
+			backup,
+			backups_config.NotificationStorageQuotaWarning,
+			quotaWarning,
+		)
+	}
+
 	start := time.Now().UTC()
 
 	backupProgressListener := func(
@@ -184,13 +234,16 @@ func (s *BackupService) MakeBackup(databaseID uuid.UUID, isLastTry bool) {
 		}
 	}
 
-	err = s.createBackupUseCase.Execute(
-		backup.ID,
-		backupConfig,
-		database,
-		storage,
-		backupProgressListener,
-	)
+	err = quotaErr
+	if err == nil {
+		err = s.createBackupUseCase.Execute(
+			backup.ID,
+			backupConfig,
+			database,
+			storage,
+			backupProgressListener,
+		)
+	}
 	if err != nil {
 		errMsg := err.Error()
 		backup.FailMessage = &errMsg
@@ -225,6 +278,19 @@ func (s *BackupService) MakeBackup(databaseID uuid.UUID, isLastTry bool) {
 	backup.Status = BackupStatusCompleted
 	backup.BackupDurationMs = time.Since(start).Milliseconds()
 
+	previousBackups, err := s.backupRepository.FindByDatabaseIDWithLimit(databaseID, 30)
+	if err != nil {
+		s.logger.Error("Failed to find previous backups", "error", err)
+	} else {
+		backup.AnomalyMessage = detectBackupAnomaly(
+			backup,
+			previousBackups,
+			backupConfig.SizeAnomalyThresholdPercent,
+			backupConfig.DurationAnomalyThresholdPercent,
+		)
+		backup.IsAnomaly = backup.AnomalyMessage != nil
+	}
+
 	if err := s.backupRepository.Save(backup); err != nil {
 		s.logger.Error("Failed to save backup", "error", err)
 		return
@@ -242,6 +308,18 @@ func (s *BackupService) MakeBackup(databaseID uuid.UUID, isLastTry bool) {
 		)
 	}
 
+	if backupConfig.HeartbeatURL != "" {
+		if err := pingHeartbeat(backupConfig.HeartbeatURL); err != nil {
+			s.logger.Error(
+				"Failed to ping heartbeat URL",
+				"databaseId",
+				databaseID,
+				"error",
+				err,
+			)
+		}
+	}
+
 	if backup.Status != BackupStatusCompleted && !isLastTry {
 		return
 	}
@@ -252,6 +330,15 @@ func (s *BackupService) MakeBackup(databaseID uuid.UUID, isLastTry bool) {
 		backups_config.NotificationBackupSuccess,
 		nil,
 	)
+
+	if backup.IsAnomaly {
+		s.SendBackupNotification(
+			backupConfig,
+			backup,
+			backups_config.NotificationBackupAnomaly,
+			backup.AnomalyMessage,
+		)
+	}
 }
 
 func (s *BackupService) SendBackupNotification(
@@ -265,60 +352,129 @@ func (s *BackupService) SendBackupNotification(
 		return
 	}
 
-	for _, notifier := range database.Notifiers {
-		if !slices.Contains(
-			backupConfig.SendNotificationsOn,
-			notificationType,
-		) {
-			continue
-		}
-
-		title := ""
-		switch notificationType {
-		case backups_config.NotificationBackupFailed:
-			title = fmt.Sprintf("❌ Backup failed for database \"%s\"", database.Name)
-		case backups_config.NotificationBackupSuccess:
-			title = fmt.Sprintf("✅ Backup completed for database \"%s\"", database.Name)
-		}
+	event := newBackupEvent(database, backup, notificationType, errorMessage)
 
-		message := ""
-		if errorMessage != nil {
-			message = *errorMessage
-		} else {
-			// Format size conditionally
-			var sizeStr string
-			if backup.BackupSizeMb < 1024 {
-				sizeStr = fmt.Sprintf("%.2f MB", backup.BackupSizeMb)
-			} else {
-				sizeGB := backup.BackupSizeMb / 1024
-				sizeStr = fmt.Sprintf("%.2f GB", sizeGB)
+	if !slices.Contains(backupConfig.SendNotificationsOn, notificationType) {
+		// incidents opened by failed backups are resolved by the
+		// successful one even if the user is not subscribed to it
+		if event.GetIncidentAction() == notifier_events.IncidentActionResolve {
+			for _, notifier := range database.Notifiers {
+				s.notificationSender.ResolveIncident(&notifier, event)
 			}
-
-			// Format duration as "0m 0s 0ms"
-			totalMs := backup.BackupDurationMs
-			minutes := totalMs / (1000 * 60)
-			seconds := (totalMs % (1000 * 60)) / 1000
-			durationStr := fmt.Sprintf("%dm %ds", minutes, seconds)
-
-			message = fmt.Sprintf(
-				"Backup completed successfully in %s.\nCompressed backup size: %s",
-				durationStr,
-				sizeStr,
-			)
 		}
 
-		s.notificationSender.SendNotification(
-			&notifier,
-			title,
-			message,
-		)
+		return
+	}
+
+	for _, notifier := range database.Notifiers {
+		s.notificationSender.SendNotification(&notifier, event)
 	}
 }
 
+// newBackupEvent describes the backup for notifiers. The message is
+// the error of failed backup or the warning of anomaly and quota
+func newBackupEvent(
+	database *databases.Database,
+	backup *Backup,
+	notificationType backups_config.BackupNotificationType,
+	message *string,
+) *notifier_events.NotificationEvent {
+	event := &notifier_events.NotificationEvent{
+		DatabaseID:   &database.ID,
+		DatabaseName: database.Name,
+		BackupID:     &backup.ID,
+		CreatedAt:    time.Now().UTC(),
+	}
+
+	switch notificationType {
+	case backups_config.NotificationBackupFailed:
+		event.Type = notifier_events.EventTypeBackupFailed
+		event.Severity = notifier_events.SeverityError
+		event.Title = fmt.Sprintf("❌ Backup failed for database \"%s\"", database.Name)
+		event.Error = message
+		event.Log = message
+	case backups_config.NotificationBackupSuccess:
+		event.Type = notifier_events.EventTypeBackupSuccess
+		event.Severity = notifier_events.SeveritySuccess
+		event.Title = fmt.Sprintf("✅ Backup completed for database \"%s\"", database.Name)
+		event.Message = "Backup completed successfully"
+		event.SizeMb = &backup.BackupSizeMb
+		event.DurationMs = &backup.BackupDurationMs
+	case backups_config.NotificationBackupAnomaly:
+		event.Type = notifier_events.EventTypeBackupAnomaly
+		event.Severity = notifier_events.SeverityWarning
+		event.Title = fmt.Sprintf("⚠️ Backup anomaly for database \"%s\"", database.Name)
+		event.SizeMb = &backup.BackupSizeMb
+		event.DurationMs = &backup.BackupDurationMs
+	case backups_config.NotificationBackupOverdue:
+		event.Type = notifier_events.EventTypeBackupOverdue
+		event.Severity = notifier_events.SeverityError
+		event.Title = fmt.Sprintf("⏰ Backup overdue for database \"%s\"", database.Name)
+	case backups_config.NotificationStorageQuotaWarning:
+		event.Type = notifier_events.EventTypeStorageQuotaWarning
+		event.Severity = notifier_events.SeverityWarning
+		event.Title = fmt.Sprintf("⚠️ Storage quota warning for database \"%s\"", database.Name)
+	}
+
+	if message != nil && event.Error == nil {
+		event.Message = *message
+	}
+
+	return event
+}
+
 func (s *BackupService) GetBackup(backupID uuid.UUID) (*Backup, error) {
 	return s.backupRepository.FindByID(backupID)
 }
 
+// GetBackupsForPeriod returns backups of the database created in the
+// period, the oldest first. Access is not checked, it is used for reports
+func (s *BackupService) GetBackupsForPeriod(
+	databaseID uuid.UUID,
+	from time.Time,
+	to time.Time,
+) ([]*Backup, error) {
+	return s.backupRepository.FindByDatabaseIDInPeriod(databaseID, from, to)
+}
+
+func (s *BackupService) GetLastCompletedBackupBefore(
+	databaseID uuid.UUID,
+	date time.Time,
+) (*Backup, error) {
+	return s.backupRepository.FindLastCompletedBeforeDate(databaseID, date)
+}
+
+func (s *BackupService) GetDatabaseBackupsByStorage(
+	databaseID uuid.UUID,
+	storageID uuid.UUID,
+) ([]*Backup, error) {
+	backups, err := s.backupRepository.FindByDatabaseID(databaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	storageBackups := make([]*Backup, 0)
+	for _, backup := range backups {
+		if backup.StorageID == storageID {
+			storageBackups = append(storageBackups, backup)
+		}
+	}
+
+	return storageBackups, nil
+}
+
+func (s *BackupService) ChangeBackupStorage(backupID uuid.UUID, storageID uuid.UUID) error {
+	backup, err := s.backupRepository.FindByID(backupID)
+	if err != nil {
+		return err
+	}
+
+	backup.StorageID = storageID
+	backup.Storage = nil
+
+	return s.backupRepository.Save(backup)
+}
+
 func (s *BackupService) GetBackupFile(
 	user *users_models.User,
 	backupID uuid.UUID,
@@ -328,8 +484,13 @@ func (s *BackupService) GetBackupFile(
 		return nil, err
 	}
 
-	if backup.Database.UserID != user.ID {
-		return nil, errors.New("user does not have access to this backup")
+	_, err = s.databaseService.GetDatabaseWithRole(
+		user,
+		backup.DatabaseID,
+		user_enums.UserRoleOperator,
+	)
+	if err != nil {
+		return nil, err
 	}
 
 	storage, err := s.storageService.GetStorageByID(backup.StorageID)
@@ -340,6 +501,24 @@ func (s *BackupService) GetBackupFile(
 	return storage.GetFile(backup.ID)
 }
 
+// getExpectedBackupSizeMb returns size of the last completed
+// backup, the next backup is expected to be about the same size
+func (s *BackupService) getExpectedBackupSizeMb(databaseID uuid.UUID) (float64, error) {
+	completedBackups, err := s.backupRepository.FindByDatabaseIdAndStatus(
+		databaseID,
+		BackupStatusCompleted,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(completedBackups) == 0 {
+		return 0, nil
+	}
+
+	return completedBackups[0].BackupSizeMb, nil
+}
+
 func (s *BackupService) deleteBackup(backup *Backup) error {
 	for _, listener := range s.backupRemoveListeners {
 		if err := listener.OnBeforeBackupRemove(backup); err != nil {
@@ -360,7 +539,10 @@ func (s *BackupService) deleteBackup(backup *Backup) error {
 	return s.backupRepository.DeleteByID(backup.ID)
 }
 
-func (s *BackupService) deleteDbBackups(databaseID uuid.UUID) error {
+func (s *BackupService) deleteDbBackups(
+	databaseID uuid.UUID,
+	keptStorageID *uuid.UUID,
+) error {
 	dbBackupsInProgress, err := s.backupRepository.FindByDatabaseIdAndStatus(
 		databaseID,
 		BackupStatusInProgress,
@@ -381,6 +563,10 @@ func (s *BackupService) deleteDbBackups(databaseID uuid.UUID) error {
 	}
 
 	for _, dbBackup := range dbBackups {
+		if keptStorageID != nil && dbBackup.StorageID == *keptStorageID {
+			continue
+		}
+
 		err := s.deleteBackup(dbBackup)
 		if err != nil {
 			return err
@@ -2,9 +2,11 @@ package backups
 
 import (
 	"errors"
+	"postgresus-backend/internal/features/audit_logs"
 	backups_config "postgresus-backend/internal/features/backups/config"
 	"postgresus-backend/internal/features/databases"
 	"postgresus-backend/internal/features/notifiers"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
 	"postgresus-backend/internal/features/storages"
 	"postgresus-backend/internal/features/users"
 	"postgresus-backend/internal/util/logger"
@@ -36,6 +38,8 @@ func Test_BackupExecuted_NotificationSent(t *testing.T) {
 			notifiers.GetNotifierService(),
 			mockNotificationSender,
 			backups_config.GetBackupConfigService(),
+			GetStorageUsageService(),
+			audit_logs.GetAuditLogService(),
 			&CreateFailedBackupUsecase{},
 			logger.GetLogger(),
 			[]BackupRemoveListener{},
@@ -44,11 +48,11 @@ func Test_BackupExecuted_NotificationSent(t *testing.T) {
 		// Set up expectations
 		mockNotificationSender.On("SendNotification",
 			mock.Anything,
-			mock.MatchedBy(func(title string) bool {
-				return strings.Contains(title, "❌ Backup failed")
-			}),
-			mock.MatchedBy(func(message string) bool {
-				return strings.Contains(message, "backup failed")
+			mock.MatchedBy(func(event *notifier_events.NotificationEvent) bool {
+				return event.Type == notifier_events.EventTypeBackupFailed &&
+					strings.Contains(event.Title, "❌ Backup failed") &&
+					event.Error != nil &&
+					strings.Contains(*event.Error, "backup failed")
 			}),
 		).Once()
 
@@ -64,11 +68,10 @@ func Test_BackupExecuted_NotificationSent(t *testing.T) {
 		// Set up expectations
 		mockNotificationSender.On("SendNotification",
 			mock.Anything,
-			mock.MatchedBy(func(title string) bool {
-				return strings.Contains(title, "✅ Backup completed")
-			}),
-			mock.MatchedBy(func(message string) bool {
-				return strings.Contains(message, "Backup completed successfully")
+			mock.MatchedBy(func(event *notifier_events.NotificationEvent) bool {
+				return event.Type == notifier_events.EventTypeBackupSuccess &&
+					strings.Contains(event.Title, "✅ Backup completed") &&
+					strings.Contains(event.Message, "Backup completed successfully")
 			}),
 		).Once()
 
@@ -79,6 +82,8 @@ func Test_BackupExecuted_NotificationSent(t *testing.T) {
 			notifiers.GetNotifierService(),
 			mockNotificationSender,
 			backups_config.GetBackupConfigService(),
+			GetStorageUsageService(),
+			audit_logs.GetAuditLogService(),
 			&CreateSuccessBackupUsecase{},
 			logger.GetLogger(),
 			[]BackupRemoveListener{},
@@ -99,6 +104,8 @@ func Test_BackupExecuted_NotificationSent(t *testing.T) {
 			notifiers.GetNotifierService(),
 			mockNotificationSender,
 			backups_config.GetBackupConfigService(),
+			GetStorageUsageService(),
+			audit_logs.GetAuditLogService(),
 			&CreateSuccessBackupUsecase{},
 			logger.GetLogger(),
 			[]BackupRemoveListener{},
@@ -106,17 +113,14 @@ func Test_BackupExecuted_NotificationSent(t *testing.T) {
 
 		// capture arguments
 		var capturedNotifier *notifiers.Notifier
-		var capturedTitle string
-		var capturedMessage string
+		var capturedEvent *notifier_events.NotificationEvent
 
 		mockNotificationSender.On("SendNotification",
 			mock.Anything,
-			mock.AnythingOfType("string"),
-			mock.AnythingOfType("string"),
+			mock.Anything,
 		).Run(func(args mock.Arguments) {
 			capturedNotifier = args.Get(0).(*notifiers.Notifier)
-			capturedTitle = args.Get(1).(string)
-			capturedMessage = args.Get(2).(string)
+			capturedEvent = args.Get(1).(*notifier_events.NotificationEvent)
 		}).Once()
 
 		backupService.MakeBackup(database.ID, true)
@@ -125,14 +129,70 @@ func Test_BackupExecuted_NotificationSent(t *testing.T) {
 		mockNotificationSender.AssertExpectations(t)
 
 		// Additional detailed assertions
-		assert.Contains(t, capturedTitle, "✅ Backup completed")
-		assert.Contains(t, capturedTitle, database.Name)
-		assert.Contains(t, capturedMessage, "Backup completed successfully")
-		assert.Contains(t, capturedMessage, "10.00 MB")
+		assert.Contains(t, capturedEvent.Title, "✅ Backup completed")
+		assert.Contains(t, capturedEvent.Title, database.Name)
+		assert.Equal(t, notifier_events.SeveritySuccess, capturedEvent.Severity)
+		assert.Equal(t, database.ID, *capturedEvent.DatabaseID)
+		assert.Contains(t, capturedEvent.GetPlainText(), "Backup completed successfully")
+		assert.Contains(t, capturedEvent.GetPlainText(), "10.00 MB")
 		assert.Equal(t, notifier.ID, capturedNotifier.ID)
 	})
 }
 
+func Test_SendBackupNotification_WhenNotSubscribedToSuccess_IncidentResolved(t *testing.T) {
+	// setup data
+	user := users.GetTestUser()
+	storage := storages.CreateTestStorage(user.UserID)
+	notifier := notifiers.CreateTestNotifier(user.UserID)
+	database := databases.CreateTestDatabase(user.UserID, storage, notifier)
+
+	backupConfig := &backups_config.BackupConfig{
+		DatabaseID: database.ID,
+		SendNotificationsOn: []backups_config.BackupNotificationType{
+			backups_config.NotificationBackupFailed,
+		},
+	}
+
+	mockNotificationSender := &MockNotificationSender{}
+	mockNotificationSender.On("ResolveIncident",
+		mock.Anything,
+		mock.MatchedBy(func(event *notifier_events.NotificationEvent) bool {
+			return event.Type == notifier_events.EventTypeBackupSuccess
+		}),
+	).Once()
+
+	backupService := &BackupService{
+		databases.GetDatabaseService(),
+		storages.GetStorageService(),
+		backupRepository,
+		notifiers.GetNotifierService(),
+		mockNotificationSender,
+		backups_config.GetBackupConfigService(),
+		GetStorageUsageService(),
+		audit_logs.GetAuditLogService(),
+		&CreateSuccessBackupUsecase{},
+		logger.GetLogger(),
+		[]BackupRemoveListener{},
+	}
+
+	// act
+	backupService.SendBackupNotification(
+		backupConfig,
+		&Backup{ID: uuid.New()},
+		backups_config.NotificationBackupSuccess,
+		nil,
+	)
+
+	// assertions
+	mockNotificationSender.AssertExpectations(t)
+	mockNotificationSender.AssertNotCalled(t, "SendNotification", mock.Anything, mock.Anything)
+
+	// cleanup
+	databases.RemoveTestDatabase(database)
+	storages.RemoveTestStorage(storage.ID)
+	notifiers.RemoveTestNotifier(notifier)
+}
+
 type CreateFailedBackupUsecase struct {
 }
 
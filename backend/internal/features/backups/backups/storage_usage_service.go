@@ -0,0 +1,251 @@
+package backups
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"postgresus-backend/internal/features/storages"
+	users_models "postgresus-backend/internal/features/users/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const storageForecastPeriod = 30 * 24 * time.Hour
+const storageUsageSnapshotsStorePeriod = 365 * 24 * time.Hour
+
+// StorageUsageService tracks how much space backups take in storages,
+// checks storage quotas and forecasts when storage becomes full
+// using daily usage snapshots
+type StorageUsageService struct {
+	backupRepository               *BackupRepository
+	storageUsageSnapshotRepository *StorageUsageSnapshotRepository
+	storageService                 *storages.StorageService
+	logger                         *slog.Logger
+}
+
+func (s *StorageUsageService) GetUsageWithAuth(
+	user *users_models.User,
+	storageID uuid.UUID,
+) (*StorageUsage, error) {
+	storage, err := s.storageService.GetStorage(user, storageID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetUsage(storage)
+}
+
+func (s *StorageUsageService) GetForecastWithAuth(
+	user *users_models.User,
+	storageID uuid.UUID,
+) (*StorageForecast, error) {
+	storage, err := s.storageService.GetStorage(user, storageID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := s.GetUsage(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	snapshots, err := s.storageUsageSnapshotRepository.FindByStorageIDAfterDate(
+		storage.ID,
+		now.Add(-storageForecastPeriod),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots = append(snapshots, &StorageUsageSnapshot{
+		StorageID: storage.ID,
+		UsedMb:    usage.UsedMb,
+		CreatedAt: now,
+	})
+
+	forecast := &StorageForecast{
+		StorageID:      storage.ID,
+		UsedMb:         usage.UsedMb,
+		QuotaMb:        storage.QuotaMb,
+		DailyGrowthMb:  calculateDailyGrowthMb(snapshots),
+		SnapshotsCount: len(snapshots) - 1,
+	}
+
+	if storage.QuotaMb == nil {
+		return forecast, nil
+	}
+
+	if usage.UsedMb >= *storage.QuotaMb {
+		daysUntilFull := float64(0)
+		forecast.DaysUntilFull = &daysUntilFull
+		forecast.FullAt = &now
+
+		return forecast, nil
+	}
+
+	if forecast.DailyGrowthMb <= 0 {
+		return forecast, nil
+	}
+
+	daysUntilFull := (*storage.QuotaMb - usage.UsedMb) / forecast.DailyGrowthMb
+	fullAt := now.Add(time.Duration(daysUntilFull * float64(24*time.Hour)))
+
+	forecast.DaysUntilFull = &daysUntilFull
+	forecast.FullAt = &fullAt
+
+	return forecast, nil
+}
+
+func (s *StorageUsageService) GetUsage(storage *storages.Storage) (*StorageUsage, error) {
+	backupsSizeMb, err := s.backupRepository.GetTotalSizeMbByStorageID(storage.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &StorageUsage{
+		StorageID:     storage.ID,
+		BackupsSizeMb: backupsSizeMb,
+		UsedMb:        backupsSizeMb,
+		QuotaMb:       storage.QuotaMb,
+	}
+
+	storageBackups, err := s.backupRepository.FindByStorageID(storage.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	storageBackupIDs := make(map[uuid.UUID]bool, len(storageBackups))
+	for _, backup := range storageBackups {
+		storageBackupIDs[backup.ID] = true
+	}
+
+	files, err := storage.ListFiles()
+	if err != nil {
+		actualSizeError := err.Error()
+		usage.ActualSizeError = &actualSizeError
+	} else {
+		// storages may share the same bucket or folder, so only files
+		// of backups of this storage are counted
+		var actualSizeBytes int64
+		for _, file := range files {
+			if backupID, isBackupFile := file.GetBackupID(); isBackupFile &&
+				storageBackupIDs[backupID] {
+				actualSizeBytes += file.SizeBytes
+			}
+		}
+
+		actualSizeMb := float64(actualSizeBytes) / (1024 * 1024)
+		usage.ActualSizeMb = &actualSizeMb
+		usage.UsedMb = max(usage.UsedMb, actualSizeMb)
+	}
+
+	if storage.QuotaMb != nil {
+		quotaUsedPercent := usage.UsedMb / *storage.QuotaMb * 100
+		usage.QuotaUsedPercent = &quotaUsedPercent
+	}
+
+	return usage, nil
+}
+
+// CheckQuota checks the storage has enough space for the next backup.
+// Returns warning message if the quota will be exceeded and storage only
+// warns about it, returns error if storage blocks such backups. Used
+// space is the size of backups, files are not listed before each backup
+func (s *StorageUsageService) CheckQuota(
+	storage *storages.Storage,
+	expectedBackupSizeMb float64,
+) (*string, error) {
+	if storage.QuotaMb == nil {
+		return nil, nil
+	}
+
+	usedMb, err := s.backupRepository.GetTotalSizeMbByStorageID(storage.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if usedMb+expectedBackupSizeMb <= *storage.QuotaMb {
+		return nil, nil
+	}
+
+	message := fmt.Sprintf(
+		"Storage \"%s\" quota will be exceeded: used %.2f MB, expected backup size %.2f MB, quota %.2f MB",
+		storage.Name,
+		usedMb,
+		expectedBackupSizeMb,
+		*storage.QuotaMb,
+	)
+
+	if storage.QuotaExceedAction == storages.StorageQuotaExceedActionBlock {
+		return nil, errors.New(message)
+	}
+
+	return &message, nil
+}
+
+func (s *StorageUsageService) SaveUsageSnapshots() {
+	allStorages, err := s.storageService.GetAllStorages()
+	if err != nil {
+		s.logger.Error("Failed to get storages for usage snapshots", "error", err)
+		return
+	}
+
+	for _, storage := range allStorages {
+		usage, err := s.GetUsage(storage)
+		if err != nil {
+			s.logger.Error("Failed to get storage usage", "storageId", storage.ID, "error", err)
+			continue
+		}
+
+		if err := s.storageUsageSnapshotRepository.Save(&StorageUsageSnapshot{
+			StorageID: storage.ID,
+			UsedMb:    usage.UsedMb,
+			CreatedAt: time.Now().UTC(),
+		}); err != nil {
+			s.logger.Error("Failed to save storage usage snapshot", "error", err)
+		}
+	}
+
+	if err := s.storageUsageSnapshotRepository.DeleteBeforeDate(
+		time.Now().UTC().Add(-storageUsageSnapshotsStorePeriod),
+	); err != nil {
+		s.logger.Error("Failed to delete old storage usage snapshots", "error", err)
+	}
+}
+
+// calculateDailyGrowthMb returns slope of linear regression of used
+// space over time in MB per day
+func calculateDailyGrowthMb(snapshots []*StorageUsageSnapshot) float64 {
+	if len(snapshots) < 2 {
+		return 0
+	}
+
+	startedAt := snapshots[0].CreatedAt
+	count := float64(len(snapshots))
+
+	var sumDays, sumUsedMb float64
+	for _, snapshot := range snapshots {
+		sumDays += snapshot.CreatedAt.Sub(startedAt).Hours() / 24
+		sumUsedMb += snapshot.UsedMb
+	}
+
+	meanDays := sumDays / count
+	meanUsedMb := sumUsedMb / count
+
+	var covariance, variance float64
+	for _, snapshot := range snapshots {
+		days := snapshot.CreatedAt.Sub(startedAt).Hours() / 24
+
+		covariance += (days - meanDays) * (snapshot.UsedMb - meanUsedMb)
+		variance += (days - meanDays) * (days - meanDays)
+	}
+
+	if variance == 0 {
+		return 0
+	}
+
+	return covariance / variance
+}
@@ -0,0 +1,150 @@
+package backups
+
+import (
+	"bytes"
+	"postgresus-backend/internal/features/databases"
+	"postgresus-backend/internal/features/notifiers"
+	"postgresus-backend/internal/features/storages"
+	"postgresus-backend/internal/features/users"
+	"postgresus-backend/internal/util/logger"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CalculateDailyGrowthMbForLinearUsage_SlopeReturned(t *testing.T) {
+	startedAt := time.Now().UTC().Add(-10 * 24 * time.Hour)
+
+	snapshots := make([]*StorageUsageSnapshot, 0)
+	for day := 0; day <= 10; day++ {
+		snapshots = append(snapshots, &StorageUsageSnapshot{
+			UsedMb:    1000 + float64(day)*50,
+			CreatedAt: startedAt.Add(time.Duration(day) * 24 * time.Hour),
+		})
+	}
+
+	assert.InDelta(t, 50, calculateDailyGrowthMb(snapshots), 0.001)
+}
+
+func Test_CalculateDailyGrowthMbForSingleSnapshot_ZeroReturned(t *testing.T) {
+	snapshots := []*StorageUsageSnapshot{
+		{UsedMb: 1000, CreatedAt: time.Now().UTC()},
+	}
+
+	assert.Equal(t, float64(0), calculateDailyGrowthMb(snapshots))
+}
+
+func Test_CheckQuotaWhenBackupsExceedQuota_UsageCalculatedBySizeOfBackups(t *testing.T) {
+	// setup data
+	user := users.GetTestUser()
+	storage := storages.CreateTestStorage(user.UserID)
+	notifier := notifiers.CreateTestNotifier(user.UserID)
+	database := databases.CreateTestDatabase(user.UserID, storage, notifier)
+
+	backup := &Backup{
+		DatabaseID:   database.ID,
+		StorageID:    storage.ID,
+		Status:       BackupStatusCompleted,
+		BackupSizeMb: 90,
+		CreatedAt:    time.Now().UTC().Add(-time.Hour),
+	}
+	require.NoError(t, backupRepository.Save(backup))
+
+	// files which are not backups are not listed by the check
+	foreignFileID := uuid.New()
+	require.NoError(
+		t,
+		storage.SaveFile(logger.GetLogger(), foreignFileID, bytes.NewReader([]byte("file"))),
+	)
+
+	quotaMb := float64(100)
+	storage.QuotaMb = &quotaMb
+
+	// act
+	fittingWarning, fittingErr := GetStorageUsageService().CheckQuota(storage, 5)
+
+	storage.QuotaExceedAction = storages.StorageQuotaExceedActionWarn
+	exceedingWarning, exceedingWarnErr := GetStorageUsageService().CheckQuota(storage, 20)
+
+	storage.QuotaExceedAction = storages.StorageQuotaExceedActionBlock
+	_, exceedingBlockErr := GetStorageUsageService().CheckQuota(storage, 20)
+
+	// assertions
+	assert.NoError(t, fittingErr)
+	assert.Nil(t, fittingWarning)
+
+	assert.NoError(t, exceedingWarnErr)
+	require.NotNil(t, exceedingWarning)
+	assert.Contains(t, *exceedingWarning, "used 90.00 MB")
+
+	assert.ErrorContains(t, exceedingBlockErr, "quota will be exceeded")
+
+	// cleanup
+	assert.NoError(t, storage.DeleteFile(foreignFileID))
+	assert.NoError(t, backupRepository.DeleteByID(backup.ID))
+
+	databases.RemoveTestDatabase(database)
+	storages.RemoveTestStorage(storage.ID)
+	notifiers.RemoveTestNotifier(notifier)
+}
+
+func Test_GetUsageOfStorageWithSharedFolder_OnlyFilesOfStorageBackupsCounted(t *testing.T) {
+	// setup data
+	user := users.GetTestUser()
+	storage := storages.CreateTestStorage(user.UserID)
+	anotherStorage := storages.CreateTestStorage(user.UserID)
+	notifier := notifiers.CreateTestNotifier(user.UserID)
+	database := databases.CreateTestDatabase(user.UserID, storage, notifier)
+
+	backup := &Backup{
+		DatabaseID: database.ID,
+		StorageID:  storage.ID,
+		Status:     BackupStatusCompleted,
+		CreatedAt:  time.Now().UTC().Add(-time.Hour),
+	}
+	require.NoError(t, backupRepository.Save(backup))
+	require.NoError(
+		t,
+		storage.SaveFile(logger.GetLogger(), backup.ID, bytes.NewReader(make([]byte, 1024*1024))),
+	)
+
+	// local storages share the folder, so the file of another storage
+	// is listed by both of them
+	anotherBackup := &Backup{
+		DatabaseID: database.ID,
+		StorageID:  anotherStorage.ID,
+		Status:     BackupStatusCompleted,
+		CreatedAt:  time.Now().UTC().Add(-time.Hour),
+	}
+	require.NoError(t, backupRepository.Save(anotherBackup))
+	require.NoError(
+		t,
+		anotherStorage.SaveFile(
+			logger.GetLogger(),
+			anotherBackup.ID,
+			bytes.NewReader(make([]byte, 2*1024*1024)),
+		),
+	)
+
+	// act
+	usage, err := GetStorageUsageService().GetUsage(storage)
+
+	// assertions
+	require.NoError(t, err)
+	require.NotNil(t, usage.ActualSizeMb)
+	assert.InDelta(t, 1, *usage.ActualSizeMb, 0.001)
+
+	// cleanup
+	assert.NoError(t, storage.DeleteFile(backup.ID))
+	assert.NoError(t, anotherStorage.DeleteFile(anotherBackup.ID))
+	assert.NoError(t, backupRepository.DeleteByID(backup.ID))
+	assert.NoError(t, backupRepository.DeleteByID(anotherBackup.ID))
+
+	databases.RemoveTestDatabase(database)
+	storages.RemoveTestStorage(storage.ID)
+	storages.RemoveTestStorage(anotherStorage.ID)
+	notifiers.RemoveTestNotifier(notifier)
+}
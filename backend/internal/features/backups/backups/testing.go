@@ -0,0 +1,30 @@
+package backups
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateTestBackup creates a completed backup record without a file,
+// the file should be saved to the storage by the test if needed
+func CreateTestBackup(databaseID uuid.UUID, storageID uuid.UUID) *Backup {
+	backup := &Backup{
+		DatabaseID: databaseID,
+		StorageID:  storageID,
+		Status:     BackupStatusCompleted,
+		CreatedAt:  time.Now().UTC().Add(-time.Hour),
+	}
+
+	if err := backupRepository.Save(backup); err != nil {
+		panic(err)
+	}
+
+	return backup
+}
+
+func RemoveTestBackup(id uuid.UUID) {
+	if err := backupRepository.DeleteByID(id); err != nil {
+		panic(err)
+	}
+}
@@ -80,6 +80,11 @@ func (uc *CreatePostgresqlBackupUsecase) Execute(
 		uc.logger.Info("Using zstd compression level 5", "version", pg.Version)
 	}
 
+	password, err := pg.ResolvePassword()
+	if err != nil {
+		return err
+	}
+
 	return uc.streamToStorage(
 		backupID,
 		backupConfig,
@@ -90,7 +95,7 @@ func (uc *CreatePostgresqlBackupUsecase) Execute(
 			config.GetEnv().PostgresesInstallDir,
 		),
 		args,
-		pg.Password,
+		password,
 		storage,
 		db,
 		backupProgressListener,
@@ -8,6 +8,14 @@ var createPostgresqlBackupUsecase = &CreatePostgresqlBackupUsecase{
 	logger.GetLogger(),
 }
 
+var readPostgresqlDumpInfoUsecase = &ReadPostgresqlDumpInfoUsecase{
+	logger.GetLogger(),
+}
+
 func GetCreatePostgresqlBackupUsecase() *CreatePostgresqlBackupUsecase {
 	return createPostgresqlBackupUsecase
 }
+
+func GetReadPostgresqlDumpInfoUsecase() *ReadPostgresqlDumpInfoUsecase {
+	return readPostgresqlDumpInfoUsecase
+}
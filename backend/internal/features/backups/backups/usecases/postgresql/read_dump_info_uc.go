@@ -0,0 +1,105 @@
+package usecases_postgresql
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"time"
+
+	"postgresus-backend/internal/config"
+	"postgresus-backend/internal/util/tools"
+)
+
+type PostgresqlDumpInfo struct {
+	Format        string
+	DatabaseName  string
+	ServerVersion string
+	PgDumpVersion string
+	CreatedAt     *time.Time
+}
+
+// GetServerMajorVersion returns major version of the server the
+// dump was made from, e.g. "16" for "16.2 (Debian 16.2-1.pgdg120+2)"
+func (i *PostgresqlDumpInfo) GetServerMajorVersion() string {
+	version, _, _ := strings.Cut(i.ServerVersion, " ")
+	major, _, _ := strings.Cut(version, ".")
+
+	return major
+}
+
+type ReadPostgresqlDumpInfoUsecase struct {
+	logger *slog.Logger
+}
+
+// Execute reads TOC header of the dump file via pg_restore -l. The
+// pg_restore of the given version fails if the dump is not an archive
+// or is made by newer pg_dump
+func (uc *ReadPostgresqlDumpInfoUsecase) Execute(
+	dumpFilePath string,
+	version tools.PostgresqlVersion,
+) (*PostgresqlDumpInfo, error) {
+	pgBin := tools.GetPostgresqlExecutable(
+		version,
+		tools.PostgresqlExecutablePgRestore,
+		config.GetEnv().EnvMode,
+		config.GetEnv().PostgresesInstallDir,
+	)
+
+	uc.logger.Info("Reading PostgreSQL dump header", "pgBin", pgBin, "file", dumpFilePath)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, pgBin, "-l", dumpFilePath).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to read dump with pg_restore: %s",
+			strings.TrimSpace(string(output)),
+		)
+	}
+
+	return ParsePostgresqlDumpInfo(string(output))
+}
+
+// ParsePostgresqlDumpInfo parses header of pg_restore -l output
+func ParsePostgresqlDumpInfo(toc string) (*PostgresqlDumpInfo, error) {
+	info := &PostgresqlDumpInfo{}
+
+	scanner := bufio.NewScanner(strings.NewReader(toc))
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), ";"))
+
+		switch {
+		case strings.HasPrefix(line, "Archive created at "):
+			createdAt, err := time.Parse(
+				"2006-01-02 15:04:05 MST",
+				strings.TrimPrefix(line, "Archive created at "),
+			)
+			if err == nil {
+				createdAt = createdAt.UTC()
+				info.CreatedAt = &createdAt
+			}
+		case strings.HasPrefix(line, "dbname: "):
+			info.DatabaseName = strings.TrimPrefix(line, "dbname: ")
+		case strings.HasPrefix(line, "Format: "):
+			info.Format = strings.TrimPrefix(line, "Format: ")
+		case strings.HasPrefix(line, "Dumped from database version: "):
+			info.ServerVersion = strings.TrimPrefix(line, "Dumped from database version: ")
+		case strings.HasPrefix(line, "Dumped by pg_dump version: "):
+			info.PgDumpVersion = strings.TrimPrefix(line, "Dumped by pg_dump version: ")
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dump header: %w", err)
+	}
+
+	if info.Format == "" || info.ServerVersion == "" {
+		return nil, fmt.Errorf("file is not a pg_dump archive")
+	}
+
+	return info, nil
+}
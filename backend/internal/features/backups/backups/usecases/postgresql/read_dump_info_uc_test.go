@@ -0,0 +1,43 @@
+package usecases_postgresql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParsePostgresqlDumpInfo_HeaderFieldsParsed(t *testing.T) {
+	toc := `;
+; Archive created at 2024-03-05 04:00:03 UTC
+;     dbname: shop
+;     TOC Entries: 215
+;     Compression: gzip
+;     Dump Version: 1.15-0
+;     Format: CUSTOM
+;     Integer: 4 bytes
+;     Offset: 8 bytes
+;     Dumped from database version: 16.2 (Debian 16.2-1.pgdg120+2)
+;     Dumped by pg_dump version: 16.2
+;
+;
+; Selected TOC Entries:
+;
+216; 1259 16385 TABLE public orders postgres
+`
+
+	info, err := ParsePostgresqlDumpInfo(toc)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "CUSTOM", info.Format)
+	assert.Equal(t, "shop", info.DatabaseName)
+	assert.Equal(t, "16", info.GetServerMajorVersion())
+	assert.Equal(t, "16.2", info.PgDumpVersion)
+	assert.NotNil(t, info.CreatedAt)
+	assert.Equal(t, time.Date(2024, 3, 5, 4, 0, 3, 0, time.UTC), *info.CreatedAt)
+}
+
+func Test_ParsePostgresqlDumpInfoWithoutHeader_ErrorReturned(t *testing.T) {
+	_, err := ParsePostgresqlDumpInfo("CREATE TABLE orders (id int);")
+	assert.Error(t, err)
+}
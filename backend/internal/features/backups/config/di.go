@@ -1,6 +1,7 @@
 package backups_config
 
 import (
+	"postgresus-backend/internal/features/audit_logs"
 	"postgresus-backend/internal/features/databases"
 	"postgresus-backend/internal/features/storages"
 	"postgresus-backend/internal/features/users"
@@ -11,6 +12,7 @@ var backupConfigService = &BackupConfigService{
 	backupConfigRepository,
 	databases.GetDatabaseService(),
 	storages.GetStorageService(),
+	audit_logs.GetAuditLogService(),
 	nil,
 }
 var backupConfigController = &BackupConfigController{
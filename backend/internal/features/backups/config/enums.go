@@ -5,4 +5,8 @@ type BackupNotificationType string
 const (
 	NotificationBackupFailed  BackupNotificationType = "BACKUP_FAILED"
 	NotificationBackupSuccess BackupNotificationType = "BACKUP_SUCCESS"
+
+	NotificationStorageQuotaWarning BackupNotificationType = "STORAGE_QUOTA_WARNING"
+	NotificationBackupAnomaly       BackupNotificationType = "BACKUP_ANOMALY"
+	NotificationBackupOverdue       BackupNotificationType = "BACKUP_OVERDUE"
 )
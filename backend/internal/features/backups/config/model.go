@@ -2,6 +2,7 @@ package backups_config
 
 import (
 	"errors"
+	"net/url"
 	"postgresus-backend/internal/features/intervals"
 	"postgresus-backend/internal/features/storages"
 	"postgresus-backend/internal/util/period"
@@ -31,6 +32,19 @@ type BackupConfig struct {
 	MaxFailedTriesCount int  `json:"maxFailedTriesCount" gorm:"column:max_failed_tries_count;type:int;not null"`
 
 	CpuCount int `json:"cpuCount" gorm:"type:int;not null"`
+
+	// backup is marked as anomaly when its size or duration differs from
+	// the median of recent backups more than by the percent, 0 disables check
+	SizeAnomalyThresholdPercent     int `json:"sizeAnomalyThresholdPercent"     gorm:"column:size_anomaly_threshold_percent;type:int;not null"`
+	DurationAnomalyThresholdPercent int `json:"durationAnomalyThresholdPercent" gorm:"column:duration_anomaly_threshold_percent;type:int;not null"`
+
+	// backup is overdue when the last completed one is older than the
+	// interval plus the grace period, it catches silently stopped schedule
+	BackupOverdueGraceMinutes int `json:"backupOverdueGraceMinutes" gorm:"column:backup_overdue_grace_minutes;type:int;not null"`
+
+	// URL pinged after each successful backup, e.g. healthchecks.io check,
+	// so an external service alerts when backups stop
+	HeartbeatURL string `json:"heartbeatUrl" gorm:"column:heartbeat_url;type:text;not null"`
 }
 
 func (h *BackupConfig) TableName() string {
@@ -88,5 +102,21 @@ func (b *BackupConfig) Validate() error {
 		return errors.New("max failed tries count must be greater than 0")
 	}
 
+	if b.SizeAnomalyThresholdPercent < 0 || b.DurationAnomalyThresholdPercent < 0 {
+		return errors.New("anomaly threshold cannot be negative")
+	}
+
+	if b.BackupOverdueGraceMinutes < 0 {
+		return errors.New("backup overdue grace period cannot be negative")
+	}
+
+	if b.HeartbeatURL != "" {
+		heartbeatURL, err := url.Parse(b.HeartbeatURL)
+		if err != nil || (heartbeatURL.Scheme != "http" && heartbeatURL.Scheme != "https") ||
+			heartbeatURL.Host == "" {
+			return errors.New("heartbeat URL must be a valid http or https URL")
+		}
+	}
+
 	return nil
 }
@@ -1,9 +1,13 @@
 package backups_config
 
 import (
+	"errors"
+	"fmt"
+	"postgresus-backend/internal/features/audit_logs"
 	"postgresus-backend/internal/features/databases"
 	"postgresus-backend/internal/features/intervals"
 	"postgresus-backend/internal/features/storages"
+	user_enums "postgresus-backend/internal/features/users/enums"
 	users_models "postgresus-backend/internal/features/users/models"
 	"postgresus-backend/internal/util/period"
 
@@ -14,6 +18,7 @@ type BackupConfigService struct {
 	backupConfigRepository *BackupConfigRepository
 	databaseService        *databases.DatabaseService
 	storageService         *storages.StorageService
+	auditLogService        *audit_logs.AuditLogService
 
 	dbStorageChangeListener BackupConfigStorageChangeListener
 }
@@ -32,12 +37,46 @@ func (s *BackupConfigService) SaveBackupConfigWithAuth(
 		return nil, err
 	}
 
-	_, err := s.databaseService.GetDatabase(user, backupConfig.DatabaseID)
+	database, err := s.databaseService.GetDatabaseWithRole(
+		user,
+		backupConfig.DatabaseID,
+		user_enums.UserRoleAdmin,
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.SaveBackupConfig(backupConfig)
+	if backupConfig.StorageID != nil {
+		storage, err := s.storageService.GetStorage(user, *backupConfig.StorageID)
+		if err != nil {
+			return nil, err
+		}
+
+		if storage.WorkspaceID != database.WorkspaceID {
+			return nil, errors.New("storage belongs to another workspace")
+		}
+	}
+
+	existingConfig, err := s.GetBackupConfigByDbId(backupConfig.DatabaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	savedBackupConfig, err := s.SaveBackupConfig(backupConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+		Action:     audit_logs.AuditLogActionUpdate,
+		TargetType: audit_logs.AuditLogTargetTypeBackupConfig,
+		TargetID:   database.ID,
+		TargetName: database.Name,
+		Message:    fmt.Sprintf("Updated backup config of database \"%s\"", database.Name),
+		Diff:       audit_logs.CalculateDiff(existingConfig, savedBackupConfig),
+	})
+
+	return savedBackupConfig, nil
 }
 
 func (s *BackupConfigService) SaveBackupConfig(
@@ -60,6 +99,7 @@ func (s *BackupConfigService) SaveBackupConfig(
 			!storageIDsEqual(existingConfig.StorageID, &backupConfig.Storage.ID) {
 			if err := s.dbStorageChangeListener.OnBeforeBackupsStorageChange(
 				backupConfig.DatabaseID,
+				&backupConfig.Storage.ID,
 			); err != nil {
 				return nil, err
 			}
@@ -69,6 +109,7 @@ func (s *BackupConfigService) SaveBackupConfig(
 	if !backupConfig.IsBackupsEnabled && existingConfig.StorageID != nil {
 		if err := s.dbStorageChangeListener.OnBeforeBackupsStorageChange(
 			backupConfig.DatabaseID,
+			nil,
 		); err != nil {
 			return nil, err
 		}
@@ -146,10 +187,16 @@ func (s *BackupConfigService) initializeDefaultConfig(
 		SendNotificationsOn: []BackupNotificationType{
 			NotificationBackupFailed,
 			NotificationBackupSuccess,
+			NotificationStorageQuotaWarning,
+			NotificationBackupAnomaly,
+			NotificationBackupOverdue,
 		},
-		CpuCount:            1,
-		IsRetryIfFailed:     true,
-		MaxFailedTriesCount: 3,
+		CpuCount:                        1,
+		IsRetryIfFailed:                 true,
+		MaxFailedTriesCount:             3,
+		SizeAnomalyThresholdPercent:     50,
+		DurationAnomalyThresholdPercent: 100,
+		BackupOverdueGraceMinutes:       60,
 	})
 
 	return err
@@ -181,6 +228,10 @@ func (s *BackupConfigService) OnDatabaseCopied(originalDatabaseID, newDatabaseID
 		IsRetryIfFailed:     originalConfig.IsRetryIfFailed,
 		MaxFailedTriesCount: originalConfig.MaxFailedTriesCount,
 		CpuCount:            originalConfig.CpuCount,
+
+		SizeAnomalyThresholdPercent:     originalConfig.SizeAnomalyThresholdPercent,
+		DurationAnomalyThresholdPercent: originalConfig.DurationAnomalyThresholdPercent,
+		BackupOverdueGraceMinutes:       originalConfig.BackupOverdueGraceMinutes,
 	}
 
 	_, err = s.SaveBackupConfig(newConfig)
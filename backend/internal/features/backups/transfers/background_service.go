@@ -0,0 +1,42 @@
+package backups_transfers
+
+import (
+	"log/slog"
+	"time"
+)
+
+type BackupTransferBackgroundService struct {
+	backupTransferRepository *BackupTransferRepository
+	logger                   *slog.Logger
+}
+
+func (s *BackupTransferBackgroundService) Run() {
+	if err := s.failTransfersInProgress(); err != nil {
+		s.logger.Error("Failed to fail backups transfers in progress", "error", err)
+		panic(err)
+	}
+}
+
+func (s *BackupTransferBackgroundService) failTransfersInProgress() error {
+	transfersInProgress, err := s.backupTransferRepository.FindByStatus(
+		BackupTransferStatusInProgress,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, transfer := range transfersInProgress {
+		failMessage := "Backups transfer failed due to application restart"
+		finishedAt := time.Now().UTC()
+
+		transfer.Status = BackupTransferStatusFailed
+		transfer.FailMessage = &failMessage
+		transfer.FinishedAt = &finishedAt
+
+		if err := s.backupTransferRepository.Save(transfer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package backups_transfers
+
+import (
+	"net/http"
+	"postgresus-backend/internal/features/users"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type BackupTransferController struct {
+	backupTransferService *BackupTransferService
+	userService           *users.UserService
+}
+
+func (c *BackupTransferController) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/backup-transfers", c.StartTransfer)
+	router.GET("/backup-transfers", c.GetTransfers)
+	router.GET("/backup-transfers/:id", c.GetTransfer)
+}
+
+// StartTransfer
+// @Summary Start backups transfer
+// @Description Copy one or all completed backups of the database from the source storage to the target storage in background
+// @Tags backup-transfers
+// @Accept json
+// @Produce json
+// @Param request body StartBackupTransferRequest true "Transfer data"
+// @Success 200 {object} BackupTransfer
+// @Failure 400
+// @Failure 401
+// @Router /backup-transfers [post]
+func (c *BackupTransferController) StartTransfer(ctx *gin.Context) {
+	var request StartBackupTransferRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authorizationHeader := ctx.GetHeader("Authorization")
+	if authorizationHeader == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+		return
+	}
+
+	user, err := c.userService.GetUserFromToken(authorizationHeader)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	transfer, err := c.backupTransferService.StartTransfer(user, &request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, transfer)
+}
+
+// GetTransfers
+// @Summary Get backups transfers
+// @Description Get all backups transfers of the database
+// @Tags backup-transfers
+// @Produce json
+// @Param database_id query string true "Database ID"
+// @Success 200 {array} BackupTransfer
+// @Failure 400
+// @Failure 401
+// @Router /backup-transfers [get]
+func (c *BackupTransferController) GetTransfers(ctx *gin.Context) {
+	databaseID, err := uuid.Parse(ctx.Query("database_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid database_id"})
+		return
+	}
+
+	authorizationHeader := ctx.GetHeader("Authorization")
+	if authorizationHeader == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+		return
+	}
+
+	user, err := c.userService.GetUserFromToken(authorizationHeader)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	transfers, err := c.backupTransferService.GetTransfers(user, databaseID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, transfers)
+}
+
+// GetTransfer
+// @Summary Get backups transfer
+// @Description Get backups transfer by ID to track its progress
+// @Tags backup-transfers
+// @Produce json
+// @Param id path string true "Transfer ID"
+// @Success 200 {object} BackupTransfer
+// @Failure 400
+// @Failure 401
+// @Router /backup-transfers/{id} [get]
+func (c *BackupTransferController) GetTransfer(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid transfer ID"})
+		return
+	}
+
+	authorizationHeader := ctx.GetHeader("Authorization")
+	if authorizationHeader == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+		return
+	}
+
+	user, err := c.userService.GetUserFromToken(authorizationHeader)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	transfer, err := c.backupTransferService.GetTransfer(user, id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, transfer)
+}
@@ -0,0 +1,45 @@
+package backups_transfers
+
+import (
+	"postgresus-backend/internal/features/audit_logs"
+	"postgresus-backend/internal/features/backups/backups"
+	"postgresus-backend/internal/features/databases"
+	"postgresus-backend/internal/features/storages"
+	"postgresus-backend/internal/features/users"
+	"postgresus-backend/internal/util/logger"
+)
+
+var backupTransferRepository = &BackupTransferRepository{}
+var backupTransferService = &BackupTransferService{
+	backupTransferRepository,
+	backups.GetBackupService(),
+	databases.GetDatabaseService(),
+	storages.GetStorageService(),
+	audit_logs.GetAuditLogService(),
+	logger.GetLogger(),
+}
+var backupTransferController = &BackupTransferController{
+	backupTransferService,
+	users.GetUserService(),
+}
+
+var backupTransferBackgroundService = &BackupTransferBackgroundService{
+	backupTransferRepository,
+	logger.GetLogger(),
+}
+
+func GetBackupTransferService() *BackupTransferService {
+	return backupTransferService
+}
+
+func GetBackupTransferController() *BackupTransferController {
+	return backupTransferController
+}
+
+func GetBackupTransferBackgroundService() *BackupTransferBackgroundService {
+	return backupTransferBackgroundService
+}
+
+func SetupDependencies() {
+	backups.GetBackupService().AddBackupRemoveListener(backupTransferService)
+}
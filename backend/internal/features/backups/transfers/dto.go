@@ -0,0 +1,14 @@
+package backups_transfers
+
+import "github.com/google/uuid"
+
+type StartBackupTransferRequest struct {
+	DatabaseID uuid.UUID `json:"databaseId" binding:"required"`
+	// transfers all database backups of the source storage if empty
+	BackupID *uuid.UUID `json:"backupId"`
+
+	SourceStorageID uuid.UUID `json:"sourceStorageId" binding:"required"`
+	TargetStorageID uuid.UUID `json:"targetStorageId" binding:"required"`
+
+	IsDeleteFromSource bool `json:"isDeleteFromSource"`
+}
@@ -0,0 +1,9 @@
+package backups_transfers
+
+type BackupTransferStatus string
+
+const (
+	BackupTransferStatusInProgress BackupTransferStatus = "IN_PROGRESS"
+	BackupTransferStatusCompleted  BackupTransferStatus = "COMPLETED"
+	BackupTransferStatusFailed     BackupTransferStatus = "FAILED"
+)
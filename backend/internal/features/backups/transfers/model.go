@@ -0,0 +1,31 @@
+package backups_transfers
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BackupTransfer struct {
+	ID         uuid.UUID  `json:"id"         gorm:"column:id;type:uuid;primaryKey"`
+	DatabaseID uuid.UUID  `json:"databaseId" gorm:"column:database_id;type:uuid;not null"`
+	BackupID   *uuid.UUID `json:"backupId"   gorm:"column:backup_id;type:uuid"`
+
+	SourceStorageID uuid.UUID `json:"sourceStorageId" gorm:"column:source_storage_id;type:uuid;not null"`
+	TargetStorageID uuid.UUID `json:"targetStorageId" gorm:"column:target_storage_id;type:uuid;not null"`
+
+	IsDeleteFromSource bool `json:"isDeleteFromSource" gorm:"column:is_delete_from_source;not null"`
+
+	Status      BackupTransferStatus `json:"status"      gorm:"column:status;type:text;not null"`
+	FailMessage *string              `json:"failMessage" gorm:"column:fail_message"`
+
+	TotalBackupsCount       int `json:"totalBackupsCount"       gorm:"column:total_backups_count;not null;default:0"`
+	TransferredBackupsCount int `json:"transferredBackupsCount" gorm:"column:transferred_backups_count;not null;default:0"`
+
+	CreatedAt  time.Time  `json:"createdAt"  gorm:"column:created_at"`
+	FinishedAt *time.Time `json:"finishedAt" gorm:"column:finished_at"`
+}
+
+func (t *BackupTransfer) TableName() string {
+	return "backup_transfers"
+}
@@ -0,0 +1,83 @@
+package backups_transfers
+
+import (
+	"postgresus-backend/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+type BackupTransferRepository struct{}
+
+func (r *BackupTransferRepository) Save(transfer *BackupTransfer) error {
+	db := storage.GetDb()
+
+	isNew := transfer.ID == uuid.Nil
+	if isNew {
+		transfer.ID = uuid.New()
+		return db.Create(transfer).Error
+	}
+
+	return db.Save(transfer).Error
+}
+
+func (r *BackupTransferRepository) FindByID(id uuid.UUID) (*BackupTransfer, error) {
+	var transfer BackupTransfer
+
+	if err := storage.
+		GetDb().
+		Where("id = ?", id).
+		First(&transfer).Error; err != nil {
+		return nil, err
+	}
+
+	return &transfer, nil
+}
+
+func (r *BackupTransferRepository) FindByDatabaseID(
+	databaseID uuid.UUID,
+) ([]*BackupTransfer, error) {
+	var transfers []*BackupTransfer
+
+	if err := storage.
+		GetDb().
+		Where("database_id = ?", databaseID).
+		Order("created_at DESC").
+		Find(&transfers).Error; err != nil {
+		return nil, err
+	}
+
+	return transfers, nil
+}
+
+func (r *BackupTransferRepository) FindByDatabaseIdAndStatus(
+	databaseID uuid.UUID,
+	status BackupTransferStatus,
+) ([]*BackupTransfer, error) {
+	var transfers []*BackupTransfer
+
+	if err := storage.
+		GetDb().
+		Where("database_id = ? AND status = ?", databaseID, status).
+		Order("created_at DESC").
+		Find(&transfers).Error; err != nil {
+		return nil, err
+	}
+
+	return transfers, nil
+}
+
+func (r *BackupTransferRepository) FindByStatus(
+	status BackupTransferStatus,
+) ([]*BackupTransfer, error) {
+	var transfers []*BackupTransfer
+
+	if err := storage.
+		GetDb().
+		Where("status = ?", status).
+		Order("created_at DESC").
+		Find(&transfers).Error; err != nil {
+		return nil, err
+	}
+
+	return transfers, nil
+}
@@ -0,0 +1,300 @@
+package backups_transfers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"postgresus-backend/internal/features/audit_logs"
+	"postgresus-backend/internal/features/backups/backups"
+	"postgresus-backend/internal/features/databases"
+	"postgresus-backend/internal/features/storages"
+	user_enums "postgresus-backend/internal/features/users/enums"
+	users_models "postgresus-backend/internal/features/users/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BackupTransferService copies backups from one storage to another in
+// background. Backup points to the target storage as soon as its file
+// is copied, so history is not lost if transfer fails in the middle
+type BackupTransferService struct {
+	backupTransferRepository *BackupTransferRepository
+	backupService            *backups.BackupService
+	databaseService          *databases.DatabaseService
+	storageService           *storages.StorageService
+	auditLogService          *audit_logs.AuditLogService
+	logger                   *slog.Logger
+}
+
+func (s *BackupTransferService) OnBeforeBackupRemove(backup *backups.Backup) error {
+	transfersInProgress, err := s.backupTransferRepository.FindByDatabaseIdAndStatus(
+		backup.DatabaseID,
+		BackupTransferStatusInProgress,
+	)
+	if err != nil {
+		return err
+	}
+
+	if len(transfersInProgress) > 0 {
+		return errors.New("backups transfer is in progress, backup cannot be removed")
+	}
+
+	return nil
+}
+
+func (s *BackupTransferService) StartTransfer(
+	user *users_models.User,
+	request *StartBackupTransferRequest,
+) (*BackupTransfer, error) {
+	database, err := s.databaseService.GetDatabaseWithRole(
+		user,
+		request.DatabaseID,
+		user_enums.UserRoleOperator,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceStorage, err := s.storageService.GetStorage(user, request.SourceStorageID)
+	if err != nil {
+		return nil, err
+	}
+
+	targetStorage, err := s.storageService.GetStorage(user, request.TargetStorageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if sourceStorage.WorkspaceID != database.WorkspaceID ||
+		targetStorage.WorkspaceID != database.WorkspaceID {
+		return nil, errors.New("storages should belong to the workspace of the database")
+	}
+
+	if sourceStorage.ID == targetStorage.ID {
+		return nil, errors.New("source and target storages should be different")
+	}
+
+	if sourceStorage.Type == storages.StorageTypeLocal &&
+		targetStorage.Type == storages.StorageTypeLocal {
+		return nil, errors.New("local storages share the same folder, transfer is not needed")
+	}
+
+	transfersInProgress, err := s.backupTransferRepository.FindByDatabaseIdAndStatus(
+		database.ID,
+		BackupTransferStatusInProgress,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(transfersInProgress) > 0 {
+		return nil, errors.New("backups transfer is already in progress for this database")
+	}
+
+	backupsToTransfer, err := s.getBackupsToTransfer(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(backupsToTransfer) == 0 {
+		return nil, errors.New("there are no completed backups to transfer")
+	}
+
+	transfer := &BackupTransfer{
+		DatabaseID: database.ID,
+		BackupID:   request.BackupID,
+
+		SourceStorageID: sourceStorage.ID,
+		TargetStorageID: targetStorage.ID,
+
+		IsDeleteFromSource: request.IsDeleteFromSource,
+
+		Status:            BackupTransferStatusInProgress,
+		TotalBackupsCount: len(backupsToTransfer),
+
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := s.backupTransferRepository.Save(transfer); err != nil {
+		return nil, err
+	}
+
+	go s.runTransfer(transfer, backupsToTransfer, sourceStorage, targetStorage)
+
+	s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+		Action:     audit_logs.AuditLogActionTransfer,
+		TargetType: audit_logs.AuditLogTargetTypeDatabase,
+		TargetID:   database.ID,
+		TargetName: database.Name,
+		Message: fmt.Sprintf(
+			"Started transfer of %d backups of database \"%s\" from storage \"%s\" to storage \"%s\"",
+			len(backupsToTransfer),
+			database.Name,
+			sourceStorage.Name,
+			targetStorage.Name,
+		),
+	})
+
+	return transfer, nil
+}
+
+func (s *BackupTransferService) GetTransfers(
+	user *users_models.User,
+	databaseID uuid.UUID,
+) ([]*BackupTransfer, error) {
+	_, err := s.databaseService.GetDatabase(user, databaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.backupTransferRepository.FindByDatabaseID(databaseID)
+}
+
+func (s *BackupTransferService) GetTransfer(
+	user *users_models.User,
+	transferID uuid.UUID,
+) (*BackupTransfer, error) {
+	transfer, err := s.backupTransferRepository.FindByID(transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.databaseService.GetDatabase(user, transfer.DatabaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+func (s *BackupTransferService) getBackupsToTransfer(
+	request *StartBackupTransferRequest,
+) ([]*backups.Backup, error) {
+	if request.BackupID != nil {
+		backup, err := s.backupService.GetBackup(*request.BackupID)
+		if err != nil {
+			return nil, err
+		}
+
+		if backup.DatabaseID != request.DatabaseID {
+			return nil, errors.New("backup does not belong to the database")
+		}
+
+		if backup.StorageID != request.SourceStorageID {
+			return nil, errors.New("backup is not stored in the source storage")
+		}
+
+		if backup.Status != backups.BackupStatusCompleted {
+			return nil, errors.New("only completed backups can be transferred")
+		}
+
+		return []*backups.Backup{backup}, nil
+	}
+
+	storageBackups, err := s.backupService.GetDatabaseBackupsByStorage(
+		request.DatabaseID,
+		request.SourceStorageID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	completedBackups := make([]*backups.Backup, 0, len(storageBackups))
+	for _, backup := range storageBackups {
+		if backup.Status == backups.BackupStatusCompleted {
+			completedBackups = append(completedBackups, backup)
+		}
+	}
+
+	return completedBackups, nil
+}
+
+func (s *BackupTransferService) runTransfer(
+	transfer *BackupTransfer,
+	backupsToTransfer []*backups.Backup,
+	sourceStorage *storages.Storage,
+	targetStorage *storages.Storage,
+) {
+	s.logger.Info(
+		"Starting backups transfer",
+		"transferId",
+		transfer.ID,
+		"backupsCount",
+		len(backupsToTransfer),
+	)
+
+	var transferErr error
+	for _, backup := range backupsToTransfer {
+		if err := s.transferBackup(transfer, backup, sourceStorage, targetStorage); err != nil {
+			transferErr = fmt.Errorf("failed to transfer backup %s: %w", backup.ID, err)
+			break
+		}
+
+		transfer.TransferredBackupsCount++
+		if err := s.backupTransferRepository.Save(transfer); err != nil {
+			s.logger.Error("Failed to update backups transfer progress", "error", err)
+		}
+	}
+
+	finishedAt := time.Now().UTC()
+	transfer.FinishedAt = &finishedAt
+	transfer.Status = BackupTransferStatusCompleted
+
+	if transferErr != nil {
+		failMessage := transferErr.Error()
+		transfer.FailMessage = &failMessage
+		transfer.Status = BackupTransferStatusFailed
+
+		s.logger.Error("Backups transfer failed", "transferId", transfer.ID, "error", transferErr)
+	}
+
+	if err := s.backupTransferRepository.Save(transfer); err != nil {
+		s.logger.Error("Failed to save backups transfer", "error", err)
+	}
+}
+
+func (s *BackupTransferService) transferBackup(
+	transfer *BackupTransfer,
+	backup *backups.Backup,
+	sourceStorage *storages.Storage,
+	targetStorage *storages.Storage,
+) error {
+	file, err := sourceStorage.GetFile(backup.ID)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	if err := targetStorage.SaveFile(s.logger, backup.ID, file); err != nil {
+		return err
+	}
+
+	// backup may be removed by store period while the file was copied
+	if _, err := s.backupService.GetBackup(backup.ID); err != nil {
+		_ = targetStorage.DeleteFile(backup.ID)
+		s.logger.Info("Backup removed during transfer", "backupId", backup.ID)
+		return nil
+	}
+
+	if err := s.backupService.ChangeBackupStorage(backup.ID, targetStorage.ID); err != nil {
+		_ = targetStorage.DeleteFile(backup.ID)
+		return err
+	}
+
+	if transfer.IsDeleteFromSource {
+		if err := sourceStorage.DeleteFile(backup.ID); err != nil {
+			s.logger.Error(
+				"Failed to delete transferred backup file from source storage",
+				"backupId",
+				backup.ID,
+				"error",
+				err,
+			)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,215 @@
+package backups_transfers
+
+import (
+	"bytes"
+	"postgresus-backend/internal/features/backups/backups"
+	"postgresus-backend/internal/features/databases"
+	"postgresus-backend/internal/features/notifiers"
+	"postgresus-backend/internal/features/storages"
+	local_storage "postgresus-backend/internal/features/storages/models/local"
+	"postgresus-backend/internal/features/users"
+	"postgresus-backend/internal/features/workspaces"
+	"postgresus-backend/internal/util/logger"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RunTransfer_WhenFileCopied_BackupPointsToTargetStorage(t *testing.T) {
+	// setup data
+	user := users.GetTestUser()
+	sourceStorage := storages.CreateTestStorage(user.UserID)
+	targetStorage := storages.CreateTestStorage(user.UserID)
+	notifier := notifiers.CreateTestNotifier(user.UserID)
+	database := databases.CreateTestDatabase(user.UserID, sourceStorage, notifier)
+
+	backup := backups.CreateTestBackup(database.ID, sourceStorage.ID)
+	require.NoError(
+		t,
+		sourceStorage.SaveFile(logger.GetLogger(), backup.ID, bytes.NewReader([]byte("dump"))),
+	)
+
+	transfer := createTestTransfer(t, database.ID, sourceStorage.ID, targetStorage.ID)
+
+	// act
+	// local storages share the folder, so the file is copied onto
+	// itself and the source file is kept to check the backup record
+	backupTransferService.runTransfer(
+		transfer,
+		[]*backups.Backup{backup},
+		sourceStorage,
+		targetStorage,
+	)
+
+	// assertions
+	savedTransfer, err := backupTransferRepository.FindByID(transfer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, BackupTransferStatusCompleted, savedTransfer.Status)
+	assert.Equal(t, 1, savedTransfer.TransferredBackupsCount)
+	assert.NotNil(t, savedTransfer.FinishedAt)
+
+	transferredBackup, err := backups.GetBackupService().GetBackup(backup.ID)
+	require.NoError(t, err)
+	assert.Equal(t, targetStorage.ID, transferredBackup.StorageID)
+
+	file, err := targetStorage.GetFile(backup.ID)
+	require.NoError(t, err)
+	assert.NoError(t, file.Close())
+
+	// cleanup
+	assert.NoError(t, targetStorage.DeleteFile(backup.ID))
+	backups.RemoveTestBackup(backup.ID)
+
+	databases.RemoveTestDatabase(database)
+	storages.RemoveTestStorage(sourceStorage.ID)
+	storages.RemoveTestStorage(targetStorage.ID)
+	notifiers.RemoveTestNotifier(notifier)
+}
+
+func Test_RunTransfer_WhenFileCopyFailed_BackupKeptInSourceStorage(t *testing.T) {
+	// setup data
+	user := users.GetTestUser()
+	sourceStorage := storages.CreateTestStorage(user.UserID)
+	targetStorage := storages.CreateTestStorage(user.UserID)
+	notifier := notifiers.CreateTestNotifier(user.UserID)
+	database := databases.CreateTestDatabase(user.UserID, sourceStorage, notifier)
+
+	// file of the backup is not saved, so reading it fails
+	backup := backups.CreateTestBackup(database.ID, sourceStorage.ID)
+
+	transfer := createTestTransfer(t, database.ID, sourceStorage.ID, targetStorage.ID)
+	transfer.IsDeleteFromSource = true
+
+	// act
+	backupTransferService.runTransfer(
+		transfer,
+		[]*backups.Backup{backup},
+		sourceStorage,
+		targetStorage,
+	)
+
+	// assertions
+	savedTransfer, err := backupTransferRepository.FindByID(transfer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, BackupTransferStatusFailed, savedTransfer.Status)
+	assert.Equal(t, 0, savedTransfer.TransferredBackupsCount)
+	require.NotNil(t, savedTransfer.FailMessage)
+	assert.Contains(t, *savedTransfer.FailMessage, backup.ID.String())
+
+	notTransferredBackup, err := backups.GetBackupService().GetBackup(backup.ID)
+	require.NoError(t, err)
+	assert.Equal(t, sourceStorage.ID, notTransferredBackup.StorageID)
+
+	// cleanup
+	backups.RemoveTestBackup(backup.ID)
+
+	databases.RemoveTestDatabase(database)
+	storages.RemoveTestStorage(sourceStorage.ID)
+	storages.RemoveTestStorage(targetStorage.ID)
+	notifiers.RemoveTestNotifier(notifier)
+}
+
+func Test_OnBeforeBackupRemove_WhenTransferInProgress_RemovalBlocked(t *testing.T) {
+	// setup data
+	user := users.GetTestUser()
+	sourceStorage := storages.CreateTestStorage(user.UserID)
+	targetStorage := storages.CreateTestStorage(user.UserID)
+	notifier := notifiers.CreateTestNotifier(user.UserID)
+	database := databases.CreateTestDatabase(user.UserID, sourceStorage, notifier)
+
+	backup := backups.CreateTestBackup(database.ID, sourceStorage.ID)
+	transfer := createTestTransfer(t, database.ID, sourceStorage.ID, targetStorage.ID)
+
+	// act
+	errInProgress := backupTransferService.OnBeforeBackupRemove(backup)
+
+	transfer.Status = BackupTransferStatusCompleted
+	require.NoError(t, backupTransferRepository.Save(transfer))
+
+	errCompleted := backupTransferService.OnBeforeBackupRemove(backup)
+
+	// assertions
+	assert.ErrorContains(t, errInProgress, "backups transfer is in progress")
+	assert.NoError(t, errCompleted)
+
+	// cleanup
+	backups.RemoveTestBackup(backup.ID)
+
+	databases.RemoveTestDatabase(database)
+	storages.RemoveTestStorage(sourceStorage.ID)
+	storages.RemoveTestStorage(targetStorage.ID)
+	notifiers.RemoveTestNotifier(notifier)
+}
+
+func Test_StartTransfer_WhenTargetStorageInAnotherWorkspace_TransferRejected(t *testing.T) {
+	// setup data
+	user := users.GetTestUser()
+	admin, err := users.GetUserService().GetFirstUser()
+	require.NoError(t, err)
+
+	sourceStorage := storages.CreateTestStorage(user.UserID)
+	notifier := notifiers.CreateTestNotifier(user.UserID)
+	database := databases.CreateTestDatabase(user.UserID, sourceStorage, notifier)
+	backup := backups.CreateTestBackup(database.ID, sourceStorage.ID)
+
+	anotherWorkspace, err := workspaces.GetWorkspaceService().CreateWorkspace(
+		admin,
+		&workspaces.SaveWorkspaceRequest{Name: "Test Workspace " + uuid.New().String()},
+	)
+	require.NoError(t, err)
+
+	targetStorage := &storages.Storage{
+		WorkspaceID:  anotherWorkspace.ID,
+		Type:         storages.StorageTypeLocal,
+		Name:         "Test Storage " + uuid.New().String(),
+		LocalStorage: &local_storage.LocalStorage{},
+	}
+	require.NoError(t, storages.GetStorageService().SaveStorage(admin, targetStorage))
+
+	// act
+	transfer, err := backupTransferService.StartTransfer(admin, &StartBackupTransferRequest{
+		DatabaseID:      database.ID,
+		SourceStorageID: sourceStorage.ID,
+		TargetStorageID: targetStorage.ID,
+	})
+
+	// assertions
+	assert.Nil(t, transfer)
+	assert.ErrorContains(t, err, "storages should belong to the workspace of the database")
+
+	transfers, err := backupTransferRepository.FindByDatabaseID(database.ID)
+	require.NoError(t, err)
+	assert.Empty(t, transfers)
+
+	// cleanup
+	backups.RemoveTestBackup(backup.ID)
+
+	databases.RemoveTestDatabase(database)
+	storages.RemoveTestStorage(sourceStorage.ID)
+	storages.RemoveTestStorage(targetStorage.ID)
+	notifiers.RemoveTestNotifier(notifier)
+
+	assert.NoError(t, workspaces.GetWorkspaceService().DeleteWorkspace(admin, anotherWorkspace.ID))
+}
+
+func createTestTransfer(
+	t *testing.T,
+	databaseID uuid.UUID,
+	sourceStorageID uuid.UUID,
+	targetStorageID uuid.UUID,
+) *BackupTransfer {
+	transfer := &BackupTransfer{
+		DatabaseID:        databaseID,
+		SourceStorageID:   sourceStorageID,
+		TargetStorageID:   targetStorageID,
+		Status:            BackupTransferStatusInProgress,
+		TotalBackupsCount: 1,
+		CreatedAt:         time.Now().UTC(),
+	}
+	require.NoError(t, backupTransferRepository.Save(transfer))
+
+	return transfer
+}
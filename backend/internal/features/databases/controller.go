@@ -63,6 +63,7 @@ func (c *DatabaseController) CreateDatabase(ctx *gin.Context) {
 		return
 	}
 
+	database.HideSensitiveData()
 	ctx.JSON(http.StatusCreated, database)
 }
 
@@ -102,6 +103,7 @@ func (c *DatabaseController) UpdateDatabase(ctx *gin.Context) {
 		return
 	}
 
+	request.HideSensitiveData()
 	ctx.JSON(http.StatusOK, request)
 }
 
@@ -177,17 +179,19 @@ func (c *DatabaseController) GetDatabase(ctx *gin.Context) {
 		return
 	}
 
+	database.HideSensitiveData()
 	ctx.JSON(http.StatusOK, database)
 }
 
 // GetDatabases
 // @Summary Get databases
-// @Description Get all databases for the authenticated user
+// @Description Get databases of workspaces available to the authenticated user
 // @Tags databases
 // @Produce json
+// @Param workspace_id query string false "Workspace ID, all available workspaces if empty"
 // @Success 200 {array} Database
+// @Failure 400
 // @Failure 401
-// @Failure 500
 // @Router /databases [get]
 func (c *DatabaseController) GetDatabases(ctx *gin.Context) {
 	authorizationHeader := ctx.GetHeader("Authorization")
@@ -202,12 +206,26 @@ func (c *DatabaseController) GetDatabases(ctx *gin.Context) {
 		return
 	}
 
-	databases, err := c.databaseService.GetDatabasesByUser(user)
+	var workspaceID *uuid.UUID
+	if workspaceIDStr := ctx.Query("workspace_id"); workspaceIDStr != "" {
+		parsedWorkspaceID, err := uuid.Parse(workspaceIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid workspace_id"})
+			return
+		}
+		workspaceID = &parsedWorkspaceID
+	}
+
+	databases, err := c.databaseService.GetDatabases(user, workspaceID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	for _, database := range databases {
+		database.HideSensitiveData()
+	}
+
 	ctx.JSON(http.StatusOK, databases)
 }
 
@@ -277,10 +295,7 @@ func (c *DatabaseController) TestDatabaseConnectionDirect(ctx *gin.Context) {
 		return
 	}
 
-	// Set user ID for validation purposes
-	request.UserID = user.ID
-
-	if err := c.databaseService.TestDatabaseConnectionDirect(&request); err != nil {
+	if err := c.databaseService.TestDatabaseConnectionDirectWithAuth(user, &request); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -363,5 +378,6 @@ func (c *DatabaseController) CopyDatabase(ctx *gin.Context) {
 		return
 	}
 
+	copiedDatabase.HideSensitiveData()
 	ctx.JSON(http.StatusCreated, copiedDatabase)
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"postgresus-backend/internal/util/secret_refs"
 	"postgresus-backend/internal/util/tools"
 	"regexp"
 	"slices"
@@ -26,7 +27,7 @@ type PostgresqlDatabase struct {
 	Host     string  `json:"host"     gorm:"type:text;not null"`
 	Port     int     `json:"port"     gorm:"type:int;not null"`
 	Username string  `json:"username" gorm:"type:text;not null"`
-	Password string  `json:"password" gorm:"type:text;not null"`
+	Password string  `json:"password" gorm:"type:text;not null;serializer:encrypted"`
 	Database *string `json:"database" gorm:"type:text"`
 	IsHttps  bool    `json:"isHttps"  gorm:"type:boolean;default:false"`
 }
@@ -56,9 +57,46 @@ func (p *PostgresqlDatabase) Validate() error {
 		return errors.New("password is required")
 	}
 
+	if err := secret_refs.Validate(p.Password); err != nil {
+		return fmt.Errorf("invalid password reference: %w", err)
+	}
+
 	return nil
 }
 
+// HideSensitiveData removes the password before the database is
+// returned to the client, the password is write only. References
+// to external secrets are not secret, so they are kept
+func (p *PostgresqlDatabase) HideSensitiveData() {
+	if !secret_refs.IsReference(p.Password) {
+		p.Password = ""
+	}
+}
+
+// FillSensitiveData keeps the saved password if the client has not
+// sent a new one. The password is kept only for the same server and
+// user, otherwise changed host would reveal it
+func (p *PostgresqlDatabase) FillSensitiveData(existing *PostgresqlDatabase) {
+	if p.Password != "" || existing == nil {
+		return
+	}
+
+	if p.Host == existing.Host && p.Port == existing.Port && p.Username == existing.Username {
+		p.Password = existing.Password
+	}
+}
+
+// ResolvePassword returns the password or the external secret it
+// references. It is called each time the password is used
+func (p *PostgresqlDatabase) ResolvePassword() (string, error) {
+	password, err := secret_refs.Resolve(p.Password)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve database password: %w", err)
+	}
+
+	return password, nil
+}
+
 func (p *PostgresqlDatabase) TestConnection(logger *slog.Logger) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
@@ -78,7 +116,10 @@ func testSingleDatabaseConnection(
 	}
 
 	// Build connection string for the specific database
-	connStr := buildConnectionStringForDB(postgresDb, *postgresDb.Database)
+	connStr, err := buildConnectionStringForDB(postgresDb, *postgresDb.Database)
+	if err != nil {
+		return err
+	}
 
 	// Test connection
 	conn, err := pgx.Connect(ctx, connStr)
@@ -161,7 +202,12 @@ func testBasicOperations(ctx context.Context, conn *pgx.Conn, dbName string) err
 }
 
 // buildConnectionStringForDB builds connection string for specific database
-func buildConnectionStringForDB(p *PostgresqlDatabase, dbName string) string {
+func buildConnectionStringForDB(p *PostgresqlDatabase, dbName string) (string, error) {
+	password, err := p.ResolvePassword()
+	if err != nil {
+		return "", err
+	}
+
 	sslMode := "disable"
 	if p.IsHttps {
 		sslMode = "require"
@@ -171,10 +217,10 @@ func buildConnectionStringForDB(p *PostgresqlDatabase, dbName string) string {
 		p.Host,
 		p.Port,
 		p.Username,
-		p.Password,
+		password,
 		dbName,
 		sslMode,
-	)
+	), nil
 }
 
 func (p *PostgresqlDatabase) InstallExtensions(extensions []tools.PostgresqlExtension) error {
@@ -190,7 +236,10 @@ func (p *PostgresqlDatabase) InstallExtensions(extensions []tools.PostgresqlExte
 	defer cancel()
 
 	// Build connection string for the specific database
-	connStr := buildConnectionStringForDB(p, *p.Database)
+	connStr, err := buildConnectionStringForDB(p, *p.Database)
+	if err != nil {
+		return err
+	}
 
 	// Connect to database
 	conn, err := pgx.Connect(ctx, connStr)
@@ -1,8 +1,10 @@
 package databases
 
 import (
+	"postgresus-backend/internal/features/audit_logs"
 	"postgresus-backend/internal/features/notifiers"
 	"postgresus-backend/internal/features/users"
+	"postgresus-backend/internal/features/workspaces"
 	"postgresus-backend/internal/util/logger"
 )
 
@@ -11,6 +13,8 @@ var databaseRepository = &DatabaseRepository{}
 var databaseService = &DatabaseService{
 	databaseRepository,
 	notifiers.GetNotifierService(),
+	workspaces.GetWorkspaceService(),
+	audit_logs.GetAuditLogService(),
 	logger.GetLogger(),
 	[]DatabaseCreationListener{},
 	[]DatabaseRemoveListener{},
@@ -29,3 +33,7 @@ func GetDatabaseService() *DatabaseService {
 func GetDatabaseController() *DatabaseController {
 	return databaseController
 }
+
+func SetupDependencies() {
+	workspaces.GetWorkspaceService().AddWorkspaceRemoveListener(databaseService)
+}
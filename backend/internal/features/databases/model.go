@@ -11,10 +11,11 @@ import (
 )
 
 type Database struct {
-	ID     uuid.UUID    `json:"id"     gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
-	UserID uuid.UUID    `json:"userId" gorm:"column:user_id;type:uuid;not null"`
-	Name   string       `json:"name"   gorm:"column:name;type:text;not null"`
-	Type   DatabaseType `json:"type"   gorm:"column:type;type:text;not null"`
+	ID          uuid.UUID    `json:"id"          gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID      uuid.UUID    `json:"userId"      gorm:"column:user_id;type:uuid;not null"`
+	WorkspaceID uuid.UUID    `json:"workspaceId" gorm:"column:workspace_id;type:uuid;not null"`
+	Name        string       `json:"name"        gorm:"column:name;type:text;not null"`
+	Type        DatabaseType `json:"type"        gorm:"column:type;type:text;not null"`
 
 	Postgresql *postgresql.PostgresqlDatabase `json:"postgresql,omitempty" gorm:"foreignKey:DatabaseID"`
 
@@ -53,6 +54,30 @@ func (d *Database) ValidateUpdate(old, new Database) error {
 	return nil
 }
 
+// HideSensitiveData removes credentials before the database is
+// returned to the client, credentials are write only
+func (d *Database) HideSensitiveData() {
+	if d.Postgresql != nil {
+		d.Postgresql.HideSensitiveData()
+	}
+
+	for index := range d.Notifiers {
+		d.Notifiers[index].HideSensitiveData()
+	}
+}
+
+// FillSensitiveData keeps saved credentials which the client has not
+// changed, because they are not returned by API
+func (d *Database) FillSensitiveData(existing *Database) {
+	if existing == nil || existing.ID != d.ID || existing.Type != d.Type {
+		return
+	}
+
+	if d.Postgresql != nil {
+		d.Postgresql.FillSensitiveData(existing.Postgresql)
+	}
+}
+
 func (d *Database) TestConnection(logger *slog.Logger) error {
 	return d.getSpecificDatabase().TestConnection(logger)
 }
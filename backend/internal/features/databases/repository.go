@@ -92,22 +92,6 @@ func (r *DatabaseRepository) FindByID(id uuid.UUID) (*Database, error) {
 	return &database, nil
 }
 
-func (r *DatabaseRepository) FindByUserID(userID uuid.UUID) ([]*Database, error) {
-	var databases []*Database
-
-	if err := storage.
-		GetDb().
-		Preload("Postgresql").
-		Preload("Notifiers").
-		Where("user_id = ?", userID).
-		Order("CASE WHEN health_status = 'UNAVAILABLE' THEN 1 WHEN health_status = 'AVAILABLE' THEN 2 WHEN health_status IS NULL THEN 3 ELSE 4 END, name ASC").
-		Find(&databases).Error; err != nil {
-		return nil, err
-	}
-
-	return databases, nil
-}
-
 func (r *DatabaseRepository) Delete(id uuid.UUID) error {
 	db := storage.GetDb()
 
@@ -159,6 +143,27 @@ func (r *DatabaseRepository) GetAllDatabases() ([]*Database, error) {
 		GetDb().
 		Preload("Postgresql").
 		Preload("Notifiers").
+		Order("CASE WHEN health_status = 'UNAVAILABLE' THEN 1 WHEN health_status = 'AVAILABLE' THEN 2 WHEN health_status IS NULL THEN 3 ELSE 4 END, name ASC").
+		Find(&databases).Error; err != nil {
+		return nil, err
+	}
+
+	return databases, nil
+}
+
+func (r *DatabaseRepository) FindByWorkspaceIDs(workspaceIDs []uuid.UUID) ([]*Database, error) {
+	var databases []*Database
+
+	if len(workspaceIDs) == 0 {
+		return databases, nil
+	}
+
+	if err := storage.
+		GetDb().
+		Preload("Postgresql").
+		Preload("Notifiers").
+		Where("workspace_id IN ?", workspaceIDs).
+		Order("CASE WHEN health_status = 'UNAVAILABLE' THEN 1 WHEN health_status = 'AVAILABLE' THEN 2 WHEN health_status IS NULL THEN 3 ELSE 4 END, name ASC").
 		Find(&databases).Error; err != nil {
 		return nil, err
 	}
@@ -2,19 +2,27 @@ package databases
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
+	"postgresus-backend/internal/features/audit_logs"
 	"postgresus-backend/internal/features/databases/databases/postgresql"
 	"postgresus-backend/internal/features/notifiers"
+	user_enums "postgresus-backend/internal/features/users/enums"
 	users_models "postgresus-backend/internal/features/users/models"
+	"postgresus-backend/internal/features/workspaces"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// DatabaseService manages databases of workspaces. Each method
+// called on behalf of a user checks the user role in the workspace
 type DatabaseService struct {
-	dbRepository    *DatabaseRepository
-	notifierService *notifiers.NotifierService
-	logger          *slog.Logger
+	dbRepository     *DatabaseRepository
+	notifierService  *notifiers.NotifierService
+	workspaceService *workspaces.WorkspaceService
+	auditLogService  *audit_logs.AuditLogService
+	logger           *slog.Logger
 
 	dbCreationListener []DatabaseCreationListener
 	dbRemoveListener   []DatabaseRemoveListener
@@ -39,17 +47,45 @@ func (s *DatabaseService) AddDbCopyListener(
 	s.dbCopyListener = append(s.dbCopyListener, dbCopyListener)
 }
 
+func (s *DatabaseService) OnBeforeWorkspaceRemove(workspaceID uuid.UUID) error {
+	databases, err := s.dbRepository.FindByWorkspaceIDs([]uuid.UUID{workspaceID})
+	if err != nil {
+		return err
+	}
+
+	if len(databases) > 0 {
+		return errors.New("workspace has databases, remove them first")
+	}
+
+	return nil
+}
+
 func (s *DatabaseService) CreateDatabase(
 	user *users_models.User,
 	database *Database,
 ) (*Database, error) {
+	workspaceID, err := s.workspaceService.ResolveWorkspaceID(user, database.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.workspaceService.CheckAccess(user, workspaceID, user_enums.UserRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
 	database.UserID = user.ID
+	database.WorkspaceID = workspaceID
 
 	if err := database.Validate(); err != nil {
 		return nil, err
 	}
 
-	database, err := s.dbRepository.Save(database)
+	if err := s.validateNotifiers(user, database); err != nil {
+		return nil, err
+	}
+
+	database, err = s.dbRepository.Save(database)
 	if err != nil {
 		return nil, err
 	}
@@ -58,6 +94,15 @@ func (s *DatabaseService) CreateDatabase(
 		listener.OnDatabaseCreated(database.ID)
 	}
 
+	s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+		Action:     audit_logs.AuditLogActionCreate,
+		TargetType: audit_logs.AuditLogTargetTypeDatabase,
+		TargetID:   database.ID,
+		TargetName: database.Name,
+		Message:    fmt.Sprintf("Created database \"%s\"", database.Name),
+		Diff:       audit_logs.CalculateDiff(nil, database),
+	})
+
 	return database, nil
 }
 
@@ -69,29 +114,44 @@ func (s *DatabaseService) UpdateDatabase(
 		return errors.New("database ID is required for update")
 	}
 
-	existingDatabase, err := s.dbRepository.FindByID(database.ID)
+	existingDatabase, err := s.GetDatabaseWithRole(user, database.ID, user_enums.UserRoleAdmin)
 	if err != nil {
 		return err
 	}
 
-	if existingDatabase.UserID != user.ID {
-		return errors.New("you have not access to this database")
-	}
-
 	// Validate the update
 	if err := database.ValidateUpdate(*existingDatabase, *database); err != nil {
 		return err
 	}
 
+	// database cannot be moved to another workspace, because
+	// its backups are kept in storages of the workspace
+	database.WorkspaceID = existingDatabase.WorkspaceID
+
+	database.FillSensitiveData(existingDatabase)
+
 	if err := database.Validate(); err != nil {
 		return err
 	}
 
+	if err := s.validateNotifiers(user, database); err != nil {
+		return err
+	}
+
 	_, err = s.dbRepository.Save(database)
 	if err != nil {
 		return err
 	}
 
+	s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+		Action:     audit_logs.AuditLogActionUpdate,
+		TargetType: audit_logs.AuditLogTargetTypeDatabase,
+		TargetID:   database.ID,
+		TargetName: database.Name,
+		Message:    fmt.Sprintf("Updated database \"%s\"", database.Name),
+		Diff:       audit_logs.CalculateDiff(existingDatabase, database),
+	})
+
 	return nil
 }
 
@@ -99,44 +159,80 @@ func (s *DatabaseService) DeleteDatabase(
 	user *users_models.User,
 	id uuid.UUID,
 ) error {
-	existingDatabase, err := s.dbRepository.FindByID(id)
+	existingDatabase, err := s.GetDatabaseWithRole(user, id, user_enums.UserRoleAdmin)
 	if err != nil {
 		return err
 	}
 
-	if existingDatabase.UserID != user.ID {
-		return errors.New("you have not access to this database")
-	}
-
 	for _, listener := range s.dbRemoveListener {
 		if err := listener.OnBeforeDatabaseRemove(id); err != nil {
 			return err
 		}
 	}
 
-	return s.dbRepository.Delete(id)
+	if err := s.dbRepository.Delete(id); err != nil {
+		return err
+	}
+
+	s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+		Action:     audit_logs.AuditLogActionDelete,
+		TargetType: audit_logs.AuditLogTargetTypeDatabase,
+		TargetID:   existingDatabase.ID,
+		TargetName: existingDatabase.Name,
+		Message:    fmt.Sprintf("Deleted database \"%s\"", existingDatabase.Name),
+		Diff:       audit_logs.CalculateDiff(existingDatabase, nil),
+	})
+
+	return nil
 }
 
 func (s *DatabaseService) GetDatabase(
 	user *users_models.User,
 	id uuid.UUID,
+) (*Database, error) {
+	return s.GetDatabaseWithRole(user, id, user_enums.UserRoleViewer)
+}
+
+// GetDatabaseWithRole returns the database if the user has at least
+// the given role in the workspace of the database
+func (s *DatabaseService) GetDatabaseWithRole(
+	user *users_models.User,
+	id uuid.UUID,
+	role user_enums.UserRole,
 ) (*Database, error) {
 	database, err := s.dbRepository.FindByID(id)
 	if err != nil {
 		return nil, err
 	}
 
-	if database.UserID != user.ID {
-		return nil, errors.New("you have not access to this database")
+	if err := s.workspaceService.CheckAccess(user, database.WorkspaceID, role); err != nil {
+		return nil, err
 	}
 
 	return database, nil
 }
 
-func (s *DatabaseService) GetDatabasesByUser(
+// GetDatabases returns databases of the workspace or, if workspace
+// is not specified, databases of all workspaces available to the user
+func (s *DatabaseService) GetDatabases(
 	user *users_models.User,
+	workspaceID *uuid.UUID,
 ) ([]*Database, error) {
-	return s.dbRepository.FindByUserID(user.ID)
+	if workspaceID != nil {
+		err := s.workspaceService.CheckAccess(user, *workspaceID, user_enums.UserRoleViewer)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.dbRepository.FindByWorkspaceIDs([]uuid.UUID{*workspaceID})
+	}
+
+	workspaceIDs, err := s.workspaceService.GetWorkspaceIDs(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.dbRepository.FindByWorkspaceIDs(workspaceIDs)
 }
 
 func (s *DatabaseService) IsNotifierUsing(
@@ -155,15 +251,11 @@ func (s *DatabaseService) TestDatabaseConnection(
 	user *users_models.User,
 	databaseID uuid.UUID,
 ) error {
-	database, err := s.dbRepository.FindByID(databaseID)
+	database, err := s.GetDatabaseWithRole(user, databaseID, user_enums.UserRoleOperator)
 	if err != nil {
 		return err
 	}
 
-	if database.UserID != user.ID {
-		return errors.New("you have not access to this database")
-	}
-
 	err = database.TestConnection(s.logger)
 	if err != nil {
 		lastSaveError := err.Error()
@@ -181,6 +273,34 @@ func (s *DatabaseService) TestDatabaseConnection(
 	return nil
 }
 
+func (s *DatabaseService) TestDatabaseConnectionDirectWithAuth(
+	user *users_models.User,
+	database *Database,
+) error {
+	workspaceID, err := s.workspaceService.ResolveWorkspaceID(user, database.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	err = s.workspaceService.CheckAccess(user, workspaceID, user_enums.UserRoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	// password of the saved database is not returned to the client, so
+	// the test of edited database uses it if it is not changed
+	if database.ID != uuid.Nil {
+		existingDatabase, err := s.GetDatabaseWithRole(user, database.ID, user_enums.UserRoleAdmin)
+		if err != nil {
+			return err
+		}
+
+		database.FillSensitiveData(existingDatabase)
+	}
+
+	return s.TestDatabaseConnectionDirect(database)
+}
+
 func (s *DatabaseService) TestDatabaseConnectionDirect(
 	database *Database,
 ) error {
@@ -232,18 +352,15 @@ func (s *DatabaseService) CopyDatabase(
 	user *users_models.User,
 	databaseID uuid.UUID,
 ) (*Database, error) {
-	existingDatabase, err := s.dbRepository.FindByID(databaseID)
+	existingDatabase, err := s.GetDatabaseWithRole(user, databaseID, user_enums.UserRoleAdmin)
 	if err != nil {
 		return nil, err
 	}
 
-	if existingDatabase.UserID != user.ID {
-		return nil, errors.New("you have not access to this database")
-	}
-
 	newDatabase := &Database{
 		ID:                     uuid.Nil,
 		UserID:                 user.ID,
+		WorkspaceID:            existingDatabase.WorkspaceID,
 		Name:                   existingDatabase.Name + " (Copy)",
 		Type:                   existingDatabase.Type,
 		Notifiers:              existingDatabase.Notifiers,
@@ -286,6 +403,15 @@ func (s *DatabaseService) CopyDatabase(
 		listener.OnDatabaseCopied(databaseID, copiedDatabase.ID)
 	}
 
+	s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+		Action:     audit_logs.AuditLogActionCopy,
+		TargetType: audit_logs.AuditLogTargetTypeDatabase,
+		TargetID:   copiedDatabase.ID,
+		TargetName: copiedDatabase.Name,
+		Message:    fmt.Sprintf("Copied database \"%s\"", existingDatabase.Name),
+		Diff:       audit_logs.CalculateDiff(nil, copiedDatabase),
+	})
+
 	return copiedDatabase, nil
 }
 
@@ -306,3 +432,23 @@ func (s *DatabaseService) SetHealthStatus(
 
 	return nil
 }
+
+// validateNotifiers checks notifiers of the database belong to the
+// same workspace, so alerts are not sent to notifiers of other teams
+func (s *DatabaseService) validateNotifiers(
+	user *users_models.User,
+	database *Database,
+) error {
+	for _, databaseNotifier := range database.Notifiers {
+		notifier, err := s.notifierService.GetNotifier(user, databaseNotifier.ID)
+		if err != nil {
+			return err
+		}
+
+		if notifier.WorkspaceID != database.WorkspaceID {
+			return fmt.Errorf("notifier \"%s\" belongs to another workspace", notifier.Name)
+		}
+	}
+
+	return nil
+}
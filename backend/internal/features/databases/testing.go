@@ -4,6 +4,7 @@ import (
 	"postgresus-backend/internal/features/databases/databases/postgresql"
 	"postgresus-backend/internal/features/notifiers"
 	"postgresus-backend/internal/features/storages"
+	"postgresus-backend/internal/features/workspaces"
 	"postgresus-backend/internal/util/tools"
 
 	"github.com/google/uuid"
@@ -15,9 +16,10 @@ func CreateTestDatabase(
 	notifier *notifiers.Notifier,
 ) *Database {
 	database := &Database{
-		UserID: userID,
-		Name:   "test " + uuid.New().String(),
-		Type:   DatabaseTypePostgres,
+		UserID:      userID,
+		WorkspaceID: workspaces.GetTestWorkspace().ID,
+		Name:        "test " + uuid.New().String(),
+		Type:        DatabaseTypePostgres,
 
 		Postgresql: &postgresql.PostgresqlDatabase{
 			Version:  tools.PostgresqlVersion16,
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"postgresus-backend/internal/features/databases"
 	healthcheck_config "postgresus-backend/internal/features/healthcheck/config"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
 	"postgresus-backend/internal/util/logger"
 	"time"
 
@@ -220,23 +221,25 @@ func (uc *CheckPgHealthUseCase) sendDbStatusNotification(
 		return
 	}
 
-	messageTitle := ""
-	messageBody := ""
+	event := &notifier_events.NotificationEvent{
+		DatabaseID:   &database.ID,
+		DatabaseName: database.Name,
+		CreatedAt:    time.Now().UTC(),
+	}
 
 	if newHealthStatus == databases.HealthStatusAvailable {
-		messageTitle = fmt.Sprintf("✅ [%s] DB is online", database.Name)
-		messageBody = fmt.Sprintf("✅ [%s] DB is back online", database.Name)
+		event.Type = notifier_events.EventTypeDatabaseAvailable
+		event.Severity = notifier_events.SeveritySuccess
+		event.Title = fmt.Sprintf("✅ [%s] DB is online", database.Name)
+		event.Message = fmt.Sprintf("✅ [%s] DB is back online", database.Name)
 	} else {
-		messageTitle = fmt.Sprintf("❌ [%s] DB is unavailable", database.Name)
-		messageBody = fmt.Sprintf("❌ [%s] DB is currently unavailable", database.Name)
+		event.Type = notifier_events.EventTypeDatabaseUnavailable
+		event.Severity = notifier_events.SeverityCritical
+		event.Title = fmt.Sprintf("❌ [%s] DB is unavailable", database.Name)
+		event.Message = fmt.Sprintf("❌ [%s] DB is currently unavailable", database.Name)
 	}
 
 	for _, notifier := range database.Notifiers {
-		uc.healthcheckAttemptSender.SendNotification(
-			&notifier,
-			messageTitle,
-			messageBody,
-		)
+		uc.healthcheckAttemptSender.SendNotification(&notifier, event)
 	}
-
 }
@@ -9,6 +9,7 @@ import (
 	"postgresus-backend/internal/features/databases"
 	healthcheck_config "postgresus-backend/internal/features/healthcheck/config"
 	"postgresus-backend/internal/features/notifiers"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
 	"postgresus-backend/internal/features/storages"
 	"postgresus-backend/internal/features/users"
 
@@ -31,7 +32,7 @@ func Test_CheckPgHealthUseCase(t *testing.T) {
 
 		// Setup mock notifier sender
 		mockSender := &MockHealthcheckAttemptSender{}
-		mockSender.On("SendNotification", mock.Anything, mock.Anything, mock.Anything).Return()
+		mockSender.On("SendNotification", mock.Anything, mock.Anything).Return()
 
 		// Setup mock database service
 		mockDatabaseService := &MockDatabaseService{}
@@ -86,8 +87,10 @@ func Test_CheckPgHealthUseCase(t *testing.T) {
 			t,
 			"SendNotification",
 			mock.Anything,
-			fmt.Sprintf("❌ [%s] DB is unavailable", database.Name),
-			fmt.Sprintf("❌ [%s] DB is currently unavailable", database.Name),
+			mock.MatchedBy(func(event *notifier_events.NotificationEvent) bool {
+				return event.Title == fmt.Sprintf("❌ [%s] DB is unavailable", database.Name) &&
+					event.Message == fmt.Sprintf("❌ [%s] DB is currently unavailable", database.Name)
+			}),
 		)
 	})
 
@@ -151,8 +154,10 @@ func Test_CheckPgHealthUseCase(t *testing.T) {
 				t,
 				"SendNotification",
 				mock.Anything,
-				fmt.Sprintf("❌ [%s] DB is unavailable", database.Name),
-				fmt.Sprintf("❌ [%s] DB is currently unavailable", database.Name),
+				mock.MatchedBy(func(event *notifier_events.NotificationEvent) bool {
+					return event.Title == fmt.Sprintf("❌ [%s] DB is unavailable", database.Name) &&
+						event.Message == fmt.Sprintf("❌ [%s] DB is currently unavailable", database.Name)
+				}),
 			)
 		},
 	)
@@ -172,7 +177,7 @@ func Test_CheckPgHealthUseCase(t *testing.T) {
 
 			// Setup mock notifier sender
 			mockSender := &MockHealthcheckAttemptSender{}
-			mockSender.On("SendNotification", mock.Anything, mock.Anything, mock.Anything).Return()
+			mockSender.On("SendNotification", mock.Anything, mock.Anything).Return()
 
 			// Setup mock database service
 			mockDatabaseService := &MockDatabaseService{}
@@ -230,8 +235,10 @@ func Test_CheckPgHealthUseCase(t *testing.T) {
 				t,
 				"SendNotification",
 				mock.Anything,
-				fmt.Sprintf("❌ [%s] DB is unavailable", database.Name),
-				fmt.Sprintf("❌ [%s] DB is currently unavailable", database.Name),
+				mock.MatchedBy(func(event *notifier_events.NotificationEvent) bool {
+					return event.Title == fmt.Sprintf("❌ [%s] DB is unavailable", database.Name) &&
+						event.Message == fmt.Sprintf("❌ [%s] DB is currently unavailable", database.Name)
+				}),
 			)
 		},
 	)
@@ -249,7 +256,7 @@ func Test_CheckPgHealthUseCase(t *testing.T) {
 
 		// Setup mock notifier sender
 		mockSender := &MockHealthcheckAttemptSender{}
-		mockSender.On("SendNotification", mock.Anything, mock.Anything, mock.Anything).Return()
+		mockSender.On("SendNotification", mock.Anything, mock.Anything).Return()
 
 		// Setup mock database service - connection succeeds
 		mockDatabaseService := &MockDatabaseService{}
@@ -303,8 +310,10 @@ func Test_CheckPgHealthUseCase(t *testing.T) {
 			t,
 			"SendNotification",
 			mock.Anything,
-			fmt.Sprintf("✅ [%s] DB is online", database.Name),
-			fmt.Sprintf("✅ [%s] DB is back online", database.Name),
+			mock.MatchedBy(func(event *notifier_events.NotificationEvent) bool {
+				return event.Title == fmt.Sprintf("✅ [%s] DB is online", database.Name) &&
+					event.Message == fmt.Sprintf("✅ [%s] DB is back online", database.Name)
+			}),
 		)
 	})
 
@@ -316,7 +325,7 @@ func Test_CheckPgHealthUseCase(t *testing.T) {
 
 			// Setup mock notifier sender
 			mockSender := &MockHealthcheckAttemptSender{}
-			mockSender.On("SendNotification", mock.Anything, mock.Anything, mock.Anything).Return()
+			mockSender.On("SendNotification", mock.Anything, mock.Anything).Return()
 
 			// Setup mock database service - connection succeeds
 			mockDatabaseService := &MockDatabaseService{}
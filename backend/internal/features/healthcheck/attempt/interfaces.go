@@ -3,6 +3,7 @@ package healthcheck_attempt
 import (
 	"postgresus-backend/internal/features/databases"
 	"postgresus-backend/internal/features/notifiers"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
 
 	"github.com/google/uuid"
 )
@@ -10,8 +11,7 @@ import (
 type HealthcheckAttemptSender interface {
 	SendNotification(
 		notifier *notifiers.Notifier,
-		title string,
-		message string,
+		event *notifier_events.NotificationEvent,
 	)
 }
 
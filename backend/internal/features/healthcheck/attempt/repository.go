@@ -100,3 +100,22 @@ func (r *HealthcheckAttemptRepository) CountByDatabaseID(
 
 	return count, nil
 }
+
+// FindByDatabaseIDInPeriod returns attempts made in [from, to)
+func (r *HealthcheckAttemptRepository) FindByDatabaseIDInPeriod(
+	databaseID uuid.UUID,
+	from time.Time,
+	to time.Time,
+) ([]*HealthcheckAttempt, error) {
+	var attempts []*HealthcheckAttempt
+
+	if err := storage.
+		GetDb().
+		Where("database_id = ? AND created_at >= ? AND created_at < ?", databaseID, from, to).
+		Order("created_at ASC").
+		Find(&attempts).Error; err != nil {
+		return nil, err
+	}
+
+	return attempts, nil
+}
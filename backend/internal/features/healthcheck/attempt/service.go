@@ -1,7 +1,6 @@
 package healthcheck_attempt
 
 import (
-	"errors"
 	"postgresus-backend/internal/features/databases"
 	users_models "postgresus-backend/internal/features/users/models"
 	"time"
@@ -19,17 +18,23 @@ func (s *HealthcheckAttemptService) GetAttemptsByDatabase(
 	databaseID uuid.UUID,
 	afterDate time.Time,
 ) ([]*HealthcheckAttempt, error) {
-	database, err := s.databaseService.GetDatabaseByID(databaseID)
+	_, err := s.databaseService.GetDatabase(&user, databaseID)
 	if err != nil {
 		return nil, err
 	}
 
-	if database.UserID != user.ID {
-		return nil, errors.New("forbidden")
-	}
-
 	return s.healthcheckAttemptRepository.FindByDatabaseIdOrderByCreatedAtDesc(
 		databaseID,
 		afterDate,
 	)
 }
+
+// GetAttemptsForPeriod returns attempts of the database for reports,
+// access is not checked
+func (s *HealthcheckAttemptService) GetAttemptsForPeriod(
+	databaseID uuid.UUID,
+	from time.Time,
+	to time.Time,
+) ([]*HealthcheckAttempt, error) {
+	return s.healthcheckAttemptRepository.FindByDatabaseIDInPeriod(databaseID, from, to)
+}
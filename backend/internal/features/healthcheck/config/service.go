@@ -1,9 +1,9 @@
 package healthcheck_config
 
 import (
-	"errors"
 	"log/slog"
 	"postgresus-backend/internal/features/databases"
+	user_enums "postgresus-backend/internal/features/users/enums"
 	users_models "postgresus-backend/internal/features/users/models"
 
 	"github.com/google/uuid"
@@ -28,15 +28,15 @@ func (s *HealthcheckConfigService) Save(
 	user users_models.User,
 	configDTO HealthcheckConfigDTO,
 ) error {
-	database, err := s.databaseService.GetDatabaseByID(configDTO.DatabaseID)
+	database, err := s.databaseService.GetDatabaseWithRole(
+		&user,
+		configDTO.DatabaseID,
+		user_enums.UserRoleAdmin,
+	)
 	if err != nil {
 		return err
 	}
 
-	if database.UserID != user.ID {
-		return errors.New("user does not have access to this database")
-	}
-
 	healthcheckConfig := configDTO.ToDTO()
 	s.logger.Info("healthcheck config", "config", healthcheckConfig)
 
@@ -67,15 +67,11 @@ func (s *HealthcheckConfigService) GetByDatabaseID(
 	user users_models.User,
 	databaseID uuid.UUID,
 ) (*HealthcheckConfig, error) {
-	database, err := s.databaseService.GetDatabaseByID(databaseID)
+	database, err := s.databaseService.GetDatabase(&user, databaseID)
 	if err != nil {
 		return nil, err
 	}
 
-	if database.UserID != user.ID {
-		return nil, errors.New("user does not have access to this database")
-	}
-
 	config, err := s.healthcheckConfigRepository.GetByDatabaseID(database.ID)
 	if err != nil {
 		return nil, err
@@ -43,6 +43,23 @@ func (i *Interval) Validate() error {
 	return nil
 }
 
+// GetMaxDuration returns the longest time between two scheduled
+// backups, e.g. 31 days for monthly backups
+func (i *Interval) GetMaxDuration() time.Duration {
+	switch i.Interval {
+	case IntervalHourly:
+		return time.Hour
+	case IntervalDaily:
+		return 24 * time.Hour
+	case IntervalWeekly:
+		return 7 * 24 * time.Hour
+	case IntervalMonthly:
+		return 31 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
 // ShouldTriggerBackup checks if a backup should be triggered based on the interval and last backup time
 func (i *Interval) ShouldTriggerBackup(now time.Time, lastBackupTime *time.Time) bool {
 	// If no backup has been made yet, trigger immediately
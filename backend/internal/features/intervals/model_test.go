@@ -527,3 +527,10 @@ func TestInterval_Validate(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestInterval_GetMaxDuration(t *testing.T) {
+	assert.Equal(t, time.Hour, (&Interval{Interval: IntervalHourly}).GetMaxDuration())
+	assert.Equal(t, 24*time.Hour, (&Interval{Interval: IntervalDaily}).GetMaxDuration())
+	assert.Equal(t, 7*24*time.Hour, (&Interval{Interval: IntervalWeekly}).GetMaxDuration())
+	assert.Equal(t, 31*24*time.Hour, (&Interval{Interval: IntervalMonthly}).GetMaxDuration())
+}
@@ -179,13 +179,22 @@ func (s *DbMonitoringBackgroundService) connectToDatabase(
 		return nil, nil
 	}
 
-	connStr := s.buildConnectionString(db.Postgresql)
+	connStr, err := s.buildConnectionString(db.Postgresql)
+	if err != nil {
+		return nil, err
+	}
+
 	return pgx.Connect(ctx, connStr)
 }
 
 func (s *DbMonitoringBackgroundService) buildConnectionString(
 	pg *postgresql.PostgresqlDatabase,
-) string {
+) (string, error) {
+	password, err := pg.ResolvePassword()
+	if err != nil {
+		return "", err
+	}
+
 	sslMode := "disable"
 	if pg.IsHttps {
 		sslMode = "require"
@@ -195,10 +204,10 @@ func (s *DbMonitoringBackgroundService) buildConnectionString(
 		pg.Host,
 		pg.Port,
 		pg.Username,
-		pg.Password,
+		password,
 		*pg.Database,
 		sslMode,
-	)
+	), nil
 }
 
 func (s *DbMonitoringBackgroundService) collectDatabaseResourceMetrics(
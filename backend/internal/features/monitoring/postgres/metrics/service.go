@@ -1,7 +1,6 @@
 package postgres_monitoring_metrics
 
 import (
-	"errors"
 	"postgresus-backend/internal/features/databases"
 	users_models "postgresus-backend/internal/features/users/models"
 	"time"
@@ -29,14 +28,10 @@ func (s *PostgresMonitoringMetricService) GetMetrics(
 	from time.Time,
 	to time.Time,
 ) ([]PostgresMonitoringMetric, error) {
-	database, err := s.databaseService.GetDatabaseByID(databaseID)
+	_, err := s.databaseService.GetDatabase(user, databaseID)
 	if err != nil {
 		return nil, err
 	}
 
-	if database.UserID != user.ID {
-		return nil, errors.New("database not found")
-	}
-
 	return s.metricsRepository.GetByMetrics(databaseID, metricType, from, to)
 }
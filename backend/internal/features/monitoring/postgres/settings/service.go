@@ -3,6 +3,7 @@ package postgres_monitoring_settings
 import (
 	"errors"
 	"postgresus-backend/internal/features/databases"
+	user_enums "postgresus-backend/internal/features/users/enums"
 	users_models "postgresus-backend/internal/features/users/models"
 	"postgresus-backend/internal/util/logger"
 
@@ -42,15 +43,15 @@ func (s *PostgresMonitoringSettingsService) Save(
 	user *users_models.User,
 	settings *PostgresMonitoringSettings,
 ) error {
-	db, err := s.databaseService.GetDatabaseByID(settings.DatabaseID)
+	_, err := s.databaseService.GetDatabaseWithRole(
+		user,
+		settings.DatabaseID,
+		user_enums.UserRoleAdmin,
+	)
 	if err != nil {
 		return err
 	}
 
-	if db.UserID != user.ID {
-		return errors.New("user does not have access to this database")
-	}
-
 	return s.postgresMonitoringSettingsRepository.Save(settings)
 }
 
@@ -58,6 +59,11 @@ func (s *PostgresMonitoringSettingsService) GetByDbID(
 	user *users_models.User,
 	dbID uuid.UUID,
 ) (*PostgresMonitoringSettings, error) {
+	_, err := s.databaseService.GetDatabase(user, dbID)
+	if err != nil {
+		return nil, err
+	}
+
 	dbSettings, err := s.postgresMonitoringSettingsRepository.GetByDbIDWithRelations(dbID)
 	if err != nil {
 		return nil, err
@@ -78,10 +84,6 @@ func (s *PostgresMonitoringSettingsService) GetByDbID(
 		return s.GetByDbID(user, dbID)
 	}
 
-	if dbSettings.Database.UserID != user.ID {
-		return nil, errors.New("user does not have access to this database")
-	}
-
 	return dbSettings, nil
 }
 
@@ -0,0 +1,101 @@
+package notifiers
+
+import (
+	"log/slog"
+	"postgresus-backend/internal/config"
+	"time"
+)
+
+const (
+	deliveriesBatchSize     = 50
+	deliveriesHistoryPeriod = 30 * 24 * time.Hour
+	deliveriesCleanupPeriod = 24 * time.Hour
+	deliveriesPollingPeriod = 5 * time.Second
+)
+
+// NotificationDeliveryBackgroundService sends notifications from the
+// outbox and removes old history
+type NotificationDeliveryBackgroundService struct {
+	notifierService                *NotifierService
+	notificationDeliveryRepository *NotificationDeliveryRepository
+
+	lastCleanupTime time.Time
+	logger          *slog.Logger
+}
+
+func (s *NotificationDeliveryBackgroundService) Run() {
+	if err := s.resetDeliveriesInSending(); err != nil {
+		s.logger.Error("Failed to reset notification deliveries in sending", "error", err)
+		panic(err)
+	}
+
+	for {
+		if config.IsShouldShutdown() {
+			return
+		}
+
+		if err := s.sendDueDeliveries(); err != nil {
+			s.logger.Error("Failed to send notifications", "error", err)
+		}
+
+		if time.Since(s.lastCleanupTime) > deliveriesCleanupPeriod {
+			before := time.Now().UTC().Add(-deliveriesHistoryPeriod)
+			if err := s.notificationDeliveryRepository.DeleteFinishedBefore(before); err != nil {
+				s.logger.Error("Failed to remove old notification deliveries", "error", err)
+			}
+
+			s.lastCleanupTime = time.Now().UTC()
+		}
+
+		time.Sleep(deliveriesPollingPeriod)
+	}
+}
+
+func (s *NotificationDeliveryBackgroundService) sendDueDeliveries() error {
+	deliveries, err := s.notificationDeliveryRepository.FindDue(
+		time.Now().UTC(),
+		deliveriesBatchSize,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		if config.IsShouldShutdown() {
+			return nil
+		}
+
+		if err := s.notifierService.deliver(delivery); err != nil {
+			s.logger.Error(
+				"Failed to save notification delivery",
+				"deliveryId", delivery.ID,
+				"error", err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// resetDeliveriesInSending returns notifications interrupted by
+// restart to the queue. The channel may have received them already,
+// but duplicate is better than lost notification
+func (s *NotificationDeliveryBackgroundService) resetDeliveriesInSending() error {
+	deliveries, err := s.notificationDeliveryRepository.FindByStatus(
+		NotificationDeliveryStatusSending,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		delivery.Status = NotificationDeliveryStatusPending
+		delivery.NextAttemptAt = time.Now().UTC()
+
+		if err := s.notificationDeliveryRepository.Save(delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -3,6 +3,8 @@ package notifiers
 import (
 	"net/http"
 	"postgresus-backend/internal/features/users"
+	user_enums "postgresus-backend/internal/features/users/enums"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -17,9 +19,11 @@ func (c *NotifierController) RegisterRoutes(router *gin.RouterGroup) {
 	router.POST("/notifiers", c.SaveNotifier)
 	router.GET("/notifiers", c.GetNotifiers)
 	router.GET("/notifiers/:id", c.GetNotifier)
+	router.GET("/notifiers/:id/deliveries", c.GetDeliveries)
 	router.DELETE("/notifiers/:id", c.DeleteNotifier)
 	router.POST("/notifiers/:id/test", c.SendTestNotification)
 	router.POST("/notifiers/direct-test", c.SendTestNotificationDirect)
+	router.POST("/notifiers/templates/preview", c.PreviewTemplate)
 }
 
 // SaveNotifier
@@ -47,16 +51,12 @@ func (c *NotifierController) SaveNotifier(ctx *gin.Context) {
 		return
 	}
 
-	if err := notifier.Validate(); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
 	if err := c.notifierService.SaveNotifier(user, &notifier); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	notifier.HideSensitiveData()
 	ctx.JSON(http.StatusOK, notifier)
 }
 
@@ -90,16 +90,19 @@ func (c *NotifierController) GetNotifier(ctx *gin.Context) {
 		return
 	}
 
+	notifier.HideSensitiveData()
 	ctx.JSON(http.StatusOK, notifier)
 }
 
 // GetNotifiers
 // @Summary Get all notifiers
-// @Description Get all notifiers for the current user
+// @Description Get notifiers of workspaces available to the current user
 // @Tags notifiers
 // @Produce json
 // @Param Authorization header string true "JWT token"
+// @Param workspace_id query string false "Workspace ID, all available workspaces if empty"
 // @Success 200 {array} Notifier
+// @Failure 400
 // @Failure 401
 // @Router /notifiers [get]
 func (c *NotifierController) GetNotifiers(ctx *gin.Context) {
@@ -109,15 +112,76 @@ func (c *NotifierController) GetNotifiers(ctx *gin.Context) {
 		return
 	}
 
-	notifiers, err := c.notifierService.GetNotifiers(user)
+	var workspaceID *uuid.UUID
+	if workspaceIDStr := ctx.Query("workspace_id"); workspaceIDStr != "" {
+		parsedWorkspaceID, err := uuid.Parse(workspaceIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid workspace_id"})
+			return
+		}
+		workspaceID = &parsedWorkspaceID
+	}
+
+	notifiers, err := c.notifierService.GetNotifiers(user, workspaceID)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	for _, notifier := range notifiers {
+		notifier.HideSensitiveData()
+	}
+
 	ctx.JSON(http.StatusOK, notifiers)
 }
 
+// GetDeliveries
+// @Summary Get delivery history of a notifier
+// @Description Get notifications of the notifier with their payload, attempts and status, the newest first
+// @Tags notifiers
+// @Produce json
+// @Param Authorization header string true "JWT token"
+// @Param id path string true "Notifier ID"
+// @Param limit query int false "Limit, 50 by default"
+// @Param offset query int false "Offset"
+// @Success 200 {object} GetNotificationDeliveriesResponse
+// @Failure 400
+// @Failure 401
+// @Router /notifiers/{id}/deliveries [get]
+func (c *NotifierController) GetDeliveries(ctx *gin.Context) {
+	user, err := c.userService.GetUserFromToken(ctx.GetHeader("Authorization"))
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid notifier ID"})
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "0"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+
+	offset, err := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
+		return
+	}
+
+	response, err := c.notifierService.GetDeliveries(user, id, limit, offset)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
 // DeleteNotifier
 // @Summary Delete a notifier
 // @Description Delete a notifier by ID
@@ -216,10 +280,50 @@ func (c *NotifierController) SendTestNotificationDirect(ctx *gin.Context) {
 	// For direct test, associate with the current user
 	notifier.UserID = user.ID
 
-	if err := c.notifierService.SendTestNotificationToNotifier(&notifier); err != nil {
+	if err := c.notifierService.SendTestNotificationToNotifier(user, &notifier); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{"message": "test notification sent successfully"})
 }
+
+// PreviewTemplate
+// @Summary Preview notification template
+// @Description Render title and body templates against a sample backup of the event type, operators and admins only
+// @Tags notifiers
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "JWT token"
+// @Param request body PreviewTemplateRequest true "Templates to render"
+// @Success 200 {object} PreviewTemplateResponse
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /notifiers/templates/preview [post]
+func (c *NotifierController) PreviewTemplate(ctx *gin.Context) {
+	user, err := c.userService.GetUserFromToken(ctx.GetHeader("Authorization"))
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !user.Role.IsAtLeast(user_enums.UserRoleOperator) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+		return
+	}
+
+	var request PreviewTemplateRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	preview, err := c.notifierService.PreviewTemplate(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preview)
+}
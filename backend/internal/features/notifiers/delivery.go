@@ -0,0 +1,145 @@
+package notifiers
+
+import (
+	"encoding/json"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	maxDeliveryAttempts       = 8
+	initialDeliveryRetryDelay = 30 * time.Second
+	maxDeliveryRetryDelay     = 1 * time.Hour
+)
+
+// NotificationDelivery is the notification waiting in the outbox or
+// already sent. It is retried with exponential backoff, so outage of
+// the channel delays the notification instead of losing it
+type NotificationDelivery struct {
+	ID         uuid.UUID                  `json:"id"         gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
+	NotifierID uuid.UUID                  `json:"notifierId" gorm:"column:notifier_id;type:uuid;not null"`
+	EventType  notifier_events.EventType  `json:"eventType"  gorm:"column:event_type;type:varchar(50);not null"`
+	Status     NotificationDeliveryStatus `json:"status"     gorm:"column:status;type:varchar(20);not null"`
+
+	// IncidentKey links deliveries which open and resolve the same
+	// incident, see NotificationEvent.GetIncidentKey
+	IncidentKey string `json:"-" gorm:"column:incident_key;type:text;not null"`
+
+	Event       *notifier_events.NotificationEvent `json:"event" gorm:"-"`
+	EventString string                             `json:"-"     gorm:"column:event;type:text;not null"`
+
+	Attempts       []NotificationDeliveryAttempt `json:"attempts"      gorm:"-"`
+	AttemptsString string                        `json:"-"             gorm:"column:attempts;type:text;not null"`
+	AttemptsCount  int                           `json:"attemptsCount" gorm:"column:attempts_count;type:int;not null"`
+
+	NextAttemptAt time.Time  `json:"nextAttemptAt" gorm:"column:next_attempt_at;not null"`
+	CreatedAt     time.Time  `json:"createdAt"     gorm:"column:created_at;not null"`
+	DeliveredAt   *time.Time `json:"deliveredAt"   gorm:"column:delivered_at"`
+}
+
+type NotificationDeliveryAttempt struct {
+	AttemptedAt time.Time `json:"attemptedAt"`
+	DurationMs  int64     `json:"durationMs"`
+	Error       *string   `json:"error,omitempty"`
+}
+
+func (d *NotificationDelivery) TableName() string {
+	return "notification_deliveries"
+}
+
+func (d *NotificationDelivery) BeforeSave(tx *gorm.DB) error {
+	event, err := json.Marshal(d.Event)
+	if err != nil {
+		return err
+	}
+
+	attempts, err := json.Marshal(d.Attempts)
+	if err != nil {
+		return err
+	}
+
+	d.EventString = string(event)
+	if d.IncidentKey == "" && d.Event != nil {
+		d.IncidentKey = d.Event.GetIncidentKey()
+	}
+
+	d.AttemptsString = string(attempts)
+	d.AttemptsCount = len(d.Attempts)
+
+	return nil
+}
+
+// IsIncidentTrigger returns true if the delivery opens an incident,
+// so the incident tool expects the event which resolves it
+func (d *NotificationDelivery) IsIncidentTrigger() bool {
+	return d.Event != nil &&
+		d.Event.GetIncidentAction() == notifier_events.IncidentActionTrigger
+}
+
+func (d *NotificationDelivery) AfterFind(tx *gorm.DB) error {
+	d.Event = &notifier_events.NotificationEvent{}
+	if err := json.Unmarshal([]byte(d.EventString), d.Event); err != nil {
+		return err
+	}
+
+	d.Attempts = []NotificationDeliveryAttempt{}
+	if d.AttemptsString == "" {
+		return nil
+	}
+
+	return json.Unmarshal([]byte(d.AttemptsString), &d.Attempts)
+}
+
+// RecordAttempt saves result of the attempt and decides what is next:
+// the delivery is done on success, otherwise it is retried with
+// exponential backoff until attempts are over
+func (d *NotificationDelivery) RecordAttempt(
+	attemptedAt time.Time,
+	duration time.Duration,
+	sendErr error,
+) {
+	attempt := NotificationDeliveryAttempt{
+		AttemptedAt: attemptedAt,
+		DurationMs:  duration.Milliseconds(),
+	}
+
+	if sendErr == nil {
+		d.Attempts = append(d.Attempts, attempt)
+		d.Status = NotificationDeliveryStatusDelivered
+
+		deliveredAt := attemptedAt.Add(duration)
+		d.DeliveredAt = &deliveredAt
+		return
+	}
+
+	errorMessage := sendErr.Error()
+	attempt.Error = &errorMessage
+	d.Attempts = append(d.Attempts, attempt)
+
+	if len(d.Attempts) >= maxDeliveryAttempts {
+		d.Status = NotificationDeliveryStatusFailed
+		return
+	}
+
+	d.Status = NotificationDeliveryStatusPending
+	d.NextAttemptAt = attemptedAt.Add(duration).Add(getDeliveryRetryDelay(len(d.Attempts)))
+}
+
+// getDeliveryRetryDelay returns 30s, 1m, 2m... up to an hour, so all
+// attempts take about an hour
+func getDeliveryRetryDelay(attemptsCount int) time.Duration {
+	delay := initialDeliveryRetryDelay
+
+	for i := 1; i < attemptsCount; i++ {
+		delay *= 2
+
+		if delay >= maxDeliveryRetryDelay {
+			return maxDeliveryRetryDelay
+		}
+	}
+
+	return delay
+}
@@ -0,0 +1,143 @@
+package notifiers
+
+import (
+	"postgresus-backend/internal/storage"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationDeliveryRepository struct{}
+
+func (r *NotificationDeliveryRepository) Save(delivery *NotificationDelivery) error {
+	db := storage.GetDb()
+
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+		return db.Create(delivery).Error
+	}
+
+	return db.Save(delivery).Error
+}
+
+// FindDue returns pending deliveries whose next attempt time has come,
+// the oldest first. Delivery waits while the older one of the same
+// notifier and incident is not sent, otherwise retried trigger could
+// reopen the incident resolved by the newer delivery
+func (r *NotificationDeliveryRepository) FindDue(
+	now time.Time,
+	limit int,
+) ([]*NotificationDelivery, error) {
+	var deliveries []*NotificationDelivery
+
+	if err := storage.
+		GetDb().
+		Where("status = ? AND next_attempt_at <= ?", NotificationDeliveryStatusPending, now).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM notification_deliveries older
+			WHERE older.notifier_id = notification_deliveries.notifier_id
+				AND older.incident_key = notification_deliveries.incident_key
+				AND older.incident_key <> ''
+				AND older.status IN ?
+				AND older.created_at < notification_deliveries.created_at
+		)`, []NotificationDeliveryStatus{
+			NotificationDeliveryStatusPending,
+			NotificationDeliveryStatusSending,
+		}).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// FindLastByIncidentKey returns the latest delivery of the notifier
+// for the incident or nil if there is no one
+func (r *NotificationDeliveryRepository) FindLastByIncidentKey(
+	notifierID uuid.UUID,
+	incidentKey string,
+) (*NotificationDelivery, error) {
+	var deliveries []*NotificationDelivery
+
+	if err := storage.
+		GetDb().
+		Where("notifier_id = ? AND incident_key = ?", notifierID, incidentKey).
+		Order("created_at DESC").
+		Limit(1).
+		Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+
+	if len(deliveries) == 0 {
+		return nil, nil
+	}
+
+	return deliveries[0], nil
+}
+
+func (r *NotificationDeliveryRepository) FindByStatus(
+	status NotificationDeliveryStatus,
+) ([]*NotificationDelivery, error) {
+	var deliveries []*NotificationDelivery
+
+	if err := storage.
+		GetDb().
+		Where("status = ?", status).
+		Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (r *NotificationDeliveryRepository) FindByNotifierID(
+	notifierID uuid.UUID,
+	limit int,
+	offset int,
+) ([]*NotificationDelivery, error) {
+	var deliveries []*NotificationDelivery
+
+	if err := storage.
+		GetDb().
+		Where("notifier_id = ?", notifierID).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (r *NotificationDeliveryRepository) CountByNotifierID(notifierID uuid.UUID) (int64, error) {
+	var count int64
+
+	if err := storage.
+		GetDb().
+		Model(&NotificationDelivery{}).
+		Where("notifier_id = ?", notifierID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// DeleteFinishedBefore removes history of delivered and failed
+// notifications created before the time
+func (r *NotificationDeliveryRepository) DeleteFinishedBefore(before time.Time) error {
+	return storage.
+		GetDb().
+		Where(
+			"status IN ? AND created_at < ?",
+			[]NotificationDeliveryStatus{
+				NotificationDeliveryStatusDelivered,
+				NotificationDeliveryStatusFailed,
+			},
+			before,
+		).
+		Delete(&NotificationDelivery{}).Error
+}
@@ -0,0 +1,104 @@
+package notifiers
+
+import (
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"postgresus-backend/internal/features/users"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FindDue_OlderDeliveryOfIncidentPending_NewerDeliveryWaits(t *testing.T) {
+	// setup data
+	user := users.GetTestUser()
+	notifier := createTestPagerDutyNotifier(t, user.UserID)
+
+	now := time.Now().UTC()
+	databaseID := uuid.New()
+
+	// the trigger failed and waits for the retry, so the resolve made
+	// after it should not be sent before
+	trigger := createTestDelivery(
+		t,
+		notifier.ID,
+		notifier_events.EventTypeBackupFailed,
+		&databaseID,
+		now.Add(-2*time.Minute),
+		now.Add(time.Minute),
+	)
+	resolve := createTestDelivery(
+		t,
+		notifier.ID,
+		notifier_events.EventTypeBackupSuccess,
+		&databaseID,
+		now.Add(-time.Minute),
+		now.Add(-time.Minute),
+	)
+
+	anotherDatabaseID := uuid.New()
+	anotherIncident := createTestDelivery(
+		t,
+		notifier.ID,
+		notifier_events.EventTypeBackupFailed,
+		&anotherDatabaseID,
+		now.Add(-time.Minute),
+		now.Add(-time.Minute),
+	)
+
+	// act
+	dueBeforeTriggerSent, err := notificationDeliveryRepository.FindDue(now, 1000)
+	require.NoError(t, err)
+
+	trigger.RecordAttempt(now, time.Second, nil)
+	require.NoError(t, notificationDeliveryRepository.Save(trigger))
+
+	dueAfterTriggerSent, err := notificationDeliveryRepository.FindDue(now, 1000)
+	require.NoError(t, err)
+
+	// assertions
+	dueBeforeIDs := getDeliveryIDs(dueBeforeTriggerSent)
+	assert.NotContains(t, dueBeforeIDs, resolve.ID)
+	assert.Contains(t, dueBeforeIDs, anotherIncident.ID)
+
+	assert.Contains(t, getDeliveryIDs(dueAfterTriggerSent), resolve.ID)
+
+	// cleanup
+	RemoveTestNotifier(notifier)
+}
+
+func createTestDelivery(
+	t *testing.T,
+	notifierID uuid.UUID,
+	eventType notifier_events.EventType,
+	databaseID *uuid.UUID,
+	createdAt time.Time,
+	nextAttemptAt time.Time,
+) *NotificationDelivery {
+	delivery := &NotificationDelivery{
+		NotifierID: notifierID,
+		EventType:  eventType,
+		Status:     NotificationDeliveryStatusPending,
+		Event: &notifier_events.NotificationEvent{
+			Type:       eventType,
+			DatabaseID: databaseID,
+		},
+		Attempts:      []NotificationDeliveryAttempt{},
+		NextAttemptAt: nextAttemptAt,
+		CreatedAt:     createdAt,
+	}
+	require.NoError(t, notificationDeliveryRepository.Save(delivery))
+
+	return delivery
+}
+
+func getDeliveryIDs(deliveries []*NotificationDelivery) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		ids = append(ids, delivery.ID)
+	}
+
+	return ids
+}
@@ -0,0 +1,47 @@
+package notifiers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RecordAttempt_FailedAttempts_RetriedWithBackoffUntilFailed(t *testing.T) {
+	delivery := &NotificationDelivery{Status: NotificationDeliveryStatusPending}
+	attemptedAt := time.Date(2025, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	delivery.RecordAttempt(attemptedAt, 0, errors.New("connection refused"))
+	assert.Equal(t, NotificationDeliveryStatusPending, delivery.Status)
+	assert.Equal(t, attemptedAt.Add(30*time.Second), delivery.NextAttemptAt)
+
+	delivery.RecordAttempt(attemptedAt, 0, errors.New("connection refused"))
+	assert.Equal(t, attemptedAt.Add(1*time.Minute), delivery.NextAttemptAt)
+
+	for len(delivery.Attempts) < maxDeliveryAttempts-1 {
+		delivery.RecordAttempt(attemptedAt, 0, errors.New("connection refused"))
+	}
+	assert.Equal(t, NotificationDeliveryStatusPending, delivery.Status)
+	assert.Equal(t, attemptedAt.Add(32*time.Minute), delivery.NextAttemptAt)
+
+	delivery.RecordAttempt(attemptedAt, 0, errors.New("connection refused"))
+	assert.Equal(t, NotificationDeliveryStatusFailed, delivery.Status)
+	assert.Len(t, delivery.Attempts, maxDeliveryAttempts)
+	assert.Equal(t, "connection refused", *delivery.Attempts[0].Error)
+	assert.Nil(t, delivery.DeliveredAt)
+}
+
+func Test_RecordAttempt_SuccessAfterFailure_Delivered(t *testing.T) {
+	delivery := &NotificationDelivery{Status: NotificationDeliveryStatusPending}
+	attemptedAt := time.Date(2025, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	delivery.RecordAttempt(attemptedAt, time.Second, errors.New("503 Service Unavailable"))
+	delivery.RecordAttempt(attemptedAt.Add(time.Minute), 2*time.Second, nil)
+
+	assert.Equal(t, NotificationDeliveryStatusDelivered, delivery.Status)
+	assert.Len(t, delivery.Attempts, 2)
+	assert.Nil(t, delivery.Attempts[1].Error)
+	assert.Equal(t, int64(2000), delivery.Attempts[1].DurationMs)
+	assert.Equal(t, attemptedAt.Add(time.Minute+2*time.Second), *delivery.DeliveredAt)
+}
@@ -1,13 +1,28 @@
 package notifiers
 
 import (
+	"postgresus-backend/internal/features/audit_logs"
 	"postgresus-backend/internal/features/users"
+	"postgresus-backend/internal/features/workspaces"
 	"postgresus-backend/internal/util/logger"
+	"time"
 )
 
 var notifierRepository = &NotifierRepository{}
+var notificationDeliveryRepository = &NotificationDeliveryRepository{}
 var notifierService = &NotifierService{
 	notifierRepository,
+	notificationDeliveryRepository,
+	workspaces.GetWorkspaceService(),
+	audit_logs.GetAuditLogService(),
+	logger.GetLogger(),
+	nil,
+	[]NotifierRemoveListener{},
+}
+var notificationDeliveryBackgroundService = &NotificationDeliveryBackgroundService{
+	notifierService,
+	notificationDeliveryRepository,
+	time.Time{},
 	logger.GetLogger(),
 }
 var notifierController = &NotifierController{
@@ -22,3 +37,12 @@ func GetNotifierController() *NotifierController {
 func GetNotifierService() *NotifierService {
 	return notifierService
 }
+
+func GetNotificationDeliveryBackgroundService() *NotificationDeliveryBackgroundService {
+	return notificationDeliveryBackgroundService
+}
+
+func SetupDependencies() {
+	workspaces.GetWorkspaceService().AddWorkspaceRemoveListener(notifierService)
+	users.GetUserService().AddAccountLockListener(notifierService)
+}
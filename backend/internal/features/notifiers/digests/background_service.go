@@ -0,0 +1,52 @@
+package notifier_digests
+
+import (
+	"log/slog"
+	"postgresus-backend/internal/config"
+	"postgresus-backend/internal/features/notifiers"
+	"time"
+)
+
+// DigestBackgroundService sends digests of notifiers when their
+// scheduled time comes
+type DigestBackgroundService struct {
+	notifierService *notifiers.NotifierService
+	digestService   *DigestService
+	logger          *slog.Logger
+}
+
+func (s *DigestBackgroundService) Run() {
+	for {
+		if config.IsShouldShutdown() {
+			return
+		}
+
+		s.sendDueDigests()
+
+		time.Sleep(1 * time.Minute)
+	}
+}
+
+func (s *DigestBackgroundService) sendDueDigests() {
+	now := time.Now().UTC()
+
+	notifiersWithDigest, err := s.notifierService.GetNotifiersWithDigest()
+	if err != nil {
+		s.logger.Error("Failed to get notifiers with digest", "error", err)
+		return
+	}
+
+	for _, notifier := range notifiersWithDigest {
+		if !notifier.IsDigestDue(now) {
+			continue
+		}
+
+		if err := s.digestService.SendDigest(notifier, now); err != nil {
+			s.logger.Error(
+				"Failed to send digest",
+				"notifierId", notifier.ID,
+				"error", err,
+			)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package notifier_digests
+
+import (
+	"postgresus-backend/internal/features/backups/backups"
+	backups_config "postgresus-backend/internal/features/backups/config"
+	"postgresus-backend/internal/features/databases"
+	healthcheck_attempt "postgresus-backend/internal/features/healthcheck/attempt"
+	"postgresus-backend/internal/features/notifiers"
+	"postgresus-backend/internal/util/logger"
+)
+
+var digestService = &DigestService{
+	notifiers.GetNotifierService(),
+	databases.GetDatabaseService(),
+	backups.GetBackupService(),
+	backups_config.GetBackupConfigService(),
+	healthcheck_attempt.GetHealthcheckAttemptService(),
+	logger.GetLogger(),
+}
+var digestBackgroundService = &DigestBackgroundService{
+	notifiers.GetNotifierService(),
+	digestService,
+	logger.GetLogger(),
+}
+
+func GetDigestService() *DigestService {
+	return digestService
+}
+
+func GetDigestBackgroundService() *DigestBackgroundService {
+	return digestBackgroundService
+}
@@ -0,0 +1,269 @@
+package notifier_digests
+
+import (
+	"fmt"
+	"math"
+	"postgresus-backend/internal/features/backups/backups"
+	"postgresus-backend/internal/features/databases"
+	healthcheck_attempt "postgresus-backend/internal/features/healthcheck/attempt"
+	"postgresus-backend/internal/features/intervals"
+	"postgresus-backend/internal/features/notifiers"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// messages longer than 2000 characters are truncated by notifier
+// service, so the digest stops listing databases a bit earlier
+const maxDigestMessageLength = 1800
+
+// DatabaseDigest is the summary of backups and healthchecks of one
+// database over the period of the digest
+type DatabaseDigest struct {
+	DatabaseID   uuid.UUID `json:"databaseId"`
+	DatabaseName string    `json:"databaseName"`
+
+	SuccessfulBackupsCount int `json:"successfulBackupsCount"`
+	FailedBackupsCount     int `json:"failedBackupsCount"`
+	MissedBackupsCount     int `json:"missedBackupsCount"`
+
+	TotalSizeMb   float64  `json:"totalSizeMb"`
+	GrowthMb      float64  `json:"growthMb"`
+	GrowthPercent *float64 `json:"growthPercent"`
+
+	// nil when there were no healthcheck attempts in the period
+	UptimePercent *float64 `json:"uptimePercent"`
+}
+
+type Digest struct {
+	Frequency notifiers.DigestFrequency `json:"frequency"`
+	From      time.Time                 `json:"from"`
+	To        time.Time                 `json:"to"`
+	Databases []*DatabaseDigest         `json:"databases"`
+}
+
+// NewDatabaseDigest summarizes backups and healthcheck attempts made
+// in [from, to). Previous backup is the last completed one before the
+// period, it is the base of the growth. Interval is nil when scheduled
+// backups are disabled, then missed backups are not counted
+func NewDatabaseDigest(
+	database *databases.Database,
+	periodBackups []*backups.Backup,
+	previousBackup *backups.Backup,
+	attempts []*healthcheck_attempt.HealthcheckAttempt,
+	interval *intervals.Interval,
+	from time.Time,
+	to time.Time,
+) *DatabaseDigest {
+	digest := &DatabaseDigest{
+		DatabaseID:   database.ID,
+		DatabaseName: database.Name,
+	}
+
+	baseBackup := previousBackup
+	var lastBackup *backups.Backup
+	startedBackupsCount := 0
+
+	for _, backup := range periodBackups {
+		if backup.IsImported {
+			continue
+		}
+
+		startedBackupsCount++
+
+		switch backup.Status {
+		case backups.BackupStatusCompleted:
+			digest.SuccessfulBackupsCount++
+			digest.TotalSizeMb += backup.BackupSizeMb
+
+			if baseBackup == nil {
+				baseBackup = backup
+			}
+			lastBackup = backup
+		case backups.BackupStatusFailed, backups.BackupStatusLost:
+			digest.FailedBackupsCount++
+		}
+	}
+
+	if baseBackup != nil && lastBackup != nil {
+		digest.GrowthMb = lastBackup.BackupSizeMb - baseBackup.BackupSizeMb
+
+		if baseBackup.BackupSizeMb > 0 {
+			growthPercent := digest.GrowthMb / baseBackup.BackupSizeMb * 100
+			digest.GrowthPercent = &growthPercent
+		}
+	}
+
+	if interval != nil {
+		scheduledBackupsCount := countScheduledBackups(interval, from, to)
+		digest.MissedBackupsCount = max(0, scheduledBackupsCount-startedBackupsCount)
+	}
+
+	if len(attempts) > 0 {
+		availableCount := 0
+		for _, attempt := range attempts {
+			if attempt.Status == databases.HealthStatusAvailable {
+				availableCount++
+			}
+		}
+
+		uptimePercent := float64(availableCount) / float64(len(attempts)) * 100
+		digest.UptimePercent = &uptimePercent
+	}
+
+	return digest
+}
+
+func (d *DatabaseDigest) HasProblems() bool {
+	if d.FailedBackupsCount > 0 || d.MissedBackupsCount > 0 {
+		return true
+	}
+
+	return d.UptimePercent != nil && *d.UptimePercent < 100
+}
+
+// ToEvent renders the digest as a notification. Databases with
+// problems are listed first, so they are not cut by the length limit
+func (d *Digest) ToEvent() *notifier_events.NotificationEvent {
+	event := &notifier_events.NotificationEvent{
+		Type:      notifier_events.EventTypeBackupDigest,
+		Severity:  notifier_events.SeverityInfo,
+		Title:     "📊 Daily backup digest",
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if d.Frequency == notifiers.DigestFrequencyWeekly {
+		event.Title = "📊 Weekly backup digest"
+	}
+
+	databaseDigests := make([]*DatabaseDigest, len(d.Databases))
+	copy(databaseDigests, d.Databases)
+	sort.SliceStable(databaseDigests, func(i, j int) bool {
+		if databaseDigests[i].HasProblems() != databaseDigests[j].HasProblems() {
+			return databaseDigests[i].HasProblems()
+		}
+
+		return databaseDigests[i].DatabaseName < databaseDigests[j].DatabaseName
+	})
+
+	problemsCount := 0
+	var largestGrowth *DatabaseDigest
+
+	for _, databaseDigest := range databaseDigests {
+		if databaseDigest.HasProblems() {
+			problemsCount++
+		}
+
+		if databaseDigest.GrowthMb > 0 &&
+			(largestGrowth == nil || databaseDigest.GrowthMb > largestGrowth.GrowthMb) {
+			largestGrowth = databaseDigest
+		}
+	}
+
+	if problemsCount > 0 {
+		event.Severity = notifier_events.SeverityWarning
+	}
+
+	header := fmt.Sprintf(
+		"%s - %s UTC\nDatabases: %d, need attention: %d",
+		d.From.UTC().Format("2 Jan 15:04"),
+		d.To.UTC().Format("2 Jan 15:04"),
+		len(databaseDigests),
+		problemsCount,
+	)
+
+	footer := ""
+	if largestGrowth != nil {
+		footer = fmt.Sprintf(
+			"\n\nLargest growth: %s (%s)",
+			largestGrowth.DatabaseName,
+			largestGrowth.formatGrowth(),
+		)
+	}
+
+	var lines []string
+	length := len(header) + len(footer)
+
+	for index, databaseDigest := range databaseDigests {
+		line := databaseDigest.format()
+
+		if length+len(line) > maxDigestMessageLength {
+			lines = append(
+				lines,
+				fmt.Sprintf("…and %d more databases", len(databaseDigests)-index),
+			)
+			break
+		}
+
+		lines = append(lines, line)
+		length += len(line) + 1
+	}
+
+	event.Message = header + "\n\n" + strings.Join(lines, "\n") + footer
+
+	return event
+}
+
+func (d *DatabaseDigest) format() string {
+	icon := "✅"
+	if d.HasProblems() {
+		icon = "⚠️"
+	}
+
+	parts := []string{
+		fmt.Sprintf("%d ok", d.SuccessfulBackupsCount),
+	}
+
+	if d.FailedBackupsCount > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", d.FailedBackupsCount))
+	}
+
+	if d.MissedBackupsCount > 0 {
+		parts = append(parts, fmt.Sprintf("%d missed", d.MissedBackupsCount))
+	}
+
+	parts = append(parts, notifier_events.FormatSize(d.TotalSizeMb))
+
+	if d.GrowthMb != 0 {
+		parts = append(parts, "growth "+d.formatGrowth())
+	}
+
+	if d.UptimePercent != nil {
+		parts = append(parts, fmt.Sprintf("uptime %.2f%%", *d.UptimePercent))
+	}
+
+	return fmt.Sprintf("%s %s: %s", icon, d.DatabaseName, strings.Join(parts, ", "))
+}
+
+func (d *DatabaseDigest) formatGrowth() string {
+	sign := "+"
+	if d.GrowthMb < 0 {
+		sign = "-"
+	}
+
+	growth := sign + notifier_events.FormatSize(math.Abs(d.GrowthMb))
+	if d.GrowthPercent != nil {
+		growth += fmt.Sprintf(", %s%.1f%%", sign, math.Abs(*d.GrowthPercent))
+	}
+
+	return growth
+}
+
+// countScheduledBackups replays the schedule over the period minute by
+// minute, as the backup background service checks it
+func countScheduledBackups(interval *intervals.Interval, from, to time.Time) int {
+	count := 0
+	lastBackupTime := from
+
+	for now := from.Add(time.Minute); now.Before(to); now = now.Add(time.Minute) {
+		if interval.ShouldTriggerBackup(now, &lastBackupTime) {
+			count++
+			lastBackupTime = now
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,105 @@
+package notifier_digests
+
+import (
+	"postgresus-backend/internal/features/backups/backups"
+	"postgresus-backend/internal/features/databases"
+	healthcheck_attempt "postgresus-backend/internal/features/healthcheck/attempt"
+	"postgresus-backend/internal/features/intervals"
+	"postgresus-backend/internal/features/notifiers"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewDatabaseDigest_HourlyBackupsWithFailures_SummaryCalculated(t *testing.T) {
+	from := time.Date(2025, 10, 15, 9, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	database := &databases.Database{ID: uuid.New(), Name: "main"}
+	previousBackup := &backups.Backup{Status: backups.BackupStatusCompleted, BackupSizeMb: 100}
+
+	// 20 completed and 2 failed backups out of 23 scheduled
+	var periodBackups []*backups.Backup
+	for i := 1; i <= 22; i++ {
+		backup := &backups.Backup{
+			Status:       backups.BackupStatusCompleted,
+			BackupSizeMb: 100 + float64(i),
+			CreatedAt:    from.Add(time.Duration(i) * time.Hour),
+		}
+
+		if i%10 == 0 {
+			backup.Status = backups.BackupStatusFailed
+			backup.BackupSizeMb = 0
+		}
+
+		periodBackups = append(periodBackups, backup)
+	}
+
+	attempts := []*healthcheck_attempt.HealthcheckAttempt{
+		{Status: databases.HealthStatusAvailable},
+		{Status: databases.HealthStatusAvailable},
+		{Status: databases.HealthStatusAvailable},
+		{Status: databases.HealthStatusUnavailable},
+	}
+
+	interval := &intervals.Interval{Interval: intervals.IntervalHourly}
+
+	digest := NewDatabaseDigest(
+		database,
+		periodBackups,
+		previousBackup,
+		attempts,
+		interval,
+		from,
+		to,
+	)
+
+	assert.Equal(t, 20, digest.SuccessfulBackupsCount)
+	assert.Equal(t, 2, digest.FailedBackupsCount)
+	assert.Equal(t, 1, digest.MissedBackupsCount)
+	assert.InDelta(t, 22.0, digest.GrowthMb, 0.001)
+	assert.InDelta(t, 22.0, *digest.GrowthPercent, 0.001)
+	assert.InDelta(t, 75.0, *digest.UptimePercent, 0.001)
+	assert.True(t, digest.HasProblems())
+}
+
+func Test_ToEvent_ManyDatabases_ProblemsListedFirstAndMessageLimited(t *testing.T) {
+	digest := &Digest{
+		Frequency: notifiers.DigestFrequencyWeekly,
+		From:      time.Date(2025, 10, 8, 9, 0, 0, 0, time.UTC),
+		To:        time.Date(2025, 10, 15, 9, 0, 0, 0, time.UTC),
+	}
+
+	for i := 0; i < 100; i++ {
+		digest.Databases = append(digest.Databases, &DatabaseDigest{
+			DatabaseID:             uuid.New(),
+			DatabaseName:           "database-" + strings.Repeat("a", i%10),
+			SuccessfulBackupsCount: 7,
+			TotalSizeMb:            700,
+		})
+	}
+
+	digest.Databases = append(digest.Databases, &DatabaseDigest{
+		DatabaseID:         uuid.New(),
+		DatabaseName:       "zzz",
+		FailedBackupsCount: 7,
+		GrowthMb:           5,
+	})
+
+	event := digest.ToEvent()
+
+	assert.Equal(t, notifier_events.EventTypeBackupDigest, event.Type)
+	assert.Equal(t, notifier_events.SeverityWarning, event.Severity)
+	assert.Equal(t, "📊 Weekly backup digest", event.Title)
+	assert.LessOrEqual(t, len(event.Message), maxDigestMessageLength+100)
+
+	lines := strings.Split(event.Message, "\n")
+	assert.Equal(t, "Databases: 101, need attention: 1", lines[1])
+	assert.True(t, strings.HasPrefix(lines[3], "⚠️ zzz: 0 ok, 7 failed"))
+	assert.Contains(t, event.Message, "more databases")
+	assert.True(t, strings.HasSuffix(event.Message, "Largest growth: zzz (+5.00 MB)"))
+}
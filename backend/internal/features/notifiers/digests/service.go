@@ -0,0 +1,126 @@
+package notifier_digests
+
+import (
+	"log/slog"
+	"postgresus-backend/internal/features/backups/backups"
+	backups_config "postgresus-backend/internal/features/backups/config"
+	"postgresus-backend/internal/features/databases"
+	healthcheck_attempt "postgresus-backend/internal/features/healthcheck/attempt"
+	"postgresus-backend/internal/features/intervals"
+	"postgresus-backend/internal/features/notifiers"
+	"time"
+)
+
+type DigestService struct {
+	notifierService           *notifiers.NotifierService
+	databaseService           *databases.DatabaseService
+	backupService             *backups.BackupService
+	backupConfigService       *backups_config.BackupConfigService
+	healthcheckAttemptService *healthcheck_attempt.HealthcheckAttemptService
+	logger                    *slog.Logger
+}
+
+// BuildDigest summarizes databases using the notifier over the digest
+// period which ends at the time
+func (s *DigestService) BuildDigest(
+	notifier *notifiers.Notifier,
+	to time.Time,
+) (*Digest, error) {
+	digest := &Digest{
+		Frequency: notifier.DigestFrequency,
+		From:      to.Add(-notifier.GetDigestPeriod()),
+		To:        to,
+		Databases: []*DatabaseDigest{},
+	}
+
+	allDatabases, err := s.databaseService.GetAllDatabases()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, database := range allDatabases {
+		if !isUsingNotifier(database, notifier) {
+			continue
+		}
+
+		databaseDigest, err := s.buildDatabaseDigest(database, digest.From, digest.To)
+		if err != nil {
+			return nil, err
+		}
+
+		digest.Databases = append(digest.Databases, databaseDigest)
+	}
+
+	return digest, nil
+}
+
+// SendDigest sends the digest for the last scheduled time, so digest
+// is not repeated after restart and a skipped one is sent only once
+func (s *DigestService) SendDigest(notifier *notifiers.Notifier, now time.Time) error {
+	slot, ok := notifier.GetLastDigestSlot(now)
+	if !ok {
+		return nil
+	}
+
+	digest, err := s.BuildDigest(notifier, slot)
+	if err != nil {
+		return err
+	}
+
+	if len(digest.Databases) > 0 {
+		s.notifierService.SendNotification(notifier, digest.ToEvent())
+	}
+
+	return s.notifierService.SetLastDigestAt(notifier.ID, slot)
+}
+
+func (s *DigestService) buildDatabaseDigest(
+	database *databases.Database,
+	from time.Time,
+	to time.Time,
+) (*DatabaseDigest, error) {
+	periodBackups, err := s.backupService.GetBackupsForPeriod(database.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	previousBackup, err := s.backupService.GetLastCompletedBackupBefore(database.ID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.healthcheckAttemptService.GetAttemptsForPeriod(database.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	backupConfig, err := s.backupConfigService.GetBackupConfigByDbId(database.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var interval *intervals.Interval
+	if backupConfig.IsBackupsEnabled {
+		interval = backupConfig.BackupInterval
+	}
+
+	return NewDatabaseDigest(
+		database,
+		periodBackups,
+		previousBackup,
+		attempts,
+		interval,
+		from,
+		to,
+	), nil
+}
+
+func isUsingNotifier(database *databases.Database, notifier *notifiers.Notifier) bool {
+	for _, databaseNotifier := range database.Notifiers {
+		if databaseNotifier.ID == notifier.ID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,27 @@
+package notifiers
+
+import (
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	notifier_templates "postgresus-backend/internal/features/notifiers/templates"
+)
+
+type PreviewTemplateRequest struct {
+	EventType     notifier_events.EventType `json:"eventType"     binding:"required"`
+	TitleTemplate string                    `json:"titleTemplate"`
+	BodyTemplate  string                    `json:"bodyTemplate"`
+}
+
+// PreviewTemplateResponse contains the rendered sample notification
+// and variables it was rendered with
+type PreviewTemplateResponse struct {
+	Title     string                           `json:"title"`
+	Body      string                           `json:"body"`
+	Variables *notifier_templates.TemplateData `json:"variables"`
+}
+
+type GetNotificationDeliveriesResponse struct {
+	Deliveries []*NotificationDelivery `json:"deliveries"`
+	Total      int64                   `json:"total"`
+	Limit      int                     `json:"limit"`
+	Offset     int                     `json:"offset"`
+}
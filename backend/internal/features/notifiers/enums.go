@@ -3,10 +3,34 @@ package notifiers
 type NotifierType string
 
 const (
-	NotifierTypeEmail    NotifierType = "EMAIL"
-	NotifierTypeTelegram NotifierType = "TELEGRAM"
-	NotifierTypeWebhook  NotifierType = "WEBHOOK"
-	NotifierTypeSlack    NotifierType = "SLACK"
-	NotifierTypeDiscord  NotifierType = "DISCORD"
-	NotifierTypeTeams    NotifierType = "TEAMS"
+	NotifierTypeEmail      NotifierType = "EMAIL"
+	NotifierTypeTelegram   NotifierType = "TELEGRAM"
+	NotifierTypeWebhook    NotifierType = "WEBHOOK"
+	NotifierTypeSlack      NotifierType = "SLACK"
+	NotifierTypeDiscord    NotifierType = "DISCORD"
+	NotifierTypeTeams      NotifierType = "TEAMS"
+	NotifierTypePagerDuty  NotifierType = "PAGERDUTY"
+	NotifierTypeOpsgenie   NotifierType = "OPSGENIE"
+	NotifierTypeMattermost NotifierType = "MATTERMOST"
+	NotifierTypeRocketChat NotifierType = "ROCKET_CHAT"
+	NotifierTypeGoogleChat NotifierType = "GOOGLE_CHAT"
+	NotifierTypeMatrix     NotifierType = "MATRIX"
+	NotifierTypeNtfy       NotifierType = "NTFY"
+)
+
+type NotificationDeliveryStatus string
+
+const (
+	NotificationDeliveryStatusPending   NotificationDeliveryStatus = "PENDING"
+	NotificationDeliveryStatusSending   NotificationDeliveryStatus = "SENDING"
+	NotificationDeliveryStatusDelivered NotificationDeliveryStatus = "DELIVERED"
+	NotificationDeliveryStatusFailed    NotificationDeliveryStatus = "FAILED"
+)
+
+type DigestFrequency string
+
+const (
+	DigestFrequencyNone   DigestFrequency = "NONE"
+	DigestFrequencyDaily  DigestFrequency = "DAILY"
+	DigestFrequencyWeekly DigestFrequency = "WEEKLY"
 )
@@ -0,0 +1,81 @@
+package notifier_events
+
+type EventType string
+
+const (
+	EventTypeBackupSuccess       EventType = "BACKUP_SUCCESS"
+	EventTypeBackupFailed        EventType = "BACKUP_FAILED"
+	EventTypeBackupAnomaly       EventType = "BACKUP_ANOMALY"
+	EventTypeBackupOverdue       EventType = "BACKUP_OVERDUE"
+	EventTypeStorageQuotaWarning EventType = "STORAGE_QUOTA_WARNING"
+	EventTypeDatabaseUnavailable EventType = "DATABASE_UNAVAILABLE"
+	EventTypeDatabaseAvailable   EventType = "DATABASE_AVAILABLE"
+	EventTypeAccountLocked       EventType = "ACCOUNT_LOCKED"
+	EventTypeBackupDigest        EventType = "BACKUP_DIGEST"
+	EventTypeTest                EventType = "TEST"
+)
+
+func GetEventTypes() []EventType {
+	return []EventType{
+		EventTypeBackupSuccess,
+		EventTypeBackupFailed,
+		EventTypeBackupAnomaly,
+		EventTypeBackupOverdue,
+		EventTypeStorageQuotaWarning,
+		EventTypeDatabaseUnavailable,
+		EventTypeDatabaseAvailable,
+		EventTypeAccountLocked,
+		EventTypeBackupDigest,
+		EventTypeTest,
+	}
+}
+
+type Severity string
+
+const (
+	SeverityInfo     Severity = "INFO"
+	SeveritySuccess  Severity = "SUCCESS"
+	SeverityWarning  Severity = "WARNING"
+	SeverityError    Severity = "ERROR"
+	SeverityCritical Severity = "CRITICAL"
+)
+
+// GetColor returns hex color of the severity for channels which
+// highlight messages, e.g. Slack attachments or Discord embeds
+func (s Severity) GetColor() string {
+	switch s {
+	case SeveritySuccess:
+		return "#2EB67D"
+	case SeverityWarning:
+		return "#ECB22E"
+	case SeverityError, SeverityCritical:
+		return "#E01E5A"
+	default:
+		return "#1D9BD1"
+	}
+}
+
+func GetSeverities() []Severity {
+	return []Severity{
+		SeverityInfo,
+		SeveritySuccess,
+		SeverityWarning,
+		SeverityError,
+		SeverityCritical,
+	}
+}
+
+// GetLevel returns the importance of the severity to compare it with
+// thresholds, success is as important as info
+func (s Severity) GetLevel() int {
+	switch s {
+	case SeverityCritical:
+		return 4
+	case SeverityError:
+		return 3
+	case SeverityWarning:
+		return 2
+	default:
+		return 1
+	}
+}
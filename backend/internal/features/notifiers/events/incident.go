@@ -0,0 +1,50 @@
+package notifier_events
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type IncidentAction string
+
+const (
+	IncidentActionTrigger IncidentAction = "TRIGGER"
+	IncidentActionResolve IncidentAction = "RESOLVE"
+)
+
+// GetIncidentAction tells incident management tools whether the event
+// opens an incident or resolves the one opened before
+func (e *NotificationEvent) GetIncidentAction() IncidentAction {
+	switch e.Type {
+	case EventTypeDatabaseAvailable, EventTypeBackupSuccess:
+		return IncidentActionResolve
+	default:
+		return IncidentActionTrigger
+	}
+}
+
+// GetIncidentKey returns the key linking the event which opens an
+// incident with the event which resolves it, e.g. unavailable and
+// available again database. Repeated triggers with the same key are
+// grouped into one incident
+func (e *NotificationEvent) GetIncidentKey() string {
+	source := "system"
+	if e.DatabaseID != nil {
+		source = "database-" + e.DatabaseID.String()
+	}
+
+	switch e.Type {
+	case EventTypeDatabaseUnavailable, EventTypeDatabaseAvailable:
+		return fmt.Sprintf("postgresus-%s-unavailable", source)
+	case EventTypeBackupFailed, EventTypeBackupOverdue, EventTypeBackupSuccess:
+		// overdue backup is resolved by the next successful one as well
+		return fmt.Sprintf("postgresus-%s-backup-failed", source)
+	case EventTypeTest:
+		// each test is a separate incident, it is resolved right away
+		return "postgresus-test-" + uuid.New().String()
+	default:
+		return fmt.Sprintf("postgresus-%s-%s", source, strings.ToLower(string(e.Type)))
+	}
+}
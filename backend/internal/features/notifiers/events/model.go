@@ -0,0 +1,126 @@
+package notifier_events
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationEvent describes what happened, so each channel renders
+// it in its own format: plain text, Slack blocks, adaptive cards,
+// HTML or JSON. Title and message are ready to show for channels
+// without rich formatting
+type NotificationEvent struct {
+	Type     EventType `json:"type"`
+	Severity Severity  `json:"severity"`
+	Title    string    `json:"title"`
+	Message  string    `json:"message"`
+
+	DatabaseID   *uuid.UUID `json:"databaseId,omitempty"`
+	DatabaseName string     `json:"databaseName,omitempty"`
+	BackupID     *uuid.UUID `json:"backupId,omitempty"`
+	RestoreID    *uuid.UUID `json:"restoreId,omitempty"`
+
+	SizeMb     *float64 `json:"sizeMb,omitempty"`
+	DurationMs *int64   `json:"durationMs,omitempty"`
+	Error      *string  `json:"error,omitempty"`
+
+	// full output of the failed command, the error is truncated while
+	// the log is attached to emails as a file
+	Log *string `json:"log,omitempty"`
+
+	Links     []EventLink `json:"links,omitempty"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
+type EventLink struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// EventField is a named value of the event shown as a table row or
+// a fact of the card
+type EventField struct {
+	Name  string
+	Value string
+}
+
+func NewTestEvent() *NotificationEvent {
+	return &NotificationEvent{
+		Type:      EventTypeTest,
+		Severity:  SeverityInfo,
+		Title:     "Test message",
+		Message:   "This is a test message",
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// GetFields returns filled details of the event in the order they
+// should be shown. The error is not included, it is usually long and
+// is shown separately
+func (e *NotificationEvent) GetFields() []EventField {
+	var fields []EventField
+
+	if e.DatabaseName != "" {
+		fields = append(fields, EventField{Name: "Database", Value: e.DatabaseName})
+	}
+
+	if e.SizeMb != nil {
+		fields = append(fields, EventField{Name: "Size", Value: FormatSize(*e.SizeMb)})
+	}
+
+	if e.DurationMs != nil {
+		fields = append(fields, EventField{Name: "Duration", Value: FormatDuration(*e.DurationMs)})
+	}
+
+	return fields
+}
+
+// GetPlainText renders the message, details and the error of the
+// event as plain text for channels without formatting
+func (e *NotificationEvent) GetPlainText() string {
+	var parts []string
+
+	if e.Message != "" {
+		parts = append(parts, e.Message)
+	}
+
+	fields := e.GetFields()
+	if len(fields) > 0 {
+		lines := make([]string, 0, len(fields))
+		for _, field := range fields {
+			lines = append(lines, field.Name+": "+field.Value)
+		}
+
+		parts = append(parts, strings.Join(lines, "\n"))
+	}
+
+	if e.Error != nil && *e.Error != "" {
+		parts = append(parts, "Error: "+*e.Error)
+	}
+
+	for _, link := range e.Links {
+		parts = append(parts, link.Title+": "+link.URL)
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+// FormatSize formats size in MB as "0.00 MB" or "0.00 GB"
+func FormatSize(sizeMb float64) string {
+	if sizeMb < 1024 {
+		return fmt.Sprintf("%.2f MB", sizeMb)
+	}
+
+	return fmt.Sprintf("%.2f GB", sizeMb/1024)
+}
+
+// FormatDuration formats duration in milliseconds as "0m 0s"
+func FormatDuration(durationMs int64) string {
+	minutes := durationMs / (1000 * 60)
+	seconds := (durationMs % (1000 * 60)) / 1000
+
+	return fmt.Sprintf("%dm %ds", minutes, seconds)
+}
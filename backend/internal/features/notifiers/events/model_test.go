@@ -0,0 +1,39 @@
+package notifier_events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetPlainText_BackupFailedEvent_DetailsAndErrorIncluded(t *testing.T) {
+	sizeMb := 1536.0
+	durationMs := int64(95_000)
+	errorText := "connection refused"
+
+	event := &NotificationEvent{
+		Type:         EventTypeBackupFailed,
+		Severity:     SeverityError,
+		Title:        "Backup failed",
+		DatabaseName: "main",
+		SizeMb:       &sizeMb,
+		DurationMs:   &durationMs,
+		Error:        &errorText,
+		Links:        []EventLink{{Title: "Open Postgresus", URL: "https://example.com"}},
+	}
+
+	assert.Equal(
+		t,
+		"Database: main\nSize: 1.50 GB\nDuration: 1m 35s\n\n"+
+			"Error: connection refused\n\n"+
+			"Open Postgresus: https://example.com",
+		event.GetPlainText(),
+	)
+}
+
+func Test_GetFields_EmptyEvent_NoFieldsReturned(t *testing.T) {
+	event := NewTestEvent()
+
+	assert.Empty(t, event.GetFields())
+	assert.Equal(t, "This is a test message", event.GetPlainText())
+}
@@ -1,9 +1,23 @@
 package notifiers
 
-import "log/slog"
+import (
+	"log/slog"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+
+	"github.com/google/uuid"
+)
 
 type NotificationSender interface {
-	Send(logger *slog.Logger, heading string, message string) error
+	Send(logger *slog.Logger, event *notifier_events.NotificationEvent) error
 
 	Validate() error
 }
+
+// NotificationRouter decides whether the notifier receives the event
+type NotificationRouter interface {
+	IsRouted(notifier *Notifier, event *notifier_events.NotificationEvent) bool
+}
+
+type NotifierRemoveListener interface {
+	OnBeforeNotifierRemove(notifierID uuid.UUID) error
+}
@@ -2,13 +2,24 @@ package notifiers
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
 	discord_notifier "postgresus-backend/internal/features/notifiers/models/discord"
 	"postgresus-backend/internal/features/notifiers/models/email_notifier"
+	google_chat_notifier "postgresus-backend/internal/features/notifiers/models/google_chat"
+	matrix_notifier "postgresus-backend/internal/features/notifiers/models/matrix"
+	mattermost_notifier "postgresus-backend/internal/features/notifiers/models/mattermost"
+	ntfy_notifier "postgresus-backend/internal/features/notifiers/models/ntfy"
+	opsgenie_notifier "postgresus-backend/internal/features/notifiers/models/opsgenie"
+	pagerduty_notifier "postgresus-backend/internal/features/notifiers/models/pagerduty"
+	rocket_chat_notifier "postgresus-backend/internal/features/notifiers/models/rocket_chat"
 	slack_notifier "postgresus-backend/internal/features/notifiers/models/slack"
 	teams_notifier "postgresus-backend/internal/features/notifiers/models/teams"
 	telegram_notifier "postgresus-backend/internal/features/notifiers/models/telegram"
 	webhook_notifier "postgresus-backend/internal/features/notifiers/models/webhook"
+	notifier_templates "postgresus-backend/internal/features/notifiers/templates"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -16,17 +27,35 @@ import (
 type Notifier struct {
 	ID            uuid.UUID    `json:"id"            gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
 	UserID        uuid.UUID    `json:"userId"        gorm:"column:user_id;not null;type:uuid;index"`
+	WorkspaceID   uuid.UUID    `json:"workspaceId"   gorm:"column:workspace_id;not null;type:uuid"`
 	Name          string       `json:"name"          gorm:"column:name;not null;type:varchar(255)"`
 	NotifierType  NotifierType `json:"notifierType"  gorm:"column:notifier_type;not null;type:varchar(50)"`
 	LastSendError *string      `json:"lastSendError" gorm:"column:last_send_error;type:text"`
 
 	// specific notifier
-	TelegramNotifier *telegram_notifier.TelegramNotifier `json:"telegramNotifier"        gorm:"foreignKey:NotifierID"`
-	EmailNotifier    *email_notifier.EmailNotifier       `json:"emailNotifier"           gorm:"foreignKey:NotifierID"`
-	WebhookNotifier  *webhook_notifier.WebhookNotifier   `json:"webhookNotifier"         gorm:"foreignKey:NotifierID"`
-	SlackNotifier    *slack_notifier.SlackNotifier       `json:"slackNotifier"           gorm:"foreignKey:NotifierID"`
-	DiscordNotifier  *discord_notifier.DiscordNotifier   `json:"discordNotifier"         gorm:"foreignKey:NotifierID"`
-	TeamsNotifier    *teams_notifier.TeamsNotifier       `json:"teamsNotifier,omitempty" gorm:"foreignKey:NotifierID;constraint:OnDelete:CASCADE"`
+	TelegramNotifier   *telegram_notifier.TelegramNotifier      `json:"telegramNotifier"             gorm:"foreignKey:NotifierID"`
+	EmailNotifier      *email_notifier.EmailNotifier            `json:"emailNotifier"                gorm:"foreignKey:NotifierID"`
+	WebhookNotifier    *webhook_notifier.WebhookNotifier        `json:"webhookNotifier"              gorm:"foreignKey:NotifierID"`
+	SlackNotifier      *slack_notifier.SlackNotifier            `json:"slackNotifier"                gorm:"foreignKey:NotifierID"`
+	DiscordNotifier    *discord_notifier.DiscordNotifier        `json:"discordNotifier"              gorm:"foreignKey:NotifierID"`
+	TeamsNotifier      *teams_notifier.TeamsNotifier            `json:"teamsNotifier,omitempty"      gorm:"foreignKey:NotifierID;constraint:OnDelete:CASCADE"`
+	PagerDutyNotifier  *pagerduty_notifier.PagerDutyNotifier    `json:"pagerDutyNotifier,omitempty"  gorm:"foreignKey:NotifierID"`
+	OpsgenieNotifier   *opsgenie_notifier.OpsgenieNotifier      `json:"opsgenieNotifier,omitempty"   gorm:"foreignKey:NotifierID"`
+	MattermostNotifier *mattermost_notifier.MattermostNotifier  `json:"mattermostNotifier,omitempty" gorm:"foreignKey:NotifierID"`
+	RocketChatNotifier *rocket_chat_notifier.RocketChatNotifier `json:"rocketChatNotifier,omitempty" gorm:"foreignKey:NotifierID"`
+	GoogleChatNotifier *google_chat_notifier.GoogleChatNotifier `json:"googleChatNotifier,omitempty" gorm:"foreignKey:NotifierID"`
+	MatrixNotifier     *matrix_notifier.MatrixNotifier          `json:"matrixNotifier,omitempty"     gorm:"foreignKey:NotifierID"`
+	NtfyNotifier       *ntfy_notifier.NtfyNotifier              `json:"ntfyNotifier,omitempty"       gorm:"foreignKey:NotifierID"`
+
+	// overrides of title and body per event type
+	Templates []*notifier_templates.NotifierTemplate `json:"templates" gorm:"foreignKey:NotifierID"`
+
+	// summary of backups of databases using the notifier, it is sent at
+	// the time of day in UTC, weekly one on the weekday (1 is Monday)
+	DigestFrequency DigestFrequency `json:"digestFrequency" gorm:"column:digest_frequency;type:varchar(20);not null"`
+	DigestTimeOfDay string          `json:"digestTimeOfDay" gorm:"column:digest_time_of_day;type:varchar(5);not null"`
+	DigestWeekday   int             `json:"digestWeekday"   gorm:"column:digest_weekday;type:int;not null"`
+	LastDigestAt    *time.Time      `json:"lastDigestAt"    gorm:"column:last_digest_at"`
 }
 
 func (n *Notifier) TableName() string {
@@ -38,11 +67,234 @@ func (n *Notifier) Validate() error {
 		return errors.New("name is required")
 	}
 
+	if err := notifier_templates.ValidateTemplates(n.Templates); err != nil {
+		return err
+	}
+
+	if err := n.validateDigest(); err != nil {
+		return err
+	}
+
 	return n.getSpecificNotifier().Validate()
 }
 
-func (n *Notifier) Send(logger *slog.Logger, heading string, message string) error {
-	err := n.getSpecificNotifier().Send(logger, heading, message)
+// IsDigestDue returns true if the digest is enabled and has not been
+// sent since its last scheduled time
+func (n *Notifier) IsDigestDue(now time.Time) bool {
+	slot, ok := n.GetLastDigestSlot(now)
+	if !ok {
+		return false
+	}
+
+	return n.LastDigestAt == nil || n.LastDigestAt.Before(slot)
+}
+
+// GetLastDigestSlot returns the latest scheduled time of the digest
+// which is not after now
+func (n *Notifier) GetLastDigestSlot(now time.Time) (time.Time, bool) {
+	if n.DigestFrequency != DigestFrequencyDaily && n.DigestFrequency != DigestFrequencyWeekly {
+		return time.Time{}, false
+	}
+
+	timeOfDay, err := time.Parse("15:04", n.DigestTimeOfDay)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	now = now.UTC()
+	slot := time.Date(
+		now.Year(), now.Month(), now.Day(),
+		timeOfDay.Hour(), timeOfDay.Minute(), 0, 0, time.UTC,
+	)
+
+	if n.DigestFrequency == DigestFrequencyDaily {
+		if slot.After(now) {
+			slot = slot.AddDate(0, 0, -1)
+		}
+
+		return slot, true
+	}
+
+	// days since the weekday of digest, Sunday is 7
+	weekday := int(now.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+
+	slot = slot.AddDate(0, 0, -((weekday - n.DigestWeekday + 7) % 7))
+	if slot.After(now) {
+		slot = slot.AddDate(0, 0, -7)
+	}
+
+	return slot, true
+}
+
+// GetDigestPeriod returns the duration summarized by the digest
+func (n *Notifier) GetDigestPeriod() time.Duration {
+	if n.DigestFrequency == DigestFrequencyWeekly {
+		return 7 * 24 * time.Hour
+	}
+
+	return 24 * time.Hour
+}
+
+func (n *Notifier) validateDigest() error {
+	switch n.DigestFrequency {
+	case "", DigestFrequencyNone:
+		return nil
+	case DigestFrequencyDaily, DigestFrequencyWeekly:
+	default:
+		return fmt.Errorf("unknown digest frequency: %s", n.DigestFrequency)
+	}
+
+	if n.IsIncidentNotifier() {
+		return errors.New("digest can't be sent to incident management tools")
+	}
+
+	if _, err := time.Parse("15:04", n.DigestTimeOfDay); err != nil {
+		return errors.New("digest time must be in HH:MM format")
+	}
+
+	if n.DigestFrequency == DigestFrequencyWeekly &&
+		(n.DigestWeekday < 1 || n.DigestWeekday > 7) {
+		return errors.New("digest weekday must be from 1 to 7")
+	}
+
+	return nil
+}
+
+// IsIncidentNotifier returns true for incident management tools, they
+// open incidents on failures and resolve them on recovery
+func (n *Notifier) IsIncidentNotifier() bool {
+	return n.NotifierType == NotifierTypePagerDuty || n.NotifierType == NotifierTypeOpsgenie
+}
+
+// IsBackupLogAttached returns true if the output of failed backups is
+// attached to notifications of the notifier
+func (n *Notifier) IsBackupLogAttached() bool {
+	return n.NotifierType == NotifierTypeEmail &&
+		n.EmailNotifier != nil &&
+		n.EmailNotifier.IsBackupLogAttached
+}
+
+// HideSensitiveData removes tokens and passwords before the notifier
+// is returned to the client, they are write only
+func (n *Notifier) HideSensitiveData() {
+	if n.TelegramNotifier != nil {
+		n.TelegramNotifier.HideSensitiveData()
+	}
+
+	if n.EmailNotifier != nil {
+		n.EmailNotifier.HideSensitiveData()
+	}
+
+	if n.WebhookNotifier != nil {
+		n.WebhookNotifier.HideSensitiveData()
+	}
+
+	if n.SlackNotifier != nil {
+		n.SlackNotifier.HideSensitiveData()
+	}
+
+	if n.DiscordNotifier != nil {
+		n.DiscordNotifier.HideSensitiveData()
+	}
+
+	if n.TeamsNotifier != nil {
+		n.TeamsNotifier.HideSensitiveData()
+	}
+
+	if n.PagerDutyNotifier != nil {
+		n.PagerDutyNotifier.HideSensitiveData()
+	}
+
+	if n.OpsgenieNotifier != nil {
+		n.OpsgenieNotifier.HideSensitiveData()
+	}
+
+	if n.MattermostNotifier != nil {
+		n.MattermostNotifier.HideSensitiveData()
+	}
+
+	if n.RocketChatNotifier != nil {
+		n.RocketChatNotifier.HideSensitiveData()
+	}
+
+	if n.GoogleChatNotifier != nil {
+		n.GoogleChatNotifier.HideSensitiveData()
+	}
+
+	if n.MatrixNotifier != nil {
+		n.MatrixNotifier.HideSensitiveData()
+	}
+
+	if n.NtfyNotifier != nil {
+		n.NtfyNotifier.HideSensitiveData()
+	}
+}
+
+// FillSensitiveData keeps saved tokens and passwords which the client
+// has not changed, because they are not returned by API
+func (n *Notifier) FillSensitiveData(existing *Notifier) {
+	if existing == nil || existing.ID != n.ID || existing.NotifierType != n.NotifierType {
+		return
+	}
+
+	if n.TelegramNotifier != nil {
+		n.TelegramNotifier.FillSensitiveData(existing.TelegramNotifier)
+	}
+
+	if n.EmailNotifier != nil {
+		n.EmailNotifier.FillSensitiveData(existing.EmailNotifier)
+	}
+
+	if n.WebhookNotifier != nil {
+		n.WebhookNotifier.FillSensitiveData(existing.WebhookNotifier)
+	}
+
+	if n.SlackNotifier != nil {
+		n.SlackNotifier.FillSensitiveData(existing.SlackNotifier)
+	}
+
+	if n.DiscordNotifier != nil {
+		n.DiscordNotifier.FillSensitiveData(existing.DiscordNotifier)
+	}
+
+	if n.TeamsNotifier != nil {
+		n.TeamsNotifier.FillSensitiveData(existing.TeamsNotifier)
+	}
+
+	if n.PagerDutyNotifier != nil {
+		n.PagerDutyNotifier.FillSensitiveData(existing.PagerDutyNotifier)
+	}
+
+	if n.OpsgenieNotifier != nil {
+		n.OpsgenieNotifier.FillSensitiveData(existing.OpsgenieNotifier)
+	}
+
+	if n.MattermostNotifier != nil {
+		n.MattermostNotifier.FillSensitiveData(existing.MattermostNotifier)
+	}
+
+	if n.RocketChatNotifier != nil {
+		n.RocketChatNotifier.FillSensitiveData(existing.RocketChatNotifier)
+	}
+
+	if n.GoogleChatNotifier != nil {
+		n.GoogleChatNotifier.FillSensitiveData(existing.GoogleChatNotifier)
+	}
+
+	if n.MatrixNotifier != nil {
+		n.MatrixNotifier.FillSensitiveData(existing.MatrixNotifier)
+	}
+
+	if n.NtfyNotifier != nil {
+		n.NtfyNotifier.FillSensitiveData(existing.NtfyNotifier)
+	}
+}
+
+func (n *Notifier) Send(logger *slog.Logger, event *notifier_events.NotificationEvent) error {
+	err := n.getSpecificNotifier().Send(logger, n.applyTemplate(logger, event))
 
 	if err != nil {
 		lastSendError := err.Error()
@@ -54,6 +306,32 @@ func (n *Notifier) Send(logger *slog.Logger, heading string, message string) err
 	return err
 }
 
+// applyTemplate renders title and body of the event by the template
+// of the notifier. Notification with default wording is better than
+// no notification, so it is used if the template fails
+func (n *Notifier) applyTemplate(
+	logger *slog.Logger,
+	event *notifier_events.NotificationEvent,
+) *notifier_events.NotificationEvent {
+	template := notifier_templates.FindTemplate(n.Templates, event.Type)
+	if template == nil {
+		return event
+	}
+
+	renderedEvent, err := template.Apply(event)
+	if err != nil {
+		logger.Error(
+			"Failed to render notification template",
+			"notifierId", n.ID,
+			"eventType", event.Type,
+			"error", err,
+		)
+		return event
+	}
+
+	return renderedEvent
+}
+
 func (n *Notifier) getSpecificNotifier() NotificationSender {
 	switch n.NotifierType {
 	case NotifierTypeTelegram:
@@ -68,6 +346,20 @@ func (n *Notifier) getSpecificNotifier() NotificationSender {
 		return n.DiscordNotifier
 	case NotifierTypeTeams:
 		return n.TeamsNotifier
+	case NotifierTypePagerDuty:
+		return n.PagerDutyNotifier
+	case NotifierTypeOpsgenie:
+		return n.OpsgenieNotifier
+	case NotifierTypeMattermost:
+		return n.MattermostNotifier
+	case NotifierTypeRocketChat:
+		return n.RocketChatNotifier
+	case NotifierTypeGoogleChat:
+		return n.GoogleChatNotifier
+	case NotifierTypeMatrix:
+		return n.MatrixNotifier
+	case NotifierTypeNtfy:
+		return n.NtfyNotifier
 	default:
 		panic("unknown notifier type: " + string(n.NotifierType))
 	}
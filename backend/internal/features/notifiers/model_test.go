@@ -0,0 +1,82 @@
+package notifiers
+
+import (
+	"encoding/json"
+	"postgresus-backend/internal/features/audit_logs"
+	opsgenie_notifier "postgresus-backend/internal/features/notifiers/models/opsgenie"
+	pagerduty_notifier "postgresus-backend/internal/features/notifiers/models/pagerduty"
+	webhook_notifier "postgresus-backend/internal/features/notifiers/models/webhook"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetLastDigestSlot_WeeklyDigest_LastScheduledWeekdayReturned(t *testing.T) {
+	notifier := &Notifier{
+		DigestFrequency: DigestFrequencyWeekly,
+		DigestTimeOfDay: "09:00",
+		DigestWeekday:   1,
+	}
+
+	// Wednesday
+	now := time.Date(2025, 10, 15, 12, 0, 0, 0, time.UTC)
+	slot, ok := notifier.GetLastDigestSlot(now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2025, 10, 13, 9, 0, 0, 0, time.UTC), slot)
+
+	// Monday before the time of digest
+	now = time.Date(2025, 10, 20, 8, 59, 0, 0, time.UTC)
+	slot, _ = notifier.GetLastDigestSlot(now)
+	assert.Equal(t, time.Date(2025, 10, 13, 9, 0, 0, 0, time.UTC), slot)
+
+	notifier.LastDigestAt = &slot
+	assert.False(t, notifier.IsDigestDue(now))
+	assert.True(t, notifier.IsDigestDue(now.Add(time.Minute)))
+}
+
+func Test_IsDigestDue_DigestDisabled_NotDue(t *testing.T) {
+	notifier := &Notifier{DigestFrequency: DigestFrequencyNone, DigestTimeOfDay: "09:00"}
+
+	assert.False(t, notifier.IsDigestDue(time.Now().UTC()))
+}
+
+func Test_CalculateDiff_NotifierCredentialsChanged_ChangeRecordedWithMaskedValues(t *testing.T) {
+	before := &Notifier{
+		Name:              "on-call",
+		NotifierType:      NotifierTypePagerDuty,
+		PagerDutyNotifier: &pagerduty_notifier.PagerDutyNotifier{RoutingKey: "old-routing-key"},
+		OpsgenieNotifier:  &opsgenie_notifier.OpsgenieNotifier{APIKey: "api-key-secret"},
+		WebhookNotifier: &webhook_notifier.WebhookNotifier{
+			WebhookURL: "https://example.com/hook",
+			Headers: []webhook_notifier.WebhookHeader{
+				{Name: "Authorization", Value: "Bearer header-secret"},
+			},
+		},
+	}
+
+	after := *before
+	after.PagerDutyNotifier = &pagerduty_notifier.PagerDutyNotifier{
+		RoutingKey: "new-routing-key",
+	}
+
+	createDiff := audit_logs.CalculateDiff(nil, before)
+	updateDiff := audit_logs.CalculateDiff(before, &after)
+	deleteDiff := audit_logs.CalculateDiff(&after, nil)
+
+	for _, diff := range []audit_logs.AuditLogDiff{createDiff, updateDiff, deleteDiff} {
+		data, err := json.Marshal(diff)
+		require.NoError(t, err)
+
+		assert.NotContains(t, string(data), "routing-key")
+		assert.NotContains(t, string(data), "api-key-secret")
+		assert.NotContains(t, string(data), "header-secret")
+		assert.NotContains(t, string(data), "example.com/hook")
+	}
+
+	assert.Contains(t, createDiff, "name")
+	assert.Contains(t, createDiff, "webhookNotifier.headers.0.name")
+	assert.Len(t, updateDiff, 1)
+	assert.Contains(t, updateDiff, "pagerDutyNotifier.routingKey")
+}
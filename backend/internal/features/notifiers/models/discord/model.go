@@ -8,13 +8,17 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type DiscordNotifier struct {
 	NotifierID        uuid.UUID `json:"notifierId"        gorm:"primaryKey;column:notifier_id"`
-	ChannelWebhookURL string    `json:"channelWebhookUrl" gorm:"not null;column:channel_webhook_url"`
+	ChannelWebhookURL string    `json:"channelWebhookUrl" gorm:"not null;column:channel_webhook_url;serializer:encrypted"`
 }
 
 func (d *DiscordNotifier) TableName() string {
@@ -29,14 +33,41 @@ func (d *DiscordNotifier) Validate() error {
 	return nil
 }
 
-func (d *DiscordNotifier) Send(logger *slog.Logger, heading string, message string) error {
-	fullMessage := heading
-	if message != "" {
-		fullMessage = fmt.Sprintf("%s\n\n%s", heading, message)
+// HideSensitiveData removes the webhook URL before the notifier is
+// returned to the client, the URL contains the token of the webhook
+func (d *DiscordNotifier) HideSensitiveData() {
+	d.ChannelWebhookURL = ""
+}
+
+// FillSensitiveData keeps the saved webhook URL if the client has not
+// sent a new one
+func (d *DiscordNotifier) FillSensitiveData(existing *DiscordNotifier) {
+	if d.ChannelWebhookURL == "" && existing != nil {
+		d.ChannelWebhookURL = existing.ChannelWebhookURL
 	}
+}
+
+type embedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
 
-	payload := map[string]interface{}{
-		"content": fullMessage,
+type embed struct {
+	Title       string       `json:"title"`
+	Description string       `json:"description,omitempty"`
+	URL         string       `json:"url,omitempty"`
+	Color       int64        `json:"color"`
+	Fields      []embedField `json:"fields,omitempty"`
+	Timestamp   string       `json:"timestamp"`
+}
+
+func (d *DiscordNotifier) Send(
+	logger *slog.Logger,
+	event *notifier_events.NotificationEvent,
+) error {
+	payload := map[string]any{
+		"embeds": []embed{renderEmbed(event)},
 	}
 
 	jsonPayload, err := json.Marshal(payload)
@@ -71,3 +102,34 @@ func (d *DiscordNotifier) Send(logger *slog.Logger, heading string, message stri
 
 	return nil
 }
+
+// renderEmbed renders the event as the embed colored by severity,
+// details are shown as inline fields
+func renderEmbed(event *notifier_events.NotificationEvent) embed {
+	description := event.Message
+	if event.Error != nil && *event.Error != "" {
+		description = strings.TrimSpace(description + "\n```\n" + *event.Error + "\n```")
+	}
+
+	color, _ := strconv.ParseInt(strings.TrimPrefix(event.Severity.GetColor(), "#"), 16, 64)
+
+	result := embed{
+		Title:       event.Title,
+		Description: description,
+		Color:       color,
+		Timestamp:   event.CreatedAt.Format(time.RFC3339),
+	}
+
+	for _, field := range event.GetFields() {
+		result.Fields = append(
+			result.Fields,
+			embedField{Name: field.Name, Value: field.Value, Inline: true},
+		)
+	}
+
+	if len(event.Links) > 0 {
+		result.URL = event.Links[0].URL
+	}
+
+	return result
+}
@@ -0,0 +1,68 @@
+package discord_notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"postgresus-backend/internal/util/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Send_BackupFailedEvent_EmbedSent(t *testing.T) {
+	var request struct {
+		Embeds []embed `json:"embeds"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := &DiscordNotifier{ChannelWebhookURL: server.URL}
+
+	createdAt := time.Date(2025, 10, 20, 11, 30, 0, 0, time.UTC)
+	backupError := "connection refused"
+	err := notifier.Send(logger.GetLogger(), &notifier_events.NotificationEvent{
+		Type:         notifier_events.EventTypeBackupFailed,
+		Severity:     notifier_events.SeverityError,
+		Title:        "❌ Backup failed",
+		Message:      "Backup of main failed",
+		DatabaseName: "main",
+		Error:        &backupError,
+		Links: []notifier_events.EventLink{
+			{Title: "Open database", URL: "https://postgresus.example.com/databases/1"},
+		},
+		CreatedAt: createdAt,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, request.Embeds, 1)
+	assert.Equal(t, embed{
+		Title:       "❌ Backup failed",
+		Description: "Backup of main failed\n```\nconnection refused\n```",
+		URL:         "https://postgresus.example.com/databases/1",
+		Color:       0xE01E5A,
+		Fields:      []embedField{{Name: "Database", Value: "main", Inline: true}},
+		Timestamp:   "2025-10-20T11:30:00Z",
+	}, request.Embeds[0])
+}
+
+func Test_Send_WebhookReturnsError_ErrorReturned(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "Unknown Webhook"}`))
+	}))
+	defer server.Close()
+
+	notifier := &DiscordNotifier{ChannelWebhookURL: server.URL}
+
+	err := notifier.Send(logger.GetLogger(), notifier_events.NewTestEvent())
+	assert.ErrorContains(t, err, "Unknown Webhook")
+}
@@ -0,0 +1,27 @@
+package email_notifier
+
+// EmailSMTPSecurity is the way the connection to SMTP server is
+// encrypted. Auto uses implicit TLS on port 465 and STARTTLS on other
+// ports if the server supports it
+type EmailSMTPSecurity string
+
+const (
+	EmailSMTPSecurityAuto     EmailSMTPSecurity = "AUTO"
+	EmailSMTPSecurityNone     EmailSMTPSecurity = "NONE"
+	EmailSMTPSecurityStartTLS EmailSMTPSecurity = "STARTTLS"
+	EmailSMTPSecurityTLS      EmailSMTPSecurity = "TLS"
+)
+
+type EmailAuthType string
+
+const (
+	EmailAuthTypePassword EmailAuthType = "PASSWORD"
+	EmailAuthTypeOAuth2   EmailAuthType = "OAUTH2"
+)
+
+type EmailOAuth2Provider string
+
+const (
+	EmailOAuth2ProviderGoogle    EmailOAuth2Provider = "GOOGLE"
+	EmailOAuth2ProviderMicrosoft EmailOAuth2Provider = "MICROSOFT"
+)
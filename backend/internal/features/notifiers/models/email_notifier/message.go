@@ -0,0 +1,142 @@
+package email_notifier
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+const base64LineLength = 76
+
+type emailAttachment struct {
+	Name    string
+	Content []byte
+}
+
+type emailMessage struct {
+	From        string
+	To          []string
+	CC          []string
+	Subject     string
+	HTMLBody    string
+	TextBody    string
+	Attachments []emailAttachment
+}
+
+// Build renders the message as multipart/alternative, so clients
+// without HTML show the plain text. With attachments the alternative
+// part is wrapped into multipart/mixed
+func (m *emailMessage) Build() ([]byte, error) {
+	var body bytes.Buffer
+
+	alternativeWriter := multipart.NewWriter(&body)
+	if err := writeTextPart(alternativeWriter, "text/plain", m.TextBody); err != nil {
+		return nil, err
+	}
+	if err := writeTextPart(alternativeWriter, "text/html", m.HTMLBody); err != nil {
+		return nil, err
+	}
+	if err := alternativeWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	contentType := "multipart/alternative; boundary=" + alternativeWriter.Boundary()
+
+	if len(m.Attachments) > 0 {
+		alternativeBody := body.Bytes()
+		body = bytes.Buffer{}
+
+		mixedWriter := multipart.NewWriter(&body)
+
+		part, err := mixedWriter.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := part.Write(alternativeBody); err != nil {
+			return nil, err
+		}
+
+		for _, attachment := range m.Attachments {
+			if err := writeAttachmentPart(mixedWriter, attachment); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := mixedWriter.Close(); err != nil {
+			return nil, err
+		}
+
+		contentType = "multipart/mixed; boundary=" + mixedWriter.Boundary()
+	}
+
+	var message bytes.Buffer
+	writeHeader(&message, "From", m.From)
+	writeHeader(&message, "To", strings.Join(m.To, ", "))
+	if len(m.CC) > 0 {
+		writeHeader(&message, "Cc", strings.Join(m.CC, ", "))
+	}
+	writeHeader(&message, "Subject", mime.QEncoding.Encode("UTF-8", m.Subject))
+	writeHeader(&message, "Date", time.Now().UTC().Format(time.RFC1123Z))
+	writeHeader(&message, "MIME-Version", "1.0")
+	writeHeader(&message, "Content-Type", contentType)
+	message.WriteString("\r\n")
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
+
+func writeHeader(message *bytes.Buffer, name string, value string) {
+	fmt.Fprintf(message, "%s: %s\r\n", name, value)
+}
+
+func writeTextPart(writer *multipart.Writer, mimeType string, text string) error {
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {mimeType + "; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+
+	encoder := quotedprintable.NewWriter(part)
+	if _, err := encoder.Write([]byte(text)); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+func writeAttachmentPart(writer *multipart.Writer, attachment emailAttachment) error {
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {mime.FormatMediaType(
+			"text/plain",
+			map[string]string{"charset": "UTF-8", "name": attachment.Name},
+		)},
+		"Content-Disposition": {mime.FormatMediaType(
+			"attachment",
+			map[string]string{"filename": attachment.Name},
+		)},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(attachment.Content)
+	for len(encoded) > 0 {
+		lineLength := min(base64LineLength, len(encoded))
+		if _, err := part.Write([]byte(encoded[:lineLength] + "\r\n")); err != nil {
+			return err
+		}
+
+		encoded = encoded[lineLength:]
+	}
+
+	return nil
+}
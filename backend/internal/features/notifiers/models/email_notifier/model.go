@@ -6,7 +6,11 @@ import (
 	"fmt"
 	"log/slog"
 	"net"
+	"net/mail"
 	"net/smtp"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,17 +20,33 @@ const (
 	ImplicitTLSPort  = 465
 	DefaultTimeout   = 5 * time.Second
 	DefaultHelloName = "localhost"
-	MIMETypeHTML     = "text/html"
-	MIMECharsetUTF8  = "UTF-8"
+
+	backupLogAttachmentName = "backup-error.log"
+	maxRecipientsCount      = 50
 )
 
 type EmailNotifier struct {
-	NotifierID   uuid.UUID `json:"notifierId"   gorm:"primaryKey;type:uuid;column:notifier_id"`
-	TargetEmail  string    `json:"targetEmail"  gorm:"not null;type:varchar(255);column:target_email"`
-	SMTPHost     string    `json:"smtpHost"     gorm:"not null;type:varchar(255);column:smtp_host"`
-	SMTPPort     int       `json:"smtpPort"     gorm:"not null;column:smtp_port"`
-	SMTPUser     string    `json:"smtpUser"     gorm:"type:varchar(255);column:smtp_user"`
-	SMTPPassword string    `json:"smtpPassword" gorm:"type:varchar(255);column:smtp_password"`
+	NotifierID   uuid.UUID         `json:"notifierId"   gorm:"primaryKey;type:uuid;column:notifier_id"`
+	TargetEmail  string            `json:"targetEmail"  gorm:"not null;type:text;column:target_email"`
+	CCEmails     string            `json:"ccEmails"     gorm:"type:text;column:cc_emails"`
+	SMTPHost     string            `json:"smtpHost"     gorm:"not null;type:varchar(255);column:smtp_host"`
+	SMTPPort     int               `json:"smtpPort"     gorm:"not null;column:smtp_port"`
+	SMTPSecurity EmailSMTPSecurity `json:"smtpSecurity" gorm:"not null;type:varchar(20);column:smtp_security"`
+	SMTPUser     string            `json:"smtpUser"     gorm:"type:varchar(255);column:smtp_user"`
+	SMTPPassword string            `json:"smtpPassword" gorm:"type:text;column:smtp_password;serializer:encrypted"`
+
+	// OAuth2 is used instead of the password by Gmail and Office 365,
+	// the refresh token is exchanged for the access token on each email
+	SMTPAuthType       EmailAuthType       `json:"smtpAuthType"       gorm:"not null;type:varchar(20);column:smtp_auth_type"`
+	OAuth2Provider     EmailOAuth2Provider `json:"oauth2Provider"     gorm:"type:varchar(20);column:oauth2_provider"`
+	OAuth2TenantID     string              `json:"oauth2TenantId"     gorm:"type:varchar(255);column:oauth2_tenant_id"`
+	OAuth2ClientID     string              `json:"oauth2ClientId"     gorm:"type:text;column:oauth2_client_id"`
+	OAuth2ClientSecret string              `json:"oauth2ClientSecret" gorm:"type:text;column:oauth2_client_secret;serializer:encrypted"`
+	OAuth2RefreshToken string              `json:"oauth2RefreshToken" gorm:"type:text;column:oauth2_refresh_token;serializer:encrypted"`
+
+	// output of failed backup is attached as a file, the message has
+	// only the beginning of it
+	IsBackupLogAttached bool `json:"isBackupLogAttached" gorm:"not null;column:is_backup_log_attached"`
 }
 
 func (e *EmailNotifier) TableName() string {
@@ -34,10 +54,19 @@ func (e *EmailNotifier) TableName() string {
 }
 
 func (e *EmailNotifier) Validate() error {
-	if e.TargetEmail == "" {
+	to, cc, err := e.GetRecipients()
+	if err != nil {
+		return err
+	}
+
+	if len(to) == 0 {
 		return errors.New("target email is required")
 	}
 
+	if len(to)+len(cc) > maxRecipientsCount {
+		return fmt.Errorf("no more than %d recipients are allowed", maxRecipientsCount)
+	}
+
 	if e.SMTPHost == "" {
 		return errors.New("SMTP host is required")
 	}
@@ -46,165 +75,295 @@ func (e *EmailNotifier) Validate() error {
 		return errors.New("SMTP port is required")
 	}
 
-	// Authentication is optional - both user and password must be provided together or both empty
-	if (e.SMTPUser == "") != (e.SMTPPassword == "") {
-		return errors.New("SMTP user and password must both be provided or both be empty")
+	switch e.SMTPSecurity {
+	case "", EmailSMTPSecurityAuto, EmailSMTPSecurityNone,
+		EmailSMTPSecurityStartTLS, EmailSMTPSecurityTLS:
+	default:
+		return fmt.Errorf("unknown SMTP security: %s", e.SMTPSecurity)
+	}
+
+	switch e.SMTPAuthType {
+	case "", EmailAuthTypePassword:
+		// Authentication is optional - both user and password must be provided together or both empty
+		if (e.SMTPUser == "") != (e.SMTPPassword == "") {
+			return errors.New("SMTP user and password must both be provided or both be empty")
+		}
+	case EmailAuthTypeOAuth2:
+		return e.validateOAuth2()
+	default:
+		return fmt.Errorf("unknown SMTP auth type: %s", e.SMTPAuthType)
 	}
 
 	return nil
 }
 
-func (e *EmailNotifier) Send(logger *slog.Logger, heading string, message string) error {
-	// Compose email
+func (e *EmailNotifier) validateOAuth2() error {
+	if _, ok := oauth2TokenURLs[e.OAuth2Provider]; !ok {
+		return fmt.Errorf("unknown OAuth2 provider: %s", e.OAuth2Provider)
+	}
+
+	if e.SMTPUser == "" {
+		return errors.New("SMTP user is required for OAuth2")
+	}
+
+	if e.OAuth2ClientID == "" {
+		return errors.New("OAuth2 client ID is required")
+	}
+
+	if e.OAuth2ClientSecret == "" {
+		return errors.New("OAuth2 client secret is required")
+	}
+
+	if e.OAuth2RefreshToken == "" {
+		return errors.New("OAuth2 refresh token is required")
+	}
+
+	return nil
+}
+
+// GetRecipients returns addresses of To and CC, emails in the lists
+// are separated by commas or semicolons
+func (e *EmailNotifier) GetRecipients() ([]string, []string, error) {
+	to, err := parseEmails(e.TargetEmail)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cc, err := parseEmails(e.CCEmails)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return to, cc, nil
+}
+
+// HideSensitiveData removes the SMTP password and OAuth2 secrets before
+// the notifier is returned to the client, they are write only
+func (e *EmailNotifier) HideSensitiveData() {
+	e.SMTPPassword = ""
+	e.OAuth2ClientSecret = ""
+	e.OAuth2RefreshToken = ""
+}
+
+// FillSensitiveData keeps the saved SMTP password and OAuth2 secrets if
+// the client has not sent new ones. They are kept only for the same
+// server and user, otherwise changed host would reveal the password or
+// the access token obtained with the secrets
+func (e *EmailNotifier) FillSensitiveData(existing *EmailNotifier) {
+	if existing == nil {
+		return
+	}
+
+	if e.SMTPHost != existing.SMTPHost ||
+		e.SMTPPort != existing.SMTPPort ||
+		e.SMTPUser != existing.SMTPUser {
+		return
+	}
+
+	if e.SMTPPassword == "" {
+		e.SMTPPassword = existing.SMTPPassword
+	}
+
+	if e.OAuth2Provider != existing.OAuth2Provider ||
+		e.OAuth2TenantID != existing.OAuth2TenantID ||
+		e.OAuth2ClientID != existing.OAuth2ClientID {
+		return
+	}
+
+	if e.OAuth2ClientSecret == "" {
+		e.OAuth2ClientSecret = existing.OAuth2ClientSecret
+	}
+
+	if e.OAuth2RefreshToken == "" {
+		e.OAuth2RefreshToken = existing.OAuth2RefreshToken
+	}
+}
+
+func (e *EmailNotifier) Send(
+	logger *slog.Logger,
+	event *notifier_events.NotificationEvent,
+) error {
+	to, cc, err := e.GetRecipients()
+	if err != nil {
+		return err
+	}
+
+	htmlBody, err := renderBody(event)
+	if err != nil {
+		return fmt.Errorf("failed to render email: %w", err)
+	}
+
 	from := e.SMTPUser
 	if from == "" {
 		from = "noreply@" + e.SMTPHost
 	}
 
-	to := []string{e.TargetEmail}
-
-	// Format the email content
-	subject := fmt.Sprintf("Subject: %s\r\n", heading)
-	mime := fmt.Sprintf(
-		"MIME-version: 1.0;\nContent-Type: %s; charset=\"%s\";\n\n",
-		MIMETypeHTML,
-		MIMECharsetUTF8,
-	)
-	body := message
-	fromHeader := fmt.Sprintf("From: %s\r\n", from)
+	message := &emailMessage{
+		From:     from,
+		To:       to,
+		CC:       cc,
+		Subject:  event.Title,
+		HTMLBody: htmlBody,
+		TextBody: renderPlainText(event),
+	}
 
-	// Combine all parts of the email
-	emailContent := []byte(fromHeader + subject + mime + body)
+	if e.IsBackupLogAttached && event.Log != nil && *event.Log != "" {
+		message.Attachments = append(message.Attachments, emailAttachment{
+			Name:    backupLogAttachmentName,
+			Content: []byte(*event.Log),
+		})
+	}
 
-	addr := net.JoinHostPort(e.SMTPHost, fmt.Sprintf("%d", e.SMTPPort))
-	timeout := DefaultTimeout
+	emailContent, err := message.Build()
+	if err != nil {
+		return fmt.Errorf("failed to build email: %w", err)
+	}
 
-	// Determine if authentication is required
-	isAuthRequired := e.SMTPUser != "" && e.SMTPPassword != ""
+	auth, err := e.getAuth()
+	if err != nil {
+		return err
+	}
 
-	// Handle different port scenarios
-	if e.SMTPPort == ImplicitTLSPort {
-		// Implicit TLS (port 465)
-		// Set up TLS config
-		tlsConfig := &tls.Config{
-			ServerName: e.SMTPHost,
+	client, err := e.connect()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	// Authenticate only if credentials are provided
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
 		}
+	}
 
-		// Dial with timeout
-		dialer := &net.Dialer{Timeout: timeout}
-		conn, err := tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
-		if err != nil {
-			return fmt.Errorf("failed to connect to SMTP server: %w", err)
-		}
-		defer func() {
-			_ = conn.Close()
-		}()
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("failed to set sender: %w", err)
+	}
 
-		// Create SMTP client
-		client, err := smtp.NewClient(conn, e.SMTPHost)
-		if err != nil {
-			return fmt.Errorf("failed to create SMTP client: %w", err)
-		}
-		defer func() {
-			_ = client.Quit()
-		}()
-
-		// Set up authentication only if credentials are provided
-		if isAuthRequired {
-			auth := smtp.PlainAuth("", e.SMTPUser, e.SMTPPassword, e.SMTPHost)
-			if err := client.Auth(auth); err != nil {
-				return fmt.Errorf("SMTP authentication failed: %w", err)
-			}
+	for _, recipient := range append(to, cc...) {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("failed to set recipient %s: %w", recipient, err)
 		}
+	}
 
-		// Set sender and recipients
-		if err := client.Mail(from); err != nil {
-			return fmt.Errorf("failed to set sender: %w", err)
-		}
-		for _, recipient := range to {
-			if err := client.Rcpt(recipient); err != nil {
-				return fmt.Errorf("failed to set recipient: %w", err)
-			}
-		}
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to get data writer: %w", err)
+	}
 
-		// Send the email body
-		writer, err := client.Data()
-		if err != nil {
-			return fmt.Errorf("failed to get data writer: %w", err)
-		}
-		_, err = writer.Write(emailContent)
-		if err != nil {
-			return fmt.Errorf("failed to write email content: %w", err)
-		}
-		err = writer.Close()
-		if err != nil {
-			return fmt.Errorf("failed to close data writer: %w", err)
-		}
+	if _, err := writer.Write(emailContent); err != nil {
+		return fmt.Errorf("failed to write email content: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close data writer: %w", err)
+	}
+
+	return client.Quit()
+}
 
-		return nil
+// connect opens the connection to SMTP server encrypted as selected:
+// implicit TLS from the start or STARTTLS after hello
+func (e *EmailNotifier) connect() (*smtp.Client, error) {
+	addr := net.JoinHostPort(e.SMTPHost, strconv.Itoa(e.SMTPPort))
+	dialer := &net.Dialer{Timeout: DefaultTimeout}
+	tlsConfig := &tls.Config{ServerName: e.SMTPHost}
+	security := e.getSMTPSecurity()
+
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	if security == EmailSMTPSecurityTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
 	} else {
-		// STARTTLS (port 587) or other ports
-		// Create a custom dialer with timeout
-		dialer := &net.Dialer{Timeout: timeout}
-		conn, err := dialer.Dial("tcp", addr)
-		if err != nil {
-			return fmt.Errorf("failed to connect to SMTP server: %w", err)
-		}
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
 
-		// Create client from connection
-		client, err := smtp.NewClient(conn, e.SMTPHost)
-		if err != nil {
-			return fmt.Errorf("failed to create SMTP client: %w", err)
-		}
-		defer func() {
-			_ = client.Quit()
-		}()
+	client, err := smtp.NewClient(conn, e.SMTPHost)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to create SMTP client: %w", err)
+	}
 
-		// Send email using the client
-		if err := client.Hello(DefaultHelloName); err != nil {
-			return fmt.Errorf("SMTP hello failed: %w", err)
-		}
+	if err := client.Hello(DefaultHelloName); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("SMTP hello failed: %w", err)
+	}
 
-		// Start TLS if available
-		if ok, _ := client.Extension("STARTTLS"); ok {
-			if err := client.StartTLS(&tls.Config{ServerName: e.SMTPHost}); err != nil {
-				return fmt.Errorf("STARTTLS failed: %w", err)
-			}
-		}
+	isStartTLSSupported, _ := client.Extension("STARTTLS")
 
-		// Authenticate only if credentials are provided
-		if isAuthRequired {
-			auth := smtp.PlainAuth("", e.SMTPUser, e.SMTPPassword, e.SMTPHost)
-			if err := client.Auth(auth); err != nil {
-				return fmt.Errorf("SMTP authentication failed: %w", err)
-			}
-		}
+	if security == EmailSMTPSecurityStartTLS && !isStartTLSSupported {
+		_ = client.Close()
+		return nil, errors.New("SMTP server does not support STARTTLS")
+	}
 
-		if err := client.Mail(from); err != nil {
-			return fmt.Errorf("failed to set sender: %w", err)
+	if (security == EmailSMTPSecurityStartTLS || security == EmailSMTPSecurityAuto) &&
+		isStartTLSSupported {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			_ = client.Close()
+			return nil, fmt.Errorf("STARTTLS failed: %w", err)
 		}
+	}
 
-		for _, recipient := range to {
-			if err := client.Rcpt(recipient); err != nil {
-				return fmt.Errorf("failed to set recipient: %w", err)
-			}
-		}
+	return client, nil
+}
 
-		writer, err := client.Data()
+// getSMTPSecurity resolves auto security by the port, notifiers saved
+// before the security was selectable have it empty
+func (e *EmailNotifier) getSMTPSecurity() EmailSMTPSecurity {
+	if e.SMTPSecurity != "" && e.SMTPSecurity != EmailSMTPSecurityAuto {
+		return e.SMTPSecurity
+	}
+
+	if e.SMTPPort == ImplicitTLSPort {
+		return EmailSMTPSecurityTLS
+	}
+
+	return EmailSMTPSecurityAuto
+}
+
+func (e *EmailNotifier) getAuth() (smtp.Auth, error) {
+	if e.SMTPAuthType == EmailAuthTypeOAuth2 {
+		accessToken, err := e.getOAuth2AccessToken()
 		if err != nil {
-			return fmt.Errorf("failed to get data writer: %w", err)
+			return nil, err
 		}
 
-		_, err = writer.Write(emailContent)
-		if err != nil {
-			return fmt.Errorf("failed to write email content: %w", err)
+		return &xoauth2Auth{username: e.SMTPUser, accessToken: accessToken}, nil
+	}
+
+	if e.SMTPUser == "" || e.SMTPPassword == "" {
+		return nil, nil
+	}
+
+	return smtp.PlainAuth("", e.SMTPUser, e.SMTPPassword, e.SMTPHost), nil
+}
+
+func parseEmails(emails string) ([]string, error) {
+	var result []string
+
+	parts := strings.FieldsFunc(emails, func(r rune) bool { return r == ',' || r == ';' })
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
 		}
 
-		err = writer.Close()
+		address, err := mail.ParseAddress(part)
 		if err != nil {
-			return fmt.Errorf("failed to close data writer: %w", err)
+			return nil, fmt.Errorf("invalid email: %s", part)
 		}
 
-		return client.Quit()
+		result = append(result, address.Address)
 	}
+
+	return result, nil
 }
@@ -0,0 +1,283 @@
+package email_notifier
+
+import (
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"postgresus-backend/internal/util/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Send_FailedBackupToSeveralRecipients_HtmlTextAndLogDelivered(t *testing.T) {
+	sink := startSMTPSink(t)
+
+	notifier := &EmailNotifier{
+		TargetEmail:         "admin@example.com; dba@example.com",
+		CCEmails:            "Team <team@example.com>",
+		SMTPHost:            "127.0.0.1",
+		SMTPPort:            sink.port,
+		SMTPSecurity:        EmailSMTPSecurityNone,
+		IsBackupLogAttached: true,
+	}
+	require.NoError(t, notifier.Validate())
+
+	backupError := "pg_dump failed: exit status 1"
+	backupLog := "pg_dump: dumping contents of table \"public.users\"\n" + backupError
+	err := notifier.Send(logger.GetLogger(), &notifier_events.NotificationEvent{
+		Type:         notifier_events.EventTypeBackupFailed,
+		Severity:     notifier_events.SeverityError,
+		Title:        "❌ Backup failed for database \"main\"",
+		DatabaseName: "main",
+		Error:        &backupError,
+		Log:          &backupLog,
+	})
+	require.NoError(t, err)
+	<-sink.done
+
+	assert.Equal(
+		t,
+		[]string{"admin@example.com", "dba@example.com", "team@example.com"},
+		sink.recipients,
+	)
+
+	message, err := mail.ReadMessage(strings.NewReader(sink.data))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "❌ Backup failed for database \"main\"", subject)
+	assert.Equal(t, "team@example.com", message.Header.Get("Cc"))
+
+	mixedParts := readParts(t, message.Header.Get("Content-Type"), message.Body)
+	require.Len(t, mixedParts, 2)
+
+	alternativeParts := readParts(
+		t,
+		mixedParts[0].header.Get("Content-Type"),
+		strings.NewReader(mixedParts[0].body),
+	)
+	require.Len(t, alternativeParts, 2)
+	assert.Contains(t, alternativeParts[0].header.Get("Content-Type"), "text/plain")
+	assert.Contains(t, alternativeParts[0].body, "Database: main")
+	assert.Contains(t, alternativeParts[1].header.Get("Content-Type"), "text/html")
+	assert.Contains(t, alternativeParts[1].body, "<html>")
+
+	assert.Contains(t, mixedParts[1].header.Get("Content-Disposition"), backupLogAttachmentName)
+	attachedLog, err := base64.StdEncoding.DecodeString(
+		strings.ReplaceAll(mixedParts[1].body, "\r\n", ""),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, backupLog, string(attachedLog))
+}
+
+func Test_Send_OAuth2Auth_RefreshedAccessTokenUsedForXOAuth2(t *testing.T) {
+	sink := startSMTPSink(t)
+
+	var refreshToken string
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		refreshToken = r.PostForm.Get("refresh_token")
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"access-token","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+
+	tokenURL := oauth2TokenURLs[EmailOAuth2ProviderGoogle]
+	oauth2TokenURLs[EmailOAuth2ProviderGoogle] = tokenServer.URL
+	defer func() {
+		oauth2TokenURLs[EmailOAuth2ProviderGoogle] = tokenURL
+	}()
+
+	notifier := &EmailNotifier{
+		TargetEmail:        "admin@example.com",
+		SMTPHost:           "127.0.0.1",
+		SMTPPort:           sink.port,
+		SMTPSecurity:       EmailSMTPSecurityNone,
+		SMTPUser:           "sender@gmail.com",
+		SMTPAuthType:       EmailAuthTypeOAuth2,
+		OAuth2Provider:     EmailOAuth2ProviderGoogle,
+		OAuth2ClientID:     "client-id",
+		OAuth2ClientSecret: "client-secret",
+		OAuth2RefreshToken: "refresh-token",
+	}
+	require.NoError(t, notifier.Validate())
+
+	err := notifier.Send(logger.GetLogger(), notifier_events.NewTestEvent())
+	require.NoError(t, err)
+	<-sink.done
+
+	assert.Equal(t, "refresh-token", refreshToken)
+	assert.Equal(
+		t,
+		"XOAUTH2 user=sender@gmail.com\x01auth=Bearer access-token\x01\x01",
+		sink.auth,
+	)
+}
+
+func Test_Validate_InvalidCCEmail_ErrorReturned(t *testing.T) {
+	notifier := &EmailNotifier{
+		TargetEmail: "admin@example.com",
+		CCEmails:    "team@example.com, not-an-email",
+		SMTPHost:    "smtp.example.com",
+		SMTPPort:    587,
+	}
+
+	assert.EqualError(t, notifier.Validate(), "invalid email: not-an-email")
+}
+
+func Test_FillSensitiveData_SmtpServerChanged_OAuth2SecretsNotKept(t *testing.T) {
+	existing := &EmailNotifier{
+		SMTPHost:           "smtp.gmail.com",
+		SMTPPort:           587,
+		SMTPUser:           "sender@gmail.com",
+		SMTPAuthType:       EmailAuthTypeOAuth2,
+		OAuth2Provider:     EmailOAuth2ProviderGoogle,
+		OAuth2ClientID:     "client-id",
+		OAuth2ClientSecret: "client-secret",
+		OAuth2RefreshToken: "refresh-token",
+	}
+
+	sameServer := *existing
+	sameServer.OAuth2ClientSecret = ""
+	sameServer.OAuth2RefreshToken = ""
+	sameServer.FillSensitiveData(existing)
+	assert.Equal(t, "client-secret", sameServer.OAuth2ClientSecret)
+	assert.Equal(t, "refresh-token", sameServer.OAuth2RefreshToken)
+
+	changedHost := sameServer
+	changedHost.SMTPHost = "smtp.attacker.com"
+	changedHost.OAuth2ClientSecret = ""
+	changedHost.OAuth2RefreshToken = ""
+	changedHost.FillSensitiveData(existing)
+	assert.Empty(t, changedHost.OAuth2ClientSecret)
+	assert.Empty(t, changedHost.OAuth2RefreshToken)
+
+	changedPort := sameServer
+	changedPort.SMTPPort = 2525
+	changedPort.OAuth2ClientSecret = ""
+	changedPort.OAuth2RefreshToken = ""
+	changedPort.FillSensitiveData(existing)
+	assert.Empty(t, changedPort.OAuth2ClientSecret)
+	assert.Empty(t, changedPort.OAuth2RefreshToken)
+}
+
+type messagePart struct {
+	header textproto.MIMEHeader
+	body   string
+}
+
+func readParts(t *testing.T, contentType string, body io.Reader) []messagePart {
+	_, params, err := mime.ParseMediaType(contentType)
+	require.NoError(t, err)
+
+	var parts []messagePart
+
+	reader := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return parts
+		}
+		require.NoError(t, err)
+
+		partBody, err := io.ReadAll(part)
+		require.NoError(t, err)
+
+		parts = append(parts, messagePart{header: part.Header, body: string(partBody)})
+	}
+}
+
+// smtpSink is the SMTP server which accepts one email and keeps it
+// to check what is delivered, as MailHog does
+type smtpSink struct {
+	port int
+	done chan struct{}
+
+	auth       string
+	recipients []string
+	data       string
+}
+
+func startSMTPSink(t *testing.T) *smtpSink {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	sink := &smtpSink{
+		port: listener.Addr().(*net.TCPAddr).Port,
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(sink.done)
+
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		sink.serve(textproto.NewConn(conn))
+	}()
+
+	return sink
+}
+
+func (s *smtpSink) serve(conn *textproto.Conn) {
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	_ = conn.PrintfLine("220 localhost ESMTP sink")
+
+	for {
+		line, err := conn.ReadLine()
+		if err != nil {
+			return
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch strings.ToUpper(fields[0]) {
+		case "EHLO":
+			_ = conn.PrintfLine("250-localhost")
+			_ = conn.PrintfLine("250 AUTH PLAIN XOAUTH2")
+		case "AUTH":
+			response, _ := base64.StdEncoding.DecodeString(fields[len(fields)-1])
+			s.auth = fields[1] + " " + string(response)
+			_ = conn.PrintfLine("235 Authentication successful")
+		case "RCPT":
+			recipient := strings.TrimPrefix(line, "RCPT TO:")
+			s.recipients = append(s.recipients, strings.Trim(recipient, "<>"))
+			_ = conn.PrintfLine("250 OK")
+		case "DATA":
+			_ = conn.PrintfLine("354 Start mail input")
+
+			lines, _ := conn.ReadDotLines()
+			s.data = strings.Join(lines, "\r\n")
+			_ = conn.PrintfLine("250 OK")
+		case "QUIT":
+			_ = conn.PrintfLine("221 Bye")
+			return
+		default:
+			_ = conn.PrintfLine("250 OK")
+		}
+	}
+}
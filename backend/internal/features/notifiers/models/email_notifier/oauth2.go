@@ -0,0 +1,87 @@
+package email_notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/smtp"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+const defaultMicrosoftTenantID = "common"
+
+// oauth2TokenURLs are token endpoints of providers, {tenant} is
+// replaced by the tenant of Microsoft. They are replaced by a local
+// server in tests
+var oauth2TokenURLs = map[EmailOAuth2Provider]string{
+	EmailOAuth2ProviderGoogle:    "https://oauth2.googleapis.com/token",
+	EmailOAuth2ProviderMicrosoft: "https://login.microsoftonline.com/{tenant}/oauth2/v2.0/token",
+}
+
+var oauth2Scopes = map[EmailOAuth2Provider][]string{
+	EmailOAuth2ProviderGoogle:    {"https://mail.google.com/"},
+	EmailOAuth2ProviderMicrosoft: {"https://outlook.office.com/SMTP.Send", "offline_access"},
+}
+
+// getOAuth2AccessToken exchanges the refresh token for the access
+// token. Notifications are rare, so the token is not cached
+func (e *EmailNotifier) getOAuth2AccessToken() (string, error) {
+	tenantID := e.OAuth2TenantID
+	if tenantID == "" {
+		tenantID = defaultMicrosoftTenantID
+	}
+
+	config := &oauth2.Config{
+		ClientID:     e.OAuth2ClientID,
+		ClientSecret: e.OAuth2ClientSecret,
+		Endpoint: oauth2.Endpoint{
+			TokenURL:  strings.ReplaceAll(oauth2TokenURLs[e.OAuth2Provider], "{tenant}", tenantID),
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+		Scopes: oauth2Scopes[e.OAuth2Provider],
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+
+	token, err := config.TokenSource(ctx, &oauth2.Token{RefreshToken: e.OAuth2RefreshToken}).Token()
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh OAuth2 token: %w", err)
+	}
+
+	return token.AccessToken, nil
+}
+
+// xoauth2Auth implements XOAUTH2 SASL mechanism of Gmail and Office 365
+type xoauth2Auth struct {
+	username    string
+	accessToken string
+}
+
+func (a *xoauth2Auth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	// the token must not be sent in clear text, as the password of
+	// plain auth
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+
+	response := "user=" + a.username + "\x01auth=Bearer " + a.accessToken + "\x01\x01"
+
+	return "XOAUTH2", []byte(response), nil
+}
+
+// Next answers the error challenge with the empty response, then the
+// server fails the authentication with the error code
+func (a *xoauth2Auth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if more {
+		return []byte{}, nil
+	}
+
+	return nil, nil
+}
+
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
@@ -0,0 +1,76 @@
+package email_notifier
+
+import (
+	"bytes"
+	"html/template"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"strings"
+)
+
+const bodyTemplateHTML = `<!DOCTYPE html>
+<html>
+<body style="margin: 0; padding: 16px; font-family: Arial, sans-serif; color: #1f2937;">
+  <div style="max-width: 600px; border-left: 4px solid {{.Color}}; padding: 4px 16px;">
+    <h2 style="margin: 0 0 12px 0; font-size: 18px;">{{.Title}}</h2>
+    {{- if .Message}}
+    <p style="margin: 0 0 12px 0; white-space: pre-wrap;">{{.Message}}</p>
+    {{- end}}
+    {{- if .Fields}}
+    <table style="margin: 0 0 12px 0; border-collapse: collapse;">
+      {{- range .Fields}}
+      <tr>
+        <td style="padding: 2px 16px 2px 0; color: #6b7280;">{{.Name}}</td>
+        <td style="padding: 2px 0;">{{.Value}}</td>
+      </tr>
+      {{- end}}
+    </table>
+    {{- end}}
+    {{- if .Error}}
+    <pre style="margin: 0 0 12px 0; padding: 8px; background: #f3f4f6; white-space: pre-wrap;">{{.Error}}</pre>
+    {{- end}}
+    {{- range .Links}}
+    <p style="margin: 0 0 12px 0;"><a href="{{.URL}}">{{.Title}}</a></p>
+    {{- end}}
+  </div>
+</body>
+</html>
+`
+
+var bodyTemplate = template.Must(template.New("email").Parse(bodyTemplateHTML))
+
+type bodyTemplateData struct {
+	Title   string
+	Message string
+	Color   string
+	Fields  []notifier_events.EventField
+	Error   string
+	Links   []notifier_events.EventLink
+}
+
+// renderBody renders the event as HTML email. The template escapes
+// texts, so errors with HTML do not break the layout
+func renderBody(event *notifier_events.NotificationEvent) (string, error) {
+	data := bodyTemplateData{
+		Title:   event.Title,
+		Message: event.Message,
+		Color:   event.Severity.GetColor(),
+		Fields:  event.GetFields(),
+		Links:   event.Links,
+	}
+
+	if event.Error != nil {
+		data.Error = *event.Error
+	}
+
+	var body bytes.Buffer
+	if err := bodyTemplate.Execute(&body, data); err != nil {
+		return "", err
+	}
+
+	return body.String(), nil
+}
+
+// renderPlainText renders the event for clients which do not show HTML
+func renderPlainText(event *notifier_events.NotificationEvent) string {
+	return strings.TrimSpace(event.Title + "\n\n" + event.GetPlainText())
+}
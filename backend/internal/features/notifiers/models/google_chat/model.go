@@ -0,0 +1,151 @@
+package google_chat_notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type GoogleChatNotifier struct {
+	NotifierID uuid.UUID `json:"notifierId" gorm:"primaryKey;column:notifier_id"`
+	WebhookURL string    `json:"webhookUrl" gorm:"not null;column:webhook_url;type:text;serializer:encrypted"`
+}
+
+func (g *GoogleChatNotifier) TableName() string {
+	return "google_chat_notifiers"
+}
+
+func (g *GoogleChatNotifier) Validate() error {
+	if g.WebhookURL == "" {
+		return errors.New("webhook URL is required")
+	}
+
+	u, err := url.Parse(g.WebhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return errors.New("invalid webhook URL")
+	}
+
+	return nil
+}
+
+// HideSensitiveData removes the webhook URL before the notifier is
+// returned to the client, the URL contains the key and the token
+func (g *GoogleChatNotifier) HideSensitiveData() {
+	g.WebhookURL = ""
+}
+
+// FillSensitiveData keeps the saved webhook URL if the client has not
+// sent a new one
+func (g *GoogleChatNotifier) FillSensitiveData(existing *GoogleChatNotifier) {
+	if g.WebhookURL == "" && existing != nil {
+		g.WebhookURL = existing.WebhookURL
+	}
+}
+
+func (g *GoogleChatNotifier) Send(
+	logger *slog.Logger,
+	event *notifier_events.NotificationEvent,
+) error {
+	// text is shown in push notifications, the card is shown in the space
+	body, err := json.Marshal(map[string]any{
+		"text": event.Title,
+		"cardsV2": []map[string]any{
+			{"cardId": "postgresus", "card": renderCard(event)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal Google Chat payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, g.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send Google Chat message: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf(
+			"google chat webhook returned non-OK status: %s. Error: %s",
+			resp.Status,
+			string(bodyBytes),
+		)
+	}
+
+	return nil
+}
+
+// renderCard renders the event as the card: the title colored by
+// severity, details as decorated texts and links as buttons. Text
+// widgets support only a few HTML tags, so values are escaped
+func renderCard(event *notifier_events.NotificationEvent) map[string]any {
+	widgets := []map[string]any{
+		textParagraph(fmt.Sprintf(
+			`<b><font color="%s">%s</font></b>`,
+			event.Severity.GetColor(),
+			html.EscapeString(event.Title),
+		)),
+	}
+
+	if event.Message != "" {
+		widgets = append(widgets, textParagraph(formatText(event.Message)))
+	}
+
+	for _, field := range event.GetFields() {
+		widgets = append(widgets, map[string]any{
+			"decoratedText": map[string]any{
+				"topLabel": field.Name,
+				"text":     html.EscapeString(field.Value),
+			},
+		})
+	}
+
+	if event.Error != nil && *event.Error != "" {
+		widgets = append(widgets, textParagraph(
+			`<font color="#E01E5A">`+formatText(*event.Error)+`</font>`,
+		))
+	}
+
+	if len(event.Links) > 0 {
+		buttons := make([]map[string]any, 0, len(event.Links))
+		for _, link := range event.Links {
+			buttons = append(buttons, map[string]any{
+				"text":    link.Title,
+				"onClick": map[string]any{"openLink": map[string]any{"url": link.URL}},
+			})
+		}
+
+		widgets = append(widgets, map[string]any{"buttonList": map[string]any{"buttons": buttons}})
+	}
+
+	return map[string]any{
+		"sections": []map[string]any{{"widgets": widgets}},
+	}
+}
+
+func textParagraph(text string) map[string]any {
+	return map[string]any{"textParagraph": map[string]any{"text": text}}
+}
+
+func formatText(text string) string {
+	return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>")
+}
@@ -0,0 +1,103 @@
+package google_chat_notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"postgresus-backend/internal/util/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Send_BackupFailedEvent_EscapedCardSent(t *testing.T) {
+	var (
+		contentType string
+		received    map[string]any
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		_ = json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	notifier := &GoogleChatNotifier{WebhookURL: server.URL}
+	require.NoError(t, notifier.Validate())
+
+	backupError := "pg_dump: error: <connection> failed"
+	err := notifier.Send(logger.GetLogger(), &notifier_events.NotificationEvent{
+		Type:         notifier_events.EventTypeBackupFailed,
+		Severity:     notifier_events.SeverityError,
+		Title:        "❌ Backup failed",
+		DatabaseName: "main",
+		Error:        &backupError,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "application/json; charset=UTF-8", contentType)
+	assert.Equal(t, "❌ Backup failed", received["text"])
+
+	body, err := json.Marshal(received["cardsV2"])
+	require.NoError(t, err)
+
+	assert.Contains(t, string(body), `"cardId":"postgresus"`)
+	assert.Contains(t, string(body), `"topLabel":"Database"`)
+	assert.NotContains(t, string(body), "<connection>")
+
+	errorCard := renderCard(&notifier_events.NotificationEvent{Error: &backupError})
+	assert.Contains(t, mustMarshalUnescaped(t, errorCard), "&lt;connection&gt;")
+}
+
+func Test_RenderCard_EventWithLinks_ButtonsAdded(t *testing.T) {
+	card := renderCard(&notifier_events.NotificationEvent{
+		Severity: notifier_events.SeveritySuccess,
+		Title:    "✅ Backup completed",
+		Message:  "first line\nsecond line",
+		Links: []notifier_events.EventLink{
+			{Title: "Open database", URL: "https://postgresus.example.com/databases/1"},
+		},
+	})
+
+	widgets := card["sections"].([]map[string]any)[0]["widgets"].([]map[string]any)
+	require.Len(t, widgets, 3)
+
+	assert.Equal(
+		t,
+		`<b><font color="#2EB67D">✅ Backup completed</font></b>`,
+		widgets[0]["textParagraph"].(map[string]any)["text"],
+	)
+	assert.Equal(
+		t,
+		"first line<br>second line",
+		widgets[1]["textParagraph"].(map[string]any)["text"],
+	)
+
+	buttons := widgets[2]["buttonList"].(map[string]any)["buttons"].([]map[string]any)
+	require.Len(t, buttons, 1)
+	assert.Equal(t, "Open database", buttons[0]["text"])
+}
+
+func Test_Validate_NotHttpUrl_ErrorReturned(t *testing.T) {
+	assert.Error(t, (&GoogleChatNotifier{}).Validate())
+	assert.Error(t, (&GoogleChatNotifier{WebhookURL: "chat.googleapis.com/v1/spaces"}).Validate())
+	assert.NoError(
+		t,
+		(&GoogleChatNotifier{WebhookURL: "https://chat.googleapis.com/v1/spaces/AAA/messages"}).
+			Validate(),
+	)
+}
+
+func mustMarshalUnescaped(t *testing.T, value any) string {
+	var buffer bytes.Buffer
+
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	require.NoError(t, encoder.Encode(value))
+
+	return buffer.String()
+}
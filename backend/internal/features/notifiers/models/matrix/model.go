@@ -0,0 +1,174 @@
+package matrix_notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type MatrixNotifier struct {
+	NotifierID    uuid.UUID `json:"notifierId"    gorm:"primaryKey;column:notifier_id"`
+	HomeserverURL string    `json:"homeserverUrl" gorm:"not null;column:homeserver_url;type:text"`
+	AccessToken   string    `json:"accessToken"   gorm:"not null;column:access_token;type:text;serializer:encrypted"`
+	RoomID        string    `json:"roomId"        gorm:"not null;column:room_id;type:varchar(255)"`
+}
+
+func (m *MatrixNotifier) TableName() string {
+	return "matrix_notifiers"
+}
+
+func (m *MatrixNotifier) Validate() error {
+	if m.HomeserverURL == "" {
+		return errors.New("homeserver URL is required")
+	}
+
+	u, err := url.Parse(m.HomeserverURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return errors.New("invalid homeserver URL")
+	}
+
+	if m.AccessToken == "" {
+		return errors.New("access token is required")
+	}
+
+	// aliases like #room:server.org would need one more request to be
+	// resolved, so only IDs are accepted
+	if !strings.HasPrefix(m.RoomID, "!") || !strings.Contains(m.RoomID, ":") {
+		return errors.New("room ID must look like !roomid:server.org")
+	}
+
+	return nil
+}
+
+// HideSensitiveData removes the access token before the notifier is
+// returned to the client, the token is write only
+func (m *MatrixNotifier) HideSensitiveData() {
+	m.AccessToken = ""
+}
+
+// FillSensitiveData keeps the saved access token if the client has not
+// sent a new one. The token is valid only on its homeserver, so it is
+// not kept when the homeserver changes
+func (m *MatrixNotifier) FillSensitiveData(existing *MatrixNotifier) {
+	if m.AccessToken == "" && existing != nil && existing.HomeserverURL == m.HomeserverURL {
+		m.AccessToken = existing.AccessToken
+	}
+}
+
+type roomMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format"`
+	FormattedBody string `json:"formatted_body"`
+}
+
+func (m *MatrixNotifier) Send(
+	logger *slog.Logger,
+	event *notifier_events.NotificationEvent,
+) error {
+	body, err := json.Marshal(roomMessage{
+		MsgType:       "m.text",
+		Body:          strings.TrimSpace(event.Title + "\n\n" + event.GetPlainText()),
+		Format:        "org.matrix.custom.html",
+		FormattedBody: renderHTML(event),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal Matrix message: %w", err)
+	}
+
+	// transaction ID makes the request idempotent, so the message is
+	// not duplicated if the homeserver receives the request twice
+	sendURL := fmt.Sprintf(
+		"%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		strings.TrimRight(m.HomeserverURL, "/"),
+		url.PathEscape(m.RoomID),
+		uuid.New().String(),
+	)
+
+	req, err := http.NewRequest(http.MethodPut, sendURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+m.AccessToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send Matrix message: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf(
+			"matrix homeserver returned non-OK status: %s. Error: %s",
+			resp.Status,
+			string(bodyBytes),
+		)
+	}
+
+	return nil
+}
+
+// renderHTML renders the event with the subset of HTML supported by
+// Matrix clients: the title colored by severity, details as a list
+func renderHTML(event *notifier_events.NotificationEvent) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(
+		&sb,
+		`<h4><font color="%s">%s</font></h4>`,
+		event.Severity.GetColor(),
+		html.EscapeString(event.Title),
+	)
+
+	if event.Message != "" {
+		sb.WriteString("<p>" + formatText(event.Message) + "</p>")
+	}
+
+	fields := event.GetFields()
+	if len(fields) > 0 {
+		sb.WriteString("<ul>")
+		for _, field := range fields {
+			fmt.Fprintf(
+				&sb,
+				"<li><b>%s:</b> %s</li>",
+				html.EscapeString(field.Name),
+				html.EscapeString(field.Value),
+			)
+		}
+		sb.WriteString("</ul>")
+	}
+
+	if event.Error != nil && *event.Error != "" {
+		sb.WriteString("<pre><code>" + html.EscapeString(*event.Error) + "</code></pre>")
+	}
+
+	for _, link := range event.Links {
+		fmt.Fprintf(
+			&sb,
+			`<p><a href="%s">%s</a></p>`,
+			html.EscapeString(link.URL),
+			html.EscapeString(link.Title),
+		)
+	}
+
+	return sb.String()
+}
+
+func formatText(text string) string {
+	return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>")
+}
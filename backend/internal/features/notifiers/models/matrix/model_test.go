@@ -0,0 +1,73 @@
+package matrix_notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"postgresus-backend/internal/util/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Send_BackupFailedEvent_HtmlMessageSentToRoom(t *testing.T) {
+	var (
+		method      string
+		path        string
+		accessToken string
+		received    roomMessage
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.EscapedPath()
+		accessToken = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&received)
+
+		_, _ = w.Write([]byte(`{"event_id":"$event"}`))
+	}))
+	defer server.Close()
+
+	notifier := &MatrixNotifier{
+		HomeserverURL: server.URL + "/",
+		AccessToken:   "access-token",
+		RoomID:        "!room:example.org",
+	}
+	require.NoError(t, notifier.Validate())
+
+	backupError := "pg_dump: error: <connection> failed"
+	err := notifier.Send(logger.GetLogger(), &notifier_events.NotificationEvent{
+		Type:         notifier_events.EventTypeBackupFailed,
+		Severity:     notifier_events.SeverityError,
+		Title:        "❌ Backup failed",
+		DatabaseName: "main",
+		Error:        &backupError,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodPut, method)
+	assert.True(t, strings.HasPrefix(
+		path,
+		"/_matrix/client/v3/rooms/%21room:example.org/send/m.room.message/",
+	))
+	assert.Equal(t, "Bearer access-token", accessToken)
+
+	assert.Equal(t, "m.text", received.MsgType)
+	assert.Contains(t, received.Body, "Database: main")
+	assert.Contains(t, received.FormattedBody, `<font color="#E01E5A">❌ Backup failed</font>`)
+	assert.Contains(t, received.FormattedBody, "&lt;connection&gt;")
+}
+
+func Test_Validate_RoomAlias_ErrorReturned(t *testing.T) {
+	notifier := &MatrixNotifier{
+		HomeserverURL: "https://matrix.org",
+		AccessToken:   "access-token",
+		RoomID:        "#backups:matrix.org",
+	}
+
+	assert.Error(t, notifier.Validate())
+}
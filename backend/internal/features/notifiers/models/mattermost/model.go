@@ -0,0 +1,148 @@
+package mattermost_notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+
+	"github.com/google/uuid"
+)
+
+type MattermostNotifier struct {
+	NotifierID uuid.UUID `json:"notifierId" gorm:"primaryKey;column:notifier_id"`
+	WebhookURL string    `json:"webhookUrl" gorm:"not null;column:webhook_url;type:text;serializer:encrypted"`
+	Channel    string    `json:"channel"    gorm:"column:channel;type:varchar(255)"`
+	Username   string    `json:"username"   gorm:"column:username;type:varchar(255)"`
+}
+
+func (m *MattermostNotifier) TableName() string {
+	return "mattermost_notifiers"
+}
+
+func (m *MattermostNotifier) Validate() error {
+	if m.WebhookURL == "" {
+		return errors.New("webhook URL is required")
+	}
+
+	u, err := url.Parse(m.WebhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return errors.New("invalid webhook URL")
+	}
+
+	return nil
+}
+
+// HideSensitiveData removes the webhook URL before the notifier is
+// returned to the client, the URL contains the key of the webhook
+func (m *MattermostNotifier) HideSensitiveData() {
+	m.WebhookURL = ""
+}
+
+// FillSensitiveData keeps the saved webhook URL if the client has not
+// sent a new one
+func (m *MattermostNotifier) FillSensitiveData(existing *MattermostNotifier) {
+	if m.WebhookURL == "" && existing != nil {
+		m.WebhookURL = existing.WebhookURL
+	}
+}
+
+type attachmentField struct {
+	Short bool   `json:"short"`
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type attachment struct {
+	Fallback  string            `json:"fallback"`
+	Color     string            `json:"color"`
+	Title     string            `json:"title"`
+	TitleLink string            `json:"title_link,omitempty"`
+	Text      string            `json:"text,omitempty"`
+	Fields    []attachmentField `json:"fields,omitempty"`
+}
+
+type payload struct {
+	Channel     string       `json:"channel,omitempty"`
+	Username    string       `json:"username,omitempty"`
+	Attachments []attachment `json:"attachments"`
+}
+
+func (m *MattermostNotifier) Send(
+	logger *slog.Logger,
+	event *notifier_events.NotificationEvent,
+) error {
+	body, err := json.Marshal(payload{
+		Channel:     m.Channel,
+		Username:    m.Username,
+		Attachments: []attachment{renderAttachment(event)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal Mattermost payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, m.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send Mattermost message: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf(
+			"mattermost webhook returned non-OK status: %s. Error: %s",
+			resp.Status,
+			string(bodyBytes),
+		)
+	}
+
+	return nil
+}
+
+// renderAttachment renders the event as the attachment colored by
+// severity, details are shown as short fields. Mattermost renders
+// markdown in the text, so the error is shown as a code block
+func renderAttachment(event *notifier_events.NotificationEvent) attachment {
+	text := event.Message
+	if event.Error != nil && *event.Error != "" {
+		if text != "" {
+			text += "\n"
+		}
+
+		text += "```\n" + *event.Error + "\n```"
+	}
+
+	result := attachment{
+		Fallback: event.Title,
+		Color:    event.Severity.GetColor(),
+		Title:    event.Title,
+		Text:     text,
+	}
+
+	for _, field := range event.GetFields() {
+		result.Fields = append(
+			result.Fields,
+			attachmentField{Short: true, Title: field.Name, Value: field.Value},
+		)
+	}
+
+	if len(event.Links) > 0 {
+		result.TitleLink = event.Links[0].URL
+	}
+
+	return result
+}
@@ -0,0 +1,79 @@
+package mattermost_notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"postgresus-backend/internal/util/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Send_BackupFailedEvent_ColoredAttachmentSentToChannel(t *testing.T) {
+	var received payload
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	notifier := &MattermostNotifier{
+		WebhookURL: server.URL,
+		Channel:    "backups",
+		Username:   "postgresus",
+	}
+	require.NoError(t, notifier.Validate())
+
+	backupError := "pg_dump: error: connection failed"
+	err := notifier.Send(logger.GetLogger(), &notifier_events.NotificationEvent{
+		Type:         notifier_events.EventTypeBackupFailed,
+		Severity:     notifier_events.SeverityError,
+		Title:        "❌ Backup failed",
+		DatabaseName: "main",
+		Error:        &backupError,
+		Links: []notifier_events.EventLink{
+			{Title: "Open database", URL: "https://postgresus.example.com/databases/1"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "backups", received.Channel)
+	assert.Equal(t, "postgresus", received.Username)
+	require.Len(t, received.Attachments, 1)
+
+	attachment := received.Attachments[0]
+	assert.Equal(t, "❌ Backup failed", attachment.Title)
+	assert.Equal(t, "#E01E5A", attachment.Color)
+	assert.Equal(t, "https://postgresus.example.com/databases/1", attachment.TitleLink)
+	assert.Equal(t, "```\npg_dump: error: connection failed\n```", attachment.Text)
+	assert.Equal(
+		t,
+		[]attachmentField{{Short: true, Title: "Database", Value: "main"}},
+		attachment.Fields,
+	)
+}
+
+func Test_Send_ServerError_ErrorReturned(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	notifier := &MattermostNotifier{WebhookURL: server.URL}
+
+	err := notifier.Send(logger.GetLogger(), notifier_events.NewTestEvent())
+	assert.ErrorContains(t, err, "400")
+}
+
+func Test_Validate_NotHttpUrl_ErrorReturned(t *testing.T) {
+	assert.Error(t, (&MattermostNotifier{}).Validate())
+	assert.Error(t, (&MattermostNotifier{WebhookURL: "ftp://mattermost.example.com"}).Validate())
+	assert.NoError(
+		t,
+		(&MattermostNotifier{WebhookURL: "https://mattermost.example.com/hooks/key"}).Validate(),
+	)
+}
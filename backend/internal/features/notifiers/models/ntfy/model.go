@@ -0,0 +1,183 @@
+package ntfy_notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultServerURL = "https://ntfy.sh"
+
+	// ntfy shows no more than 3 action buttons
+	maxActionsCount = 3
+)
+
+var topicRegexp = regexp.MustCompile(`^[-_A-Za-z0-9]{1,64}$`)
+
+type NtfyNotifier struct {
+	NotifierID  uuid.UUID `json:"notifierId"  gorm:"primaryKey;column:notifier_id"`
+	ServerURL   string    `json:"serverUrl"   gorm:"not null;column:server_url;type:text"`
+	Topic       string    `json:"topic"       gorm:"not null;column:topic;type:varchar(64)"`
+	AccessToken string    `json:"accessToken" gorm:"column:access_token;type:text;serializer:encrypted"`
+}
+
+func (n *NtfyNotifier) TableName() string {
+	return "ntfy_notifiers"
+}
+
+func (n *NtfyNotifier) Validate() error {
+	if n.ServerURL != "" {
+		u, err := url.Parse(n.ServerURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return errors.New("invalid server URL")
+		}
+	}
+
+	if n.Topic == "" {
+		return errors.New("topic is required")
+	}
+
+	if !topicRegexp.MatchString(n.Topic) {
+		return errors.New("topic may contain only letters, digits, - and _")
+	}
+
+	return nil
+}
+
+// HideSensitiveData removes the access token before the notifier is
+// returned to the client, the token is write only
+func (n *NtfyNotifier) HideSensitiveData() {
+	n.AccessToken = ""
+}
+
+// FillSensitiveData keeps the saved access token if the client has not
+// sent a new one. The token is not kept when the server changes, so it
+// is not sent to the server it was not issued by
+func (n *NtfyNotifier) FillSensitiveData(existing *NtfyNotifier) {
+	if n.AccessToken == "" && existing != nil && existing.ServerURL == n.ServerURL {
+		n.AccessToken = existing.AccessToken
+	}
+}
+
+type action struct {
+	Action string `json:"action"`
+	Label  string `json:"label"`
+	URL    string `json:"url"`
+}
+
+type message struct {
+	Topic    string   `json:"topic"`
+	Title    string   `json:"title"`
+	Message  string   `json:"message"`
+	Priority int      `json:"priority"`
+	Tags     []string `json:"tags,omitempty"`
+	Click    string   `json:"click,omitempty"`
+	Actions  []action `json:"actions,omitempty"`
+}
+
+func (n *NtfyNotifier) Send(
+	logger *slog.Logger,
+	event *notifier_events.NotificationEvent,
+) error {
+	body, err := json.Marshal(renderMessage(n.Topic, event))
+	if err != nil {
+		return fmt.Errorf("failed to marshal ntfy message: %w", err)
+	}
+
+	// JSON is published to the root of the server, the topic is in body
+	req, err := http.NewRequest(http.MethodPost, n.getServerURL(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if n.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+n.AccessToken)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send ntfy message: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf(
+			"ntfy server returned non-OK status: %s. Error: %s",
+			resp.Status,
+			string(bodyBytes),
+		)
+	}
+
+	return nil
+}
+
+func (n *NtfyNotifier) getServerURL() string {
+	if n.ServerURL == "" {
+		return defaultServerURL
+	}
+
+	return strings.TrimRight(n.ServerURL, "/")
+}
+
+func renderMessage(topic string, event *notifier_events.NotificationEvent) message {
+	result := message{
+		Topic:    topic,
+		Title:    event.Title,
+		Message:  event.GetPlainText(),
+		Priority: getPriority(event.Severity),
+	}
+
+	// ntfy shows "triggered" instead of empty message
+	if result.Message == "" {
+		result.Message = event.Title
+	}
+
+	if event.DatabaseName != "" {
+		result.Tags = []string{event.DatabaseName}
+	}
+
+	for index, link := range event.Links {
+		if index == 0 {
+			result.Click = link.URL
+		}
+
+		if index < maxActionsCount {
+			result.Actions = append(
+				result.Actions,
+				action{Action: "view", Label: link.Title, URL: link.URL},
+			)
+		}
+	}
+
+	return result
+}
+
+// getPriority maps severity to ntfy priority from 1 (min) to 5 (max),
+// only critical events pop over other apps with long vibration
+func getPriority(severity notifier_events.Severity) int {
+	switch severity {
+	case notifier_events.SeverityCritical:
+		return 5
+	case notifier_events.SeverityError:
+		return 4
+	case notifier_events.SeverityWarning:
+		return 3
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,67 @@
+package ntfy_notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"postgresus-backend/internal/util/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Send_CriticalEventWithLinks_MaxPriorityMessagePublished(t *testing.T) {
+	var (
+		path        string
+		accessToken string
+		received    message
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		accessToken = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	notifier := &NtfyNotifier{
+		ServerURL:   server.URL,
+		Topic:       "backups",
+		AccessToken: "tk_token",
+	}
+	require.NoError(t, notifier.Validate())
+
+	err := notifier.Send(logger.GetLogger(), &notifier_events.NotificationEvent{
+		Type:         notifier_events.EventTypeDatabaseUnavailable,
+		Severity:     notifier_events.SeverityCritical,
+		Title:        "❌ [main] DB is unavailable",
+		DatabaseName: "main",
+		Links: []notifier_events.EventLink{
+			{Title: "Open database", URL: "https://postgresus.example.com/databases/1"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/", path)
+	assert.Equal(t, "Bearer tk_token", accessToken)
+	assert.Equal(t, "backups", received.Topic)
+	assert.Equal(t, 5, received.Priority)
+	assert.Equal(t, []string{"main"}, received.Tags)
+	assert.Equal(t, "https://postgresus.example.com/databases/1", received.Click)
+	assert.Len(t, received.Actions, 1)
+}
+
+func Test_FillSensitiveData_ServerChanged_TokenNotKept(t *testing.T) {
+	existing := &NtfyNotifier{ServerURL: "https://ntfy.example.com", AccessToken: "tk_token"}
+
+	notifier := &NtfyNotifier{ServerURL: "https://ntfy.sh"}
+	notifier.FillSensitiveData(existing)
+	assert.Empty(t, notifier.AccessToken)
+
+	notifier = &NtfyNotifier{ServerURL: "https://ntfy.example.com"}
+	notifier.FillSensitiveData(existing)
+	assert.Equal(t, "tk_token", notifier.AccessToken)
+}
@@ -0,0 +1,211 @@
+package opsgenie_notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OpsgenieRegion string
+
+const (
+	OpsgenieRegionUS OpsgenieRegion = "US"
+	OpsgenieRegionEU OpsgenieRegion = "EU"
+)
+
+// apiURLs are Alert API endpoints of regions, they are replaced by
+// a local server in tests
+var apiURLs = map[OpsgenieRegion]string{
+	OpsgenieRegionUS: "https://api.opsgenie.com/v2/alerts",
+	OpsgenieRegionEU: "https://api.eu.opsgenie.com/v2/alerts",
+}
+
+type OpsgenieNotifier struct {
+	NotifierID uuid.UUID      `json:"notifierId" gorm:"primaryKey;column:notifier_id"`
+	APIKey     string         `json:"apiKey"     gorm:"not null;column:api_key;type:text;serializer:encrypted"`
+	Region     OpsgenieRegion `json:"region"     gorm:"not null;column:region;type:varchar(10)"`
+}
+
+func (o *OpsgenieNotifier) TableName() string {
+	return "opsgenie_notifiers"
+}
+
+func (o *OpsgenieNotifier) Validate() error {
+	if o.APIKey == "" {
+		return errors.New("API key is required")
+	}
+
+	if _, ok := apiURLs[o.Region]; !ok {
+		return fmt.Errorf("unknown Opsgenie region: %s", o.Region)
+	}
+
+	return nil
+}
+
+// HideSensitiveData removes the API key before the notifier is
+// returned to the client, the key is write only
+func (o *OpsgenieNotifier) HideSensitiveData() {
+	o.APIKey = ""
+}
+
+// FillSensitiveData keeps the saved API key if the client has not
+// sent a new one. The key is sent only to Opsgenie of the region,
+// so it is safe to keep it when the region changes
+func (o *OpsgenieNotifier) FillSensitiveData(existing *OpsgenieNotifier) {
+	if o.APIKey == "" && existing != nil {
+		o.APIKey = existing.APIKey
+	}
+}
+
+type createAlertRequest struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Priority    string            `json:"priority"`
+	Source      string            `json:"source"`
+	Entity      string            `json:"entity,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
+}
+
+type closeAlertRequest struct {
+	Source string `json:"source"`
+	Note   string `json:"note,omitempty"`
+}
+
+// Send creates or closes Opsgenie alert. Alerts with the same alias
+// are deduplicated, so the database which is available again closes
+// the alert created when it became unavailable
+func (o *OpsgenieNotifier) Send(
+	logger *slog.Logger,
+	event *notifier_events.NotificationEvent,
+) error {
+	alias := event.GetIncidentKey()
+
+	if event.GetIncidentAction() == notifier_events.IncidentActionResolve {
+		return o.closeAlert(logger, alias, event.Title)
+	}
+
+	request := &createAlertRequest{
+		Message:     truncate(event.Title, 130),
+		Alias:       alias,
+		Description: truncate(event.GetPlainText(), 15000),
+		Priority:    getPriority(event.Severity),
+		Source:      "Postgresus",
+		Entity:      event.DatabaseName,
+		Tags:        []string{"postgresus", string(event.Type)},
+		Details:     getDetails(event),
+	}
+
+	if err := o.sendRequest(logger, apiURLs[o.Region], request); err != nil {
+		return err
+	}
+
+	// test should not leave open alert for on-call
+	if event.Type == notifier_events.EventTypeTest {
+		return o.closeAlert(logger, alias, event.Title)
+	}
+
+	return nil
+}
+
+func (o *OpsgenieNotifier) closeAlert(logger *slog.Logger, alias, note string) error {
+	closeURL := fmt.Sprintf(
+		"%s/%s/close?identifierType=alias",
+		apiURLs[o.Region],
+		url.PathEscape(alias),
+	)
+
+	return o.sendRequest(logger, closeURL, &closeAlertRequest{
+		Source: "Postgresus",
+		Note:   note,
+	})
+}
+
+func (o *OpsgenieNotifier) sendRequest(logger *slog.Logger, reqURL string, request any) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Opsgenie request: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, reqURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create Opsgenie request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "GenieKey "+o.APIKey)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send Opsgenie request: %w", err)
+	}
+
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			logger.Error("failed to close response body", "error", cerr)
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf(
+			"Opsgenie returned status: %s, body: %s",
+			resp.Status,
+			string(respBody),
+		)
+	}
+
+	return nil
+}
+
+func getPriority(severity notifier_events.Severity) string {
+	switch severity {
+	case notifier_events.SeverityCritical:
+		return "P1"
+	case notifier_events.SeverityError:
+		return "P2"
+	case notifier_events.SeverityWarning:
+		return "P3"
+	default:
+		return "P5"
+	}
+}
+
+func getDetails(event *notifier_events.NotificationEvent) map[string]string {
+	details := map[string]string{}
+
+	for _, field := range event.GetFields() {
+		details[field.Name] = field.Value
+	}
+
+	if event.DatabaseID != nil {
+		details["Database ID"] = event.DatabaseID.String()
+	}
+
+	if event.BackupID != nil {
+		details["Backup ID"] = event.BackupID.String()
+	}
+
+	return details
+}
+
+func truncate(text string, maxLength int) string {
+	runes := []rune(text)
+	if len(runes) > maxLength {
+		return string(runes[:maxLength])
+	}
+
+	return text
+}
@@ -0,0 +1,75 @@
+package opsgenie_notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"postgresus-backend/internal/util/logger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Send_DatabaseUnavailableThenAvailable_AlertCreatedAndClosed(t *testing.T) {
+	var (
+		paths        []string
+		createdAlert createAlertRequest
+		apiKey       string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.RequestURI())
+		apiKey = r.Header.Get("Authorization")
+
+		if len(paths) == 1 {
+			_ = json.NewDecoder(r.Body).Decode(&createdAlert)
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	apiURLs[OpsgenieRegionEU] = server.URL + "/v2/alerts"
+
+	databaseID := uuid.New()
+	notifier := &OpsgenieNotifier{APIKey: "api-key", Region: OpsgenieRegionEU}
+
+	err := notifier.Send(logger.GetLogger(), &notifier_events.NotificationEvent{
+		Type:         notifier_events.EventTypeDatabaseUnavailable,
+		Severity:     notifier_events.SeverityCritical,
+		Title:        "❌ [main] DB is unavailable",
+		DatabaseID:   &databaseID,
+		DatabaseName: "main",
+		CreatedAt:    time.Now().UTC(),
+	})
+	require.NoError(t, err)
+
+	err = notifier.Send(logger.GetLogger(), &notifier_events.NotificationEvent{
+		Type:         notifier_events.EventTypeDatabaseAvailable,
+		Severity:     notifier_events.SeveritySuccess,
+		Title:        "✅ [main] DB is online",
+		DatabaseID:   &databaseID,
+		DatabaseName: "main",
+		CreatedAt:    time.Now().UTC(),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, paths, 2)
+	assert.Equal(t, "GenieKey api-key", apiKey)
+
+	assert.Equal(t, "/v2/alerts", paths[0])
+	assert.Equal(t, "P1", createdAlert.Priority)
+	assert.Equal(t, "main", createdAlert.Entity)
+
+	assert.Equal(t, "/v2/alerts/"+createdAlert.Alias+"/close?identifierType=alias", paths[1])
+}
+
+func Test_Validate_UnknownRegion_ErrorReturned(t *testing.T) {
+	notifier := &OpsgenieNotifier{APIKey: "api-key", Region: "ASIA"}
+
+	assert.ErrorContains(t, notifier.Validate(), "unknown Opsgenie region")
+}
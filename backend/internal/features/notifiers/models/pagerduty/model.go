@@ -0,0 +1,212 @@
+package pagerduty_notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// eventsAPIURL is the endpoint of PagerDuty Events API v2, it is
+// replaced by a local server in tests
+var eventsAPIURL = "https://events.pagerduty.com/v2/enqueue"
+
+type PagerDutyNotifier struct {
+	NotifierID uuid.UUID `json:"notifierId" gorm:"primaryKey;column:notifier_id"`
+	RoutingKey string    `json:"routingKey" gorm:"not null;column:routing_key;type:text;serializer:encrypted"`
+}
+
+func (p *PagerDutyNotifier) TableName() string {
+	return "pagerduty_notifiers"
+}
+
+func (p *PagerDutyNotifier) Validate() error {
+	if p.RoutingKey == "" {
+		return errors.New("routing key is required")
+	}
+
+	return nil
+}
+
+// HideSensitiveData removes the routing key before the notifier is
+// returned to the client, the key is write only
+func (p *PagerDutyNotifier) HideSensitiveData() {
+	p.RoutingKey = ""
+}
+
+// FillSensitiveData keeps the saved routing key if the client has not
+// sent a new one
+func (p *PagerDutyNotifier) FillSensitiveData(existing *PagerDutyNotifier) {
+	if p.RoutingKey == "" && existing != nil {
+		p.RoutingKey = existing.RoutingKey
+	}
+}
+
+type eventPayload struct {
+	Summary       string         `json:"summary"`
+	Source        string         `json:"source"`
+	Severity      string         `json:"severity"`
+	Timestamp     string         `json:"timestamp,omitempty"`
+	Component     string         `json:"component,omitempty"`
+	Class         string         `json:"class,omitempty"`
+	CustomDetails map[string]any `json:"custom_details,omitempty"`
+}
+
+type eventLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+type eventRequest struct {
+	RoutingKey  string        `json:"routing_key"`
+	EventAction string        `json:"event_action"`
+	DedupKey    string        `json:"dedup_key"`
+	Payload     *eventPayload `json:"payload,omitempty"`
+	Links       []eventLink   `json:"links,omitempty"`
+}
+
+// Send triggers or resolves PagerDuty alert. Alerts are grouped into
+// incident by the dedup key, so the database which is available again
+// resolves the incident opened when it became unavailable
+func (p *PagerDutyNotifier) Send(
+	logger *slog.Logger,
+	event *notifier_events.NotificationEvent,
+) error {
+	dedupKey := event.GetIncidentKey()
+
+	if event.GetIncidentAction() == notifier_events.IncidentActionResolve {
+		return p.sendEvent(logger, &eventRequest{
+			RoutingKey:  p.RoutingKey,
+			EventAction: "resolve",
+			DedupKey:    dedupKey,
+		})
+	}
+
+	request := &eventRequest{
+		RoutingKey:  p.RoutingKey,
+		EventAction: "trigger",
+		DedupKey:    dedupKey,
+		Payload: &eventPayload{
+			Summary:       truncate(event.Title, 1024),
+			Source:        getSource(event),
+			Severity:      getSeverity(event.Severity),
+			Timestamp:     event.CreatedAt.Format(time.RFC3339),
+			Component:     event.DatabaseName,
+			Class:         string(event.Type),
+			CustomDetails: getCustomDetails(event),
+		},
+	}
+
+	for _, link := range event.Links {
+		request.Links = append(request.Links, eventLink{Href: link.URL, Text: link.Title})
+	}
+
+	if err := p.sendEvent(logger, request); err != nil {
+		return err
+	}
+
+	// test should not leave open incident for on-call
+	if event.Type == notifier_events.EventTypeTest {
+		return p.sendEvent(logger, &eventRequest{
+			RoutingKey:  p.RoutingKey,
+			EventAction: "resolve",
+			DedupKey:    dedupKey,
+		})
+	}
+
+	return nil
+}
+
+func (p *PagerDutyNotifier) sendEvent(logger *slog.Logger, request *eventRequest) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal PagerDuty event: %w", err)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	resp, err := client.Post(eventsAPIURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send PagerDuty event: %w", err)
+	}
+
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			logger.Error("failed to close response body", "error", cerr)
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf(
+			"PagerDuty returned status: %s, body: %s",
+			resp.Status,
+			string(respBody),
+		)
+	}
+
+	return nil
+}
+
+func getSource(event *notifier_events.NotificationEvent) string {
+	if event.DatabaseName != "" {
+		return event.DatabaseName
+	}
+
+	return "postgresus"
+}
+
+func getSeverity(severity notifier_events.Severity) string {
+	switch severity {
+	case notifier_events.SeverityCritical:
+		return "critical"
+	case notifier_events.SeverityError:
+		return "error"
+	case notifier_events.SeverityWarning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+func getCustomDetails(event *notifier_events.NotificationEvent) map[string]any {
+	details := map[string]any{}
+
+	if event.Message != "" {
+		details["message"] = event.Message
+	}
+
+	for _, field := range event.GetFields() {
+		details[field.Name] = field.Value
+	}
+
+	if event.Error != nil && *event.Error != "" {
+		details["error"] = *event.Error
+	}
+
+	if event.DatabaseID != nil {
+		details["databaseId"] = event.DatabaseID.String()
+	}
+
+	if event.BackupID != nil {
+		details["backupId"] = event.BackupID.String()
+	}
+
+	return details
+}
+
+func truncate(text string, maxLength int) string {
+	runes := []rune(text)
+	if len(runes) > maxLength {
+		return string(runes[:maxLength])
+	}
+
+	return text
+}
@@ -0,0 +1,85 @@
+package pagerduty_notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"postgresus-backend/internal/util/logger"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Send_DatabaseUnavailableThenAvailable_IncidentTriggeredAndResolved(t *testing.T) {
+	var requests []eventRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request eventRequest
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		requests = append(requests, request)
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	eventsAPIURL = server.URL
+
+	databaseID := uuid.New()
+	notifier := &PagerDutyNotifier{RoutingKey: "routing-key"}
+
+	err := notifier.Send(logger.GetLogger(), &notifier_events.NotificationEvent{
+		Type:         notifier_events.EventTypeDatabaseUnavailable,
+		Severity:     notifier_events.SeverityCritical,
+		Title:        "❌ [main] DB is unavailable",
+		DatabaseID:   &databaseID,
+		DatabaseName: "main",
+		CreatedAt:    time.Now().UTC(),
+	})
+	require.NoError(t, err)
+
+	err = notifier.Send(logger.GetLogger(), &notifier_events.NotificationEvent{
+		Type:         notifier_events.EventTypeDatabaseAvailable,
+		Severity:     notifier_events.SeveritySuccess,
+		Title:        "✅ [main] DB is online",
+		DatabaseID:   &databaseID,
+		DatabaseName: "main",
+		CreatedAt:    time.Now().UTC(),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, requests, 2)
+
+	assert.Equal(t, "trigger", requests[0].EventAction)
+	assert.Equal(t, "routing-key", requests[0].RoutingKey)
+	assert.Equal(t, "critical", requests[0].Payload.Severity)
+	assert.Equal(t, "main", requests[0].Payload.Source)
+
+	assert.Equal(t, "resolve", requests[1].EventAction)
+	assert.Nil(t, requests[1].Payload)
+	assert.Equal(t, requests[0].DedupKey, requests[1].DedupKey)
+}
+
+func Test_Send_TestEvent_IncidentResolvedRightAway(t *testing.T) {
+	var actions []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request eventRequest
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		actions = append(actions, request.EventAction)
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	eventsAPIURL = server.URL
+
+	notifier := &PagerDutyNotifier{RoutingKey: "routing-key"}
+
+	err := notifier.Send(logger.GetLogger(), notifier_events.NewTestEvent())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"trigger", "resolve"}, actions)
+}
@@ -0,0 +1,144 @@
+package rocket_chat_notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+
+	"github.com/google/uuid"
+)
+
+type RocketChatNotifier struct {
+	NotifierID uuid.UUID `json:"notifierId" gorm:"primaryKey;column:notifier_id"`
+	WebhookURL string    `json:"webhookUrl" gorm:"not null;column:webhook_url;type:text;serializer:encrypted"`
+}
+
+func (r *RocketChatNotifier) TableName() string {
+	return "rocket_chat_notifiers"
+}
+
+func (r *RocketChatNotifier) Validate() error {
+	if r.WebhookURL == "" {
+		return errors.New("webhook URL is required")
+	}
+
+	u, err := url.Parse(r.WebhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return errors.New("invalid webhook URL")
+	}
+
+	return nil
+}
+
+// HideSensitiveData removes the webhook URL before the notifier is
+// returned to the client, the URL contains the token of the webhook
+func (r *RocketChatNotifier) HideSensitiveData() {
+	r.WebhookURL = ""
+}
+
+// FillSensitiveData keeps the saved webhook URL if the client has not
+// sent a new one
+func (r *RocketChatNotifier) FillSensitiveData(existing *RocketChatNotifier) {
+	if r.WebhookURL == "" && existing != nil {
+		r.WebhookURL = existing.WebhookURL
+	}
+}
+
+type attachmentField struct {
+	Short bool   `json:"short"`
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type attachment struct {
+	Color     string            `json:"color"`
+	Title     string            `json:"title,omitempty"`
+	TitleLink string            `json:"title_link,omitempty"`
+	Text      string            `json:"text,omitempty"`
+	Fields    []attachmentField `json:"fields,omitempty"`
+}
+
+type payload struct {
+	Text        string       `json:"text"`
+	Attachments []attachment `json:"attachments,omitempty"`
+}
+
+func (r *RocketChatNotifier) Send(
+	logger *slog.Logger,
+	event *notifier_events.NotificationEvent,
+) error {
+	body, err := json.Marshal(renderPayload(event))
+	if err != nil {
+		return fmt.Errorf("failed to marshal Rocket.Chat payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send Rocket.Chat message: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf(
+			"rocket.chat webhook returned non-OK status: %s. Error: %s",
+			resp.Status,
+			string(bodyBytes),
+		)
+	}
+
+	return nil
+}
+
+// renderPayload puts the title to the text of the message, so it is
+// shown in push notifications, and the rest to the attachment colored
+// by severity
+func renderPayload(event *notifier_events.NotificationEvent) payload {
+	text := event.Message
+	if event.Error != nil && *event.Error != "" {
+		if text != "" {
+			text += "\n"
+		}
+
+		text += "```\n" + *event.Error + "\n```"
+	}
+
+	details := attachment{
+		Color: event.Severity.GetColor(),
+		Text:  text,
+	}
+
+	for _, field := range event.GetFields() {
+		details.Fields = append(
+			details.Fields,
+			attachmentField{Short: true, Title: field.Name, Value: field.Value},
+		)
+	}
+
+	if len(event.Links) > 0 {
+		details.Title = event.Links[0].Title
+		details.TitleLink = event.Links[0].URL
+	}
+
+	result := payload{Text: "*" + event.Title + "*"}
+	if details.Text != "" || len(details.Fields) > 0 || details.TitleLink != "" {
+		result.Attachments = []attachment{details}
+	}
+
+	return result
+}
@@ -0,0 +1,69 @@
+package rocket_chat_notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"postgresus-backend/internal/util/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Send_BackupSuccessEvent_TitleInTextAndDetailsInAttachment(t *testing.T) {
+	var received payload
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	notifier := &RocketChatNotifier{WebhookURL: server.URL}
+	require.NoError(t, notifier.Validate())
+
+	sizeMb := float64(10)
+	err := notifier.Send(logger.GetLogger(), &notifier_events.NotificationEvent{
+		Type:         notifier_events.EventTypeBackupSuccess,
+		Severity:     notifier_events.SeveritySuccess,
+		Title:        "✅ Backup completed",
+		Message:      "Backup completed successfully",
+		DatabaseName: "main",
+		SizeMb:       &sizeMb,
+		Links: []notifier_events.EventLink{
+			{Title: "Open database", URL: "https://postgresus.example.com/databases/1"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "*✅ Backup completed*", received.Text)
+	require.Len(t, received.Attachments, 1)
+
+	attachment := received.Attachments[0]
+	assert.Equal(t, "#2EB67D", attachment.Color)
+	assert.Equal(t, "Backup completed successfully", attachment.Text)
+	assert.Equal(t, "Open database", attachment.Title)
+	assert.Equal(t, "https://postgresus.example.com/databases/1", attachment.TitleLink)
+	assert.Len(t, attachment.Fields, 2)
+}
+
+func Test_RenderPayload_EventWithoutDetails_AttachmentNotAdded(t *testing.T) {
+	result := renderPayload(&notifier_events.NotificationEvent{
+		Severity: notifier_events.SeverityInfo,
+		Title:    "Test notification",
+	})
+
+	assert.Equal(t, "*Test notification*", result.Text)
+	assert.Empty(t, result.Attachments)
+}
+
+func Test_Validate_NotHttpUrl_ErrorReturned(t *testing.T) {
+	assert.Error(t, (&RocketChatNotifier{}).Validate())
+	assert.Error(t, (&RocketChatNotifier{WebhookURL: "rocket.example.com/hooks"}).Validate())
+	assert.NoError(
+		t,
+		(&RocketChatNotifier{WebhookURL: "https://rocket.example.com/hooks/id/token"}).Validate(),
+	)
+}
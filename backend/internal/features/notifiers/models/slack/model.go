@@ -8,6 +8,7 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
 	"strconv"
 	"strings"
 	"time"
@@ -15,9 +16,13 @@ import (
 	"github.com/google/uuid"
 )
 
+// postMessageURL is the endpoint of Slack Web API, it is replaced by a
+// local server in tests
+var postMessageURL = "https://slack.com/api/chat.postMessage"
+
 type SlackNotifier struct {
 	NotifierID   uuid.UUID `json:"notifierId"   gorm:"primaryKey;column:notifier_id"`
-	BotToken     string    `json:"botToken"     gorm:"not null;column:bot_token"`
+	BotToken     string    `json:"botToken"     gorm:"not null;column:bot_token;serializer:encrypted"`
 	TargetChatID string    `json:"targetChatId" gorm:"not null;column:target_chat_id"`
 }
 
@@ -43,17 +48,36 @@ func (s *SlackNotifier) Validate() error {
 	return nil
 }
 
-func (s *SlackNotifier) Send(logger *slog.Logger, heading, message string) error {
-	full := fmt.Sprintf("*%s*", heading)
+// HideSensitiveData removes the bot token before the notifier is
+// returned to the client, the token is write only
+func (s *SlackNotifier) HideSensitiveData() {
+	s.BotToken = ""
+}
 
-	if message != "" {
-		full = fmt.Sprintf("%s\n\n%s", full, message)
+// FillSensitiveData keeps the saved bot token if the client has not
+// sent a new one. The token is sent only to Slack API, so it is safe
+// to keep it for another chat
+func (s *SlackNotifier) FillSensitiveData(existing *SlackNotifier) {
+	if s.BotToken == "" && existing != nil {
+		s.BotToken = existing.BotToken
 	}
+}
 
+func (s *SlackNotifier) Send(
+	logger *slog.Logger,
+	event *notifier_events.NotificationEvent,
+) error {
+	// text is shown in push notifications, blocks are shown in the
+	// channel inside the attachment colored by severity
 	payload, _ := json.Marshal(map[string]any{
 		"channel": s.TargetChatID,
-		"text":    full,
-		"mrkdwn":  true,
+		"text":    event.Title,
+		"attachments": []map[string]any{
+			{
+				"color":  event.Severity.GetColor(),
+				"blocks": renderBlocks(event),
+			},
+		},
 	})
 
 	const (
@@ -70,11 +94,7 @@ func (s *SlackNotifier) Send(logger *slog.Logger, heading, message string) error
 	for {
 		attempts++
 
-		req, err := http.NewRequest(
-			"POST",
-			"https://slack.com/api/chat.postMessage",
-			bytes.NewReader(payload),
-		)
+		req, err := http.NewRequest("POST", postMessageURL, bytes.NewReader(payload))
 		if err != nil {
 			return fmt.Errorf("create request: %w", err)
 		}
@@ -132,3 +152,60 @@ func (s *SlackNotifier) Send(logger *slog.Logger, heading, message string) error
 		return nil
 	}
 }
+
+func renderBlocks(event *notifier_events.NotificationEvent) []map[string]any {
+	blocks := []map[string]any{
+		{
+			"type": "header",
+			"text": map[string]any{"type": "plain_text", "text": event.Title, "emoji": true},
+		},
+	}
+
+	if event.Message != "" {
+		blocks = append(blocks, markdownSection(escapeMarkdown(event.Message)))
+	}
+
+	fields := event.GetFields()
+	if len(fields) > 0 {
+		fieldBlocks := make([]map[string]any, 0, len(fields))
+		for _, field := range fields {
+			fieldBlocks = append(fieldBlocks, map[string]any{
+				"type": "mrkdwn",
+				"text": fmt.Sprintf("*%s*\n%s", field.Name, escapeMarkdown(field.Value)),
+			})
+		}
+
+		blocks = append(blocks, map[string]any{"type": "section", "fields": fieldBlocks})
+	}
+
+	if event.Error != nil && *event.Error != "" {
+		blocks = append(blocks, markdownSection("```"+escapeMarkdown(*event.Error)+"```"))
+	}
+
+	if len(event.Links) > 0 {
+		buttons := make([]map[string]any, 0, len(event.Links))
+		for _, link := range event.Links {
+			buttons = append(buttons, map[string]any{
+				"type": "button",
+				"text": map[string]any{"type": "plain_text", "text": link.Title},
+				"url":  link.URL,
+			})
+		}
+
+		blocks = append(blocks, map[string]any{"type": "actions", "elements": buttons})
+	}
+
+	return blocks
+}
+
+func markdownSection(text string) map[string]any {
+	return map[string]any{
+		"type": "section",
+		"text": map[string]any{"type": "mrkdwn", "text": text},
+	}
+}
+
+// escapeMarkdown escapes characters Slack uses for mentions and links
+func escapeMarkdown(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
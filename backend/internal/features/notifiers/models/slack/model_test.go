@@ -0,0 +1,102 @@
+package slack_notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"postgresus-backend/internal/util/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type slackRequest struct {
+	Channel     string `json:"channel"`
+	Text        string `json:"text"`
+	Attachments []struct {
+		Color  string           `json:"color"`
+		Blocks []map[string]any `json:"blocks"`
+	} `json:"attachments"`
+}
+
+func Test_Send_BackupFailedEvent_BlocksWithEscapedTextsSent(t *testing.T) {
+	var (
+		request       slackRequest
+		authorization string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&request)
+
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+	postMessageURL = server.URL
+
+	notifier := &SlackNotifier{BotToken: "xoxb-token", TargetChatID: "C123"}
+	require.NoError(t, notifier.Validate())
+
+	backupError := "relation <users> & <orders> not found"
+	err := notifier.Send(logger.GetLogger(), &notifier_events.NotificationEvent{
+		Type:         notifier_events.EventTypeBackupFailed,
+		Severity:     notifier_events.SeverityError,
+		Title:        "❌ Backup failed",
+		Message:      "Backup of <main> failed",
+		DatabaseName: "main",
+		Error:        &backupError,
+		Links: []notifier_events.EventLink{
+			{Title: "Open database", URL: "https://postgresus.example.com/databases/1"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Bearer xoxb-token", authorization)
+	assert.Equal(t, "C123", request.Channel)
+	assert.Equal(t, "❌ Backup failed", request.Text)
+	require.Len(t, request.Attachments, 1)
+	assert.Equal(t, "#E01E5A", request.Attachments[0].Color)
+
+	blocks := request.Attachments[0].Blocks
+	require.Len(t, blocks, 5)
+
+	assert.Equal(t, "header", blocks[0]["type"])
+	assert.Equal(t, "❌ Backup failed", blocks[0]["text"].(map[string]any)["text"])
+
+	assert.Equal(t, "Backup of &lt;main&gt; failed", blocks[1]["text"].(map[string]any)["text"])
+
+	fields := blocks[2]["fields"].([]any)
+	require.Len(t, fields, 1)
+	assert.Equal(t, "*Database*\nmain", fields[0].(map[string]any)["text"])
+
+	assert.Equal(
+		t,
+		"```relation &lt;users&gt; &amp; &lt;orders&gt; not found```",
+		blocks[3]["text"].(map[string]any)["text"],
+	)
+
+	assert.Equal(t, "actions", blocks[4]["type"])
+	buttons := blocks[4]["elements"].([]any)
+	require.Len(t, buttons, 1)
+	assert.Equal(
+		t,
+		"https://postgresus.example.com/databases/1",
+		buttons[0].(map[string]any)["url"],
+	)
+}
+
+func Test_Send_SlackApiError_ErrorReturned(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": false, "error": "channel_not_found"}`))
+	}))
+	defer server.Close()
+	postMessageURL = server.URL
+
+	notifier := &SlackNotifier{BotToken: "xoxb-token", TargetChatID: "C123"}
+
+	err := notifier.Send(logger.GetLogger(), notifier_events.NewTestEvent())
+	assert.ErrorContains(t, err, "channel_not_found")
+}
@@ -8,13 +8,14 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
 
 	"github.com/google/uuid"
 )
 
 type TeamsNotifier struct {
 	NotifierID uuid.UUID `gorm:"type:uuid;primaryKey;column:notifier_id"      json:"notifierId"`
-	WebhookURL string    `gorm:"type:text;not null;column:power_automate_url" json:"powerAutomateUrl"`
+	WebhookURL string    `gorm:"type:text;not null;column:power_automate_url;serializer:encrypted" json:"powerAutomateUrl"`
 }
 
 func (TeamsNotifier) TableName() string {
@@ -32,6 +33,20 @@ func (n *TeamsNotifier) Validate() error {
 	return nil
 }
 
+// HideSensitiveData removes the webhook URL before the notifier is
+// returned to the client, the URL contains the signature of the flow
+func (n *TeamsNotifier) HideSensitiveData() {
+	n.WebhookURL = ""
+}
+
+// FillSensitiveData keeps the saved webhook URL if the client has not
+// sent a new one
+func (n *TeamsNotifier) FillSensitiveData(existing *TeamsNotifier) {
+	if n.WebhookURL == "" && existing != nil {
+		n.WebhookURL = existing.WebhookURL
+	}
+}
+
 type cardAttachment struct {
 	ContentType string      `json:"contentType"`
 	Content     interface{} `json:"content"`
@@ -43,30 +58,19 @@ type payload struct {
 	Attachments []cardAttachment `json:"attachments,omitempty"`
 }
 
-func (n *TeamsNotifier) Send(logger *slog.Logger, heading, message string) error {
+func (n *TeamsNotifier) Send(
+	logger *slog.Logger,
+	event *notifier_events.NotificationEvent,
+) error {
 	if err := n.Validate(); err != nil {
 		return err
 	}
 
-	card := map[string]any{
-		"type":    "AdaptiveCard",
-		"version": "1.4",
-		"body": []any{
-			map[string]any{
-				"type":   "TextBlock",
-				"size":   "Medium",
-				"weight": "Bolder",
-				"text":   heading,
-			},
-			map[string]any{"type": "TextBlock", "wrap": true, "text": message},
-		},
-	}
-
 	p := payload{
-		Title: heading,
-		Text:  message,
+		Title: event.Title,
+		Text:  event.GetPlainText(),
 		Attachments: []cardAttachment{
-			{ContentType: "application/vnd.microsoft.card.adaptive", Content: card},
+			{ContentType: "application/vnd.microsoft.card.adaptive", Content: renderCard(event)},
 		},
 	}
 
@@ -94,3 +98,76 @@ func (n *TeamsNotifier) Send(logger *slog.Logger, heading, message string) error
 
 	return nil
 }
+
+// renderCard renders the event as adaptive card: the title colored by
+// severity, details as facts and links as buttons
+func renderCard(event *notifier_events.NotificationEvent) map[string]any {
+	body := []any{
+		map[string]any{
+			"type":   "TextBlock",
+			"size":   "Medium",
+			"weight": "Bolder",
+			"wrap":   true,
+			"color":  getTextColor(event.Severity),
+			"text":   event.Title,
+		},
+	}
+
+	if event.Message != "" {
+		body = append(body, map[string]any{"type": "TextBlock", "wrap": true, "text": event.Message})
+	}
+
+	fields := event.GetFields()
+	if len(fields) > 0 {
+		facts := make([]any, 0, len(fields))
+		for _, field := range fields {
+			facts = append(facts, map[string]any{"title": field.Name, "value": field.Value})
+		}
+
+		body = append(body, map[string]any{"type": "FactSet", "facts": facts})
+	}
+
+	if event.Error != nil && *event.Error != "" {
+		body = append(body, map[string]any{
+			"type":     "TextBlock",
+			"wrap":     true,
+			"fontType": "Monospace",
+			"color":    "Attention",
+			"text":     *event.Error,
+		})
+	}
+
+	card := map[string]any{
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+
+	if len(event.Links) > 0 {
+		actions := make([]any, 0, len(event.Links))
+		for _, link := range event.Links {
+			actions = append(actions, map[string]any{
+				"type":  "Action.OpenUrl",
+				"title": link.Title,
+				"url":   link.URL,
+			})
+		}
+
+		card["actions"] = actions
+	}
+
+	return card
+}
+
+func getTextColor(severity notifier_events.Severity) string {
+	switch severity {
+	case notifier_events.SeveritySuccess:
+		return "Good"
+	case notifier_events.SeverityWarning:
+		return "Warning"
+	case notifier_events.SeverityError, notifier_events.SeverityCritical:
+		return "Attention"
+	default:
+		return "Default"
+	}
+}
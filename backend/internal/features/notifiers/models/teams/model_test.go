@@ -0,0 +1,94 @@
+package teams_notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"postgresus-backend/internal/util/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type teamsRequest struct {
+	Title       string `json:"title"`
+	Text        string `json:"text"`
+	Attachments []struct {
+		ContentType string         `json:"contentType"`
+		Content     map[string]any `json:"content"`
+	} `json:"attachments"`
+}
+
+func Test_Send_BackupFailedEvent_AdaptiveCardSent(t *testing.T) {
+	var request teamsRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&request)
+	}))
+	defer server.Close()
+
+	notifier := &TeamsNotifier{WebhookURL: server.URL}
+
+	backupError := "connection refused"
+	err := notifier.Send(logger.GetLogger(), &notifier_events.NotificationEvent{
+		Type:         notifier_events.EventTypeBackupFailed,
+		Severity:     notifier_events.SeverityError,
+		Title:        "❌ Backup failed",
+		DatabaseName: "main",
+		Error:        &backupError,
+		Links: []notifier_events.EventLink{
+			{Title: "Open database", URL: "https://postgresus.example.com/databases/1"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "❌ Backup failed", request.Title)
+	require.Len(t, request.Attachments, 1)
+	assert.Equal(t, "application/vnd.microsoft.card.adaptive", request.Attachments[0].ContentType)
+
+	card := request.Attachments[0].Content
+	assert.Equal(t, "AdaptiveCard", card["type"])
+	assert.Equal(t, "1.4", card["version"])
+
+	body := card["body"].([]any)
+	require.Len(t, body, 3)
+
+	title := body[0].(map[string]any)
+	assert.Equal(t, "❌ Backup failed", title["text"])
+	assert.Equal(t, "Attention", title["color"])
+
+	factSet := body[1].(map[string]any)
+	assert.Equal(t, "FactSet", factSet["type"])
+	assert.Equal(
+		t,
+		[]any{map[string]any{"title": "Database", "value": "main"}},
+		factSet["facts"],
+	)
+
+	errorBlock := body[2].(map[string]any)
+	assert.Equal(t, "connection refused", errorBlock["text"])
+	assert.Equal(t, "Monospace", errorBlock["fontType"])
+
+	actions := card["actions"].([]any)
+	require.Len(t, actions, 1)
+	assert.Equal(t, map[string]any{
+		"type":  "Action.OpenUrl",
+		"title": "Open database",
+		"url":   "https://postgresus.example.com/databases/1",
+	}, actions[0])
+}
+
+func Test_Send_WebhookReturnsError_ErrorReturned(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	notifier := &TeamsNotifier{WebhookURL: server.URL}
+
+	err := notifier.Send(logger.GetLogger(), notifier_events.NewTestEvent())
+	assert.ErrorContains(t, err, "400")
+}
@@ -3,19 +3,25 @@ package telegram_notifier
 import (
 	"errors"
 	"fmt"
+	"html"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
 	"strconv"
 	"strings"
 
 	"github.com/google/uuid"
 )
 
+// apiBaseURL is the address of Telegram Bot API, it is replaced by a
+// local server in tests
+var apiBaseURL = "https://api.telegram.org"
+
 type TelegramNotifier struct {
 	NotifierID   uuid.UUID `json:"notifierId"   gorm:"primaryKey;column:notifier_id"`
-	BotToken     string    `json:"botToken"     gorm:"not null;column:bot_token"`
+	BotToken     string    `json:"botToken"     gorm:"not null;column:bot_token;serializer:encrypted"`
 	TargetChatID string    `json:"targetChatId" gorm:"not null;column:target_chat_id"`
 	ThreadID     *int64    `json:"threadId"     gorm:"column:thread_id"`
 }
@@ -36,13 +42,28 @@ func (t *TelegramNotifier) Validate() error {
 	return nil
 }
 
-func (t *TelegramNotifier) Send(logger *slog.Logger, heading string, message string) error {
-	fullMessage := heading
-	if message != "" {
-		fullMessage = fmt.Sprintf("%s\n\n%s", heading, message)
+// HideSensitiveData removes the bot token before the notifier is
+// returned to the client, the token is write only
+func (t *TelegramNotifier) HideSensitiveData() {
+	t.BotToken = ""
+}
+
+// FillSensitiveData keeps the saved bot token if the client has not
+// sent a new one. The token is sent only to Telegram API, so it is
+// safe to keep it for another chat
+func (t *TelegramNotifier) FillSensitiveData(existing *TelegramNotifier) {
+	if t.BotToken == "" && existing != nil {
+		t.BotToken = existing.BotToken
 	}
+}
+
+func (t *TelegramNotifier) Send(
+	logger *slog.Logger,
+	event *notifier_events.NotificationEvent,
+) error {
+	fullMessage := renderMessage(event)
 
-	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", t.BotToken)
+	apiURL := fmt.Sprintf("%s/bot%s/sendMessage", apiBaseURL, t.BotToken)
 
 	data := url.Values{}
 	data.Set("chat_id", t.TargetChatID)
@@ -80,3 +101,42 @@ func (t *TelegramNotifier) Send(logger *slog.Logger, heading string, message str
 
 	return nil
 }
+
+// renderMessage renders the event as HTML supported by Telegram. Texts
+// are escaped, otherwise errors with "<" break the message
+func renderMessage(event *notifier_events.NotificationEvent) string {
+	var builder strings.Builder
+
+	builder.WriteString("<b>" + html.EscapeString(event.Title) + "</b>")
+
+	if event.Message != "" {
+		builder.WriteString("\n\n" + html.EscapeString(event.Message))
+	}
+
+	fields := event.GetFields()
+	if len(fields) > 0 {
+		builder.WriteString("\n")
+	}
+
+	for _, field := range fields {
+		builder.WriteString(fmt.Sprintf(
+			"\n<b>%s:</b> %s",
+			html.EscapeString(field.Name),
+			html.EscapeString(field.Value),
+		))
+	}
+
+	if event.Error != nil && *event.Error != "" {
+		builder.WriteString("\n\n<pre>" + html.EscapeString(*event.Error) + "</pre>")
+	}
+
+	for _, link := range event.Links {
+		builder.WriteString(fmt.Sprintf(
+			"\n\n<a href=\"%s\">%s</a>",
+			html.EscapeString(link.URL),
+			html.EscapeString(link.Title),
+		))
+	}
+
+	return builder.String()
+}
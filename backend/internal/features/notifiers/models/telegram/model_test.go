@@ -0,0 +1,80 @@
+package telegram_notifier
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"postgresus-backend/internal/util/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Send_EventWithHtmlCharacters_EscapedHtmlMessageSent(t *testing.T) {
+	var (
+		path     string
+		formData map[string]string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_ = r.ParseForm()
+
+		formData = map[string]string{}
+		for key := range r.PostForm {
+			formData[key] = r.PostForm.Get(key)
+		}
+	}))
+	defer server.Close()
+	apiBaseURL = server.URL
+
+	threadID := int64(42)
+	notifier := &TelegramNotifier{
+		BotToken:     "123:token",
+		TargetChatID: "-100123",
+		ThreadID:     &threadID,
+	}
+
+	backupError := "relation <users> & \"orders\" not found"
+	err := notifier.Send(logger.GetLogger(), &notifier_events.NotificationEvent{
+		Type:         notifier_events.EventTypeBackupFailed,
+		Severity:     notifier_events.SeverityError,
+		Title:        "❌ Backup failed for <main>",
+		DatabaseName: "main & co",
+		Error:        &backupError,
+		Links: []notifier_events.EventLink{
+			{Title: "Open <database>", URL: "https://postgresus.example.com/databases/1?a=1&b=2"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/bot123:token/sendMessage", path)
+	assert.Equal(t, "-100123", formData["chat_id"])
+	assert.Equal(t, "42", formData["message_thread_id"])
+	assert.Equal(t, "HTML", formData["parse_mode"])
+	assert.Equal(
+		t,
+		"<b>❌ Backup failed for &lt;main&gt;</b>\n"+
+			"\n<b>Database:</b> main &amp; co"+
+			"\n\n<pre>relation &lt;users&gt; &amp; &#34;orders&#34; not found</pre>"+
+			"\n\n<a href=\"https://postgresus.example.com/databases/1?a=1&amp;b=2\">"+
+			"Open &lt;database&gt;</a>",
+		formData["text"],
+	)
+}
+
+func Test_Send_TelegramApiError_ErrorReturned(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"ok": false, "description": "chat not found"}`))
+	}))
+	defer server.Close()
+	apiBaseURL = server.URL
+
+	notifier := &TelegramNotifier{BotToken: "123:token", TargetChatID: "-100123"}
+
+	err := notifier.Send(logger.GetLogger(), notifier_events.NewTestEvent())
+	assert.ErrorContains(t, err, "chat not found")
+}
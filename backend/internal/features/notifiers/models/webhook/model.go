@@ -2,6 +2,9 @@ package webhook_notifier
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,96 +12,318 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	notifier_templates "postgresus-backend/internal/features/notifiers/templates"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultTimeoutSeconds = 10
+	maxTimeoutSeconds     = 60
+
+	signatureHeader = "X-Postgresus-Signature"
+	timestampHeader = "X-Postgresus-Timestamp"
 )
 
 type WebhookNotifier struct {
 	NotifierID    uuid.UUID     `json:"notifierId"    gorm:"primaryKey;column:notifier_id"`
 	WebhookURL    string        `json:"webhookUrl"    gorm:"not null;column:webhook_url"`
 	WebhookMethod WebhookMethod `json:"webhookMethod" gorm:"not null;column:webhook_method"`
+
+	// headers may contain tokens, so they are stored encrypted
+	Headers       []WebhookHeader `json:"headers" gorm:"-"`
+	HeadersString string          `json:"-"       gorm:"not null;column:headers;type:text;serializer:encrypted"`
+
+	// body of POST request, the default JSON payload is sent if empty
+	BodyTemplate string `json:"bodyTemplate" gorm:"not null;column:body_template;type:text"`
+
+	TimeoutSeconds int `json:"timeoutSeconds" gorm:"not null;column:timeout_seconds;type:int"`
+
+	// requests are signed by HMAC-SHA256 if the secret is set
+	SigningSecret string `json:"signingSecret" gorm:"not null;column:signing_secret;type:text;serializer:encrypted"`
+}
+
+type WebhookHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
 
 func (t *WebhookNotifier) TableName() string {
 	return "webhook_notifiers"
 }
 
+func (t *WebhookNotifier) BeforeSave(tx *gorm.DB) error {
+	if len(t.Headers) == 0 {
+		t.HeadersString = ""
+		return nil
+	}
+
+	headers, err := json.Marshal(t.Headers)
+	if err != nil {
+		return err
+	}
+
+	t.HeadersString = string(headers)
+	return nil
+}
+
+func (t *WebhookNotifier) AfterFind(tx *gorm.DB) error {
+	t.Headers = []WebhookHeader{}
+
+	if t.HeadersString == "" {
+		return nil
+	}
+
+	return json.Unmarshal([]byte(t.HeadersString), &t.Headers)
+}
+
 func (t *WebhookNotifier) Validate() error {
 	if t.WebhookURL == "" {
 		return errors.New("webhook URL is required")
 	}
 
+	parsedURL, err := url.Parse(t.WebhookURL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") {
+		return errors.New("webhook URL must be a valid http or https URL")
+	}
+
 	if t.WebhookMethod == "" {
 		return errors.New("webhook method is required")
 	}
 
+	for _, header := range t.Headers {
+		if header.Name == "" || strings.ContainsAny(header.Name, " :\t\r\n") {
+			return fmt.Errorf("invalid webhook header name: \"%s\"", header.Name)
+		}
+
+		if strings.ContainsAny(header.Value, "\r\n") {
+			return fmt.Errorf("value of webhook header %s contains line breaks", header.Name)
+		}
+
+		if strings.EqualFold(header.Name, signatureHeader) ||
+			strings.EqualFold(header.Name, timestampHeader) {
+			return fmt.Errorf("webhook header %s is set by Postgresus", header.Name)
+		}
+	}
+
+	if t.BodyTemplate != "" {
+		if t.WebhookMethod != WebhookMethodPOST {
+			return errors.New("body template can be used only with POST method")
+		}
+
+		sampleEvent := notifier_templates.NewSampleEvent(notifier_events.EventTypeBackupSuccess)
+		if _, err := t.renderBody(sampleEvent); err != nil {
+			return fmt.Errorf("invalid webhook body template: %w", err)
+		}
+	}
+
+	// signature covers the body only, while GET sends the event in the
+	// query, so such requests could be altered without notice
+	if t.SigningSecret != "" && t.WebhookMethod != WebhookMethodPOST {
+		return errors.New("signing secret can be used only with POST method")
+	}
+
+	if t.TimeoutSeconds < 0 || t.TimeoutSeconds > maxTimeoutSeconds {
+		return fmt.Errorf("webhook timeout must be up to %d seconds", maxTimeoutSeconds)
+	}
+
 	return nil
 }
 
-func (t *WebhookNotifier) Send(logger *slog.Logger, heading string, message string) error {
+// HideSensitiveData removes values of headers and the signing secret
+// before the notifier is returned to the client, they are write only
+func (t *WebhookNotifier) HideSensitiveData() {
+	for i := range t.Headers {
+		t.Headers[i].Value = ""
+	}
+
+	t.SigningSecret = ""
+}
+
+// FillSensitiveData keeps saved values of headers and the signing
+// secret if the client has not sent new ones. They are kept only for
+// the same URL, otherwise the changed URL would receive the tokens
+func (t *WebhookNotifier) FillSensitiveData(existing *WebhookNotifier) {
+	if existing == nil || existing.WebhookURL != t.WebhookURL {
+		return
+	}
+
+	for i, header := range t.Headers {
+		if header.Value != "" {
+			continue
+		}
+
+		for _, existingHeader := range existing.Headers {
+			if strings.EqualFold(existingHeader.Name, header.Name) {
+				t.Headers[i].Value = existingHeader.Value
+				break
+			}
+		}
+	}
+
+	// GET requests are not signed, so the secret is dropped when the
+	// method is switched to GET
+	if t.SigningSecret == "" && t.WebhookMethod == WebhookMethodPOST {
+		t.SigningSecret = existing.SigningSecret
+	}
+}
+
+// webhookPayload is sent by POST. Heading and message are plain text
+// kept for receivers made before the event was added
+type webhookPayload struct {
+	Heading string                             `json:"heading"`
+	Message string                             `json:"message"`
+	Event   *notifier_events.NotificationEvent `json:"event"`
+}
+
+func (t *WebhookNotifier) Send(
+	logger *slog.Logger,
+	event *notifier_events.NotificationEvent,
+) error {
+	var (
+		reqURL = t.WebhookURL
+		body   []byte
+	)
+
 	switch t.WebhookMethod {
 	case WebhookMethodGET:
-		reqURL := fmt.Sprintf("%s?heading=%s&message=%s",
-			t.WebhookURL,
-			url.QueryEscape(heading),
-			url.QueryEscape(message),
-		)
+		query := url.Values{}
+		query.Set("heading", event.Title)
+		query.Set("message", event.GetPlainText())
+		query.Set("type", string(event.Type))
+		query.Set("severity", string(event.Severity))
 
-		resp, err := http.Get(reqURL)
+		separator := "?"
+		if strings.Contains(reqURL, "?") {
+			separator = "&"
+		}
+		reqURL += separator + query.Encode()
+
+	case WebhookMethodPOST:
+		renderedBody, err := t.renderBody(event)
 		if err != nil {
-			return fmt.Errorf("failed to send GET webhook: %w", err)
+			return err
 		}
-		defer func() {
-			if cerr := resp.Body.Close(); cerr != nil {
-				logger.Error("failed to close response body", "error", cerr)
-			}
-		}()
-
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			body, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf(
-				"webhook GET returned status: %s, body: %s",
-				resp.Status,
-				string(body),
-			)
+
+		body = renderedBody
+
+	default:
+		return fmt.Errorf("unsupported webhook method: %s", t.WebhookMethod)
+	}
+
+	return t.sendRequest(logger, reqURL, body)
+}
+
+// sendRequest makes a single request to the webhook. Failed requests
+// are not retried here, the notification outbox retries them later
+func (t *WebhookNotifier) sendRequest(
+	logger *slog.Logger,
+	reqURL string,
+	body []byte,
+) error {
+	method := http.MethodGet
+	var bodyReader io.Reader
+	if t.WebhookMethod == WebhookMethodPOST {
+		method = http.MethodPost
+		bodyReader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequest(method, reqURL, bodyReader)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	if t.WebhookMethod == WebhookMethodPOST {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	req.Header.Set("User-Agent", "Postgresus")
+
+	for _, header := range t.Headers {
+		req.Header.Set(header.Name, header.Value)
+	}
+
+	if t.SigningSecret != "" {
+		timestamp := strconv.FormatInt(time.Now().UTC().Unix(), 10)
+		req.Header.Set(timestampHeader, timestamp)
+		req.Header.Set(signatureHeader, "sha256="+Sign(t.SigningSecret, timestamp, body))
+	}
+
+	client := &http.Client{Timeout: t.getTimeout()}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send %s webhook: %w", t.WebhookMethod, err)
+	}
+
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			logger.Error("failed to close response body", "error", cerr)
 		}
+	}()
 
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
 		return nil
+	}
 
-	case WebhookMethodPOST:
-		payload := map[string]string{
-			"heading": heading,
-			"message": message,
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return fmt.Errorf(
+		"webhook %s returned status: %s, body: %s",
+		t.WebhookMethod,
+		resp.Status,
+		string(respBody),
+	)
+}
+
+func (t *WebhookNotifier) renderBody(event *notifier_events.NotificationEvent) ([]byte, error) {
+	if t.BodyTemplate == "" {
+		payload := webhookPayload{
+			Heading: event.Title,
+			Message: event.GetPlainText(),
+			Event:   event,
 		}
 
 		body, err := json.Marshal(payload)
 		if err != nil {
-			return fmt.Errorf("failed to marshal webhook payload: %w", err)
+			return nil, fmt.Errorf("failed to marshal webhook payload: %w", err)
 		}
 
-		resp, err := http.Post(t.WebhookURL, "application/json", bytes.NewReader(body))
-		if err != nil {
-			return fmt.Errorf("failed to send POST webhook: %w", err)
-		}
+		return body, nil
+	}
 
-		defer func() {
-			if cerr := resp.Body.Close(); cerr != nil {
-				logger.Error("failed to close response body", "error", cerr)
-			}
-		}()
-
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			body, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf(
-				"webhook POST returned status: %s, body: %s",
-				resp.Status,
-				string(body),
-			)
-		}
+	body, err := notifier_templates.Render(
+		"webhook body",
+		t.BodyTemplate,
+		notifier_templates.NewTemplateData(event),
+	)
+	if err != nil {
+		return nil, err
+	}
 
-		return nil
+	return []byte(body), nil
+}
 
-	default:
-		return fmt.Errorf("unsupported webhook method: %s", t.WebhookMethod)
+func (t *WebhookNotifier) getTimeout() time.Duration {
+	if t.TimeoutSeconds <= 0 {
+		return defaultTimeoutSeconds * time.Second
 	}
+
+	return time.Duration(t.TimeoutSeconds) * time.Second
+}
+
+// Sign returns hex encoded HMAC-SHA256 of "timestamp.body". Receivers
+// compute the same value with the shared secret and compare it with
+// the signature header, the timestamp lets them reject old requests
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
 }
@@ -0,0 +1,141 @@
+package webhook_notifier
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"postgresus-backend/internal/util/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Send_PostWithTemplateAndSecret_SignedRequestWithHeadersSent(t *testing.T) {
+	var (
+		receivedBody      string
+		receivedAuth      string
+		receivedTimestamp string
+		receivedSignature string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receivedBody = string(body)
+		receivedAuth = r.Header.Get("Authorization")
+		receivedTimestamp = r.Header.Get(timestampHeader)
+		receivedSignature = r.Header.Get(signatureHeader)
+	}))
+	defer server.Close()
+
+	notifier := &WebhookNotifier{
+		WebhookURL:    server.URL,
+		WebhookMethod: WebhookMethodPOST,
+		Headers:       []WebhookHeader{{Name: "Authorization", Value: "Bearer token"}},
+		BodyTemplate:  `{"text": {{json .Title}}, "type": "{{.EventType}}"}`,
+		SigningSecret: "shared-secret",
+	}
+	require.NoError(t, notifier.Validate())
+
+	err := notifier.Send(logger.GetLogger(), &notifier_events.NotificationEvent{
+		Type:  notifier_events.EventTypeBackupFailed,
+		Title: `Backup of "main" failed`,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `{"text": "Backup of \"main\" failed", "type": "BACKUP_FAILED"}`, receivedBody)
+	assert.Equal(t, "Bearer token", receivedAuth)
+	assert.Equal(
+		t,
+		"sha256="+Sign("shared-secret", receivedTimestamp, []byte(receivedBody)),
+		receivedSignature,
+	)
+}
+
+func Test_Send_ServerError_ErrorReturnedWithoutRetry(t *testing.T) {
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	notifier := &WebhookNotifier{
+		WebhookURL:    server.URL,
+		WebhookMethod: WebhookMethodPOST,
+	}
+
+	err := notifier.Send(logger.GetLogger(), notifier_events.NewTestEvent())
+	assert.ErrorContains(t, err, "502")
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func Test_Send_ClientError_ErrorReturned(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	notifier := &WebhookNotifier{
+		WebhookURL:    server.URL,
+		WebhookMethod: WebhookMethodPOST,
+	}
+
+	err := notifier.Send(logger.GetLogger(), notifier_events.NewTestEvent())
+	assert.ErrorContains(t, err, "401")
+}
+
+func Test_FillSensitiveData_UrlChanged_SecretsNotKept(t *testing.T) {
+	existing := &WebhookNotifier{
+		WebhookURL:    "https://example.com/hook",
+		WebhookMethod: WebhookMethodPOST,
+		Headers:       []WebhookHeader{{Name: "Authorization", Value: "Bearer token"}},
+		SigningSecret: "shared-secret",
+	}
+
+	sameURL := &WebhookNotifier{
+		WebhookURL:    "https://example.com/hook",
+		WebhookMethod: WebhookMethodPOST,
+		Headers:       []WebhookHeader{{Name: "authorization"}},
+	}
+	sameURL.FillSensitiveData(existing)
+
+	assert.Equal(t, "Bearer token", sameURL.Headers[0].Value)
+	assert.Equal(t, "shared-secret", sameURL.SigningSecret)
+
+	changedURL := &WebhookNotifier{
+		WebhookURL: "https://attacker.example.com/hook",
+		Headers:    []WebhookHeader{{Name: "Authorization"}},
+	}
+	changedURL.FillSensitiveData(existing)
+
+	assert.Empty(t, changedURL.Headers[0].Value)
+	assert.Empty(t, changedURL.SigningSecret)
+}
+
+func Test_Validate_GetWithSigningSecret_ErrorReturned(t *testing.T) {
+	notifier := &WebhookNotifier{
+		WebhookURL:    "https://example.com/hook",
+		WebhookMethod: WebhookMethodGET,
+		SigningSecret: "shared-secret",
+	}
+
+	assert.EqualError(t, notifier.Validate(), "signing secret can be used only with POST method")
+
+	switchedToGet := &WebhookNotifier{
+		WebhookURL:    "https://example.com/hook",
+		WebhookMethod: WebhookMethodGET,
+	}
+	switchedToGet.FillSensitiveData(&WebhookNotifier{
+		WebhookURL:    "https://example.com/hook",
+		WebhookMethod: WebhookMethodPOST,
+		SigningSecret: "shared-secret",
+	})
+
+	assert.Empty(t, switchedToGet.SigningSecret)
+	assert.NoError(t, switchedToGet.Validate())
+}
@@ -1,7 +1,10 @@
 package notifiers
 
 import (
+	notifier_templates "postgresus-backend/internal/features/notifiers/templates"
 	"postgresus-backend/internal/storage"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -39,6 +42,34 @@ func (r *NotifierRepository) Save(notifier *Notifier) (*Notifier, error) {
 			if notifier.TeamsNotifier != nil {
 				notifier.TeamsNotifier.NotifierID = notifier.ID
 			}
+		case NotifierTypePagerDuty:
+			if notifier.PagerDutyNotifier != nil {
+				notifier.PagerDutyNotifier.NotifierID = notifier.ID
+			}
+		case NotifierTypeOpsgenie:
+			if notifier.OpsgenieNotifier != nil {
+				notifier.OpsgenieNotifier.NotifierID = notifier.ID
+			}
+		case NotifierTypeMattermost:
+			if notifier.MattermostNotifier != nil {
+				notifier.MattermostNotifier.NotifierID = notifier.ID
+			}
+		case NotifierTypeRocketChat:
+			if notifier.RocketChatNotifier != nil {
+				notifier.RocketChatNotifier.NotifierID = notifier.ID
+			}
+		case NotifierTypeGoogleChat:
+			if notifier.GoogleChatNotifier != nil {
+				notifier.GoogleChatNotifier.NotifierID = notifier.ID
+			}
+		case NotifierTypeMatrix:
+			if notifier.MatrixNotifier != nil {
+				notifier.MatrixNotifier.NotifierID = notifier.ID
+			}
+		case NotifierTypeNtfy:
+			if notifier.NtfyNotifier != nil {
+				notifier.NtfyNotifier.NotifierID = notifier.ID
+			}
 		}
 
 		if notifier.ID == uuid.Nil {
@@ -50,6 +81,14 @@ func (r *NotifierRepository) Save(notifier *Notifier) (*Notifier, error) {
 					"SlackNotifier",
 					"DiscordNotifier",
 					"TeamsNotifier",
+					"PagerDutyNotifier",
+					"OpsgenieNotifier",
+					"MattermostNotifier",
+					"RocketChatNotifier",
+					"GoogleChatNotifier",
+					"MatrixNotifier",
+					"NtfyNotifier",
+					"Templates",
 				).
 				Create(notifier).Error; err != nil {
 				return err
@@ -63,6 +102,14 @@ func (r *NotifierRepository) Save(notifier *Notifier) (*Notifier, error) {
 					"SlackNotifier",
 					"DiscordNotifier",
 					"TeamsNotifier",
+					"PagerDutyNotifier",
+					"OpsgenieNotifier",
+					"MattermostNotifier",
+					"RocketChatNotifier",
+					"GoogleChatNotifier",
+					"MatrixNotifier",
+					"NtfyNotifier",
+					"Templates",
 				).
 				Save(notifier).Error; err != nil {
 				return err
@@ -112,6 +159,59 @@ func (r *NotifierRepository) Save(notifier *Notifier) (*Notifier, error) {
 					return err
 				}
 			}
+		case NotifierTypePagerDuty:
+			if notifier.PagerDutyNotifier != nil {
+				notifier.PagerDutyNotifier.NotifierID = notifier.ID
+				if err := tx.Save(notifier.PagerDutyNotifier).Error; err != nil {
+					return err
+				}
+			}
+		case NotifierTypeOpsgenie:
+			if notifier.OpsgenieNotifier != nil {
+				notifier.OpsgenieNotifier.NotifierID = notifier.ID
+				if err := tx.Save(notifier.OpsgenieNotifier).Error; err != nil {
+					return err
+				}
+			}
+		case NotifierTypeMattermost:
+			if notifier.MattermostNotifier != nil {
+				notifier.MattermostNotifier.NotifierID = notifier.ID
+				if err := tx.Save(notifier.MattermostNotifier).Error; err != nil {
+					return err
+				}
+			}
+		case NotifierTypeRocketChat:
+			if notifier.RocketChatNotifier != nil {
+				notifier.RocketChatNotifier.NotifierID = notifier.ID
+				if err := tx.Save(notifier.RocketChatNotifier).Error; err != nil {
+					return err
+				}
+			}
+		case NotifierTypeGoogleChat:
+			if notifier.GoogleChatNotifier != nil {
+				notifier.GoogleChatNotifier.NotifierID = notifier.ID
+				if err := tx.Save(notifier.GoogleChatNotifier).Error; err != nil {
+					return err
+				}
+			}
+		case NotifierTypeMatrix:
+			if notifier.MatrixNotifier != nil {
+				notifier.MatrixNotifier.NotifierID = notifier.ID
+				if err := tx.Save(notifier.MatrixNotifier).Error; err != nil {
+					return err
+				}
+			}
+		case NotifierTypeNtfy:
+			if notifier.NtfyNotifier != nil {
+				notifier.NtfyNotifier.NotifierID = notifier.ID
+				if err := tx.Save(notifier.NtfyNotifier).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := r.saveTemplates(tx, notifier); err != nil {
+			return err
 		}
 
 		return nil
@@ -124,6 +224,55 @@ func (r *NotifierRepository) Save(notifier *Notifier) (*Notifier, error) {
 	return notifier, nil
 }
 
+// saveTemplates updates templates of the notifier and removes the ones
+// which are not sent anymore. IDs of templates of other notifiers are
+// not trusted, such templates are created as new ones
+func (r *NotifierRepository) saveTemplates(tx *gorm.DB, notifier *Notifier) error {
+	var existingIDs []uuid.UUID
+	if err := tx.
+		Model(&notifier_templates.NotifierTemplate{}).
+		Where("notifier_id = ?", notifier.ID).
+		Pluck("id", &existingIDs).Error; err != nil {
+		return err
+	}
+
+	keptIDs := []uuid.UUID{}
+	for _, template := range notifier.Templates {
+		if slices.Contains(existingIDs, template.ID) {
+			keptIDs = append(keptIDs, template.ID)
+		} else {
+			template.ID = uuid.Nil
+		}
+
+		template.NotifierID = notifier.ID
+	}
+
+	// removed before saving, so the event type of removed template can
+	// be used by the new one
+	query := tx.Where("notifier_id = ?", notifier.ID)
+	if len(keptIDs) > 0 {
+		query = query.Where("id NOT IN ?", keptIDs)
+	}
+
+	if err := query.Delete(&notifier_templates.NotifierTemplate{}).Error; err != nil {
+		return err
+	}
+
+	for _, template := range notifier.Templates {
+		if template.ID == uuid.Nil {
+			if err := tx.Create(template).Error; err != nil {
+				return err
+			}
+		} else {
+			if err := tx.Save(template).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func (r *NotifierRepository) FindByID(id uuid.UUID) (*Notifier, error) {
 	var notifier Notifier
 
@@ -135,6 +284,16 @@ func (r *NotifierRepository) FindByID(id uuid.UUID) (*Notifier, error) {
 		Preload("SlackNotifier").
 		Preload("DiscordNotifier").
 		Preload("TeamsNotifier").
+		Preload("PagerDutyNotifier").
+		Preload("OpsgenieNotifier").
+		Preload("MattermostNotifier").
+		Preload("RocketChatNotifier").
+		Preload("GoogleChatNotifier").
+		Preload("MatrixNotifier").
+		Preload("NtfyNotifier").
+		Preload("Templates", func(db *gorm.DB) *gorm.DB {
+			return db.Order("event_type ASC")
+		}).
 		Where("id = ?", id).
 		First(&notifier).Error; err != nil {
 		return nil, err
@@ -143,9 +302,13 @@ func (r *NotifierRepository) FindByID(id uuid.UUID) (*Notifier, error) {
 	return &notifier, nil
 }
 
-func (r *NotifierRepository) FindByUserID(userID uuid.UUID) ([]*Notifier, error) {
+func (r *NotifierRepository) FindByWorkspaceIDs(workspaceIDs []uuid.UUID) ([]*Notifier, error) {
 	var notifiers []*Notifier
 
+	if len(workspaceIDs) == 0 {
+		return notifiers, nil
+	}
+
 	if err := storage.
 		GetDb().
 		Preload("TelegramNotifier").
@@ -154,7 +317,17 @@ func (r *NotifierRepository) FindByUserID(userID uuid.UUID) ([]*Notifier, error)
 		Preload("SlackNotifier").
 		Preload("DiscordNotifier").
 		Preload("TeamsNotifier").
-		Where("user_id = ?", userID).
+		Preload("PagerDutyNotifier").
+		Preload("OpsgenieNotifier").
+		Preload("MattermostNotifier").
+		Preload("RocketChatNotifier").
+		Preload("GoogleChatNotifier").
+		Preload("MatrixNotifier").
+		Preload("NtfyNotifier").
+		Preload("Templates", func(db *gorm.DB) *gorm.DB {
+			return db.Order("event_type ASC")
+		}).
+		Where("workspace_id IN ?", workspaceIDs).
 		Order("name ASC").
 		Find(&notifiers).Error; err != nil {
 		return nil, err
@@ -203,8 +376,92 @@ func (r *NotifierRepository) Delete(notifier *Notifier) error {
 					return err
 				}
 			}
+		case NotifierTypePagerDuty:
+			if notifier.PagerDutyNotifier != nil {
+				if err := tx.Delete(notifier.PagerDutyNotifier).Error; err != nil {
+					return err
+				}
+			}
+		case NotifierTypeOpsgenie:
+			if notifier.OpsgenieNotifier != nil {
+				if err := tx.Delete(notifier.OpsgenieNotifier).Error; err != nil {
+					return err
+				}
+			}
+		case NotifierTypeMattermost:
+			if notifier.MattermostNotifier != nil {
+				if err := tx.Delete(notifier.MattermostNotifier).Error; err != nil {
+					return err
+				}
+			}
+		case NotifierTypeRocketChat:
+			if notifier.RocketChatNotifier != nil {
+				if err := tx.Delete(notifier.RocketChatNotifier).Error; err != nil {
+					return err
+				}
+			}
+		case NotifierTypeGoogleChat:
+			if notifier.GoogleChatNotifier != nil {
+				if err := tx.Delete(notifier.GoogleChatNotifier).Error; err != nil {
+					return err
+				}
+			}
+		case NotifierTypeMatrix:
+			if notifier.MatrixNotifier != nil {
+				if err := tx.Delete(notifier.MatrixNotifier).Error; err != nil {
+					return err
+				}
+			}
+		case NotifierTypeNtfy:
+			if notifier.NtfyNotifier != nil {
+				if err := tx.Delete(notifier.NtfyNotifier).Error; err != nil {
+					return err
+				}
+			}
 		}
 
 		return tx.Delete(notifier).Error
 	})
 }
+
+// UpdateLastSendError saves only the error of the last notification,
+// so the worker does not overwrite the notifier edited in meantime
+func (r *NotifierRepository) UpdateLastSendError(
+	notifierID uuid.UUID,
+	lastSendError *string,
+) error {
+	return storage.
+		GetDb().
+		Model(&Notifier{}).
+		Where("id = ?", notifierID).
+		Update("last_send_error", lastSendError).Error
+}
+
+// FindWithDigest returns notifiers with enabled digest, specific
+// notifiers are not loaded
+func (r *NotifierRepository) FindWithDigest() ([]*Notifier, error) {
+	var notifiers []*Notifier
+
+	if err := storage.
+		GetDb().
+		Where(
+			"digest_frequency IN ?",
+			[]DigestFrequency{DigestFrequencyDaily, DigestFrequencyWeekly},
+		).
+		Find(&notifiers).Error; err != nil {
+		return nil, err
+	}
+
+	return notifiers, nil
+}
+
+func (r *NotifierRepository) UpdateLastDigestAt(
+	notifierID uuid.UUID,
+	lastDigestAt time.Time,
+) error {
+	return storage.
+		GetDb().
+		Model(&Notifier{}).
+		Where("id = ?", notifierID).
+		Update("last_digest_at", lastDigestAt).Error
+}
@@ -0,0 +1,122 @@
+package notification_rules
+
+import (
+	"net/http"
+	"postgresus-backend/internal/features/users"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type NotificationRuleController struct {
+	notificationRuleService *NotificationRuleService
+	userService             *users.UserService
+}
+
+func (c *NotificationRuleController) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/notification-rules", c.SaveNotificationRule)
+	router.GET("/notification-rules", c.GetNotificationRules)
+	router.DELETE("/notification-rules/:id", c.DeleteNotificationRule)
+}
+
+// SaveNotificationRule
+// @Summary Save a notification rule
+// @Description Create or update a rule which routes events to notifiers
+// @Tags notification-rules
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "JWT token"
+// @Param rule body NotificationRule true "Notification rule data"
+// @Success 200 {object} NotificationRule
+// @Failure 400
+// @Failure 401
+// @Router /notification-rules [post]
+func (c *NotificationRuleController) SaveNotificationRule(ctx *gin.Context) {
+	user, err := c.userService.GetUserFromToken(ctx.GetHeader("Authorization"))
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var rule NotificationRule
+	if err := ctx.ShouldBindJSON(&rule); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.notificationRuleService.SaveNotificationRule(user, &rule); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rule)
+}
+
+// GetNotificationRules
+// @Summary Get notification rules
+// @Description Get notification rules of workspaces available to the current user
+// @Tags notification-rules
+// @Produce json
+// @Param Authorization header string true "JWT token"
+// @Param workspace_id query string false "Workspace ID, all available workspaces if empty"
+// @Success 200 {array} NotificationRule
+// @Failure 400
+// @Failure 401
+// @Router /notification-rules [get]
+func (c *NotificationRuleController) GetNotificationRules(ctx *gin.Context) {
+	user, err := c.userService.GetUserFromToken(ctx.GetHeader("Authorization"))
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var workspaceID *uuid.UUID
+	if workspaceIDStr := ctx.Query("workspace_id"); workspaceIDStr != "" {
+		parsedWorkspaceID, err := uuid.Parse(workspaceIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid workspace_id"})
+			return
+		}
+		workspaceID = &parsedWorkspaceID
+	}
+
+	rules, err := c.notificationRuleService.GetNotificationRules(user, workspaceID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rules)
+}
+
+// DeleteNotificationRule
+// @Summary Delete a notification rule
+// @Description Delete a notification rule by ID
+// @Tags notification-rules
+// @Produce json
+// @Param Authorization header string true "JWT token"
+// @Param id path string true "Notification rule ID"
+// @Success 200
+// @Failure 400
+// @Failure 401
+// @Router /notification-rules/{id} [delete]
+func (c *NotificationRuleController) DeleteNotificationRule(ctx *gin.Context) {
+	user, err := c.userService.GetUserFromToken(ctx.GetHeader("Authorization"))
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid notification rule ID"})
+		return
+	}
+
+	if err := c.notificationRuleService.DeleteNotificationRule(user, id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "notification rule deleted successfully"})
+}
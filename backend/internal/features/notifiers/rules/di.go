@@ -0,0 +1,38 @@
+package notification_rules
+
+import (
+	"postgresus-backend/internal/features/audit_logs"
+	"postgresus-backend/internal/features/databases"
+	"postgresus-backend/internal/features/notifiers"
+	"postgresus-backend/internal/features/users"
+	"postgresus-backend/internal/features/workspaces"
+	"postgresus-backend/internal/util/logger"
+)
+
+var notificationRuleRepository = &NotificationRuleRepository{}
+var notificationRuleService = &NotificationRuleService{
+	notificationRuleRepository,
+	notifiers.GetNotifierService(),
+	databases.GetDatabaseService(),
+	workspaces.GetWorkspaceService(),
+	audit_logs.GetAuditLogService(),
+	logger.GetLogger(),
+}
+var notificationRuleController = &NotificationRuleController{
+	notificationRuleService,
+	users.GetUserService(),
+}
+
+func GetNotificationRuleController() *NotificationRuleController {
+	return notificationRuleController
+}
+
+func GetNotificationRuleService() *NotificationRuleService {
+	return notificationRuleService
+}
+
+func SetupDependencies() {
+	notifiers.GetNotifierService().SetNotificationRouter(notificationRuleService)
+	notifiers.GetNotifierService().AddNotifierRemoveListener(notificationRuleService)
+	databases.GetDatabaseService().AddDbRemoveListener(notificationRuleService)
+}
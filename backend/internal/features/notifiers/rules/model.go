@@ -0,0 +1,239 @@
+package notification_rules
+
+import (
+	"errors"
+	"fmt"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NotificationRule routes events of the workspace to notifiers. Empty
+// event types, severity or databases match any value. Notifiers not
+// used by rules receive all events of their databases as before
+type NotificationRule struct {
+	ID          uuid.UUID `json:"id"          gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
+	WorkspaceID uuid.UUID `json:"workspaceId" gorm:"column:workspace_id;type:uuid;not null"`
+	Name        string    `json:"name"        gorm:"column:name;type:text;not null"`
+
+	EventTypes       []notifier_events.EventType `json:"eventTypes" gorm:"-"`
+	EventTypesString string                      `json:"-"          gorm:"column:event_types;type:text;not null"`
+
+	// events of lower severity are not sent, e.g. WARNING lets through
+	// warnings, errors and critical events
+	MinSeverity notifier_events.Severity `json:"minSeverity" gorm:"column:min_severity;type:varchar(20);not null"`
+
+	DatabaseIDs       []uuid.UUID `json:"databaseIds" gorm:"-"`
+	DatabaseIDsString string      `json:"-"           gorm:"column:database_ids;type:text;not null"`
+
+	NotifierIDs       []uuid.UUID `json:"notifierIds" gorm:"-"`
+	NotifierIDsString string      `json:"-"           gorm:"column:notifier_ids;type:text;not null"`
+
+	// only critical events are sent in quiet hours, time is HH:MM in UTC
+	// and the range may span midnight, e.g. from 22:00 to 07:00
+	IsQuietHoursEnabled bool   `json:"isQuietHoursEnabled" gorm:"column:is_quiet_hours_enabled;type:boolean;not null"`
+	QuietHoursFrom      string `json:"quietHoursFrom"      gorm:"column:quiet_hours_from;type:varchar(5);not null"`
+	QuietHoursTo        string `json:"quietHoursTo"        gorm:"column:quiet_hours_to;type:varchar(5);not null"`
+
+	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at;not null"`
+}
+
+func (r *NotificationRule) TableName() string {
+	return "notification_rules"
+}
+
+func (r *NotificationRule) BeforeSave(tx *gorm.DB) error {
+	eventTypes := make([]string, len(r.EventTypes))
+	for i, eventType := range r.EventTypes {
+		eventTypes[i] = string(eventType)
+	}
+
+	r.EventTypesString = strings.Join(eventTypes, ",")
+	r.DatabaseIDsString = joinIDs(r.DatabaseIDs)
+	r.NotifierIDsString = joinIDs(r.NotifierIDs)
+
+	return nil
+}
+
+func (r *NotificationRule) AfterFind(tx *gorm.DB) error {
+	r.EventTypes = []notifier_events.EventType{}
+	if r.EventTypesString != "" {
+		for _, eventType := range strings.Split(r.EventTypesString, ",") {
+			r.EventTypes = append(r.EventTypes, notifier_events.EventType(eventType))
+		}
+	}
+
+	var err error
+
+	if r.DatabaseIDs, err = splitIDs(r.DatabaseIDsString); err != nil {
+		return err
+	}
+
+	if r.NotifierIDs, err = splitIDs(r.NotifierIDsString); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *NotificationRule) Validate() error {
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if len(r.NotifierIDs) == 0 {
+		return errors.New("at least one notifier is required")
+	}
+
+	for _, eventType := range r.EventTypes {
+		if !slices.Contains(notifier_events.GetEventTypes(), eventType) {
+			return fmt.Errorf("unknown event type: %s", eventType)
+		}
+	}
+
+	if r.MinSeverity != "" &&
+		!slices.Contains(notifier_events.GetSeverities(), r.MinSeverity) {
+		return fmt.Errorf("unknown severity: %s", r.MinSeverity)
+	}
+
+	if r.IsQuietHoursEnabled {
+		from, err := time.Parse("15:04", r.QuietHoursFrom)
+		if err != nil {
+			return errors.New("start of quiet hours must be in HH:MM format")
+		}
+
+		to, err := time.Parse("15:04", r.QuietHoursTo)
+		if err != nil {
+			return errors.New("end of quiet hours must be in HH:MM format")
+		}
+
+		if from.Equal(to) {
+			return errors.New("start and end of quiet hours must differ")
+		}
+	}
+
+	return nil
+}
+
+// IsUsingNotifier returns true if the rule sends events to the notifier
+func (r *NotificationRule) IsUsingNotifier(notifierID uuid.UUID) bool {
+	return slices.Contains(r.NotifierIDs, notifierID)
+}
+
+// IsMatching returns true if the event should be sent by the rule at
+// the given time. Events without database, e.g. locked sign in, match
+// only rules of all databases
+func (r *NotificationRule) IsMatching(
+	event *notifier_events.NotificationEvent,
+	now time.Time,
+) bool {
+	if len(r.EventTypes) > 0 && !slices.Contains(r.EventTypes, event.Type) {
+		return false
+	}
+
+	if r.MinSeverity != "" && event.Severity.GetLevel() < r.MinSeverity.GetLevel() {
+		return false
+	}
+
+	if len(r.DatabaseIDs) > 0 &&
+		(event.DatabaseID == nil || !slices.Contains(r.DatabaseIDs, *event.DatabaseID)) {
+		return false
+	}
+
+	if event.Severity != notifier_events.SeverityCritical && r.isQuietHours(now) {
+		return false
+	}
+
+	return true
+}
+
+func (r *NotificationRule) isQuietHours(now time.Time) bool {
+	if !r.IsQuietHoursEnabled {
+		return false
+	}
+
+	from, err := time.Parse("15:04", r.QuietHoursFrom)
+	if err != nil {
+		return false
+	}
+
+	to, err := time.Parse("15:04", r.QuietHoursTo)
+	if err != nil {
+		return false
+	}
+
+	now = now.UTC()
+	minute := now.Hour()*60 + now.Minute()
+	fromMinute := from.Hour()*60 + from.Minute()
+	toMinute := to.Hour()*60 + to.Minute()
+
+	// range within the day, e.g. from 12:00 to 14:00
+	if fromMinute < toMinute {
+		return minute >= fromMinute && minute < toMinute
+	}
+
+	// range over midnight, e.g. from 22:00 to 07:00
+	return minute >= fromMinute || minute < toMinute
+}
+
+// IsRouted decides whether the notifier receives the event. Notifier
+// without rules receives all events, otherwise at least one of its
+// rules should match the event
+func IsRouted(
+	rules []*NotificationRule,
+	notifierID uuid.UUID,
+	event *notifier_events.NotificationEvent,
+	now time.Time,
+) bool {
+	// digests are enabled per notifier, so rules don't filter them
+	if event.Type == notifier_events.EventTypeBackupDigest {
+		return true
+	}
+
+	hasRules := false
+
+	for _, rule := range rules {
+		if !rule.IsUsingNotifier(notifierID) {
+			continue
+		}
+
+		if rule.IsMatching(event, now) {
+			return true
+		}
+
+		hasRules = true
+	}
+
+	return !hasRules
+}
+
+func joinIDs(ids []uuid.UUID) string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+
+	return strings.Join(values, ",")
+}
+
+func splitIDs(value string) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	if value == "" {
+		return ids, nil
+	}
+
+	for _, idString := range strings.Split(value, ",") {
+		id, err := uuid.Parse(idString)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,108 @@
+package notification_rules
+
+import (
+	"testing"
+	"time"
+
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_IsRouted_NotifierWithoutRules_AllEventsRouted(t *testing.T) {
+	notifierID := uuid.New()
+	rules := []*NotificationRule{
+		{
+			EventTypes:  []notifier_events.EventType{notifier_events.EventTypeBackupFailed},
+			NotifierIDs: []uuid.UUID{uuid.New()},
+		},
+	}
+
+	event := &notifier_events.NotificationEvent{
+		Type:     notifier_events.EventTypeBackupSuccess,
+		Severity: notifier_events.SeveritySuccess,
+	}
+
+	assert.True(t, IsRouted(rules, notifierID, event, time.Now().UTC()))
+}
+
+func Test_IsRouted_RulesByEventTypeAndSeverity_OnlyMatchingEventsRouted(t *testing.T) {
+	pagerDutyID := uuid.New()
+	slackID := uuid.New()
+	databaseID := uuid.New()
+
+	rules := []*NotificationRule{
+		{
+			MinSeverity: notifier_events.SeverityError,
+			NotifierIDs: []uuid.UUID{pagerDutyID},
+		},
+		{
+			EventTypes:  []notifier_events.EventType{notifier_events.EventTypeBackupSuccess},
+			DatabaseIDs: []uuid.UUID{databaseID},
+			NotifierIDs: []uuid.UUID{slackID},
+		},
+	}
+
+	failedEvent := &notifier_events.NotificationEvent{
+		Type:       notifier_events.EventTypeBackupFailed,
+		Severity:   notifier_events.SeverityError,
+		DatabaseID: &databaseID,
+	}
+	successEvent := &notifier_events.NotificationEvent{
+		Type:       notifier_events.EventTypeBackupSuccess,
+		Severity:   notifier_events.SeveritySuccess,
+		DatabaseID: &databaseID,
+	}
+	now := time.Now().UTC()
+
+	assert.True(t, IsRouted(rules, pagerDutyID, failedEvent, now))
+	assert.False(t, IsRouted(rules, pagerDutyID, successEvent, now))
+	assert.False(t, IsRouted(rules, slackID, failedEvent, now))
+	assert.True(t, IsRouted(rules, slackID, successEvent, now))
+
+	otherDatabaseID := uuid.New()
+	successEvent.DatabaseID = &otherDatabaseID
+	assert.False(t, IsRouted(rules, slackID, successEvent, now))
+}
+
+func Test_IsMatching_QuietHoursOverMidnight_OnlyCriticalEventsMatched(t *testing.T) {
+	rule := &NotificationRule{
+		IsQuietHoursEnabled: true,
+		QuietHoursFrom:      "22:00",
+		QuietHoursTo:        "07:00",
+	}
+
+	warningEvent := &notifier_events.NotificationEvent{
+		Type:     notifier_events.EventTypeBackupAnomaly,
+		Severity: notifier_events.SeverityWarning,
+	}
+	criticalEvent := &notifier_events.NotificationEvent{
+		Type:     notifier_events.EventTypeDatabaseUnavailable,
+		Severity: notifier_events.SeverityCritical,
+	}
+
+	night := time.Date(2025, 10, 14, 23, 30, 0, 0, time.UTC)
+	morning := time.Date(2025, 10, 14, 6, 59, 0, 0, time.UTC)
+	day := time.Date(2025, 10, 14, 7, 0, 0, 0, time.UTC)
+
+	assert.False(t, rule.IsMatching(warningEvent, night))
+	assert.False(t, rule.IsMatching(warningEvent, morning))
+	assert.True(t, rule.IsMatching(warningEvent, day))
+	assert.True(t, rule.IsMatching(criticalEvent, night))
+}
+
+func Test_Validate_InvalidQuietHours_ErrorReturned(t *testing.T) {
+	rule := &NotificationRule{
+		Name:                "Night",
+		NotifierIDs:         []uuid.UUID{uuid.New()},
+		IsQuietHoursEnabled: true,
+		QuietHoursFrom:      "22:00",
+		QuietHoursTo:        "25:00",
+	}
+
+	assert.ErrorContains(t, rule.Validate(), "end of quiet hours")
+
+	rule.QuietHoursTo = "07:00"
+	assert.NoError(t, rule.Validate())
+}
@@ -0,0 +1,91 @@
+package notification_rules
+
+import (
+	"postgresus-backend/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+type NotificationRuleRepository struct{}
+
+func (r *NotificationRuleRepository) Save(rule *NotificationRule) error {
+	db := storage.GetDb()
+
+	if rule.ID == uuid.Nil {
+		rule.ID = uuid.New()
+		return db.Create(rule).Error
+	}
+
+	return db.Save(rule).Error
+}
+
+func (r *NotificationRuleRepository) FindByID(id uuid.UUID) (*NotificationRule, error) {
+	var rule NotificationRule
+
+	if err := storage.
+		GetDb().
+		Where("id = ?", id).
+		First(&rule).Error; err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+func (r *NotificationRuleRepository) FindByWorkspaceIDs(
+	workspaceIDs []uuid.UUID,
+) ([]*NotificationRule, error) {
+	var rules []*NotificationRule
+
+	if len(workspaceIDs) == 0 {
+		return rules, nil
+	}
+
+	if err := storage.
+		GetDb().
+		Where("workspace_id IN ?", workspaceIDs).
+		Order("created_at ASC").
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// FindByNotifierID returns rules which send events to the notifier
+func (r *NotificationRuleRepository) FindByNotifierID(
+	notifierID uuid.UUID,
+) ([]*NotificationRule, error) {
+	var rules []*NotificationRule
+
+	if err := storage.
+		GetDb().
+		Where("notifier_ids LIKE ?", "%"+notifierID.String()+"%").
+		Order("created_at ASC").
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// FindByDatabaseID returns rules limited to the database
+func (r *NotificationRuleRepository) FindByDatabaseID(
+	databaseID uuid.UUID,
+) ([]*NotificationRule, error) {
+	var rules []*NotificationRule
+
+	if err := storage.
+		GetDb().
+		Where("database_ids LIKE ?", "%"+databaseID.String()+"%").
+		Order("created_at ASC").
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (r *NotificationRuleRepository) Delete(rule *NotificationRule) error {
+	return storage.GetDb().Delete(rule).Error
+}
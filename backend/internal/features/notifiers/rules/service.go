@@ -0,0 +1,240 @@
+package notification_rules
+
+import (
+	"fmt"
+	"log/slog"
+	"postgresus-backend/internal/features/audit_logs"
+	"postgresus-backend/internal/features/databases"
+	"postgresus-backend/internal/features/notifiers"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	user_enums "postgresus-backend/internal/features/users/enums"
+	users_models "postgresus-backend/internal/features/users/models"
+	"postgresus-backend/internal/features/workspaces"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationRuleService manages routing rules of workspaces and
+// decides which notifiers receive events
+type NotificationRuleService struct {
+	notificationRuleRepository *NotificationRuleRepository
+	notifierService            *notifiers.NotifierService
+	databaseService            *databases.DatabaseService
+	workspaceService           *workspaces.WorkspaceService
+	auditLogService            *audit_logs.AuditLogService
+	logger                     *slog.Logger
+}
+
+// IsRouted is called before each notification is sent. If rules can't
+// be loaded the event is sent, it is better to get extra notification
+// than to miss a failed backup
+func (s *NotificationRuleService) IsRouted(
+	notifier *notifiers.Notifier,
+	event *notifier_events.NotificationEvent,
+) bool {
+	rules, err := s.notificationRuleRepository.FindByWorkspaceIDs(
+		[]uuid.UUID{notifier.WorkspaceID},
+	)
+	if err != nil {
+		s.logger.Error("Failed to get notification rules", "error", err)
+		return true
+	}
+
+	return IsRouted(rules, notifier.ID, event, time.Now().UTC())
+}
+
+func (s *NotificationRuleService) OnBeforeNotifierRemove(notifierID uuid.UUID) error {
+	rules, err := s.notificationRuleRepository.FindByNotifierID(notifierID)
+	if err != nil {
+		return err
+	}
+
+	if len(rules) > 0 {
+		return fmt.Errorf(
+			"notifier is used by notification rule \"%s\", remove it from the rule first",
+			rules[0].Name,
+		)
+	}
+
+	return nil
+}
+
+// OnBeforeDatabaseRemove removes the database from rules. Rule of the
+// removed database only is removed too, otherwise it would start to
+// match all databases
+func (s *NotificationRuleService) OnBeforeDatabaseRemove(databaseID uuid.UUID) error {
+	rules, err := s.notificationRuleRepository.FindByDatabaseID(databaseID)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		rule.DatabaseIDs = slices.DeleteFunc(rule.DatabaseIDs, func(id uuid.UUID) bool {
+			return id == databaseID
+		})
+
+		if len(rule.DatabaseIDs) == 0 {
+			err = s.notificationRuleRepository.Delete(rule)
+		} else {
+			err = s.notificationRuleRepository.Save(rule)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *NotificationRuleService) SaveNotificationRule(
+	user *users_models.User,
+	rule *NotificationRule,
+) error {
+	isNew := rule.ID == uuid.Nil
+
+	var existingRule *NotificationRule
+	if !isNew {
+		var err error
+		existingRule, err = s.notificationRuleRepository.FindByID(rule.ID)
+		if err != nil {
+			return err
+		}
+
+		rule.WorkspaceID = existingRule.WorkspaceID
+		rule.CreatedAt = existingRule.CreatedAt
+	} else {
+		workspaceID, err := s.workspaceService.ResolveWorkspaceID(user, rule.WorkspaceID)
+		if err != nil {
+			return err
+		}
+
+		rule.WorkspaceID = workspaceID
+		rule.CreatedAt = time.Now().UTC()
+	}
+
+	err := s.workspaceService.CheckAccess(user, rule.WorkspaceID, user_enums.UserRoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.validateReferences(user, rule); err != nil {
+		return err
+	}
+
+	if err := s.notificationRuleRepository.Save(rule); err != nil {
+		return err
+	}
+
+	if isNew {
+		s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+			Action:     audit_logs.AuditLogActionCreate,
+			TargetType: audit_logs.AuditLogTargetTypeNotificationRule,
+			TargetID:   rule.ID,
+			TargetName: rule.Name,
+			Message:    fmt.Sprintf("Created notification rule \"%s\"", rule.Name),
+			Diff:       audit_logs.CalculateDiff(nil, rule),
+		})
+	} else {
+		s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+			Action:     audit_logs.AuditLogActionUpdate,
+			TargetType: audit_logs.AuditLogTargetTypeNotificationRule,
+			TargetID:   rule.ID,
+			TargetName: rule.Name,
+			Message:    fmt.Sprintf("Updated notification rule \"%s\"", rule.Name),
+			Diff:       audit_logs.CalculateDiff(existingRule, rule),
+		})
+	}
+
+	return nil
+}
+
+func (s *NotificationRuleService) DeleteNotificationRule(
+	user *users_models.User,
+	id uuid.UUID,
+) error {
+	rule, err := s.notificationRuleRepository.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	err = s.workspaceService.CheckAccess(user, rule.WorkspaceID, user_enums.UserRoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	if err := s.notificationRuleRepository.Delete(rule); err != nil {
+		return err
+	}
+
+	s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+		Action:     audit_logs.AuditLogActionDelete,
+		TargetType: audit_logs.AuditLogTargetTypeNotificationRule,
+		TargetID:   rule.ID,
+		TargetName: rule.Name,
+		Message:    fmt.Sprintf("Deleted notification rule \"%s\"", rule.Name),
+		Diff:       audit_logs.CalculateDiff(rule, nil),
+	})
+
+	return nil
+}
+
+// GetNotificationRules returns rules of the workspace or, if workspace
+// is not specified, rules of all workspaces available to the user
+func (s *NotificationRuleService) GetNotificationRules(
+	user *users_models.User,
+	workspaceID *uuid.UUID,
+) ([]*NotificationRule, error) {
+	if workspaceID != nil {
+		err := s.workspaceService.CheckAccess(user, *workspaceID, user_enums.UserRoleViewer)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.notificationRuleRepository.FindByWorkspaceIDs([]uuid.UUID{*workspaceID})
+	}
+
+	workspaceIDs, err := s.workspaceService.GetWorkspaceIDs(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.notificationRuleRepository.FindByWorkspaceIDs(workspaceIDs)
+}
+
+// validateReferences checks notifiers and databases of the rule belong
+// to its workspace
+func (s *NotificationRuleService) validateReferences(
+	user *users_models.User,
+	rule *NotificationRule,
+) error {
+	for _, notifierID := range rule.NotifierIDs {
+		notifier, err := s.notifierService.GetNotifier(user, notifierID)
+		if err != nil {
+			return err
+		}
+
+		if notifier.WorkspaceID != rule.WorkspaceID {
+			return fmt.Errorf("notifier \"%s\" belongs to another workspace", notifier.Name)
+		}
+	}
+
+	for _, databaseID := range rule.DatabaseIDs {
+		database, err := s.databaseService.GetDatabase(user, databaseID)
+		if err != nil {
+			return err
+		}
+
+		if database.WorkspaceID != rule.WorkspaceID {
+			return fmt.Errorf("database \"%s\" belongs to another workspace", database.Name)
+		}
+	}
+
+	return nil
+}
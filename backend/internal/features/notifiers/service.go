@@ -2,41 +2,168 @@ package notifiers
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
+	"postgresus-backend/internal/config"
+	"postgresus-backend/internal/features/audit_logs"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	notifier_templates "postgresus-backend/internal/features/notifiers/templates"
+	user_enums "postgresus-backend/internal/features/users/enums"
 	users_models "postgresus-backend/internal/features/users/models"
+	"postgresus-backend/internal/features/workspaces"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const (
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 500
+
+	// pg_dump is verbose, so its log is cut to keep deliveries small
+	maxAttachedLogLength = 1024 * 1024
+)
+
+// NotifierService manages notifiers of workspaces. Each method
+// called on behalf of a user checks the user role in the workspace
 type NotifierService struct {
-	notifierRepository *NotifierRepository
-	logger             *slog.Logger
+	notifierRepository             *NotifierRepository
+	notificationDeliveryRepository *NotificationDeliveryRepository
+	workspaceService               *workspaces.WorkspaceService
+	auditLogService                *audit_logs.AuditLogService
+	logger                         *slog.Logger
+
+	notificationRouter      NotificationRouter
+	notifierRemoveListeners []NotifierRemoveListener
+}
+
+func (s *NotifierService) SetNotificationRouter(notificationRouter NotificationRouter) {
+	s.notificationRouter = notificationRouter
+}
+
+func (s *NotifierService) AddNotifierRemoveListener(listener NotifierRemoveListener) {
+	s.notifierRemoveListeners = append(s.notifierRemoveListeners, listener)
+}
+
+func (s *NotifierService) OnBeforeWorkspaceRemove(workspaceID uuid.UUID) error {
+	notifiers, err := s.notifierRepository.FindByWorkspaceIDs([]uuid.UUID{workspaceID})
+	if err != nil {
+		return err
+	}
+
+	if len(notifiers) > 0 {
+		return errors.New("workspace has notifiers, remove them first")
+	}
+
+	return nil
+}
+
+// OnAccountLocked notifies notifiers of workspaces available to the
+// user whose sign in is locked after too many failed attempts
+func (s *NotifierService) OnAccountLocked(
+	user *users_models.User,
+	ipAddress string,
+	lockedUntil time.Time,
+) {
+	workspaceIDs, err := s.workspaceService.GetWorkspaceIDs(user)
+	if err != nil {
+		s.logger.Error("Failed to get workspaces of locked user", "error", err)
+		return
+	}
+
+	notifiers, err := s.notifierRepository.FindByWorkspaceIDs(workspaceIDs)
+	if err != nil {
+		s.logger.Error("Failed to get notifiers of locked user", "error", err)
+		return
+	}
+
+	event := &notifier_events.NotificationEvent{
+		Type:     notifier_events.EventTypeAccountLocked,
+		Severity: notifier_events.SeverityWarning,
+		Title:    fmt.Sprintf("🔒 Sign in of %s is locked", user.Email),
+		Message: fmt.Sprintf(
+			"Sign in of %s is locked until %s UTC after too many failed attempts. "+
+				"Last attempt was made from %s",
+			user.Email,
+			lockedUntil.Format("2006-01-02 15:04:05"),
+			ipAddress,
+		),
+		CreatedAt: time.Now().UTC(),
+	}
+
+	for _, notifier := range notifiers {
+		s.SendNotification(notifier, event)
+	}
 }
 
 func (s *NotifierService) SaveNotifier(
 	user *users_models.User,
 	notifier *Notifier,
 ) error {
-	if notifier.ID != uuid.Nil {
-		existingNotifier, err := s.notifierRepository.FindByID(notifier.ID)
+	isNew := notifier.ID == uuid.Nil
+
+	var existingNotifier *Notifier
+	if !isNew {
+		var err error
+		existingNotifier, err = s.notifierRepository.FindByID(notifier.ID)
 		if err != nil {
 			return err
 		}
 
-		if existingNotifier.UserID != user.ID {
-			return errors.New("you have not access to this notifier")
-		}
-
 		notifier.UserID = existingNotifier.UserID
+		notifier.WorkspaceID = existingNotifier.WorkspaceID
+		notifier.LastDigestAt = existingNotifier.LastDigestAt
 	} else {
+		workspaceID, err := s.workspaceService.ResolveWorkspaceID(user, notifier.WorkspaceID)
+		if err != nil {
+			return err
+		}
+
 		notifier.UserID = user.ID
+		notifier.WorkspaceID = workspaceID
+		notifier.LastDigestAt = nil
+	}
+
+	if notifier.DigestFrequency == "" {
+		notifier.DigestFrequency = DigestFrequencyNone
 	}
 
-	_, err := s.notifierRepository.Save(notifier)
+	err := s.workspaceService.CheckAccess(user, notifier.WorkspaceID, user_enums.UserRoleAdmin)
 	if err != nil {
 		return err
 	}
 
+	notifier.FillSensitiveData(existingNotifier)
+
+	if err := notifier.Validate(); err != nil {
+		return err
+	}
+
+	_, err = s.notifierRepository.Save(notifier)
+	if err != nil {
+		return err
+	}
+
+	if isNew {
+		s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+			Action:     audit_logs.AuditLogActionCreate,
+			TargetType: audit_logs.AuditLogTargetTypeNotifier,
+			TargetID:   notifier.ID,
+			TargetName: notifier.Name,
+			Message:    fmt.Sprintf("Created notifier \"%s\"", notifier.Name),
+			Diff:       audit_logs.CalculateDiff(nil, notifier),
+		})
+	} else {
+		s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+			Action:     audit_logs.AuditLogActionUpdate,
+			TargetType: audit_logs.AuditLogTargetTypeNotifier,
+			TargetID:   notifier.ID,
+			TargetName: notifier.Name,
+			Message:    fmt.Sprintf("Updated notifier \"%s\"", notifier.Name),
+			Diff:       audit_logs.CalculateDiff(existingNotifier, notifier),
+		})
+	}
+
 	return nil
 }
 
@@ -44,54 +171,92 @@ func (s *NotifierService) DeleteNotifier(
 	user *users_models.User,
 	notifierID uuid.UUID,
 ) error {
-	notifier, err := s.notifierRepository.FindByID(notifierID)
+	notifier, err := s.GetNotifierWithRole(user, notifierID, user_enums.UserRoleAdmin)
 	if err != nil {
 		return err
 	}
 
-	if notifier.UserID != user.ID {
-		return errors.New("you have not access to this notifier")
+	for _, listener := range s.notifierRemoveListeners {
+		if err := listener.OnBeforeNotifierRemove(notifier.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.notifierRepository.Delete(notifier); err != nil {
+		return err
 	}
 
-	return s.notifierRepository.Delete(notifier)
+	s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+		Action:     audit_logs.AuditLogActionDelete,
+		TargetType: audit_logs.AuditLogTargetTypeNotifier,
+		TargetID:   notifier.ID,
+		TargetName: notifier.Name,
+		Message:    fmt.Sprintf("Deleted notifier \"%s\"", notifier.Name),
+		Diff:       audit_logs.CalculateDiff(notifier, nil),
+	})
+
+	return nil
 }
 
 func (s *NotifierService) GetNotifier(
 	user *users_models.User,
 	id uuid.UUID,
+) (*Notifier, error) {
+	return s.GetNotifierWithRole(user, id, user_enums.UserRoleViewer)
+}
+
+// GetNotifierWithRole returns the notifier if the user has at least
+// the given role in the workspace of the notifier
+func (s *NotifierService) GetNotifierWithRole(
+	user *users_models.User,
+	id uuid.UUID,
+	role user_enums.UserRole,
 ) (*Notifier, error) {
 	notifier, err := s.notifierRepository.FindByID(id)
 	if err != nil {
 		return nil, err
 	}
 
-	if notifier.UserID != user.ID {
-		return nil, errors.New("you have not access to this notifier")
+	if err := s.workspaceService.CheckAccess(user, notifier.WorkspaceID, role); err != nil {
+		return nil, err
 	}
 
 	return notifier, nil
 }
 
+// GetNotifiers returns notifiers of the workspace or, if workspace
+// is not specified, notifiers of all workspaces available to the user
 func (s *NotifierService) GetNotifiers(
 	user *users_models.User,
+	workspaceID *uuid.UUID,
 ) ([]*Notifier, error) {
-	return s.notifierRepository.FindByUserID(user.ID)
+	if workspaceID != nil {
+		err := s.workspaceService.CheckAccess(user, *workspaceID, user_enums.UserRoleViewer)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.notifierRepository.FindByWorkspaceIDs([]uuid.UUID{*workspaceID})
+	}
+
+	workspaceIDs, err := s.workspaceService.GetWorkspaceIDs(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.notifierRepository.FindByWorkspaceIDs(workspaceIDs)
 }
 
 func (s *NotifierService) SendTestNotification(
 	user *users_models.User,
 	notifierID uuid.UUID,
 ) error {
-	notifier, err := s.notifierRepository.FindByID(notifierID)
+	notifier, err := s.GetNotifierWithRole(user, notifierID, user_enums.UserRoleOperator)
 	if err != nil {
 		return err
 	}
 
-	if notifier.UserID != user.ID {
-		return errors.New("you have not access to this notifier")
-	}
-
-	err = notifier.Send(s.logger, "Test message", "This is a test message")
+	err = notifier.Send(s.logger, s.withLinks(notifier_events.NewTestEvent()))
 	if err != nil {
 		return err
 	}
@@ -105,20 +270,75 @@ func (s *NotifierService) SendTestNotification(
 }
 
 func (s *NotifierService) SendTestNotificationToNotifier(
+	user *users_models.User,
 	notifier *Notifier,
 ) error {
-	return notifier.Send(s.logger, "Test message", "This is a test message")
+	workspaceID, err := s.workspaceService.ResolveWorkspaceID(user, notifier.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	err = s.workspaceService.CheckAccess(user, workspaceID, user_enums.UserRoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	// tokens of the saved notifier are not returned to the client, so
+	// the test of edited notifier uses them if they are not changed
+	if notifier.ID != uuid.Nil {
+		existingNotifier, err := s.GetNotifierWithRole(user, notifier.ID, user_enums.UserRoleAdmin)
+		if err != nil {
+			return err
+		}
+
+		notifier.FillSensitiveData(existingNotifier)
+	}
+
+	return notifier.Send(s.logger, s.withLinks(notifier_events.NewTestEvent()))
+}
+
+// PreviewTemplate renders the template against the sample event, so
+// the user sees the notification before the template is saved
+func (s *NotifierService) PreviewTemplate(
+	request *PreviewTemplateRequest,
+) (*PreviewTemplateResponse, error) {
+	template := &notifier_templates.NotifierTemplate{
+		EventType:     request.EventType,
+		TitleTemplate: request.TitleTemplate,
+		BodyTemplate:  request.BodyTemplate,
+	}
+
+	if err := template.Validate(); err != nil {
+		return nil, err
+	}
+
+	sampleEvent := notifier_templates.NewSampleEvent(request.EventType)
+
+	renderedEvent, err := template.Apply(sampleEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PreviewTemplateResponse{
+		Title:     renderedEvent.Title,
+		Body:      renderedEvent.Message,
+		Variables: notifier_templates.NewTemplateData(sampleEvent),
+	}, nil
 }
 
+// SendNotification puts the notification into the outbox, it is sent
+// by the background worker which retries failed attempts
 func (s *NotifierService) SendNotification(
 	notifier *Notifier,
-	title string,
-	message string,
+	event *notifier_events.NotificationEvent,
 ) {
-	// Truncate message to 2000 characters if it's too long
-	messageRunes := []rune(message)
-	if len(messageRunes) > 2000 {
-		message = string(messageRunes[:2000])
+	// the event is shared by notifiers of the database, so it is
+	// copied before long texts are truncated to 2000 characters
+	event = s.withLinks(event)
+	event.Message = truncateText(event.Message, 2000)
+	if event.Error != nil {
+		errorMessage := truncateText(*event.Error, 2000)
+		event.Error = &errorMessage
 	}
 
 	notifiedFromDb, err := s.notifierRepository.FindByID(notifier.ID)
@@ -126,20 +346,197 @@ func (s *NotifierService) SendNotification(
 		return
 	}
 
-	err = notifiedFromDb.Send(s.logger, title, message)
+	if s.notificationRouter != nil &&
+		!s.isResolvingIncident(notifiedFromDb, event) &&
+		!s.notificationRouter.IsRouted(notifiedFromDb, event) {
+		s.logger.Debug(
+			"Notification is not routed to notifier",
+			"notifierId", notifiedFromDb.ID,
+			"eventType", event.Type,
+		)
+		return
+	}
+
+	// the log is kept only for emails which attach it, other notifiers
+	// do not need it in the history of deliveries
+	if notifiedFromDb.IsBackupLogAttached() && event.Log != nil {
+		log := truncateTextStart(*event.Log, maxAttachedLogLength)
+		event.Log = &log
+	} else {
+		event.Log = nil
+	}
+
+	now := time.Now().UTC()
+	delivery := &NotificationDelivery{
+		NotifierID:    notifiedFromDb.ID,
+		EventType:     event.Type,
+		Status:        NotificationDeliveryStatusPending,
+		Event:         event,
+		Attempts:      []NotificationDeliveryAttempt{},
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+
+	if err := s.notificationDeliveryRepository.Save(delivery); err != nil {
+		s.logger.Error("Failed to save notification delivery", "error", err)
+	}
+}
+
+// ResolveIncident sends the event which resolves the incident to the
+// incident tool even if the user is not subscribed to such events,
+// otherwise incidents opened by failures would never be resolved
+func (s *NotifierService) ResolveIncident(
+	notifier *Notifier,
+	event *notifier_events.NotificationEvent,
+) {
+	if !notifier.IsIncidentNotifier() || !s.isResolvingIncident(notifier, event) {
+		return
+	}
+
+	s.SendNotification(notifier, event)
+}
+
+// isResolvingIncident returns true if the event resolves the incident
+// opened in the incident tool by the notifier. Such event bypasses
+// the rules, otherwise severity and quiet hours filters would drop
+// success events and the incident would stay open forever
+func (s *NotifierService) isResolvingIncident(
+	notifier *Notifier,
+	event *notifier_events.NotificationEvent,
+) bool {
+	if !notifier.IsIncidentNotifier() ||
+		event.GetIncidentAction() != notifier_events.IncidentActionResolve {
+		return false
+	}
+
+	lastDelivery, err := s.notificationDeliveryRepository.FindLastByIncidentKey(
+		notifier.ID,
+		event.GetIncidentKey(),
+	)
 	if err != nil {
-		errMsg := err.Error()
-		notifiedFromDb.LastSendError = &errMsg
+		s.logger.Error("Failed to get last delivery of incident", "error", err)
+		return true
+	}
 
-		_, err = s.notifierRepository.Save(notifiedFromDb)
-		if err != nil {
-			s.logger.Error("Failed to save notifier", "error", err)
+	return lastDelivery != nil && lastDelivery.IsIncidentTrigger()
+}
+
+// GetNotifiersWithDigest returns notifiers with enabled digest for the
+// background service, access is not checked
+func (s *NotifierService) GetNotifiersWithDigest() ([]*Notifier, error) {
+	return s.notifierRepository.FindWithDigest()
+}
+
+func (s *NotifierService) SetLastDigestAt(notifierID uuid.UUID, lastDigestAt time.Time) error {
+	return s.notifierRepository.UpdateLastDigestAt(notifierID, lastDigestAt)
+}
+
+// GetDeliveries returns history of notifications of the notifier,
+// the newest first
+func (s *NotifierService) GetDeliveries(
+	user *users_models.User,
+	notifierID uuid.UUID,
+	limit int,
+	offset int,
+) (*GetNotificationDeliveriesResponse, error) {
+	if _, err := s.GetNotifier(user, notifierID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultDeliveriesLimit
+	}
+
+	limit = min(limit, maxDeliveriesLimit)
+	offset = max(offset, 0)
+
+	deliveries, err := s.notificationDeliveryRepository.FindByNotifierID(notifierID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.notificationDeliveryRepository.CountByNotifierID(notifierID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GetNotificationDeliveriesResponse{
+		Deliveries: deliveries,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+	}, nil
+}
+
+// deliver makes one attempt to send the notification. The delivery is
+// marked as sending first, so the notification interrupted by restart
+// is sent again rather than lost
+func (s *NotifierService) deliver(delivery *NotificationDelivery) error {
+	delivery.Status = NotificationDeliveryStatusSending
+	if err := s.notificationDeliveryRepository.Save(delivery); err != nil {
+		return err
+	}
+
+	startedAt := time.Now().UTC()
+
+	notifier, err := s.notifierRepository.FindByID(delivery.NotifierID)
+	if err == nil {
+		err = notifier.Send(s.logger, delivery.Event)
+
+		updateErr := s.notifierRepository.UpdateLastSendError(notifier.ID, notifier.LastSendError)
+		if updateErr != nil {
+			s.logger.Error("Failed to save notifier", "error", updateErr)
 		}
 	}
 
-	notifiedFromDb.LastSendError = nil
-	_, err = s.notifierRepository.Save(notifiedFromDb)
 	if err != nil {
-		s.logger.Error("Failed to save notifier", "error", err)
+		s.logger.Warn(
+			"Failed to send notification",
+			"notifierId", delivery.NotifierID,
+			"deliveryId", delivery.ID,
+			"attempt", len(delivery.Attempts)+1,
+			"error", err,
+		)
 	}
+
+	delivery.RecordAttempt(startedAt, time.Since(startedAt), err)
+
+	return s.notificationDeliveryRepository.Save(delivery)
+}
+
+// withLinks returns the copy of the event with the link to Postgresus
+// if its URL is configured
+func (s *NotifierService) withLinks(
+	event *notifier_events.NotificationEvent,
+) *notifier_events.NotificationEvent {
+	eventCopy := *event
+
+	appURL := config.GetEnv().AppURL
+	if appURL != "" && len(eventCopy.Links) == 0 {
+		eventCopy.Links = []notifier_events.EventLink{
+			{Title: "Open Postgresus", URL: appURL},
+		}
+	}
+
+	return &eventCopy
+}
+
+func truncateText(text string, maxLength int) string {
+	runes := []rune(text)
+	if len(runes) > maxLength {
+		return string(runes[:maxLength])
+	}
+
+	return text
+}
+
+// truncateTextStart keeps the end of the text, errors are usually at
+// the end of logs
+func truncateTextStart(text string, maxLength int) string {
+	runes := []rune(text)
+	if len(runes) > maxLength {
+		return string(runes[len(runes)-maxLength:])
+	}
+
+	return text
 }
@@ -0,0 +1,111 @@
+package notifiers
+
+import (
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	pagerduty_notifier "postgresus-backend/internal/features/notifiers/models/pagerduty"
+	"postgresus-backend/internal/features/users"
+	"postgresus-backend/internal/features/workspaces"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type rejectingNotificationRouter struct{}
+
+func (r *rejectingNotificationRouter) IsRouted(
+	notifier *Notifier,
+	event *notifier_events.NotificationEvent,
+) bool {
+	return false
+}
+
+func Test_SendNotification_WhenIncidentTriggered_ResolveBypassesRules(t *testing.T) {
+	// setup data
+	user := users.GetTestUser()
+	notifier := createTestPagerDutyNotifier(t, user.UserID)
+
+	notifierService.SetNotificationRouter(&rejectingNotificationRouter{})
+	defer notifierService.SetNotificationRouter(nil)
+
+	databaseID := uuid.New()
+	trigger := &NotificationDelivery{
+		NotifierID: notifier.ID,
+		EventType:  notifier_events.EventTypeBackupFailed,
+		Status:     NotificationDeliveryStatusDelivered,
+		Event: &notifier_events.NotificationEvent{
+			Type:       notifier_events.EventTypeBackupFailed,
+			Severity:   notifier_events.SeverityCritical,
+			DatabaseID: &databaseID,
+		},
+		Attempts:      []NotificationDeliveryAttempt{},
+		NextAttemptAt: time.Now().UTC(),
+		CreatedAt:     time.Now().UTC().Add(-time.Minute),
+	}
+	require.NoError(t, notificationDeliveryRepository.Save(trigger))
+
+	// act
+	notifierService.SendNotification(notifier, &notifier_events.NotificationEvent{
+		Type:       notifier_events.EventTypeBackupSuccess,
+		Severity:   notifier_events.SeveritySuccess,
+		DatabaseID: &databaseID,
+	})
+	notifierService.SendNotification(notifier, &notifier_events.NotificationEvent{
+		Type:       notifier_events.EventTypeBackupSuccess,
+		Severity:   notifier_events.SeveritySuccess,
+		DatabaseID: &databaseID,
+	})
+
+	// assertions
+	deliveries, err := notificationDeliveryRepository.FindByNotifierID(notifier.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	assert.Equal(t, notifier_events.EventTypeBackupSuccess, deliveries[0].EventType)
+	assert.Equal(t, trigger.IncidentKey, deliveries[0].IncidentKey)
+
+	// cleanup
+	RemoveTestNotifier(notifier)
+}
+
+func Test_SendNotification_WhenNoIncidentTriggered_ResolveFilteredByRules(t *testing.T) {
+	// setup data
+	user := users.GetTestUser()
+	notifier := createTestPagerDutyNotifier(t, user.UserID)
+
+	notifierService.SetNotificationRouter(&rejectingNotificationRouter{})
+	defer notifierService.SetNotificationRouter(nil)
+
+	databaseID := uuid.New()
+
+	// act
+	notifierService.SendNotification(notifier, &notifier_events.NotificationEvent{
+		Type:       notifier_events.EventTypeDatabaseAvailable,
+		Severity:   notifier_events.SeveritySuccess,
+		DatabaseID: &databaseID,
+	})
+
+	// assertions
+	deliveries, err := notificationDeliveryRepository.FindByNotifierID(notifier.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+
+	// cleanup
+	RemoveTestNotifier(notifier)
+}
+
+func createTestPagerDutyNotifier(t *testing.T, userID uuid.UUID) *Notifier {
+	notifier, err := notifierRepository.Save(&Notifier{
+		UserID:       userID,
+		WorkspaceID:  workspaces.GetTestWorkspace().ID,
+		Name:         "test " + uuid.New().String(),
+		NotifierType: NotifierTypePagerDuty,
+		PagerDutyNotifier: &pagerduty_notifier.PagerDutyNotifier{
+			RoutingKey: "test-routing-key",
+		},
+	})
+	require.NoError(t, err)
+
+	return notifier
+}
@@ -0,0 +1,109 @@
+package notifier_templates
+
+import (
+	"errors"
+	"fmt"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// NotifierTemplate overrides the title and the body of notifications
+// of one event type. Empty template keeps the default wording
+type NotifierTemplate struct {
+	ID            uuid.UUID                 `json:"id"            gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
+	NotifierID    uuid.UUID                 `json:"notifierId"    gorm:"column:notifier_id;not null;type:uuid"`
+	EventType     notifier_events.EventType `json:"eventType"     gorm:"column:event_type;not null;type:varchar(50)"`
+	TitleTemplate string                    `json:"titleTemplate" gorm:"column:title_template;not null;type:text"`
+	BodyTemplate  string                    `json:"bodyTemplate"  gorm:"column:body_template;not null;type:text"`
+}
+
+func (t *NotifierTemplate) TableName() string {
+	return "notifier_templates"
+}
+
+func (t *NotifierTemplate) Validate() error {
+	if !slices.Contains(notifier_events.GetEventTypes(), t.EventType) {
+		return fmt.Errorf("unknown event type of template: %s", t.EventType)
+	}
+
+	if t.TitleTemplate == "" && t.BodyTemplate == "" {
+		return fmt.Errorf("template of %s event is empty", t.EventType)
+	}
+
+	// unknown variables are found only on execution, so the template
+	// is rendered against the sample event
+	if _, err := t.Apply(NewSampleEvent(t.EventType)); err != nil {
+		return fmt.Errorf("invalid template of %s event: %w", t.EventType, err)
+	}
+
+	return nil
+}
+
+// Apply returns the copy of the event with title and message rendered
+// by the template
+func (t *NotifierTemplate) Apply(
+	event *notifier_events.NotificationEvent,
+) (*notifier_events.NotificationEvent, error) {
+	eventCopy := *event
+	data := NewTemplateData(event)
+
+	if t.TitleTemplate != "" {
+		title, err := Render("title", t.TitleTemplate, data)
+		if err != nil {
+			return nil, err
+		}
+
+		eventCopy.Title = title
+	}
+
+	if t.BodyTemplate != "" {
+		body, err := Render("body", t.BodyTemplate, data)
+		if err != nil {
+			return nil, err
+		}
+
+		eventCopy.Message = body
+	}
+
+	return &eventCopy, nil
+}
+
+// ValidateTemplates checks templates of the notifier, each event type
+// can be overridden only once
+func ValidateTemplates(templates []*NotifierTemplate) error {
+	eventTypes := make(map[notifier_events.EventType]bool)
+
+	for _, template := range templates {
+		if template == nil {
+			return errors.New("template is required")
+		}
+
+		if eventTypes[template.EventType] {
+			return fmt.Errorf("template of %s event is duplicated", template.EventType)
+		}
+		eventTypes[template.EventType] = true
+
+		if err := template.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FindTemplate returns the template of the event type or nil if the
+// default wording is used
+func FindTemplate(
+	templates []*NotifierTemplate,
+	eventType notifier_events.EventType,
+) *NotifierTemplate {
+	for _, template := range templates {
+		if template.EventType == eventType {
+			return template
+		}
+	}
+
+	return nil
+}
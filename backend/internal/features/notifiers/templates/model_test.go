@@ -0,0 +1,116 @@
+package notifier_templates
+
+import (
+	"strings"
+	"testing"
+
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Apply_BackupSuccessTemplate_TitleAndBodyRendered(t *testing.T) {
+	template := &NotifierTemplate{
+		EventType:     notifier_events.EventTypeBackupSuccess,
+		TitleTemplate: "[{{.Severity}}] {{.DatabaseName}} backed up",
+		BodyTemplate:  "Size: {{.Size}}, took {{.Duration}}{{if .Error}}, {{.Error}}{{end}}",
+	}
+
+	event, err := template.Apply(NewSampleEvent(notifier_events.EventTypeBackupSuccess))
+	require.NoError(t, err)
+
+	assert.Equal(t, "[SUCCESS] main backed up", event.Title)
+	assert.Equal(t, "Size: 1.70 GB, took 2m 17s", event.Message)
+}
+
+func Test_Apply_OnlyTitleTemplate_DefaultBodyKept(t *testing.T) {
+	template := &NotifierTemplate{
+		EventType:     notifier_events.EventTypeBackupFailed,
+		TitleTemplate: "Backup of {{.DatabaseName}} failed",
+	}
+
+	sampleEvent := NewSampleEvent(notifier_events.EventTypeBackupFailed)
+
+	event, err := template.Apply(sampleEvent)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Backup of main failed", event.Title)
+	assert.Equal(t, sampleEvent.Message, event.Message)
+	assert.Equal(t, sampleEvent.Error, event.Error)
+}
+
+func Test_Validate_UnknownVariable_ErrorReturned(t *testing.T) {
+	template := &NotifierTemplate{
+		EventType:    notifier_events.EventTypeBackupSuccess,
+		BodyTemplate: "{{.Database}}",
+	}
+
+	err := template.Validate()
+	assert.ErrorContains(t, err, "can't evaluate field Database")
+}
+
+func Test_Validate_RenderedTextTooLong_ErrorReturned(t *testing.T) {
+	template := &NotifierTemplate{
+		EventType:    notifier_events.EventTypeBackupSuccess,
+		BodyTemplate: "{{range 1000}}" + strings.Repeat("a", 10) + "{{end}}",
+	}
+
+	err := template.Validate()
+	assert.ErrorContains(t, err, "rendered text is longer than")
+}
+
+func Test_ValidateTemplates_EventTypeDuplicated_ErrorReturned(t *testing.T) {
+	templates := []*NotifierTemplate{
+		{EventType: notifier_events.EventTypeBackupFailed, TitleTemplate: "first"},
+		{EventType: notifier_events.EventTypeBackupFailed, TitleTemplate: "second"},
+	}
+
+	err := ValidateTemplates(templates)
+	assert.ErrorContains(t, err, "duplicated")
+}
+
+func Test_Validate_HugeRange_ErrorReturned(t *testing.T) {
+	testCases := []struct {
+		name         string
+		bodyTemplate string
+		errorMessage string
+	}{
+		{"huge range", "{{range 100000000000}}{{end}}", "ranges can make up to"},
+		{
+			"nested ranges",
+			"{{range 100}}{{range 100}}{{end}}{{end}}",
+			"ranges can make up to",
+		},
+		{"range over variable", "{{range $.DurationMs}}{{end}}", "range can be used only"},
+		{
+			"recursive template",
+			`{{define "loop"}}{{template "loop" .}}{{end}}{{template "loop" .}}`,
+			"cannot define other templates",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			template := &NotifierTemplate{
+				EventType:    notifier_events.EventTypeBackupSuccess,
+				BodyTemplate: testCase.bodyTemplate,
+			}
+
+			err := template.Validate()
+			assert.ErrorContains(t, err, testCase.errorMessage)
+		})
+	}
+}
+
+func Test_Validate_SmallNestedRanges_TemplateRendered(t *testing.T) {
+	template := &NotifierTemplate{
+		EventType:    notifier_events.EventTypeBackupSuccess,
+		BodyTemplate: "{{range 3}}{{range 2}}-{{end}}{{end}}",
+	}
+
+	event, err := template.Apply(NewSampleEvent(notifier_events.EventTypeBackupSuccess))
+	require.NoError(t, err)
+
+	assert.Equal(t, "------", event.Message)
+}
@@ -0,0 +1,287 @@
+package notifier_templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	notifier_events "postgresus-backend/internal/features/notifiers/events"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxTemplateLength = 4000
+	maxRenderedLength = 8000
+	// loops without output are not stopped by the length limit, so
+	// iterations of nested ranges are limited before execution
+	maxRangeIterations = 1000
+)
+
+// TemplateData holds variables available in templates, e.g.
+// {{.DatabaseName}} or {{if .Error}}{{.Error}}{{end}}. Optional values
+// are empty strings or zeros if the event has not them
+type TemplateData struct {
+	EventType    string
+	Severity     string
+	Title        string
+	Message      string
+	DatabaseID   string
+	DatabaseName string
+	BackupID     string
+	RestoreID    string
+	Size         string
+	SizeMb       float64
+	Duration     string
+	DurationMs   int64
+	Error        string
+	Link         string
+	CreatedAt    time.Time
+}
+
+// NewTemplateData converts the event to variables of templates. Title
+// and Message are the default wording of the event
+func NewTemplateData(event *notifier_events.NotificationEvent) *TemplateData {
+	data := &TemplateData{
+		EventType:    string(event.Type),
+		Severity:     string(event.Severity),
+		Title:        event.Title,
+		Message:      event.Message,
+		DatabaseName: event.DatabaseName,
+		CreatedAt:    event.CreatedAt,
+	}
+
+	if event.DatabaseID != nil {
+		data.DatabaseID = event.DatabaseID.String()
+	}
+
+	if event.BackupID != nil {
+		data.BackupID = event.BackupID.String()
+	}
+
+	if event.RestoreID != nil {
+		data.RestoreID = event.RestoreID.String()
+	}
+
+	if event.SizeMb != nil {
+		data.SizeMb = *event.SizeMb
+		data.Size = notifier_events.FormatSize(*event.SizeMb)
+	}
+
+	if event.DurationMs != nil {
+		data.DurationMs = *event.DurationMs
+		data.Duration = notifier_events.FormatDuration(*event.DurationMs)
+	}
+
+	if event.Error != nil {
+		data.Error = *event.Error
+	}
+
+	if len(event.Links) > 0 {
+		data.Link = event.Links[0].URL
+	}
+
+	return data
+}
+
+// NewSampleEvent returns the event of a backup of sample database to
+// validate and preview templates
+func NewSampleEvent(eventType notifier_events.EventType) *notifier_events.NotificationEvent {
+	databaseID := uuid.MustParse("6f1c2b7e-3a0d-4a5e-9b8f-1d2e3f4a5b6c")
+	backupID := uuid.MustParse("0a9b8c7d-6e5f-4a3b-8c1d-2e3f4a5b6c7d")
+	sizeMb := 1740.8
+	durationMs := int64(137_000)
+
+	event := &notifier_events.NotificationEvent{
+		Type:         eventType,
+		Severity:     notifier_events.SeverityInfo,
+		DatabaseID:   &databaseID,
+		DatabaseName: "main",
+		BackupID:     &backupID,
+		SizeMb:       &sizeMb,
+		DurationMs:   &durationMs,
+		Links: []notifier_events.EventLink{
+			{Title: "Open Postgresus", URL: "https://postgresus.example.com"},
+		},
+		CreatedAt: time.Now().UTC(),
+	}
+
+	switch eventType {
+	case notifier_events.EventTypeBackupSuccess:
+		event.Severity = notifier_events.SeveritySuccess
+		event.Title = "✅ Backup completed for database \"main\""
+		event.Message = "Backup completed successfully"
+	case notifier_events.EventTypeBackupFailed:
+		errorMessage := "pg_dump: error: connection to server failed: Connection refused"
+		event.Severity = notifier_events.SeverityError
+		event.Title = "❌ Backup failed for database \"main\""
+		event.Error = &errorMessage
+		event.SizeMb = nil
+	case notifier_events.EventTypeBackupAnomaly:
+		event.Severity = notifier_events.SeverityWarning
+		event.Title = "⚠️ Backup anomaly for database \"main\""
+		event.Message = "Backup size is 80% smaller than the average of previous backups"
+	case notifier_events.EventTypeBackupOverdue:
+		event.Severity = notifier_events.SeverityError
+		event.Title = "⏰ Backup overdue for database \"main\""
+		event.Message = "Last successful backup was made 1d 2h ago, daily backups are expected"
+		event.SizeMb, event.DurationMs = nil, nil
+	case notifier_events.EventTypeStorageQuotaWarning:
+		event.Severity = notifier_events.SeverityWarning
+		event.Title = "⚠️ Storage quota warning for database \"main\""
+		event.Message = "Backups of the database use 90% of the storage quota"
+	case notifier_events.EventTypeDatabaseUnavailable:
+		event.Severity = notifier_events.SeverityCritical
+		event.Title = "❌ [main] DB is unavailable"
+		event.Message = "❌ [main] DB is currently unavailable"
+		event.BackupID, event.SizeMb, event.DurationMs = nil, nil, nil
+	case notifier_events.EventTypeDatabaseAvailable:
+		event.Severity = notifier_events.SeveritySuccess
+		event.Title = "✅ [main] DB is online"
+		event.Message = "✅ [main] DB is back online"
+		event.BackupID, event.SizeMb, event.DurationMs = nil, nil, nil
+	case notifier_events.EventTypeBackupDigest:
+		event.Title = "📊 Daily backup digest"
+		event.Message = "14 Oct 09:00 - 15 Oct 09:00 UTC\nDatabases: 1, need attention: 0\n\n" +
+			"✅ main: 24 ok, 40.80 GB, growth +12.40 MB, +0.7%, uptime 100.00%"
+		event.DatabaseID, event.DatabaseName = nil, ""
+		event.BackupID, event.SizeMb, event.DurationMs = nil, nil, nil
+	default:
+		sample := notifier_events.NewTestEvent()
+		event.Title = sample.Title
+		event.Message = sample.Message
+	}
+
+	return event
+}
+
+// Render executes the template with the data. Besides builtin functions
+// templates can use json to quote values inside JSON bodies, e.g.
+// {"text": {{json .Message}}}
+func Render(name, text string, data *TemplateData) (string, error) {
+	if len(text) > maxTemplateLength {
+		return "", fmt.Errorf("%s is longer than %d characters", name, maxTemplateLength)
+	}
+
+	parsed, err := template.New(name).
+		Option("missingkey=error").
+		Funcs(template.FuncMap{"json": toJSON}).
+		Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	if err := checkLoops(parsed); err != nil {
+		return "", err
+	}
+
+	// the limit stops loops of the template early instead of checking
+	// the length after the whole text is rendered
+	result := &limitedBuffer{maxLength: maxRenderedLength}
+	if err := parsed.Execute(result, data); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(result.String()), nil
+}
+
+// checkLoops rejects templates which may run for too long. Ranges are
+// allowed only over numbers, because variables have no lists, and
+// defined templates are forbidden to exclude recursion
+func checkLoops(parsed *template.Template) error {
+	if len(parsed.Templates()) > 1 {
+		return errors.New("templates cannot define other templates")
+	}
+
+	if parsed.Tree == nil {
+		return nil
+	}
+
+	return checkRanges(parsed.Tree.Root, 1)
+}
+
+func checkRanges(node parse.Node, iterations int64) error {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return nil
+		}
+
+		for _, child := range node.Nodes {
+			if err := checkRanges(child, iterations); err != nil {
+				return err
+			}
+		}
+
+	case *parse.IfNode:
+		return checkBranches(&node.BranchNode, iterations)
+
+	case *parse.WithNode:
+		return checkBranches(&node.BranchNode, iterations)
+
+	case *parse.RangeNode:
+		count, ok := getRangeCount(node.Pipe)
+		if !ok {
+			return errors.New("range can be used only with a number, e.g. {{range 3}}")
+		}
+
+		if count > maxRangeIterations || iterations*count > maxRangeIterations {
+			return fmt.Errorf("ranges can make up to %d iterations", maxRangeIterations)
+		}
+
+		if err := checkRanges(node.List, iterations*count); err != nil {
+			return err
+		}
+
+		return checkRanges(node.ElseList, iterations)
+	}
+
+	return nil
+}
+
+func checkBranches(node *parse.BranchNode, iterations int64) error {
+	if err := checkRanges(node.List, iterations); err != nil {
+		return err
+	}
+
+	return checkRanges(node.ElseList, iterations)
+}
+
+func getRangeCount(pipe *parse.PipeNode) (int64, bool) {
+	if pipe == nil || len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return 0, false
+	}
+
+	number, ok := pipe.Cmds[0].Args[0].(*parse.NumberNode)
+	if !ok || !number.IsInt {
+		return 0, false
+	}
+
+	return max(number.Int64, 0), true
+}
+
+func toJSON(value any) (string, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
+
+type limitedBuffer struct {
+	bytes.Buffer
+	maxLength int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.maxLength {
+		return 0, fmt.Errorf("rendered text is longer than %d characters", b.maxLength)
+	}
+
+	return b.Buffer.Write(p)
+}
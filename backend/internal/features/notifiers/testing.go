@@ -2,6 +2,7 @@ package notifiers
 
 import (
 	webhook_notifier "postgresus-backend/internal/features/notifiers/models/webhook"
+	"postgresus-backend/internal/features/workspaces"
 
 	"github.com/google/uuid"
 )
@@ -9,6 +10,7 @@ import (
 func CreateTestNotifier(userID uuid.UUID) *Notifier {
 	notifier := &Notifier{
 		UserID:       userID,
+		WorkspaceID:  workspaces.GetTestWorkspace().ID,
 		Name:         "test " + uuid.New().String(),
 		NotifierType: NotifierTypeWebhook,
 		WebhookNotifier: &webhook_notifier.WebhookNotifier{
@@ -53,6 +53,10 @@ func (c *RestoreController) GetRestores(ctx *gin.Context) {
 		return
 	}
 
+	for _, restore := range restores {
+		restore.HideSensitiveData()
+	}
+
 	ctx.JSON(http.StatusOK, restores)
 }
 
@@ -1,6 +1,7 @@
 package restores
 
 import (
+	"postgresus-backend/internal/features/audit_logs"
 	"postgresus-backend/internal/features/backups/backups"
 	backups_config "postgresus-backend/internal/features/backups/config"
 	"postgresus-backend/internal/features/databases"
@@ -18,6 +19,7 @@ var restoreService = &RestoreService{
 	backups_config.GetBackupConfigService(),
 	usecases.GetRestoreBackupUsecase(),
 	databases.GetDatabaseService(),
+	audit_logs.GetAuditLogService(),
 	logger.GetLogger(),
 }
 var restoreController = &RestoreController{
@@ -23,3 +23,11 @@ type Restore struct {
 	RestoreDurationMs int64     `json:"restoreDurationMs" gorm:"column:restore_duration_ms;default:0"`
 	CreatedAt         time.Time `json:"createdAt"         gorm:"column:created_at;default:now()"`
 }
+
+// HideSensitiveData removes the password of the target database
+// before the restore is returned to the client
+func (r *Restore) HideSensitiveData() {
+	if r.Postgresql != nil {
+		r.Postgresql.HideSensitiveData()
+	}
+}
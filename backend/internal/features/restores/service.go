@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"postgresus-backend/internal/features/audit_logs"
 	"postgresus-backend/internal/features/backups/backups"
 	backups_config "postgresus-backend/internal/features/backups/config"
 	"postgresus-backend/internal/features/databases"
@@ -11,6 +12,7 @@ import (
 	"postgresus-backend/internal/features/restores/models"
 	"postgresus-backend/internal/features/restores/usecases"
 	"postgresus-backend/internal/features/storages"
+	user_enums "postgresus-backend/internal/features/users/enums"
 	users_models "postgresus-backend/internal/features/users/models"
 	"postgresus-backend/internal/util/tools"
 	"time"
@@ -25,6 +27,7 @@ type RestoreService struct {
 	backupConfigService  *backups_config.BackupConfigService
 	restoreBackupUsecase *usecases.RestoreBackupUsecase
 	databaseService      *databases.DatabaseService
+	auditLogService      *audit_logs.AuditLogService
 	logger               *slog.Logger
 }
 
@@ -58,8 +61,9 @@ func (s *RestoreService) GetRestores(
 		return nil, err
 	}
 
-	if backup.Database.UserID != user.ID {
-		return nil, errors.New("user does not have access to this backup")
+	_, err = s.databaseService.GetDatabase(user, backup.DatabaseID)
+	if err != nil {
+		return nil, err
 	}
 
 	return s.restoreRepository.FindByBackupID(backupID)
@@ -75,11 +79,11 @@ func (s *RestoreService) RestoreBackupWithAuth(
 		return err
 	}
 
-	if backup.Database.UserID != user.ID {
-		return errors.New("user does not have access to this backup")
-	}
-
-	backupDatabase, err := s.databaseService.GetDatabase(user, backup.DatabaseID)
+	backupDatabase, err := s.databaseService.GetDatabaseWithRole(
+		user,
+		backup.DatabaseID,
+		user_enums.UserRoleOperator,
+	)
 	if err != nil {
 		return err
 	}
@@ -105,6 +109,18 @@ func (s *RestoreService) RestoreBackupWithAuth(
 		}
 	}()
 
+	s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+		Action:     audit_logs.AuditLogActionRestore,
+		TargetType: audit_logs.AuditLogTargetTypeBackup,
+		TargetID:   backup.ID,
+		TargetName: backupDatabase.Name,
+		Message: fmt.Sprintf(
+			"Started restore of database \"%s\" backup made at %s",
+			backupDatabase.Name,
+			backup.CreatedAt.Format(time.RFC3339),
+		),
+	})
+
 	return nil
 }
 
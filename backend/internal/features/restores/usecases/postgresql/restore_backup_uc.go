@@ -75,6 +75,11 @@ func (uc *RestorePostgresqlBackupUsecase) Execute(
 		"--no-owner",
 	}
 
+	password, err := pg.ResolvePassword()
+	if err != nil {
+		return err
+	}
+
 	return uc.restoreFromStorage(
 		tools.GetPostgresqlExecutable(
 			pg.Version,
@@ -83,7 +88,7 @@ func (uc *RestorePostgresqlBackupUsecase) Execute(
 			config.GetEnv().PostgresesInstallDir,
 		),
 		args,
-		pg.Password,
+		password,
 		backup,
 		storage,
 		pg,
@@ -0,0 +1,17 @@
+package secrets
+
+import (
+	"postgresus-backend/internal/features/audit_logs"
+	"postgresus-backend/internal/util/logger"
+)
+
+var secretRepository = &SecretRepository{}
+var secretService = &SecretService{
+	secretRepository,
+	audit_logs.GetAuditLogService(),
+	logger.GetLogger(),
+}
+
+func GetSecretService() *SecretService {
+	return secretService
+}
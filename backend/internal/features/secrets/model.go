@@ -0,0 +1,16 @@
+package secrets
+
+// EncryptedColumns are columns of the table stored via encrypted
+// serializer
+type EncryptedColumns struct {
+	TableName  string
+	PrimaryKey string
+	Columns    []string
+}
+
+// EncryptedValue is the stored value of a single encrypted column
+type EncryptedValue struct {
+	ID     string
+	Column string
+	Value  string
+}
@@ -0,0 +1,79 @@
+package secrets
+
+import (
+	"fmt"
+	db "postgresus-backend/internal/storage"
+	"postgresus-backend/internal/util/encryption"
+
+	"gorm.io/gorm"
+)
+
+type SecretRepository struct{}
+
+// GetEncryptedColumns reads columns marked by encrypted serializer
+// from the schema of the model
+func (r *SecretRepository) GetEncryptedColumns(model any) (*EncryptedColumns, error) {
+	statement := &gorm.Statement{DB: db.GetDb()}
+	if err := statement.Parse(model); err != nil {
+		return nil, fmt.Errorf("failed to parse model schema: %w", err)
+	}
+
+	if statement.Schema.PrioritizedPrimaryField == nil {
+		return nil, fmt.Errorf("table %s has no single primary key", statement.Schema.Table)
+	}
+
+	encryptedColumns := &EncryptedColumns{
+		TableName:  statement.Schema.Table,
+		PrimaryKey: statement.Schema.PrioritizedPrimaryField.DBName,
+	}
+
+	for _, field := range statement.Schema.Fields {
+		if field.TagSettings["SERIALIZER"] == encryption.SerializerName {
+			encryptedColumns.Columns = append(encryptedColumns.Columns, field.DBName)
+		}
+	}
+
+	return encryptedColumns, nil
+}
+
+// FindValues returns raw stored values, so they are not decrypted by
+// the serializer
+func (r *SecretRepository) FindValues(
+	encryptedColumns *EncryptedColumns,
+) ([]*EncryptedValue, error) {
+	values := make([]*EncryptedValue, 0)
+
+	for _, column := range encryptedColumns.Columns {
+		var columnValues []*EncryptedValue
+
+		if err := db.GetDb().
+			Table(encryptedColumns.TableName).
+			Select(fmt.Sprintf("%s::text AS id, %s AS value", encryptedColumns.PrimaryKey, column)).
+			Where(fmt.Sprintf("%s IS NOT NULL AND %s <> ''", column, column)).
+			Scan(&columnValues).Error; err != nil {
+			return nil, err
+		}
+
+		for _, columnValue := range columnValues {
+			columnValue.Column = column
+		}
+
+		values = append(values, columnValues...)
+	}
+
+	return values, nil
+}
+
+// UpdateValue replaces the stored value only if it was not changed
+// since it was read, so concurrent updates of users are not lost
+func (r *SecretRepository) UpdateValue(
+	encryptedColumns *EncryptedColumns,
+	value *EncryptedValue,
+	newValue string,
+) error {
+	return db.GetDb().
+		Table(encryptedColumns.TableName).
+		Where(fmt.Sprintf("%s = ?", encryptedColumns.PrimaryKey), value.ID).
+		Where(fmt.Sprintf("%s = ?", value.Column), value.Value).
+		Update(value.Column, newValue).Error
+}
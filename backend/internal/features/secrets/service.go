@@ -0,0 +1,178 @@
+package secrets
+
+import (
+	"fmt"
+	"log/slog"
+	"postgresus-backend/internal/features/audit_logs"
+	"postgresus-backend/internal/features/databases/databases/postgresql"
+	discord_notifier "postgresus-backend/internal/features/notifiers/models/discord"
+	"postgresus-backend/internal/features/notifiers/models/email_notifier"
+	google_chat_notifier "postgresus-backend/internal/features/notifiers/models/google_chat"
+	matrix_notifier "postgresus-backend/internal/features/notifiers/models/matrix"
+	mattermost_notifier "postgresus-backend/internal/features/notifiers/models/mattermost"
+	ntfy_notifier "postgresus-backend/internal/features/notifiers/models/ntfy"
+	opsgenie_notifier "postgresus-backend/internal/features/notifiers/models/opsgenie"
+	pagerduty_notifier "postgresus-backend/internal/features/notifiers/models/pagerduty"
+	rocket_chat_notifier "postgresus-backend/internal/features/notifiers/models/rocket_chat"
+	slack_notifier "postgresus-backend/internal/features/notifiers/models/slack"
+	teams_notifier "postgresus-backend/internal/features/notifiers/models/teams"
+	telegram_notifier "postgresus-backend/internal/features/notifiers/models/telegram"
+	webhook_notifier "postgresus-backend/internal/features/notifiers/models/webhook"
+	google_drive_storage "postgresus-backend/internal/features/storages/models/google_drive"
+	nas_storage "postgresus-backend/internal/features/storages/models/nas"
+	s3_storage "postgresus-backend/internal/features/storages/models/s3"
+	users_models "postgresus-backend/internal/features/users/models"
+	"postgresus-backend/internal/util/encryption"
+)
+
+// encryptedModels are models with fields stored via encrypted
+// serializer. New models with secrets should be added here, otherwise
+// their values are not re-encrypted on key rotation
+var encryptedModels = []any{
+	&postgresql.PostgresqlDatabase{},
+	&s3_storage.S3Storage{},
+	&nas_storage.NASStorage{},
+	&google_drive_storage.GoogleDriveStorage{},
+	&telegram_notifier.TelegramNotifier{},
+	&slack_notifier.SlackNotifier{},
+	&discord_notifier.DiscordNotifier{},
+	&teams_notifier.TeamsNotifier{},
+	&email_notifier.EmailNotifier{},
+	&webhook_notifier.WebhookNotifier{},
+	&pagerduty_notifier.PagerDutyNotifier{},
+	&opsgenie_notifier.OpsgenieNotifier{},
+	&mattermost_notifier.MattermostNotifier{},
+	&rocket_chat_notifier.RocketChatNotifier{},
+	&google_chat_notifier.GoogleChatNotifier{},
+	&matrix_notifier.MatrixNotifier{},
+	&ntfy_notifier.NtfyNotifier{},
+	&users_models.User{},
+}
+
+// SecretService maintains encrypted credentials as a whole. Single
+// values are encrypted and decrypted transparently on save and load
+type SecretService struct {
+	secretRepository *SecretRepository
+	auditLogService  *audit_logs.AuditLogService
+	logger           *slog.Logger
+}
+
+// EncryptPlainValues encrypts credentials saved before encryption was
+// introduced. Called on start, encrypted values are skipped
+func (s *SecretService) EncryptPlainValues() error {
+	encryptedCount, err := s.reencryptValues(func(value string) (bool, error) {
+		return !encryption.IsEncrypted(value), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if encryptedCount > 0 {
+		s.logger.Info("Encrypted plain text credentials", "count", encryptedCount)
+	}
+
+	return nil
+}
+
+// RotateEncryptionKey replaces the data key and re-encrypts all
+// credentials by the new one. Previous keys are deleted only if no
+// value uses them anymore, e.g. written by another running instance
+// during rotation
+func (s *SecretService) RotateEncryptionKey() error {
+	if err := encryption.RotateDataKey(); err != nil {
+		return fmt.Errorf("failed to create new encryption key: %w", err)
+	}
+
+	isReencryptionNeeded := func(value string) (bool, error) {
+		isEncryptedWithActiveKey, err := encryption.IsEncryptedWithActiveKey(value)
+		return !isEncryptedWithActiveKey, err
+	}
+
+	reencryptedCount, err := s.reencryptValues(isReencryptionNeeded)
+	if err != nil {
+		return err
+	}
+
+	remainingCount, err := s.reencryptValues(isReencryptionNeeded)
+	if err != nil {
+		return err
+	}
+
+	if remainingCount > 0 {
+		s.logger.Warn(
+			"Credentials were changed during rotation, previous keys are kept. Run rotation again",
+			"count",
+			remainingCount,
+		)
+	} else if err := encryption.DeleteInactiveDataKeys(); err != nil {
+		return fmt.Errorf("failed to delete previous encryption keys: %w", err)
+	}
+
+	s.auditLogService.WriteSystemAuditLog(&audit_logs.AuditLogEntry{
+		Action:     audit_logs.AuditLogActionRotateEncryptionKey,
+		TargetType: audit_logs.AuditLogTargetTypeSystem,
+		TargetName: "Encryption key",
+		Message: fmt.Sprintf(
+			"Rotated encryption key, re-encrypted %d credentials",
+			reencryptedCount+remainingCount,
+		),
+	})
+
+	return nil
+}
+
+// reencryptValues decrypts values matched by the predicate and saves
+// them encrypted by the active data key
+func (s *SecretService) reencryptValues(
+	isReencryptionNeeded func(value string) (bool, error),
+) (int, error) {
+	reencryptedCount := 0
+
+	for _, model := range encryptedModels {
+		encryptedColumns, err := s.secretRepository.GetEncryptedColumns(model)
+		if err != nil {
+			return reencryptedCount, err
+		}
+
+		values, err := s.secretRepository.FindValues(encryptedColumns)
+		if err != nil {
+			return reencryptedCount, err
+		}
+
+		for _, value := range values {
+			isNeeded, err := isReencryptionNeeded(value.Value)
+			if err != nil {
+				return reencryptedCount, err
+			}
+
+			if !isNeeded {
+				continue
+			}
+
+			plaintext, err := encryption.Decrypt(value.Value)
+			if err != nil {
+				return reencryptedCount, fmt.Errorf(
+					"failed to decrypt %s.%s of %s: %w",
+					encryptedColumns.TableName,
+					value.Column,
+					value.ID,
+					err,
+				)
+			}
+
+			ciphertext, err := encryption.Encrypt(plaintext)
+			if err != nil {
+				return reencryptedCount, err
+			}
+
+			err = s.secretRepository.UpdateValue(encryptedColumns, value, ciphertext)
+			if err != nil {
+				return reencryptedCount, err
+			}
+
+			reencryptedCount++
+		}
+	}
+
+	return reencryptedCount, nil
+}
@@ -47,16 +47,12 @@ func (c *StorageController) SaveStorage(ctx *gin.Context) {
 		return
 	}
 
-	if err := storage.Validate(); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
 	if err := c.storageService.SaveStorage(user, &storage); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	storage.HideSensitiveData()
 	ctx.JSON(http.StatusOK, storage)
 }
 
@@ -90,16 +86,19 @@ func (c *StorageController) GetStorage(ctx *gin.Context) {
 		return
 	}
 
+	storage.HideSensitiveData()
 	ctx.JSON(http.StatusOK, storage)
 }
 
 // GetStorages
 // @Summary Get all storages
-// @Description Get all storages for the current user
+// @Description Get storages of workspaces available to the current user
 // @Tags storages
 // @Produce json
 // @Param Authorization header string true "JWT token"
+// @Param workspace_id query string false "Workspace ID, all available workspaces if empty"
 // @Success 200 {array} Storage
+// @Failure 400
 // @Failure 401
 // @Router /storages [get]
 func (c *StorageController) GetStorages(ctx *gin.Context) {
@@ -109,12 +108,26 @@ func (c *StorageController) GetStorages(ctx *gin.Context) {
 		return
 	}
 
-	storages, err := c.storageService.GetStorages(user)
+	var workspaceID *uuid.UUID
+	if workspaceIDStr := ctx.Query("workspace_id"); workspaceIDStr != "" {
+		parsedWorkspaceID, err := uuid.Parse(workspaceIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid workspace_id"})
+			return
+		}
+		workspaceID = &parsedWorkspaceID
+	}
+
+	storages, err := c.storageService.GetStorages(user, workspaceID)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	for _, storage := range storages {
+		storage.HideSensitiveData()
+	}
+
 	ctx.JSON(http.StatusOK, storages)
 }
 
@@ -210,12 +223,7 @@ func (c *StorageController) TestStorageConnectionDirect(ctx *gin.Context) {
 	// For direct test, associate with the current user
 	storage.UserID = user.ID
 
-	if err := storage.Validate(); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	if err := c.storageService.TestStorageConnectionDirect(&storage); err != nil {
+	if err := c.storageService.TestStorageConnectionDirect(user, &storage); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -3,7 +3,10 @@ package storages
 import (
 	"net/http"
 	local_storage "postgresus-backend/internal/features/storages/models/local"
+	s3_storage "postgresus-backend/internal/features/storages/models/s3"
 	"postgresus-backend/internal/features/users"
+	user_enums "postgresus-backend/internal/features/users/enums"
+	"postgresus-backend/internal/features/workspaces"
 	test_utils "postgresus-backend/internal/util/testing"
 	"testing"
 
@@ -130,6 +133,59 @@ func Test_DeleteStorage_StorageNotReturnedViaGet(t *testing.T) {
 	assert.NotContains(t, storages, savedStorage)
 }
 
+func Test_SaveStorageWithSecret_SecretNotReturnedAndKeptOnUpdate(t *testing.T) {
+	user := users.GetTestUser()
+	router := createRouter()
+	storage := &Storage{
+		UserID:      user.UserID,
+		WorkspaceID: workspaces.GetTestWorkspace().ID,
+		Type:        StorageTypeS3,
+		Name:        "Test Storage " + uuid.New().String(),
+		S3Storage: &s3_storage.S3Storage{
+			S3Bucket:    "bucket",
+			S3Region:    "us-east-1",
+			S3AccessKey: "access-key",
+			S3SecretKey: "secret-key",
+			S3Endpoint:  "http://localhost:9000",
+		},
+	}
+
+	var savedStorage Storage
+	test_utils.MakePostRequestAndUnmarshal(
+		t, router, "/api/v1/storages", user.Token, storage, http.StatusOK, &savedStorage,
+	)
+	assert.Empty(t, savedStorage.S3Storage.S3SecretKey)
+
+	// update without secret keeps the saved one
+	savedStorage.Name = "Updated Storage " + uuid.New().String()
+	test_utils.MakePostRequestAndUnmarshal(
+		t, router, "/api/v1/storages", user.Token, savedStorage, http.StatusOK, &savedStorage,
+	)
+
+	var retrievedStorage Storage
+	test_utils.MakeGetRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/storages/"+savedStorage.ID.String(),
+		user.Token,
+		http.StatusOK,
+		&retrievedStorage,
+	)
+	assert.Empty(t, retrievedStorage.S3Storage.S3SecretKey)
+
+	storedStorage, err := GetStorageService().GetStorageByID(savedStorage.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret-key", storedStorage.S3Storage.S3SecretKey)
+
+	// secret is not kept if it would be sent to another endpoint
+	savedStorage.S3Storage.S3Endpoint = "http://attacker.example.com"
+	test_utils.MakePostRequest(
+		t, router, "/api/v1/storages", user.Token, savedStorage, http.StatusBadRequest,
+	)
+
+	RemoveTestStorage(savedStorage.ID)
+}
+
 func Test_TestDirectStorageConnection_ConnectionEstablished(t *testing.T) {
 	user := users.GetTestUser()
 	router := createRouter()
@@ -190,6 +246,102 @@ func Test_CallAllMethodsWithoutAuth_UnauthorizedErrorReturned(t *testing.T) {
 	}
 }
 
+func Test_ChangeStorageAsWorkspaceViewerOrOperator_BadRequestReturned(t *testing.T) {
+	router := createRouter()
+	workspace := workspaces.GetTestWorkspace()
+	storage := CreateTestStorage(users.GetTestUser().UserID)
+
+	for _, role := range []user_enums.UserRole{user_enums.UserRoleViewer, user_enums.UserRoleOperator} {
+		user := users.CreateTestUserWithRole(role)
+		workspaces.AddTestWorkspaceMember(workspace.ID, user.UserID, role)
+
+		test_utils.MakeRequest(t, router, test_utils.RequestOptions{
+			Method:         "POST",
+			URL:            "/api/v1/storages",
+			Body:           createNewStorage(user.UserID),
+			AuthToken:      user.Token,
+			ExpectedStatus: http.StatusBadRequest,
+		})
+
+		test_utils.MakeRequest(t, router, test_utils.RequestOptions{
+			Method:         "DELETE",
+			URL:            "/api/v1/storages/" + storage.ID.String(),
+			AuthToken:      user.Token,
+			ExpectedStatus: http.StatusBadRequest,
+		})
+
+		users.RemoveTestUser(user.UserID)
+	}
+
+	RemoveTestStorage(storage.ID)
+}
+
+func Test_GetStoragesAsWorkspaceViewer_StorageOfWorkspaceReturned(t *testing.T) {
+	admin := users.GetTestUser()
+	viewer := users.CreateTestUserWithRole(user_enums.UserRoleViewer)
+	workspaces.AddTestWorkspaceMember(
+		workspaces.GetTestWorkspace().ID,
+		viewer.UserID,
+		user_enums.UserRoleViewer,
+	)
+	router := createRouter()
+
+	var savedStorage Storage
+	test_utils.MakePostRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/storages",
+		admin.Token,
+		createNewStorage(admin.UserID),
+		http.StatusOK,
+		&savedStorage,
+	)
+
+	var storages []Storage
+	test_utils.MakeGetRequestAndUnmarshal(
+		t, router, "/api/v1/storages", viewer.Token, http.StatusOK, &storages,
+	)
+
+	assert.Contains(t, storages, savedStorage)
+
+	RemoveTestStorage(savedStorage.ID)
+	users.RemoveTestUser(viewer.UserID)
+}
+
+func Test_GetStoragesAsNotWorkspaceMember_StorageOfWorkspaceNotReturned(t *testing.T) {
+	admin := users.GetTestUser()
+	viewer := users.CreateTestUserWithRole(user_enums.UserRoleViewer)
+	router := createRouter()
+
+	var savedStorage Storage
+	test_utils.MakePostRequestAndUnmarshal(
+		t,
+		router,
+		"/api/v1/storages",
+		admin.Token,
+		createNewStorage(admin.UserID),
+		http.StatusOK,
+		&savedStorage,
+	)
+
+	var storages []Storage
+	test_utils.MakeGetRequestAndUnmarshal(
+		t, router, "/api/v1/storages", viewer.Token, http.StatusOK, &storages,
+	)
+
+	assert.NotContains(t, storages, savedStorage)
+
+	test_utils.MakeRequest(t, router, test_utils.RequestOptions{
+		Method:         "GET",
+		URL:            "/api/v1/storages/" + savedStorage.ID.String(),
+		AuthToken:      viewer.Token,
+		ExpectedStatus: http.StatusBadRequest,
+	})
+
+	RemoveTestStorage(savedStorage.ID)
+	users.RemoveTestUser(viewer.UserID)
+}
+
 func testUnauthorizedEndpoint(
 	t *testing.T,
 	router *gin.Engine,
@@ -216,6 +368,7 @@ func createRouter() *gin.Engine {
 func createNewStorage(userID uuid.UUID) *Storage {
 	return &Storage{
 		UserID:       userID,
+		WorkspaceID:  workspaces.GetTestWorkspace().ID,
 		Type:         StorageTypeLocal,
 		Name:         "Test Storage " + uuid.New().String(),
 		LocalStorage: &local_storage.LocalStorage{},
@@ -1,12 +1,16 @@
 package storages
 
 import (
+	"postgresus-backend/internal/features/audit_logs"
 	"postgresus-backend/internal/features/users"
+	"postgresus-backend/internal/features/workspaces"
 )
 
 var storageRepository = &StorageRepository{}
 var storageService = &StorageService{
 	storageRepository,
+	workspaces.GetWorkspaceService(),
+	audit_logs.GetAuditLogService(),
 }
 var storageController = &StorageController{
 	storageService,
@@ -20,3 +24,7 @@ func GetStorageService() *StorageService {
 func GetStorageController() *StorageController {
 	return storageController
 }
+
+func SetupDependencies() {
+	workspaces.GetWorkspaceService().AddWorkspaceRemoveListener(storageService)
+}
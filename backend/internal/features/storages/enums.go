@@ -8,3 +8,10 @@ const (
 	StorageTypeGoogleDrive StorageType = "GOOGLE_DRIVE"
 	StorageTypeNAS         StorageType = "NAS"
 )
+
+type StorageQuotaExceedAction string
+
+const (
+	StorageQuotaExceedActionWarn  StorageQuotaExceedAction = "WARN"
+	StorageQuotaExceedActionBlock StorageQuotaExceedAction = "BLOCK"
+)
@@ -0,0 +1,25 @@
+package storages_files
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StorageFile describes a single object kept in a storage. Backups
+// are saved under their ID, so objects with another name are not
+// backups made by Postgresus
+type StorageFile struct {
+	Name           string    `json:"name"`
+	SizeBytes      int64     `json:"sizeBytes"`
+	LastModifiedAt time.Time `json:"lastModifiedAt"`
+}
+
+func (f *StorageFile) GetBackupID() (uuid.UUID, bool) {
+	id, err := uuid.Parse(f.Name)
+	if err != nil || id.String() != f.Name {
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
@@ -3,6 +3,7 @@ package storages
 import (
 	"io"
 	"log/slog"
+	storages_files "postgresus-backend/internal/features/storages/files"
 
 	"github.com/google/uuid"
 )
@@ -12,8 +13,12 @@ type StorageFileSaver interface {
 
 	GetFile(fileID uuid.UUID) (io.ReadCloser, error)
 
+	GetFileByName(name string) (io.ReadCloser, error)
+
 	DeleteFile(fileID uuid.UUID) error
 
+	ListFiles() ([]*storages_files.StorageFile, error)
+
 	Validate() error
 
 	TestConnection() error
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"io"
 	"log/slog"
+	storages_files "postgresus-backend/internal/features/storages/files"
 	google_drive_storage "postgresus-backend/internal/features/storages/models/google_drive"
 	local_storage "postgresus-backend/internal/features/storages/models/local"
 	nas_storage "postgresus-backend/internal/features/storages/models/nas"
@@ -15,10 +16,15 @@ import (
 type Storage struct {
 	ID            uuid.UUID   `json:"id"            gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
 	UserID        uuid.UUID   `json:"userId"        gorm:"column:user_id;not null;type:uuid;index"`
+	WorkspaceID   uuid.UUID   `json:"workspaceId"   gorm:"column:workspace_id;not null;type:uuid"`
 	Type          StorageType `json:"type"          gorm:"column:type;not null;type:text"`
 	Name          string      `json:"name"          gorm:"column:name;not null;type:text"`
 	LastSaveError *string     `json:"lastSaveError" gorm:"column:last_save_error;type:text"`
 
+	// quota is not limited if empty
+	QuotaMb           *float64                 `json:"quotaMb"           gorm:"column:quota_mb"`
+	QuotaExceedAction StorageQuotaExceedAction `json:"quotaExceedAction" gorm:"column:quota_exceed_action;type:text;not null;default:'WARN'"`
+
 	// specific storage
 	LocalStorage       *local_storage.LocalStorage              `json:"localStorage"       gorm:"foreignKey:StorageID"`
 	S3Storage          *s3_storage.S3Storage                    `json:"s3Storage"          gorm:"foreignKey:StorageID"`
@@ -43,10 +49,18 @@ func (s *Storage) GetFile(fileID uuid.UUID) (io.ReadCloser, error) {
 	return s.getSpecificStorage().GetFile(fileID)
 }
 
+func (s *Storage) GetFileByName(name string) (io.ReadCloser, error) {
+	return s.getSpecificStorage().GetFileByName(name)
+}
+
 func (s *Storage) DeleteFile(fileID uuid.UUID) error {
 	return s.getSpecificStorage().DeleteFile(fileID)
 }
 
+func (s *Storage) ListFiles() ([]*storages_files.StorageFile, error) {
+	return s.getSpecificStorage().ListFiles()
+}
+
 func (s *Storage) Validate() error {
 	if s.Type == "" {
 		return errors.New("storage type is required")
@@ -56,9 +70,56 @@ func (s *Storage) Validate() error {
 		return errors.New("storage name is required")
 	}
 
+	if s.QuotaMb != nil {
+		if *s.QuotaMb <= 0 {
+			return errors.New("storage quota must be greater than 0")
+		}
+
+		if s.QuotaExceedAction != StorageQuotaExceedActionWarn &&
+			s.QuotaExceedAction != StorageQuotaExceedActionBlock {
+			return errors.New("storage quota exceed action must be WARN or BLOCK")
+		}
+	}
+
 	return s.getSpecificStorage().Validate()
 }
 
+// HideSensitiveData removes credentials before the storage is returned
+// to the client, credentials are write only
+func (s *Storage) HideSensitiveData() {
+	if s.S3Storage != nil {
+		s.S3Storage.HideSensitiveData()
+	}
+
+	if s.GoogleDriveStorage != nil {
+		s.GoogleDriveStorage.HideSensitiveData()
+	}
+
+	if s.NASStorage != nil {
+		s.NASStorage.HideSensitiveData()
+	}
+}
+
+// FillSensitiveData keeps saved credentials which the client has not
+// changed, because they are not returned by API
+func (s *Storage) FillSensitiveData(existing *Storage) {
+	if existing == nil || existing.ID != s.ID || existing.Type != s.Type {
+		return
+	}
+
+	if s.S3Storage != nil {
+		s.S3Storage.FillSensitiveData(existing.S3Storage)
+	}
+
+	if s.GoogleDriveStorage != nil {
+		s.GoogleDriveStorage.FillSensitiveData(existing.GoogleDriveStorage)
+	}
+
+	if s.NASStorage != nil {
+		s.NASStorage.FillSensitiveData(existing.NASStorage)
+	}
+}
+
 func (s *Storage) TestConnection() error {
 	return s.getSpecificStorage().TestConnection()
 }
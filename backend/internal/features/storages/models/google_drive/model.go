@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	storages_files "postgresus-backend/internal/features/storages/files"
+	"postgresus-backend/internal/util/secret_refs"
 	"strings"
 	"time"
 
@@ -18,11 +20,13 @@ import (
 	"google.golang.org/api/option"
 )
 
+var errBackupsFolderNotFound = errors.New("postgresus_backups folder not found")
+
 type GoogleDriveStorage struct {
 	StorageID    uuid.UUID `json:"storageId"    gorm:"primaryKey;type:uuid;column:storage_id"`
 	ClientID     string    `json:"clientId"     gorm:"not null;type:text;column:client_id"`
-	ClientSecret string    `json:"clientSecret" gorm:"not null;type:text;column:client_secret"`
-	TokenJSON    string    `json:"tokenJson"    gorm:"not null;type:text;column:token_json"`
+	ClientSecret string    `json:"clientSecret" gorm:"not null;type:text;column:client_secret;serializer:encrypted"`
+	TokenJSON    string    `json:"tokenJson"    gorm:"not null;type:text;column:token_json;serializer:encrypted"`
 }
 
 func (s *GoogleDriveStorage) TableName() string {
@@ -69,6 +73,10 @@ func (s *GoogleDriveStorage) SaveFile(
 }
 
 func (s *GoogleDriveStorage) GetFile(fileID uuid.UUID) (io.ReadCloser, error) {
+	return s.GetFileByName(fileID.String())
+}
+
+func (s *GoogleDriveStorage) GetFileByName(name string) (io.ReadCloser, error) {
 	var result io.ReadCloser
 	err := s.withRetryOnAuth(func(driveService *drive.Service) error {
 		folderID, err := s.findBackupsFolder(driveService)
@@ -76,7 +84,7 @@ func (s *GoogleDriveStorage) GetFile(fileID uuid.UUID) (io.ReadCloser, error) {
 			return fmt.Errorf("failed to find backups folder: %w", err)
 		}
 
-		fileIDGoogle, err := s.lookupFileID(driveService, fileID.String(), folderID)
+		fileIDGoogle, err := s.lookupFileID(driveService, name, folderID)
 		if err != nil {
 			return err
 		}
@@ -105,6 +113,51 @@ func (s *GoogleDriveStorage) DeleteFile(fileID uuid.UUID) error {
 	})
 }
 
+func (s *GoogleDriveStorage) ListFiles() ([]*storages_files.StorageFile, error) {
+	files := make([]*storages_files.StorageFile, 0)
+
+	err := s.withRetryOnAuth(func(driveService *drive.Service) error {
+		ctx := context.Background()
+		files = files[:0]
+
+		folderID, err := s.findBackupsFolder(driveService)
+		if errors.Is(err, errBackupsFolderNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to find backups folder: %w", err)
+		}
+
+		query := fmt.Sprintf("trashed = false and '%s' in parents", folderID)
+
+		err = driveService.
+			Files.
+			List().
+			Q(query).
+			Fields("nextPageToken, files(id, name, size, modifiedTime)").
+			Pages(ctx, func(p *drive.FileList) error {
+				for _, file := range p.Files {
+					modifiedAt, _ := time.Parse(time.RFC3339, file.ModifiedTime)
+
+					files = append(files, &storages_files.StorageFile{
+						Name:           file.Name,
+						SizeBytes:      file.Size,
+						LastModifiedAt: modifiedAt.UTC(),
+					})
+				}
+
+				return nil
+			})
+		if err != nil {
+			return fmt.Errorf("failed to list files in Google Drive: %w", err)
+		}
+
+		return nil
+	})
+
+	return files, err
+}
+
 func (s *GoogleDriveStorage) Validate() error {
 	switch {
 	case s.ClientID == "":
@@ -115,6 +168,10 @@ func (s *GoogleDriveStorage) Validate() error {
 		return errors.New("token JSON is required")
 	}
 
+	if err := secret_refs.Validate(s.ClientSecret); err != nil {
+		return fmt.Errorf("invalid client secret reference: %w", err)
+	}
+
 	// Also validate that the token JSON contains a refresh token
 	var token oauth2.Token
 	if err := json.Unmarshal([]byte(s.TokenJSON), &token); err != nil {
@@ -128,6 +185,34 @@ func (s *GoogleDriveStorage) Validate() error {
 	return nil
 }
 
+// HideSensitiveData removes the client secret and the token before
+// the storage is returned to the client, they are write only. The
+// token is refreshed and saved by Postgresus, so only the client
+// secret can reference external secret, such reference is kept
+func (s *GoogleDriveStorage) HideSensitiveData() {
+	if !secret_refs.IsReference(s.ClientSecret) {
+		s.ClientSecret = ""
+	}
+	s.TokenJSON = ""
+}
+
+// FillSensitiveData keeps the saved client secret and token if the
+// client has not sent new ones. They are kept only for the same OAuth
+// client
+func (s *GoogleDriveStorage) FillSensitiveData(existing *GoogleDriveStorage) {
+	if existing == nil || s.ClientID != existing.ClientID {
+		return
+	}
+
+	if s.ClientSecret == "" {
+		s.ClientSecret = existing.ClientSecret
+	}
+
+	if s.TokenJSON == "" {
+		s.TokenJSON = existing.TokenJSON
+	}
+}
+
 func (s *GoogleDriveStorage) TestConnection() error {
 	return s.withRetryOnAuth(func(driveService *drive.Service) error {
 		ctx := context.Background()
@@ -274,10 +359,15 @@ func (s *GoogleDriveStorage) refreshToken() error {
 	// Debug: Print the full token JSON structure (sensitive data masked)
 	fmt.Printf("Original token JSON structure: %s\n", maskSensitiveData(s.TokenJSON))
 
+	clientSecret, err := secret_refs.Resolve(s.ClientSecret)
+	if err != nil {
+		return fmt.Errorf("failed to resolve client secret: %w", err)
+	}
+
 	ctx := context.Background()
 	cfg := &oauth2.Config{
 		ClientID:     s.ClientID,
-		ClientSecret: s.ClientSecret,
+		ClientSecret: clientSecret,
 		Endpoint:     google.Endpoint,
 		Scopes:       []string{"https://www.googleapis.com/auth/drive.file"},
 	}
@@ -361,11 +451,16 @@ func (s *GoogleDriveStorage) getDriveService() (*drive.Service, error) {
 		return nil, fmt.Errorf("invalid token JSON: %w", err)
 	}
 
+	clientSecret, err := secret_refs.Resolve(s.ClientSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve client secret: %w", err)
+	}
+
 	ctx := context.Background()
 
 	cfg := &oauth2.Config{
 		ClientID:     s.ClientID,
-		ClientSecret: s.ClientSecret,
+		ClientSecret: clientSecret,
 		Endpoint:     google.Endpoint,
 		Scopes:       []string{"https://www.googleapis.com/auth/drive.file"},
 	}
@@ -492,7 +587,7 @@ func (s *GoogleDriveStorage) findBackupsFolder(driveService *drive.Service) (str
 	}
 
 	if len(results.Files) == 0 {
-		return "", fmt.Errorf("postgresus_backups folder not found")
+		return "", errBackupsFolderNotFound
 	}
 
 	return results.Files[0].Id, nil
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"postgresus-backend/internal/config"
+	storages_files "postgresus-backend/internal/features/storages/files"
 	files_utils "postgresus-backend/internal/util/files"
 
 	"github.com/google/uuid"
@@ -108,10 +109,18 @@ func (l *LocalStorage) SaveFile(logger *slog.Logger, fileID uuid.UUID, file io.R
 }
 
 func (l *LocalStorage) GetFile(fileID uuid.UUID) (io.ReadCloser, error) {
-	filePath := filepath.Join(config.GetEnv().DataFolder, fileID.String())
+	return l.GetFileByName(fileID.String())
+}
+
+func (l *LocalStorage) GetFileByName(name string) (io.ReadCloser, error) {
+	if name == "" || filepath.Base(name) != name || name == ".." {
+		return nil, fmt.Errorf("invalid file name: %s", name)
+	}
+
+	filePath := filepath.Join(config.GetEnv().DataFolder, name)
 
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("file not found: %s", fileID.String())
+		return nil, fmt.Errorf("file not found: %s", name)
 	}
 
 	file, err := os.Open(filePath)
@@ -136,6 +145,37 @@ func (l *LocalStorage) DeleteFile(fileID uuid.UUID) error {
 	return nil
 }
 
+func (l *LocalStorage) ListFiles() ([]*storages_files.StorageFile, error) {
+	entries, err := os.ReadDir(config.GetEnv().DataFolder)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*storages_files.StorageFile{}, nil
+		}
+
+		return nil, fmt.Errorf("failed to read backups directory: %w", err)
+	}
+
+	files := make([]*storages_files.StorageFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get file info: %w", err)
+		}
+
+		files = append(files, &storages_files.StorageFile{
+			Name:           entry.Name(),
+			SizeBytes:      info.Size(),
+			LastModifiedAt: info.ModTime().UTC(),
+		})
+	}
+
+	return files, nil
+}
+
 func (l *LocalStorage) Validate() error {
 	return nil
 }
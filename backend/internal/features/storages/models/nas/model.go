@@ -8,6 +8,8 @@ import (
 	"log/slog"
 	"net"
 	"path/filepath"
+	storages_files "postgresus-backend/internal/features/storages/files"
+	"postgresus-backend/internal/util/secret_refs"
 	"strings"
 	"time"
 
@@ -21,7 +23,7 @@ type NASStorage struct {
 	Port      int       `json:"port"      gorm:"not null;default:445;column:port"`
 	Share     string    `json:"share"     gorm:"not null;type:text;column:share"`
 	Username  string    `json:"username"  gorm:"not null;type:text;column:username"`
-	Password  string    `json:"password"  gorm:"not null;type:text;column:password"`
+	Password  string    `json:"password"  gorm:"not null;type:text;column:password;serializer:encrypted"`
 	UseSSL    bool      `json:"useSsl"    gorm:"not null;default:false;column:use_ssl"`
 	Domain    string    `json:"domain"    gorm:"type:text;column:domain"`
 	Path      string    `json:"path"      gorm:"type:text;column:path"`
@@ -132,6 +134,14 @@ func (n *NASStorage) SaveFile(logger *slog.Logger, fileID uuid.UUID, file io.Rea
 }
 
 func (n *NASStorage) GetFile(fileID uuid.UUID) (io.ReadCloser, error) {
+	return n.GetFileByName(fileID.String())
+}
+
+func (n *NASStorage) GetFileByName(name string) (io.ReadCloser, error) {
+	if name == "" || strings.ContainsAny(name, "/\\") || name == ".." {
+		return nil, fmt.Errorf("invalid file name: %s", name)
+	}
+
 	session, err := n.createSession()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create NAS session: %w", err)
@@ -143,14 +153,14 @@ func (n *NASStorage) GetFile(fileID uuid.UUID) (io.ReadCloser, error) {
 		return nil, fmt.Errorf("failed to mount share '%s': %w", n.Share, err)
 	}
 
-	filePath := n.getFilePath(fileID.String())
+	filePath := n.getFilePath(name)
 
 	// Check if file exists
 	_, err = fs.Stat(filePath)
 	if err != nil {
 		_ = fs.Umount()
 		_ = session.Logoff()
-		return nil, fmt.Errorf("file not found: %s", fileID.String())
+		return nil, fmt.Errorf("file not found: %s", name)
 	}
 
 	nasFile, err := fs.Open(filePath)
@@ -202,6 +212,49 @@ func (n *NASStorage) DeleteFile(fileID uuid.UUID) error {
 	return nil
 }
 
+func (n *NASStorage) ListFiles() ([]*storages_files.StorageFile, error) {
+	session, err := n.createSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create NAS session: %w", err)
+	}
+	defer func() {
+		_ = session.Logoff()
+	}()
+
+	fs, err := session.Mount(n.Share)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mount share '%s': %w", n.Share, err)
+	}
+	defer func() {
+		_ = fs.Umount()
+	}()
+
+	dirPath := "."
+	if n.Path != "" {
+		dirPath = strings.ReplaceAll(filepath.Clean(n.Path), "\\", "/")
+	}
+
+	entries, err := fs.ReadDir(dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory '%s': %w", dirPath, err)
+	}
+
+	files := make([]*storages_files.StorageFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		files = append(files, &storages_files.StorageFile{
+			Name:           entry.Name(),
+			SizeBytes:      entry.Size(),
+			LastModifiedAt: entry.ModTime().UTC(),
+		})
+	}
+
+	return files, nil
+}
+
 func (n *NASStorage) Validate() error {
 	if n.Host == "" {
 		return errors.New("NAS host is required")
@@ -215,6 +268,9 @@ func (n *NASStorage) Validate() error {
 	if n.Password == "" {
 		return errors.New("NAS password is required")
 	}
+	if err := secret_refs.Validate(n.Password); err != nil {
+		return fmt.Errorf("invalid NAS password reference: %w", err)
+	}
 	if n.Port <= 0 || n.Port > 65535 {
 		return errors.New("NAS port must be between 1 and 65535")
 	}
@@ -223,6 +279,29 @@ func (n *NASStorage) Validate() error {
 	return n.TestConnection()
 }
 
+// HideSensitiveData removes the password before the storage is
+// returned to the client, the password is write only. References to
+// external secrets are kept
+func (n *NASStorage) HideSensitiveData() {
+	if !secret_refs.IsReference(n.Password) {
+		n.Password = ""
+	}
+}
+
+// FillSensitiveData keeps the saved password if the client has not
+// sent a new one. The password is kept only for the same host and
+// user, otherwise changed host would reveal it
+func (n *NASStorage) FillSensitiveData(existing *NASStorage) {
+	if n.Password != "" || existing == nil {
+		return
+	}
+
+	if n.Host == existing.Host && n.Port == existing.Port && n.Username == existing.Username &&
+		n.Domain == existing.Domain {
+		n.Password = existing.Password
+	}
+}
+
 func (n *NASStorage) TestConnection() error {
 	session, err := n.createSession()
 	if err != nil {
@@ -252,6 +331,11 @@ func (n *NASStorage) TestConnection() error {
 }
 
 func (n *NASStorage) createSession() (*smb2.Session, error) {
+	password, err := secret_refs.Resolve(n.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve NAS password: %w", err)
+	}
+
 	// Create connection with timeout
 	conn, err := n.createConnection()
 	if err != nil {
@@ -262,7 +346,7 @@ func (n *NASStorage) createSession() (*smb2.Session, error) {
 	d := &smb2.Dialer{
 		Initiator: &smb2.NTLMInitiator{
 			User:     n.Username,
-			Password: n.Password,
+			Password: password,
 			Domain:   n.Domain,
 		},
 	}
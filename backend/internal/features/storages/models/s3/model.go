@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	storages_files "postgresus-backend/internal/features/storages/files"
+	"postgresus-backend/internal/util/secret_refs"
 	"strings"
 	"time"
 
@@ -19,7 +21,7 @@ type S3Storage struct {
 	S3Bucket    string    `json:"s3Bucket"    gorm:"not null;type:text;column:s3_bucket"`
 	S3Region    string    `json:"s3Region"    gorm:"not null;type:text;column:s3_region"`
 	S3AccessKey string    `json:"s3AccessKey" gorm:"not null;type:text;column:s3_access_key"`
-	S3SecretKey string    `json:"s3SecretKey" gorm:"not null;type:text;column:s3_secret_key"`
+	S3SecretKey string    `json:"s3SecretKey" gorm:"not null;type:text;column:s3_secret_key;serializer:encrypted"`
 	S3Endpoint  string    `json:"s3Endpoint"  gorm:"type:text;column:s3_endpoint"`
 }
 
@@ -50,6 +52,10 @@ func (s *S3Storage) SaveFile(logger *slog.Logger, fileID uuid.UUID, file io.Read
 }
 
 func (s *S3Storage) GetFile(fileID uuid.UUID) (io.ReadCloser, error) {
+	return s.GetFileByName(fileID.String())
+}
+
+func (s *S3Storage) GetFileByName(name string) (io.ReadCloser, error) {
 	client, err := s.getClient()
 	if err != nil {
 		return nil, err
@@ -58,7 +64,7 @@ func (s *S3Storage) GetFile(fileID uuid.UUID) (io.ReadCloser, error) {
 	object, err := client.GetObject(
 		context.TODO(),
 		s.S3Bucket,
-		fileID.String(),
+		name,
 		minio.GetObjectOptions{},
 	)
 	if err != nil {
@@ -103,6 +109,40 @@ func (s *S3Storage) DeleteFile(fileID uuid.UUID) error {
 	return nil
 }
 
+func (s *S3Storage) ListFiles() ([]*storages_files.StorageFile, error) {
+	client, err := s.getClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// backups are saved in the root of the bucket under their ID, so
+	// nested folders and objects of other applications are not listed
+	files := make([]*storages_files.StorageFile, 0)
+	for object := range client.ListObjects(
+		context.TODO(),
+		s.S3Bucket,
+		minio.ListObjectsOptions{Recursive: false},
+	) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("failed to list files in S3: %w", object.Err)
+		}
+
+		file := &storages_files.StorageFile{
+			Name:           object.Key,
+			SizeBytes:      object.Size,
+			LastModifiedAt: object.LastModified.UTC(),
+		}
+
+		if _, isBackupFile := file.GetBackupID(); !isBackupFile {
+			continue
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
 func (s *S3Storage) Validate() error {
 	if s.S3Bucket == "" {
 		return errors.New("S3 bucket is required")
@@ -113,6 +153,9 @@ func (s *S3Storage) Validate() error {
 	if s.S3SecretKey == "" {
 		return errors.New("S3 secret key is required")
 	}
+	if err := secret_refs.Validate(s.S3SecretKey); err != nil {
+		return fmt.Errorf("invalid S3 secret key reference: %w", err)
+	}
 
 	// Try to create a client to validate the configuration
 	_, err := s.getClient()
@@ -123,6 +166,28 @@ func (s *S3Storage) Validate() error {
 	return nil
 }
 
+// HideSensitiveData removes the secret key before the storage is
+// returned to the client, the secret key is write only. References to
+// external secrets are kept
+func (s *S3Storage) HideSensitiveData() {
+	if !secret_refs.IsReference(s.S3SecretKey) {
+		s.S3SecretKey = ""
+	}
+}
+
+// FillSensitiveData keeps the saved secret key if the client has not
+// sent a new one. The key is kept only for the same endpoint and
+// access key, otherwise changed endpoint would reveal it
+func (s *S3Storage) FillSensitiveData(existing *S3Storage) {
+	if s.S3SecretKey != "" || existing == nil {
+		return
+	}
+
+	if s.S3Endpoint == existing.S3Endpoint && s.S3AccessKey == existing.S3AccessKey {
+		s.S3SecretKey = existing.S3SecretKey
+	}
+}
+
 func (s *S3Storage) TestConnection() error {
 	client, err := s.getClient()
 	if err != nil {
@@ -166,9 +231,14 @@ func (s *S3Storage) getClient() (*minio.Client, error) {
 		endpoint = fmt.Sprintf("s3.%s.amazonaws.com", s.S3Region)
 	}
 
+	secretKey, err := secret_refs.Resolve(s.S3SecretKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve S3 secret key: %w", err)
+	}
+
 	// Initialize the MinIO client
 	minioClient, err := minio.New(endpoint, &minio.Options{
-		Creds:  credentials.NewStaticV4(s.S3AccessKey, s.S3SecretKey, ""),
+		Creds:  credentials.NewStaticV4(s.S3AccessKey, secretKey, ""),
 		Secure: useSSL,
 		Region: s.S3Region,
 	})
@@ -104,7 +104,29 @@ func (r *StorageRepository) FindByID(id uuid.UUID) (*Storage, error) {
 	return &s, nil
 }
 
-func (r *StorageRepository) FindByUserID(userID uuid.UUID) ([]*Storage, error) {
+func (r *StorageRepository) FindByWorkspaceIDs(workspaceIDs []uuid.UUID) ([]*Storage, error) {
+	var storages []*Storage
+
+	if len(workspaceIDs) == 0 {
+		return storages, nil
+	}
+
+	if err := db.
+		GetDb().
+		Preload("LocalStorage").
+		Preload("S3Storage").
+		Preload("GoogleDriveStorage").
+		Preload("NASStorage").
+		Where("workspace_id IN ?", workspaceIDs).
+		Order("name ASC").
+		Find(&storages).Error; err != nil {
+		return nil, err
+	}
+
+	return storages, nil
+}
+
+func (r *StorageRepository) FindAll() ([]*Storage, error) {
 	var storages []*Storage
 
 	if err := db.
@@ -113,7 +135,6 @@ func (r *StorageRepository) FindByUserID(userID uuid.UUID) ([]*Storage, error) {
 		Preload("S3Storage").
 		Preload("GoogleDriveStorage").
 		Preload("NASStorage").
-		Where("user_id = ?", userID).
 		Order("name ASC").
 		Find(&storages).Error; err != nil {
 		return nil, err
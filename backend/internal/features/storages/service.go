@@ -2,39 +2,100 @@ package storages
 
 import (
 	"errors"
+	"fmt"
+	"postgresus-backend/internal/features/audit_logs"
+	user_enums "postgresus-backend/internal/features/users/enums"
 	users_models "postgresus-backend/internal/features/users/models"
+	"postgresus-backend/internal/features/workspaces"
 
 	"github.com/google/uuid"
 )
 
+// StorageService manages storages of workspaces. Each method
+// called on behalf of a user checks the user role in the workspace
 type StorageService struct {
 	storageRepository *StorageRepository
+	workspaceService  *workspaces.WorkspaceService
+	auditLogService   *audit_logs.AuditLogService
+}
+
+func (s *StorageService) OnBeforeWorkspaceRemove(workspaceID uuid.UUID) error {
+	storages, err := s.storageRepository.FindByWorkspaceIDs([]uuid.UUID{workspaceID})
+	if err != nil {
+		return err
+	}
+
+	if len(storages) > 0 {
+		return errors.New("workspace has storages, remove them first")
+	}
+
+	return nil
 }
 
 func (s *StorageService) SaveStorage(
 	user *users_models.User,
 	storage *Storage,
 ) error {
-	if storage.ID != uuid.Nil {
-		existingStorage, err := s.storageRepository.FindByID(storage.ID)
+	isNew := storage.ID == uuid.Nil
+
+	var existingStorage *Storage
+	if !isNew {
+		var err error
+		existingStorage, err = s.storageRepository.FindByID(storage.ID)
 		if err != nil {
 			return err
 		}
 
-		if existingStorage.UserID != user.ID {
-			return errors.New("you have not access to this storage")
-		}
-
+		// storage cannot be moved to another workspace, because
+		// databases of the workspace may keep backups in it
 		storage.UserID = existingStorage.UserID
+		storage.WorkspaceID = existingStorage.WorkspaceID
 	} else {
+		workspaceID, err := s.workspaceService.ResolveWorkspaceID(user, storage.WorkspaceID)
+		if err != nil {
+			return err
+		}
+
 		storage.UserID = user.ID
+		storage.WorkspaceID = workspaceID
 	}
 
-	_, err := s.storageRepository.Save(storage)
+	err := s.workspaceService.CheckAccess(user, storage.WorkspaceID, user_enums.UserRoleAdmin)
 	if err != nil {
 		return err
 	}
 
+	storage.FillSensitiveData(existingStorage)
+
+	if err := storage.Validate(); err != nil {
+		return err
+	}
+
+	_, err = s.storageRepository.Save(storage)
+	if err != nil {
+		return err
+	}
+
+	if isNew {
+		s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+			Action:     audit_logs.AuditLogActionCreate,
+			TargetType: audit_logs.AuditLogTargetTypeStorage,
+			TargetID:   storage.ID,
+			TargetName: storage.Name,
+			Message:    fmt.Sprintf("Created storage \"%s\"", storage.Name),
+			Diff:       audit_logs.CalculateDiff(nil, storage),
+		})
+	} else {
+		s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+			Action:     audit_logs.AuditLogActionUpdate,
+			TargetType: audit_logs.AuditLogTargetTypeStorage,
+			TargetID:   storage.ID,
+			TargetName: storage.Name,
+			Message:    fmt.Sprintf("Updated storage \"%s\"", storage.Name),
+			Diff:       audit_logs.CalculateDiff(existingStorage, storage),
+		})
+	}
+
 	return nil
 }
 
@@ -42,53 +103,85 @@ func (s *StorageService) DeleteStorage(
 	user *users_models.User,
 	storageID uuid.UUID,
 ) error {
-	storage, err := s.storageRepository.FindByID(storageID)
+	storage, err := s.GetStorageWithRole(user, storageID, user_enums.UserRoleAdmin)
 	if err != nil {
 		return err
 	}
 
-	if storage.UserID != user.ID {
-		return errors.New("you have not access to this storage")
+	if err := s.storageRepository.Delete(storage); err != nil {
+		return err
 	}
 
-	return s.storageRepository.Delete(storage)
+	s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+		Action:     audit_logs.AuditLogActionDelete,
+		TargetType: audit_logs.AuditLogTargetTypeStorage,
+		TargetID:   storage.ID,
+		TargetName: storage.Name,
+		Message:    fmt.Sprintf("Deleted storage \"%s\"", storage.Name),
+		Diff:       audit_logs.CalculateDiff(storage, nil),
+	})
+
+	return nil
 }
 
 func (s *StorageService) GetStorage(
 	user *users_models.User,
 	id uuid.UUID,
+) (*Storage, error) {
+	return s.GetStorageWithRole(user, id, user_enums.UserRoleViewer)
+}
+
+// GetStorageWithRole returns the storage if the user has at least
+// the given role in the workspace of the storage
+func (s *StorageService) GetStorageWithRole(
+	user *users_models.User,
+	id uuid.UUID,
+	role user_enums.UserRole,
 ) (*Storage, error) {
 	storage, err := s.storageRepository.FindByID(id)
 	if err != nil {
 		return nil, err
 	}
 
-	if storage.UserID != user.ID {
-		return nil, errors.New("you have not access to this storage")
+	if err := s.workspaceService.CheckAccess(user, storage.WorkspaceID, role); err != nil {
+		return nil, err
 	}
 
 	return storage, nil
 }
 
+// GetStorages returns storages of the workspace or, if workspace
+// is not specified, storages of all workspaces available to the user
 func (s *StorageService) GetStorages(
 	user *users_models.User,
+	workspaceID *uuid.UUID,
 ) ([]*Storage, error) {
-	return s.storageRepository.FindByUserID(user.ID)
+	if workspaceID != nil {
+		err := s.workspaceService.CheckAccess(user, *workspaceID, user_enums.UserRoleViewer)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.storageRepository.FindByWorkspaceIDs([]uuid.UUID{*workspaceID})
+	}
+
+	workspaceIDs, err := s.workspaceService.GetWorkspaceIDs(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.storageRepository.FindByWorkspaceIDs(workspaceIDs)
 }
 
 func (s *StorageService) TestStorageConnection(
 	user *users_models.User,
 	storageID uuid.UUID,
 ) error {
-	storage, err := s.storageRepository.FindByID(storageID)
+	storage, err := s.GetStorageWithRole(user, storageID, user_enums.UserRoleOperator)
 	if err != nil {
 		return err
 	}
 
-	if storage.UserID != user.ID {
-		return errors.New("you have not access to this storage")
-	}
-
 	err = storage.TestConnection()
 	if err != nil {
 		lastSaveError := err.Error()
@@ -106,8 +199,34 @@ func (s *StorageService) TestStorageConnection(
 }
 
 func (s *StorageService) TestStorageConnectionDirect(
+	user *users_models.User,
 	storage *Storage,
 ) error {
+	workspaceID, err := s.workspaceService.ResolveWorkspaceID(user, storage.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	err = s.workspaceService.CheckAccess(user, workspaceID, user_enums.UserRoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	// credentials of the saved storage are not returned to the client,
+	// so the test of edited storage uses them if they are not changed
+	if storage.ID != uuid.Nil {
+		existingStorage, err := s.GetStorageWithRole(user, storage.ID, user_enums.UserRoleAdmin)
+		if err != nil {
+			return err
+		}
+
+		storage.FillSensitiveData(existingStorage)
+	}
+
+	if err := storage.Validate(); err != nil {
+		return err
+	}
+
 	return storage.TestConnection()
 }
 
@@ -116,3 +235,7 @@ func (s *StorageService) GetStorageByID(
 ) (*Storage, error) {
 	return s.storageRepository.FindByID(id)
 }
+
+func (s *StorageService) GetAllStorages() ([]*Storage, error) {
+	return s.storageRepository.FindAll()
+}
@@ -1,21 +1,82 @@
 package users
 
 import (
+	"errors"
 	"net/http"
+	user_enums "postgresus-backend/internal/features/users/enums"
+	user_models "postgresus-backend/internal/features/users/models"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
+	"github.com/google/uuid"
 )
 
 type UserController struct {
-	userService   *UserService
-	signinLimiter *rate.Limiter
+	userService     *UserService
+	signinThrottler *SigninThrottler
 }
 
 func (c *UserController) RegisterRoutes(router *gin.RouterGroup) {
 	router.POST("/users/signup", c.SignUp)
 	router.POST("/users/signin", c.SignIn)
+	router.POST("/users/signin/totp", c.SignInWithTotp)
+	router.POST("/users/signin/totp/enroll", c.StartTotpEnrollment)
+	router.POST("/users/refresh", c.RefreshToken)
+	router.POST("/users/logout", c.Logout)
 	router.GET("/users/is-any-user-exist", c.IsAnyUserExist)
+
+	router.GET("/users/oidc/config", c.GetOidcConfig)
+	router.GET("/users/oidc/authorization-url", c.GetOidcAuthorizationURL)
+	router.POST("/users/oidc/signin", c.SignInWithOidc)
+
+	router.GET("/users/me", c.GetCurrentUser)
+	router.POST("/users/me/change-password", c.ChangePassword)
+	router.GET("/users/me/sessions", c.GetOwnSessions)
+	router.DELETE("/users/me/sessions/:id", c.RevokeOwnSession)
+
+	router.POST("/users/me/totp/setup", c.SetupTotp)
+	router.POST("/users/me/totp/enable", c.EnableTotp)
+	router.POST("/users/me/totp/disable", c.DisableTotp)
+	router.POST("/users/me/totp/recovery-codes", c.RegenerateRecoveryCodes)
+
+	router.GET("/users/security-settings", c.GetSecuritySettings)
+	router.PUT("/users/security-settings", c.UpdateSecuritySettings)
+
+	router.POST("/users/api-keys", c.CreateApiKey)
+	router.GET("/users/api-keys", c.GetApiKeys)
+	router.DELETE("/users/api-keys/:id", c.DeleteApiKey)
+
+	router.POST("/users/secret-key/rotate", c.RotateSecretKey)
+
+	router.POST("/users", c.CreateUser)
+	router.GET("/users", c.GetUsers)
+	router.PUT("/users/:id/role", c.ChangeUserRole)
+	router.DELETE("/users/:id", c.DeleteUser)
+	router.GET("/users/:id/sessions", c.GetUserSessions)
+	router.DELETE("/users/:id/sessions", c.RevokeUserSessions)
+	router.DELETE("/users/:id/totp", c.ResetUserTotp)
+}
+
+// CheckApiKeyScope is a middleware rejecting requests made with API
+// keys if the scope of the key does not allow them. Requests with
+// access tokens are passed as is
+func (c *UserController) CheckApiKeyScope(ctx *gin.Context) {
+	authorizationHeader := ctx.GetHeader("Authorization")
+	if !IsApiKey(authorizationHeader) {
+		ctx.Next()
+		return
+	}
+
+	err := c.userService.CheckApiKeyScope(
+		authorizationHeader,
+		ctx.Request.Method,
+		ctx.FullPath(),
+	)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Next()
 }
 
 // SignUp
@@ -46,7 +107,7 @@ func (c *UserController) SignUp(ctx *gin.Context) {
 
 // SignIn
 // @Summary Authenticate a user
-// @Description Authenticate a user with email and password
+// @Description Authenticate a user with email and password. If two-factor authentication is enabled or required, only the token to pass the second factor is returned
 // @Tags users
 // @Accept json
 // @Produce json
@@ -57,7 +118,7 @@ func (c *UserController) SignUp(ctx *gin.Context) {
 // @Router /users/signin [post]
 func (c *UserController) SignIn(ctx *gin.Context) {
 	// We use rate limiter to prevent brute force attacks
-	if !c.signinLimiter.Allow() {
+	if !c.signinThrottler.AllowRequest(ctx.ClientIP()) {
 		ctx.JSON(
 			http.StatusTooManyRequests,
 			gin.H{"error": "Rate limit exceeded. Please try again later."},
@@ -71,7 +132,165 @@ func (c *UserController) SignIn(ctx *gin.Context) {
 		return
 	}
 
-	response, err := c.userService.SignIn(&request)
+	response, err := c.userService.SignIn(&request, ctx.Request.UserAgent(), ctx.ClientIP())
+	if errors.Is(err, ErrTooManySigninAttempts) {
+		ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// SignInWithTotp
+// @Summary Pass two-factor authentication
+// @Description Finish sign in with the code of authenticator app or a recovery code. If enrollment is required, the code confirms the new secret and recovery codes are returned
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body TotpSignInRequest true "Two-factor token and code"
+// @Success 200 {object} SignInResponse
+// @Failure 400
+// @Failure 429 {object} map[string]string "Rate limit exceeded"
+// @Router /users/signin/totp [post]
+func (c *UserController) SignInWithTotp(ctx *gin.Context) {
+	if !c.signinThrottler.AllowRequest(ctx.ClientIP()) {
+		ctx.JSON(
+			http.StatusTooManyRequests,
+			gin.H{"error": "Rate limit exceeded. Please try again later."},
+		)
+		return
+	}
+
+	var request TotpSignInRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	response, err := c.userService.SignInWithTotp(
+		&request,
+		ctx.Request.UserAgent(),
+		ctx.ClientIP(),
+	)
+	if errors.Is(err, ErrTooManySigninAttempts) {
+		ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// StartTotpEnrollment
+// @Summary Set up two-factor authentication on sign in
+// @Description Generate the secret for authenticator app when two-factor authentication is required, but the user has not enrolled yet
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body TotpEnrollmentRequest true "Two-factor token"
+// @Success 200 {object} TotpSetupResponse
+// @Failure 400
+// @Failure 429 {object} map[string]string "Rate limit exceeded"
+// @Router /users/signin/totp/enroll [post]
+func (c *UserController) StartTotpEnrollment(ctx *gin.Context) {
+	if !c.signinThrottler.AllowRequest(ctx.ClientIP()) {
+		ctx.JSON(
+			http.StatusTooManyRequests,
+			gin.H{"error": "Rate limit exceeded. Please try again later."},
+		)
+		return
+	}
+
+	var request TotpEnrollmentRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	response, err := c.userService.StartTotpEnrollment(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetOidcConfig
+// @Summary Get SSO config
+// @Description Get whether sign in via OpenID Connect is enabled and whether password sign in is disabled
+// @Tags users
+// @Produce json
+// @Success 200 {object} OidcConfigResponse
+// @Router /users/oidc/config [get]
+func (c *UserController) GetOidcConfig(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.userService.GetOidcConfig())
+}
+
+// GetOidcAuthorizationURL
+// @Summary Get SSO authorization URL
+// @Description Get URL of the identity provider login page. The provider redirects back to the frontend with code and state
+// @Tags users
+// @Produce json
+// @Success 200 {object} OidcAuthorizationURLResponse
+// @Failure 400
+// @Router /users/oidc/authorization-url [get]
+func (c *UserController) GetOidcAuthorizationURL(ctx *gin.Context) {
+	url, codeVerifier, err := c.userService.GetOidcAuthorizationURL()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	setOidcVerifierCookie(ctx, codeVerifier, int(oidcStateLifetime.Seconds()))
+
+	ctx.JSON(http.StatusOK, OidcAuthorizationURLResponse{URL: url})
+}
+
+// SignInWithOidc
+// @Summary Sign in via SSO
+// @Description Sign in with the authorization code of the identity provider. The user is created on first sign in. If two-factor authentication is enabled or required, only the token to pass the second factor is returned
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body OidcSignInRequest true "Code and state from the identity provider"
+// @Success 200 {object} SignInResponse
+// @Failure 400
+// @Failure 429 {object} map[string]string "Rate limit exceeded"
+// @Router /users/oidc/signin [post]
+func (c *UserController) SignInWithOidc(ctx *gin.Context) {
+	if !c.signinThrottler.AllowRequest(ctx.ClientIP()) {
+		ctx.JSON(
+			http.StatusTooManyRequests,
+			gin.H{"error": "Rate limit exceeded. Please try again later."},
+		)
+		return
+	}
+
+	var request OidcSignInRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	// the cookie is missing if the sign in was started in another
+	// browser, the state is rejected then
+	codeVerifier, _ := ctx.Cookie(oidcVerifierCookieName)
+	setOidcVerifierCookie(ctx, "", -1)
+
+	response, err := c.userService.SignInWithOidc(
+		&request,
+		codeVerifier,
+		ctx.Request.UserAgent(),
+		ctx.ClientIP(),
+	)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -80,6 +299,59 @@ func (c *UserController) SignIn(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, response)
 }
 
+// RefreshToken
+// @Summary Refresh access token
+// @Description Issue a new access token by the refresh token. The refresh token is rotated, the response contains a new one
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} SignInResponse
+// @Failure 400
+// @Failure 401
+// @Router /users/refresh [post]
+func (c *UserController) RefreshToken(ctx *gin.Context) {
+	var request RefreshTokenRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format"})
+		return
+	}
+
+	response, err := c.userService.RefreshSession(
+		&request,
+		ctx.Request.UserAgent(),
+		ctx.ClientIP(),
+	)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Logout
+// @Summary Log out
+// @Description Revoke the session of the token. Its refresh token stops working immediately
+// @Tags users
+// @Success 200
+// @Failure 401
+// @Router /users/logout [post]
+func (c *UserController) Logout(ctx *gin.Context) {
+	authorizationHeader := ctx.GetHeader("Authorization")
+	if authorizationHeader == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+		return
+	}
+
+	if err := c.userService.Logout(authorizationHeader); err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
 // IsAnyUserExist
 // @Summary Check if any user exists
 // @Description Check if any user exists in the system
@@ -96,3 +368,677 @@ func (c *UserController) IsAnyUserExist(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, gin.H{"isExist": isExist})
 }
+
+// GetCurrentUser
+// @Summary Get current user
+// @Description Get the user of the token
+// @Tags users
+// @Produce json
+// @Success 200 {object} users_models.User
+// @Failure 401
+// @Router /users/me [get]
+func (c *UserController) GetCurrentUser(ctx *gin.Context) {
+	user, ok := c.getUserWithRole(ctx, user_enums.UserRoleViewer)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
+
+// ChangePassword
+// @Summary Change own password
+// @Description Change password of the current user. All sessions of the user are revoked
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body ChangePasswordRequest true "Passwords data"
+// @Success 200
+// @Failure 400
+// @Failure 401
+// @Router /users/me/change-password [post]
+func (c *UserController) ChangePassword(ctx *gin.Context) {
+	user, ok := c.getUserWithoutApiKey(ctx, user_enums.UserRoleViewer)
+	if !ok {
+		return
+	}
+
+	var request ChangePasswordRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.userService.ChangePassword(user, &request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
+}
+
+// GetOwnSessions
+// @Summary Get own sessions
+// @Description Get active sessions of the current user. The session of the token is marked as current
+// @Tags users
+// @Produce json
+// @Success 200 {array} users_models.UserSession
+// @Failure 401
+// @Router /users/me/sessions [get]
+func (c *UserController) GetOwnSessions(ctx *gin.Context) {
+	user, ok := c.getUserWithoutApiKey(ctx, user_enums.UserRoleViewer)
+	if !ok {
+		return
+	}
+
+	sessionID, err := c.userService.GetSessionIDFromToken(ctx.GetHeader("Authorization"))
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	sessions, err := c.userService.GetActiveSessions(user.ID, sessionID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sessions)
+}
+
+// RevokeOwnSession
+// @Summary Revoke own session
+// @Description Revoke the session of the current user, e.g. signed in on a lost device
+// @Tags users
+// @Param id path string true "Session ID"
+// @Success 204
+// @Failure 400
+// @Failure 401
+// @Router /users/me/sessions/{id} [delete]
+func (c *UserController) RevokeOwnSession(ctx *gin.Context) {
+	user, ok := c.getUserWithoutApiKey(ctx, user_enums.UserRoleViewer)
+	if !ok {
+		return
+	}
+
+	sessionID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid session ID"})
+		return
+	}
+
+	if err := c.userService.RevokeOwnSession(user, sessionID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// SetupTotp
+// @Summary Set up two-factor authentication
+// @Description Generate a new secret for authenticator app. Two-factor authentication is enabled after the first code is confirmed
+// @Tags users
+// @Produce json
+// @Success 200 {object} TotpSetupResponse
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /users/me/totp/setup [post]
+func (c *UserController) SetupTotp(ctx *gin.Context) {
+	user, ok := c.getUserWithoutApiKey(ctx, user_enums.UserRoleViewer)
+	if !ok {
+		return
+	}
+
+	response, err := c.userService.SetupTotp(user)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// EnableTotp
+// @Summary Enable two-factor authentication
+// @Description Confirm the secret by the code of authenticator app. Recovery codes are returned only once
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body TotpCodeRequest true "Code of authenticator app"
+// @Success 200 {object} RecoveryCodesResponse
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /users/me/totp/enable [post]
+func (c *UserController) EnableTotp(ctx *gin.Context) {
+	user, ok := c.getUserWithoutApiKey(ctx, user_enums.UserRoleViewer)
+	if !ok {
+		return
+	}
+
+	var request TotpCodeRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recoveryCodes, err := c.userService.EnableTotp(user, &request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: recoveryCodes})
+}
+
+// DisableTotp
+// @Summary Disable two-factor authentication
+// @Description Disable two-factor authentication by the code of authenticator app or a recovery code. Not available when admin requires two-factor authentication
+// @Tags users
+// @Accept json
+// @Param request body TotpCodeRequest true "Code of authenticator app or recovery code"
+// @Success 200
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /users/me/totp/disable [post]
+func (c *UserController) DisableTotp(ctx *gin.Context) {
+	user, ok := c.getUserWithoutApiKey(ctx, user_enums.UserRoleViewer)
+	if !ok {
+		return
+	}
+
+	var request TotpCodeRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.userService.DisableTotp(user, &request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes
+// @Summary Regenerate recovery codes
+// @Description Replace recovery codes by new ones. Previous codes stop working
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body TotpCodeRequest true "Code of authenticator app"
+// @Success 200 {object} RecoveryCodesResponse
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /users/me/totp/recovery-codes [post]
+func (c *UserController) RegenerateRecoveryCodes(ctx *gin.Context) {
+	user, ok := c.getUserWithoutApiKey(ctx, user_enums.UserRoleViewer)
+	if !ok {
+		return
+	}
+
+	var request TotpCodeRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recoveryCodes, err := c.userService.RegenerateRecoveryCodes(user, &request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: recoveryCodes})
+}
+
+// GetSecuritySettings
+// @Summary Get security settings
+// @Description Get global authentication settings. Available for admins only
+// @Tags users
+// @Produce json
+// @Success 200 {object} users_models.SecuritySettings
+// @Failure 401
+// @Failure 403
+// @Router /users/security-settings [get]
+func (c *UserController) GetSecuritySettings(ctx *gin.Context) {
+	if _, ok := c.getUserWithoutApiKey(ctx, user_enums.UserRoleAdmin); !ok {
+		return
+	}
+
+	securitySettings, err := c.userService.GetSecuritySettings()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, securitySettings)
+}
+
+// UpdateSecuritySettings
+// @Summary Update security settings
+// @Description Update global authentication settings, e.g. require two-factor authentication for all users. Available for admins only
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body users_models.SecuritySettings true "Security settings"
+// @Success 200 {object} users_models.SecuritySettings
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /users/security-settings [put]
+func (c *UserController) UpdateSecuritySettings(ctx *gin.Context) {
+	admin, ok := c.getUserWithoutApiKey(ctx, user_enums.UserRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var request user_models.SecuritySettings
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	securitySettings, err := c.userService.UpdateSecuritySettings(admin, &request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, securitySettings)
+}
+
+// CreateApiKey
+// @Summary Create an API key
+// @Description Create a key for scripts and CI pipelines acting on behalf of the current user. The key is returned only once. API keys cannot create other keys
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body CreateApiKeyRequest true "API key data"
+// @Success 200 {object} CreateApiKeyResponse
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /users/api-keys [post]
+func (c *UserController) CreateApiKey(ctx *gin.Context) {
+	user, ok := c.getUserWithoutApiKey(ctx, user_enums.UserRoleViewer)
+	if !ok {
+		return
+	}
+
+	var request CreateApiKeyRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := c.userService.CreateApiKey(user, &request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetApiKeys
+// @Summary Get own API keys
+// @Description Get API keys of the current user
+// @Tags users
+// @Produce json
+// @Success 200 {array} users_models.ApiKey
+// @Failure 401
+// @Failure 403
+// @Router /users/api-keys [get]
+func (c *UserController) GetApiKeys(ctx *gin.Context) {
+	user, ok := c.getUserWithoutApiKey(ctx, user_enums.UserRoleViewer)
+	if !ok {
+		return
+	}
+
+	apiKeys, err := c.userService.GetApiKeys(user)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, apiKeys)
+}
+
+// DeleteApiKey
+// @Summary Delete an API key
+// @Description Revoke the API key. Admins can revoke keys of any user
+// @Tags users
+// @Param id path string true "API key ID"
+// @Success 204
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /users/api-keys/{id} [delete]
+func (c *UserController) DeleteApiKey(ctx *gin.Context) {
+	user, ok := c.getUserWithoutApiKey(ctx, user_enums.UserRoleViewer)
+	if !ok {
+		return
+	}
+
+	apiKeyID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid API key ID"})
+		return
+	}
+
+	if err := c.userService.DeleteApiKey(user, apiKeyID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// RotateSecretKey
+// @Summary Rotate secret key
+// @Description Replace the key signing access tokens. Tokens signed by the previous key keep working until they expire, so users are not signed out. Available for admins only
+// @Tags users
+// @Success 200
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /users/secret-key/rotate [post]
+func (c *UserController) RotateSecretKey(ctx *gin.Context) {
+	admin, ok := c.getUserWithoutApiKey(ctx, user_enums.UserRoleAdmin)
+	if !ok {
+		return
+	}
+
+	if err := c.userService.RotateSecretKey(admin); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Secret key rotated successfully"})
+}
+
+// CreateUser
+// @Summary Create a user
+// @Description Create a user with the given role. Available for admins only
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body CreateUserRequest true "User data"
+// @Success 200 {object} users_models.User
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /users [post]
+func (c *UserController) CreateUser(ctx *gin.Context) {
+	admin, ok := c.getUserWithoutApiKey(ctx, user_enums.UserRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var request CreateUserRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := c.userService.CreateUser(admin, &request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
+
+// GetUsers
+// @Summary Get users
+// @Description Get all users. Available for admins only
+// @Tags users
+// @Produce json
+// @Success 200 {array} users_models.User
+// @Failure 401
+// @Failure 403
+// @Router /users [get]
+func (c *UserController) GetUsers(ctx *gin.Context) {
+	if _, ok := c.getUserWithoutApiKey(ctx, user_enums.UserRoleAdmin); !ok {
+		return
+	}
+
+	users, err := c.userService.GetUsers()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, users)
+}
+
+// ChangeUserRole
+// @Summary Change user role
+// @Description Change role of the user. Available for admins only
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body ChangeUserRoleRequest true "Role data"
+// @Success 200 {object} users_models.User
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /users/{id}/role [put]
+func (c *UserController) ChangeUserRole(ctx *gin.Context) {
+	admin, ok := c.getUserWithoutApiKey(ctx, user_enums.UserRoleAdmin)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	var request ChangeUserRoleRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := c.userService.ChangeUserRole(admin, userID, request.Role)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
+
+// DeleteUser
+// @Summary Delete a user
+// @Description Delete the user. Databases, storages and notifiers created by the user are handed over to the admin. Available for admins only
+// @Tags users
+// @Param id path string true "User ID"
+// @Success 204
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /users/{id} [delete]
+func (c *UserController) DeleteUser(ctx *gin.Context) {
+	admin, ok := c.getUserWithoutApiKey(ctx, user_enums.UserRoleAdmin)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	if err := c.userService.DeleteUser(admin, userID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetUserSessions
+// @Summary Get sessions of a user
+// @Description Get active sessions of the user. Available for admins only
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {array} users_models.UserSession
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /users/{id}/sessions [get]
+func (c *UserController) GetUserSessions(ctx *gin.Context) {
+	if _, ok := c.getUserWithoutApiKey(ctx, user_enums.UserRoleAdmin); !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	sessionID, err := c.userService.GetSessionIDFromToken(ctx.GetHeader("Authorization"))
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	sessions, err := c.userService.GetActiveSessions(userID, sessionID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sessions)
+}
+
+// RevokeUserSessions
+// @Summary Revoke sessions of a user
+// @Description Sign the user out on all devices. Available for admins only
+// @Tags users
+// @Param id path string true "User ID"
+// @Success 204
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /users/{id}/sessions [delete]
+func (c *UserController) RevokeUserSessions(ctx *gin.Context) {
+	admin, ok := c.getUserWithoutApiKey(ctx, user_enums.UserRoleAdmin)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	if err := c.userService.RevokeAllSessions(admin, userID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ResetUserTotp
+// @Summary Reset two-factor authentication of a user
+// @Description Disable two-factor authentication of the user who lost authenticator app and recovery codes. Available for admins only
+// @Tags users
+// @Param id path string true "User ID"
+// @Success 204
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Router /users/{id}/totp [delete]
+func (c *UserController) ResetUserTotp(ctx *gin.Context) {
+	admin, ok := c.getUserWithoutApiKey(ctx, user_enums.UserRoleAdmin)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	if err := c.userService.ResetUserTotp(admin, userID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *UserController) getUserWithRole(
+	ctx *gin.Context,
+	role user_enums.UserRole,
+) (*user_models.User, bool) {
+	authorizationHeader := ctx.GetHeader("Authorization")
+	if authorizationHeader == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+		return nil, false
+	}
+
+	user, err := c.userService.GetUserFromToken(authorizationHeader)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return nil, false
+	}
+
+	if !user.Role.IsAtLeast(role) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+		return nil, false
+	}
+
+	return user, true
+}
+
+// getUserWithoutApiKey returns the user of access token. API keys are
+// rejected, so a leaked key cannot be used to issue new keys, manage
+// users and sessions or change security settings
+func (c *UserController) getUserWithoutApiKey(
+	ctx *gin.Context,
+	role user_enums.UserRole,
+) (*user_models.User, bool) {
+	if IsApiKey(ctx.GetHeader("Authorization")) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "the action is not available for API keys"})
+		return nil, false
+	}
+
+	return c.getUserWithRole(ctx, role)
+}
+
+// setOidcVerifierCookie keeps PKCE code verifier in the browser between
+// the start of SSO sign in and the return from the identity provider.
+// The cookie is sent only to SSO routes and is not readable by scripts
+func setOidcVerifierCookie(ctx *gin.Context, codeVerifier string, maxAge int) {
+	isSecure := ctx.Request.TLS != nil || ctx.GetHeader("X-Forwarded-Proto") == "https"
+
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(
+		oidcVerifierCookieName,
+		codeVerifier,
+		maxAge,
+		oidcVerifierCookiePath,
+		"",
+		isSecure,
+		true,
+	)
+}
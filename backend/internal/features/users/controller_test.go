@@ -0,0 +1,133 @@
+package users
+
+import (
+	"net/http"
+	user_enums "postgresus-backend/internal/features/users/enums"
+	test_utils "postgresus-backend/internal/util/testing"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ManageApiKeysAndTotp_WhenRequestedWithApiKey_RequestRejected(t *testing.T) {
+	// setup data
+	router := createRouter()
+	user := createTestUser(t, user_enums.UserRoleOperator)
+	key, apiKey := createTestApiKey(t, user, user_enums.ApiKeyScopeFull)
+
+	requests := []struct {
+		method string
+		url    string
+		body   interface{}
+	}{
+		{
+			http.MethodPost,
+			"/api/v1/users/api-keys",
+			CreateApiKeyRequest{Name: "Test key", Scope: user_enums.ApiKeyScopeFull},
+		},
+		{http.MethodGet, "/api/v1/users/api-keys", nil},
+		{http.MethodDelete, "/api/v1/users/api-keys/" + apiKey.ID.String(), nil},
+		{http.MethodPost, "/api/v1/users/me/totp/setup", nil},
+		{http.MethodPost, "/api/v1/users/me/totp/disable", TotpCodeRequest{Code: "000000"}},
+		{http.MethodPost, "/api/v1/users/me/totp/recovery-codes", TotpCodeRequest{Code: "000000"}},
+	}
+
+	for _, request := range requests {
+		// act & assertions
+		test_utils.MakeRequest(t, router, test_utils.RequestOptions{
+			Method:         request.method,
+			URL:            request.url,
+			Body:           request.body,
+			AuthToken:      key,
+			ExpectedStatus: http.StatusForbidden,
+		})
+	}
+
+	// cleanup
+	RemoveTestUser(user.ID)
+}
+
+func Test_ManageUsers_WhenRequestedWithAdminApiKey_RequestRejected(t *testing.T) {
+	// setup data
+	router := createRouter()
+	admin := getTestAdmin(t)
+	key, apiKey := createTestApiKey(t, admin, user_enums.ApiKeyScopeFull)
+
+	requests := []struct {
+		method string
+		url    string
+		body   interface{}
+	}{
+		{
+			http.MethodPost,
+			"/api/v1/users",
+			CreateUserRequest{
+				Email:    "test-" + uuid.New().String() + "@test.com",
+				Password: testUserPassword,
+				Role:     user_enums.UserRoleAdmin,
+			},
+		},
+		{http.MethodGet, "/api/v1/users", nil},
+		{http.MethodPost, "/api/v1/users/secret-key/rotate", nil},
+		{http.MethodGet, "/api/v1/users/security-settings", nil},
+		{http.MethodDelete, "/api/v1/users/" + admin.ID.String() + "/totp", nil},
+		{http.MethodDelete, "/api/v1/users/" + admin.ID.String() + "/sessions", nil},
+	}
+
+	for _, request := range requests {
+		// act & assertions
+		test_utils.MakeRequest(t, router, test_utils.RequestOptions{
+			Method:         request.method,
+			URL:            request.url,
+			Body:           request.body,
+			AuthToken:      key,
+			ExpectedStatus: http.StatusForbidden,
+		})
+	}
+
+	// cleanup
+	assert.NoError(t, userService.DeleteApiKey(admin, apiKey.ID))
+}
+
+func Test_GetBackupFile_WhenRequestedWithReadOnlyApiKey_RequestRejected(t *testing.T) {
+	// setup data
+	router := createRouter()
+	router.GET("/api/v1/backups/:id/file", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	user := createTestUser(t, user_enums.UserRoleOperator)
+	readOnlyKey, _ := createTestApiKey(t, user, user_enums.ApiKeyScopeReadOnly)
+	fullKey, _ := createTestApiKey(t, user, user_enums.ApiKeyScopeFull)
+
+	// act & assertions
+	test_utils.MakeGetRequest(
+		t,
+		router,
+		"/api/v1/backups/00000000-0000-0000-0000-000000000000/file",
+		readOnlyKey,
+		http.StatusForbidden,
+	)
+	test_utils.MakeGetRequest(
+		t,
+		router,
+		"/api/v1/backups/00000000-0000-0000-0000-000000000000/file",
+		fullKey,
+		http.StatusOK,
+	)
+
+	// cleanup
+	RemoveTestUser(user.ID)
+}
+
+func createRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	controller := GetUserController()
+	router.Use(controller.CheckApiKeyScope)
+	v1 := router.Group("/api/v1")
+	controller.RegisterRoutes(v1)
+	return router
+}
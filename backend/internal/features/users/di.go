@@ -1,20 +1,33 @@
 package users
 
 import (
+	users_oidc "postgresus-backend/internal/features/users/oidc"
 	user_repositories "postgresus-backend/internal/features/users/repositories"
-
-	"golang.org/x/time/rate"
 )
 
 var secretKeyRepository = &user_repositories.SecretKeyRepository{}
 var userRepository = &user_repositories.UserRepository{}
+var userSessionRepository = &user_repositories.UserSessionRepository{}
+var apiKeyRepository = &user_repositories.ApiKeyRepository{}
+var recoveryCodeRepository = &user_repositories.UserRecoveryCodeRepository{}
+var securitySettingsRepository = &user_repositories.SecuritySettingsRepository{}
+var oidcProvider = &users_oidc.OidcProvider{}
+var signinThrottler = NewSigninThrottler()
 var userService = &UserService{
 	userRepository,
 	secretKeyRepository,
+	userSessionRepository,
+	apiKeyRepository,
+	recoveryCodeRepository,
+	securitySettingsRepository,
+	oidcProvider,
+	signinThrottler,
+	nil,
+	[]AccountLockListener{},
 }
 var userController = &UserController{
 	userService,
-	rate.NewLimiter(rate.Limit(3), 3), // 3 RPS with burst of 3
+	signinThrottler,
 }
 
 func GetUserService() *UserService {
@@ -1,6 +1,12 @@
 package users
 
-import "github.com/google/uuid"
+import (
+	user_enums "postgresus-backend/internal/features/users/enums"
+	user_models "postgresus-backend/internal/features/users/models"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type SignUpRequest struct {
 	Email    string `json:"email"    validate:"required,email"`
@@ -12,7 +18,85 @@ type SignInRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// SignInResponse contains tokens of the new session. If the second
+// factor is needed, only TotpToken is set to pass it
 type SignInResponse struct {
-	UserID uuid.UUID `json:"userId"`
-	Token  string    `json:"token"`
+	UserID       uuid.UUID `json:"userId"`
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refreshToken"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+
+	IsTotpRequired           bool     `json:"isTotpRequired"`
+	IsTotpEnrollmentRequired bool     `json:"isTotpEnrollmentRequired"`
+	TotpToken                string   `json:"totpToken,omitempty"`
+	RecoveryCodes            []string `json:"recoveryCodes,omitempty"`
+}
+
+type TotpSignInRequest struct {
+	TotpToken string `json:"totpToken" binding:"required"`
+	// Code is the code of authenticator app or a recovery code
+	Code string `json:"code" binding:"required"`
+}
+
+type TotpEnrollmentRequest struct {
+	TotpToken string `json:"totpToken" binding:"required"`
+}
+
+type TotpSetupResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURL string `json:"otpauthUrl"`
+}
+
+type TotpCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+type OidcConfigResponse struct {
+	IsOidcEnabled           bool   `json:"isOidcEnabled"`
+	ProviderName            string `json:"providerName"`
+	IsPasswordLoginDisabled bool   `json:"isPasswordLoginDisabled"`
+}
+
+type OidcAuthorizationURLResponse struct {
+	URL string `json:"url"`
+}
+
+type OidcSignInRequest struct {
+	Code  string `json:"code"  binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+type CreateUserRequest struct {
+	Email    string              `json:"email"    binding:"required,email"`
+	Password string              `json:"password" binding:"required,min=8"`
+	Role     user_enums.UserRole `json:"role"     binding:"required"`
+}
+
+type ChangeUserRoleRequest struct {
+	Role user_enums.UserRole `json:"role" binding:"required"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" binding:"required"`
+	NewPassword     string `json:"newPassword"     binding:"required,min=8"`
+}
+
+type CreateApiKeyRequest struct {
+	Name      string                 `json:"name"      binding:"required"`
+	Scope     user_enums.ApiKeyScope `json:"scope"     binding:"required"`
+	ExpiresAt *time.Time             `json:"expiresAt"`
+}
+
+type CreateApiKeyResponse struct {
+	ApiKey *user_models.ApiKey `json:"apiKey"`
+	// Key is shown only once, it is not stored
+	Key string `json:"key"`
 }
@@ -0,0 +1,26 @@
+package user_enums
+
+import "errors"
+
+type ApiKeyScope string
+
+const (
+	// ApiKeyScopeReadOnly allows only reading requests, except downloading
+	// backups and exporting the audit log
+	ApiKeyScopeReadOnly ApiKeyScope = "READ_ONLY"
+	// ApiKeyScopeBackup allows reading requests and starting backups
+	ApiKeyScopeBackup ApiKeyScope = "BACKUP"
+	// ApiKeyScopeRestore allows reading requests and restoring backups
+	ApiKeyScopeRestore ApiKeyScope = "RESTORE"
+	// ApiKeyScopeFull allows everything the owner of the key can do
+	ApiKeyScopeFull ApiKeyScope = "FULL"
+)
+
+func (s ApiKeyScope) Validate() error {
+	switch s {
+	case ApiKeyScopeReadOnly, ApiKeyScopeBackup, ApiKeyScopeRestore, ApiKeyScopeFull:
+		return nil
+	default:
+		return errors.New("invalid API key scope")
+	}
+}
@@ -1,7 +1,43 @@
 package user_enums
 
+import "errors"
+
 type UserRole string
 
 const (
+	// UserRoleAdmin manages users, databases, storages, notifiers
+	// and their settings
 	UserRoleAdmin UserRole = "ADMIN"
+	// UserRoleOperator runs backups, restores and connection tests
+	UserRoleOperator UserRole = "OPERATOR"
+	// UserRoleViewer has read-only access
+	UserRoleViewer UserRole = "VIEWER"
 )
+
+func (r UserRole) Validate() error {
+	switch r {
+	case UserRoleAdmin, UserRoleOperator, UserRoleViewer:
+		return nil
+	default:
+		return errors.New("invalid user role")
+	}
+}
+
+// IsAtLeast checks the role has the same or more permissions
+// than the given role
+func (r UserRole) IsAtLeast(role UserRole) bool {
+	return r.getLevel() >= role.getLevel()
+}
+
+func (r UserRole) getLevel() int {
+	switch r {
+	case UserRoleAdmin:
+		return 3
+	case UserRoleOperator:
+		return 2
+	case UserRoleViewer:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,35 @@
+package users
+
+import (
+	user_models "postgresus-backend/internal/features/users/models"
+	"time"
+)
+
+// UserAuditLogWriter records changes of users into audit log. Action
+// is one of audit log actions, e.g. "CREATE". Before is nil for
+// created users, after is nil for deleted ones. Actor is nil for
+// actions made via CLI
+type UserAuditLogWriter interface {
+	WriteUserAuditLog(
+		actor *user_models.User,
+		action string,
+		before *user_models.User,
+		after *user_models.User,
+		message string,
+	)
+
+	// WriteSigninAuditLog records sign in attempts. User is nil if
+	// nobody has the email
+	WriteSigninAuditLog(
+		action string,
+		user *user_models.User,
+		email string,
+		message string,
+	)
+}
+
+// AccountLockListener is notified when sign in to the account is
+// locked after too many failed attempts
+type AccountLockListener interface {
+	OnAccountLocked(user *user_models.User, ipAddress string, lockedUntil time.Time)
+}
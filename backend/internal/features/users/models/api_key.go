@@ -0,0 +1,31 @@
+package users_models
+
+import (
+	user_enums "postgresus-backend/internal/features/users/enums"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApiKey authenticates requests of scripts and CI pipelines on behalf
+// of the user. The key itself is shown once on creation, only its hash
+// and first characters are stored
+type ApiKey struct {
+	ID         uuid.UUID              `json:"id"         gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID     uuid.UUID              `json:"userId"     gorm:"column:user_id;type:uuid;not null"`
+	Name       string                 `json:"name"       gorm:"column:name;not null"`
+	KeyPrefix  string                 `json:"keyPrefix"  gorm:"column:key_prefix;not null"`
+	KeyHash    string                 `json:"-"          gorm:"column:key_hash;not null"`
+	Scope      user_enums.ApiKeyScope `json:"scope"      gorm:"column:scope;type:text;not null"`
+	ExpiresAt  *time.Time             `json:"expiresAt"  gorm:"column:expires_at"`
+	LastUsedAt *time.Time             `json:"lastUsedAt" gorm:"column:last_used_at"`
+	CreatedAt  time.Time              `json:"createdAt"  gorm:"column:created_at;not null"`
+}
+
+func (ApiKey) TableName() string {
+	return "api_keys"
+}
+
+func (k *ApiKey) IsExpired() bool {
+	return k.ExpiresAt != nil && !k.ExpiresAt.After(time.Now().UTC())
+}
@@ -1,7 +1,19 @@
 package users_models
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SecretKey signs access tokens. Only one key is current (ExpiresAt
+// is nil), rotated keys keep verifying already issued tokens until
+// they expire
 type SecretKey struct {
-	Secret string `gorm:"column:secret;uniqueIndex;not null"`
+	ID        uuid.UUID  `gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
+	Secret    string     `gorm:"column:secret;uniqueIndex;not null"`
+	CreatedAt time.Time  `gorm:"column:created_at;not null;default:now()"`
+	ExpiresAt *time.Time `gorm:"column:expires_at"`
 }
 
 func (SecretKey) TableName() string {
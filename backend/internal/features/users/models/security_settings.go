@@ -0,0 +1,16 @@
+package users_models
+
+import "github.com/google/uuid"
+
+// SecuritySettings are global settings of authentication, there is
+// a single row of them
+type SecuritySettings struct {
+	ID uuid.UUID `json:"-" gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
+	// IsTotpRequired forces users without two-factor authentication
+	// to enroll on next sign in, both password and SSO one
+	IsTotpRequired bool `json:"isTotpRequired" gorm:"column:is_totp_required;not null"`
+}
+
+func (SecuritySettings) TableName() string {
+	return "security_settings"
+}
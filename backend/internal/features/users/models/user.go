@@ -14,6 +14,18 @@ type User struct {
 	PasswordCreationTime time.Time           `json:"-"         gorm:"not null"`
 	CreatedAt            time.Time           `json:"createdAt" gorm:"not null;default:now()"`
 	Role                 user_enums.UserRole `json:"role"      gorm:"type:text;not null"`
+
+	// TotpSecret is set on enrollment start, two-factor authentication
+	// is enforced only after the first code is confirmed
+	TotpSecret    string `json:"-"             gorm:"column:totp_secret;not null;default:'';serializer:encrypted"`
+	IsTotpEnabled bool   `json:"isTotpEnabled" gorm:"column:is_totp_enabled;not null;default:false"`
+	// TotpLastUsedStep is the period of the last accepted code, codes of
+	// this and earlier periods are rejected, so a code cannot be replayed
+	TotpLastUsedStep int64 `json:"-" gorm:"column:totp_last_used_step;not null;default:0"`
+
+	// OidcSubject is the "sub" claim of the user at the identity provider.
+	// Returning SSO users are found by it rather than by email
+	OidcSubject string `json:"-" gorm:"column:oidc_subject;not null;default:''"`
 }
 
 func (User) TableName() string {
@@ -0,0 +1,21 @@
+package users_models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserRecoveryCode allows to pass two-factor authentication once when
+// the authenticator app is lost. Only hash of the code is stored
+type UserRecoveryCode struct {
+	ID        uuid.UUID  `gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID    uuid.UUID  `gorm:"column:user_id;type:uuid;not null"`
+	CodeHash  string     `gorm:"column:code_hash;not null"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;not null"`
+}
+
+func (UserRecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
@@ -0,0 +1,31 @@
+package users_models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserSession is created on sign in. Refresh token of the session is
+// stored as hash only and is replaced on each refresh
+type UserSession struct {
+	ID               uuid.UUID  `json:"id"         gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
+	UserID           uuid.UUID  `json:"userId"     gorm:"column:user_id;type:uuid;not null"`
+	RefreshTokenHash string     `json:"-"          gorm:"column:refresh_token_hash;not null"`
+	UserAgent        string     `json:"userAgent"  gorm:"column:user_agent;not null"`
+	IpAddress        string     `json:"ipAddress"  gorm:"column:ip_address;not null"`
+	CreatedAt        time.Time  `json:"createdAt"  gorm:"column:created_at;not null"`
+	LastUsedAt       time.Time  `json:"lastUsedAt" gorm:"column:last_used_at;not null"`
+	ExpiresAt        time.Time  `json:"expiresAt"  gorm:"column:expires_at;not null"`
+	RevokedAt        *time.Time `json:"-"          gorm:"column:revoked_at"`
+
+	IsCurrent bool `json:"isCurrent" gorm:"-"`
+}
+
+func (UserSession) TableName() string {
+	return "user_sessions"
+}
+
+func (s *UserSession) IsActive() bool {
+	return s.RevokedAt == nil && s.ExpiresAt.After(time.Now().UTC())
+}
@@ -0,0 +1,81 @@
+package users_oidc
+
+import (
+	"fmt"
+	user_enums "postgresus-backend/internal/features/users/enums"
+	"strings"
+)
+
+type OidcConfig struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	ProviderName string
+
+	// RolesClaim is the claim with groups or roles of the user
+	RolesClaim  string
+	RoleMapping map[string]user_enums.UserRole
+	// DefaultRole is given when no claim value is mapped. Users
+	// without role are not allowed to sign in if it is empty
+	DefaultRole user_enums.UserRole
+	// AllowedDomains are checked by "hd" claim of Google Workspace,
+	// any domain is allowed if it is empty
+	AllowedDomains []string
+}
+
+type OidcIdentity struct {
+	Subject string
+	Email   string
+	Role    user_enums.UserRole
+	// IsEmailVerified is true only if the provider explicitly claims it,
+	// the email is linked to the existing user only in this case
+	IsEmailVerified bool
+	// IsRoleMapped is false when the role is the default one
+	IsRoleMapped bool
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// ParseRoleMapping parses mapping in "claimValue=ROLE,claimValue=ROLE"
+// format, e.g. "postgresus-admins=ADMIN,developers=VIEWER"
+func ParseRoleMapping(value string) (map[string]user_enums.UserRole, error) {
+	roleMapping := make(map[string]user_enums.UserRole)
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		claimValue, role, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(claimValue) == "" {
+			return nil, fmt.Errorf("invalid role mapping item \"%s\"", item)
+		}
+
+		userRole := user_enums.UserRole(strings.TrimSpace(role))
+		if err := userRole.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid role of mapping item \"%s\": %w", item, err)
+		}
+
+		roleMapping[strings.TrimSpace(claimValue)] = userRole
+	}
+
+	return roleMapping, nil
+}
@@ -0,0 +1,459 @@
+package users_oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"postgresus-backend/internal/config"
+	user_enums "postgresus-backend/internal/features/users/enums"
+	"postgresus-backend/internal/util/logger"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/oauth2"
+)
+
+// minJwksReloadInterval limits reloads of signing keys, otherwise each
+// token with unknown key ID makes a request to the issuer
+const minJwksReloadInterval = time.Minute
+
+// OidcProvider signs users in via OpenID Connect authorization code
+// flow. Discovery document and signing keys of the issuer are loaded
+// on first use and cached
+type OidcProvider struct {
+	config *OidcConfig
+
+	mutex        sync.Mutex
+	discovery    *oidcDiscovery
+	keys         map[string]any
+	keysLoadedAt time.Time
+}
+
+func (p *OidcProvider) IsEnabled() bool {
+	return p.getConfig().IssuerURL != ""
+}
+
+func (p *OidcProvider) GetProviderName() string {
+	return p.getConfig().ProviderName
+}
+
+// GetAuthorizationURL returns URL of the issuer login page. State and
+// nonce are returned back to the redirect URL and inside ID token. The
+// code is bound to the verifier by PKCE, so intercepted code is useless
+// without the verifier kept by the browser
+func (p *OidcProvider) GetAuthorizationURL(
+	state string,
+	nonce string,
+	codeVerifier string,
+) (string, error) {
+	oauthConfig, err := p.getOauthConfig()
+	if err != nil {
+		return "", err
+	}
+
+	return oauthConfig.AuthCodeURL(
+		state,
+		oauth2.SetAuthURLParam("nonce", nonce),
+		oauth2.S256ChallengeOption(codeVerifier),
+	), nil
+}
+
+// Exchange exchanges the authorization code for ID token, verifies
+// the token and maps its claims to the identity of the user
+func (p *OidcProvider) Exchange(
+	code string,
+	nonce string,
+	codeVerifier string,
+) (*OidcIdentity, error) {
+	oauthConfig, err := p.getOauthConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	token, err := oauthConfig.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("ID token is missing in token response")
+	}
+
+	claims, err := p.verifyIDToken(rawIDToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.getIdentity(claims)
+}
+
+func (p *OidcProvider) getConfig() *OidcConfig {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.config == nil {
+		p.config = loadOidcConfigFromEnv()
+	}
+
+	return p.config
+}
+
+func (p *OidcProvider) getOauthConfig() (*oauth2.Config, error) {
+	if !p.IsEnabled() {
+		return nil, errors.New("OIDC sign in is not configured")
+	}
+
+	discovery, err := p.getDiscovery()
+	if err != nil {
+		return nil, err
+	}
+
+	oidcConfig := p.getConfig()
+
+	return &oauth2.Config{
+		ClientID:     oidcConfig.ClientID,
+		ClientSecret: oidcConfig.ClientSecret,
+		RedirectURL:  oidcConfig.RedirectURL,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  discovery.AuthorizationEndpoint,
+			TokenURL: discovery.TokenEndpoint,
+		},
+		Scopes: []string{"openid", "email", "profile"},
+	}, nil
+}
+
+func (p *OidcProvider) getDiscovery() (*oidcDiscovery, error) {
+	oidcConfig := p.getConfig()
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	discoveryURL := strings.TrimSuffix(oidcConfig.IssuerURL, "/") +
+		"/.well-known/openid-configuration"
+
+	var discovery oidcDiscovery
+	if err := getJSON(discoveryURL, &discovery); err != nil {
+		return nil, fmt.Errorf("failed to load OIDC discovery document: %w", err)
+	}
+
+	if discovery.AuthorizationEndpoint == "" ||
+		discovery.TokenEndpoint == "" ||
+		discovery.JwksURI == "" {
+		return nil, errors.New("OIDC discovery document is incomplete")
+	}
+
+	p.discovery = &discovery
+
+	return p.discovery, nil
+}
+
+// getSigningKey returns the key by ID. Keys are reloaded if the key
+// is unknown, because issuers rotate keys from time to time, but not
+// more often than once per minJwksReloadInterval
+func (p *OidcProvider) getSigningKey(keyID string) (any, error) {
+	discovery, err := p.getDiscovery()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if key, ok := p.keys[keyID]; ok {
+		return key, nil
+	}
+
+	if time.Since(p.keysLoadedAt) < minJwksReloadInterval {
+		return nil, fmt.Errorf("unknown signing key %s", keyID)
+	}
+
+	keys, err := loadJwks(discovery.JwksURI)
+	if err != nil {
+		return nil, err
+	}
+
+	p.keys = keys
+	p.keysLoadedAt = time.Now()
+
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %s", keyID)
+	}
+
+	return key, nil
+}
+
+func (p *OidcProvider) verifyIDToken(rawIDToken string, nonce string) (jwt.MapClaims, error) {
+	discovery, err := p.getDiscovery()
+	if err != nil {
+		return nil, err
+	}
+
+	oidcConfig := p.getConfig()
+
+	parsedToken, err := jwt.Parse(rawIDToken, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+		default:
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+
+		keyID, _ := token.Header["kid"].(string)
+		return p.getSigningKey(keyID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %w", err)
+	}
+
+	claims, ok := parsedToken.Claims.(jwt.MapClaims)
+	if !ok || !parsedToken.Valid {
+		return nil, errors.New("invalid ID token")
+	}
+
+	if !claims.VerifyIssuer(discovery.Issuer, true) {
+		return nil, errors.New("ID token is issued by another issuer")
+	}
+
+	if !claims.VerifyAudience(oidcConfig.ClientID, true) {
+		return nil, errors.New("ID token is issued for another client")
+	}
+
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, errors.New("ID token nonce does not match")
+	}
+
+	return claims, nil
+}
+
+func (p *OidcProvider) getIdentity(claims jwt.MapClaims) (*OidcIdentity, error) {
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, errors.New("ID token does not contain subject")
+	}
+
+	email, _ := claims["email"].(string)
+	if email == "" {
+		return nil, errors.New("ID token does not contain email")
+	}
+
+	isEmailVerified, ok := claims["email_verified"].(bool)
+	if ok && !isEmailVerified {
+		return nil, errors.New("email is not verified by the identity provider")
+	}
+
+	oidcConfig := p.getConfig()
+
+	if len(oidcConfig.AllowedDomains) > 0 {
+		hostedDomain, _ := claims["hd"].(string)
+		if !slices.Contains(oidcConfig.AllowedDomains, strings.ToLower(hostedDomain)) {
+			return nil, errors.New("your domain is not allowed to sign in")
+		}
+	}
+
+	identity := &OidcIdentity{
+		Subject:         subject,
+		Email:           strings.ToLower(email),
+		Role:            oidcConfig.DefaultRole,
+		IsEmailVerified: isEmailVerified,
+	}
+
+	for _, claimValue := range getClaimValues(claims, oidcConfig.RolesClaim) {
+		role, ok := oidcConfig.RoleMapping[claimValue]
+		if !ok {
+			continue
+		}
+
+		if !identity.IsRoleMapped || role.IsAtLeast(identity.Role) {
+			identity.Role = role
+			identity.IsRoleMapped = true
+		}
+	}
+
+	return identity, nil
+}
+
+// getClaimValues returns values of the claim as strings. Nested
+// claims are addressed by dots, e.g. "realm_access.roles"
+func getClaimValues(claims map[string]any, claimPath string) []string {
+	var value any = claims
+	for _, part := range strings.Split(claimPath, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		value = object[part]
+	}
+
+	switch typedValue := value.(type) {
+	case string:
+		return []string{typedValue}
+	case []any:
+		values := make([]string, 0, len(typedValue))
+		for _, item := range typedValue {
+			if stringItem, ok := item.(string); ok {
+				values = append(values, stringItem)
+			}
+		}
+
+		return values
+	default:
+		return nil
+	}
+}
+
+func loadJwks(jwksURI string) (map[string]any, error) {
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	if err := getJSON(jwksURI, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to load OIDC signing keys: %w", err)
+	}
+
+	keys := make(map[string]any, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.toPublicKey()
+		if err != nil {
+			continue
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func (k *jsonWebKey) toPublicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBase64BigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBase64BigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+
+		x, err := decodeBase64BigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBase64BigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+	}
+}
+
+func decodeBase64BigInt(value string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(bytes), nil
+}
+
+func getJSON(requestURL string, target any) error {
+	if _, err := url.ParseRequestURI(requestURL); err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	resp, err := client.Get(requestURL)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+func loadOidcConfigFromEnv() *OidcConfig {
+	env := config.GetEnv()
+
+	oidcConfig := &OidcConfig{
+		IssuerURL:    env.OidcIssuerURL,
+		ClientID:     env.OidcClientID,
+		ClientSecret: env.OidcClientSecret,
+		RedirectURL:  env.OidcRedirectURL,
+		ProviderName: env.OidcProviderName,
+		RolesClaim:   env.OidcRolesClaim,
+		DefaultRole:  user_enums.UserRole(env.OidcDefaultRole),
+	}
+
+	for _, domain := range strings.Split(env.OidcAllowedDomains, ",") {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain != "" {
+			oidcConfig.AllowedDomains = append(oidcConfig.AllowedDomains, domain)
+		}
+	}
+
+	roleMapping, err := ParseRoleMapping(env.OidcRoleMapping)
+	if err != nil {
+		logger.GetLogger().Error("Invalid OIDC_ROLE_MAPPING, OIDC is disabled", "error", err)
+		return &OidcConfig{}
+	}
+	oidcConfig.RoleMapping = roleMapping
+
+	if oidcConfig.DefaultRole != "" {
+		if err := oidcConfig.DefaultRole.Validate(); err != nil {
+			logger.GetLogger().Error("Invalid OIDC_DEFAULT_ROLE, OIDC is disabled", "error", err)
+			return &OidcConfig{}
+		}
+	}
+
+	return oidcConfig
+}
@@ -0,0 +1,325 @@
+package users_oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	user_enums "postgresus-backend/internal/features/users/enums"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+const (
+	testClientID = "postgresus"
+	testKeyID    = "test-key"
+	testNonce    = "test-nonce"
+
+	testCodeVerifier = "test-code-verifier-0123456789-0123456789-0123456789"
+)
+
+func Test_Exchange_WhenIdTokenIsValid_IdentityWithMappedRoleReturned(t *testing.T) {
+	mockProvider := newMockOidcProvider(t, map[string]any{
+		"email":  "John@Example.com",
+		"groups": []any{"developers", "postgresus-admins"},
+	})
+
+	identity, err := mockProvider.provider.Exchange("code", testNonce, testCodeVerifier)
+	require.NoError(t, err)
+
+	assert.Equal(t, "john@example.com", identity.Email)
+	assert.Equal(t, user_enums.UserRoleAdmin, identity.Role)
+	assert.True(t, identity.IsRoleMapped)
+}
+
+func Test_Exchange_WhenNoGroupIsMapped_DefaultRoleReturned(t *testing.T) {
+	mockProvider := newMockOidcProvider(t, map[string]any{
+		"email":  "john@example.com",
+		"groups": []any{"marketing"},
+	})
+
+	identity, err := mockProvider.provider.Exchange("code", testNonce, testCodeVerifier)
+	require.NoError(t, err)
+
+	assert.Equal(t, user_enums.UserRoleViewer, identity.Role)
+	assert.False(t, identity.IsRoleMapped)
+}
+
+func Test_Exchange_WhenNonceDoesNotMatch_ErrorReturned(t *testing.T) {
+	mockProvider := newMockOidcProvider(t, map[string]any{
+		"email": "john@example.com",
+	})
+
+	_, err := mockProvider.provider.Exchange("code", "another-nonce", testCodeVerifier)
+	assert.ErrorContains(t, err, "nonce")
+}
+
+func Test_Exchange_WhenTokenIsIssuedForAnotherClient_ErrorReturned(t *testing.T) {
+	mockProvider := newMockOidcProvider(t, map[string]any{
+		"email": "john@example.com",
+		"aud":   "another-client",
+	})
+
+	_, err := mockProvider.provider.Exchange("code", testNonce, testCodeVerifier)
+	assert.ErrorContains(t, err, "another client")
+}
+
+func Test_Exchange_WhenTokenIsSignedByUnknownKey_ErrorReturned(t *testing.T) {
+	mockProvider := newMockOidcProvider(t, map[string]any{
+		"email": "john@example.com",
+	})
+
+	anotherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	mockProvider.signingKey = anotherKey
+
+	_, err = mockProvider.provider.Exchange("code", testNonce, testCodeVerifier)
+	assert.ErrorContains(t, err, "invalid ID token")
+}
+
+func Test_Exchange_WhenKeysAreRecentlyLoaded_UnknownKeyDoesNotReloadKeys(t *testing.T) {
+	mockProvider := newMockOidcProvider(t, map[string]any{
+		"email": "john@example.com",
+	})
+
+	_, err := mockProvider.provider.Exchange("code", testNonce, testCodeVerifier)
+	require.NoError(t, err)
+	assert.Equal(t, 1, mockProvider.jwksRequestsCount)
+
+	mockProvider.keyID = "rotated-key"
+
+	for range 3 {
+		_, err = mockProvider.provider.Exchange("code", testNonce, testCodeVerifier)
+		assert.ErrorContains(t, err, "unknown signing key")
+	}
+	assert.Equal(t, 1, mockProvider.jwksRequestsCount)
+
+	mockProvider.provider.keysLoadedAt = time.Now().Add(-minJwksReloadInterval)
+
+	_, err = mockProvider.provider.Exchange("code", testNonce, testCodeVerifier)
+	require.NoError(t, err)
+	assert.Equal(t, 2, mockProvider.jwksRequestsCount)
+}
+
+func Test_Exchange_WhenEmailIsNotVerified_ErrorReturned(t *testing.T) {
+	mockProvider := newMockOidcProvider(t, map[string]any{
+		"email":          "john@example.com",
+		"email_verified": false,
+	})
+
+	_, err := mockProvider.provider.Exchange("code", testNonce, testCodeVerifier)
+	assert.ErrorContains(t, err, "not verified")
+}
+
+func Test_Exchange_WhenEmailVerifiedClaimIsMissing_EmailIsNotTreatedAsVerified(t *testing.T) {
+	mockProvider := newMockOidcProvider(t, map[string]any{
+		"email": "john@example.com",
+	})
+
+	identity, err := mockProvider.provider.Exchange("code", testNonce, testCodeVerifier)
+	require.NoError(t, err)
+	assert.False(t, identity.IsEmailVerified)
+	assert.Equal(t, "user-1", identity.Subject)
+
+	mockProvider.claims["email_verified"] = true
+
+	identity, err = mockProvider.provider.Exchange("code", testNonce, testCodeVerifier)
+	require.NoError(t, err)
+	assert.True(t, identity.IsEmailVerified)
+}
+
+func Test_Exchange_WhenDomainIsNotAllowed_ErrorReturned(t *testing.T) {
+	mockProvider := newMockOidcProvider(t, map[string]any{
+		"email": "john@gmail.com",
+	})
+	mockProvider.provider.config.AllowedDomains = []string{"example.com"}
+
+	_, err := mockProvider.provider.Exchange("code", testNonce, testCodeVerifier)
+	assert.ErrorContains(t, err, "domain is not allowed")
+
+	mockProvider.claims["hd"] = "another.com"
+
+	_, err = mockProvider.provider.Exchange("code", testNonce, testCodeVerifier)
+	assert.ErrorContains(t, err, "domain is not allowed")
+
+	mockProvider.claims["hd"] = "Example.com"
+
+	_, err = mockProvider.provider.Exchange("code", testNonce, testCodeVerifier)
+	assert.NoError(t, err)
+}
+
+func Test_GetAuthorizationURL_StateAndNonceIncluded(t *testing.T) {
+	mockProvider := newMockOidcProvider(t, map[string]any{})
+
+	authorizationURL, err := mockProvider.provider.GetAuthorizationURL(
+		"test-state",
+		testNonce,
+		testCodeVerifier,
+	)
+	require.NoError(t, err)
+
+	parsedURL, err := url.Parse(authorizationURL)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/authorize", parsedURL.Path)
+	assert.Equal(t, "test-state", parsedURL.Query().Get("state"))
+	assert.Equal(t, testNonce, parsedURL.Query().Get("nonce"))
+	assert.Equal(t, testClientID, parsedURL.Query().Get("client_id"))
+	assert.Equal(
+		t,
+		oauth2.S256ChallengeFromVerifier(testCodeVerifier),
+		parsedURL.Query().Get("code_challenge"),
+	)
+	assert.Equal(t, "S256", parsedURL.Query().Get("code_challenge_method"))
+}
+
+func Test_Exchange_WhenCodeVerifierDoesNotMatch_ErrorReturned(t *testing.T) {
+	mockProvider := newMockOidcProvider(t, map[string]any{})
+
+	_, err := mockProvider.provider.Exchange("code", testNonce, "another-code-verifier")
+	assert.ErrorContains(t, err, "failed to exchange authorization code")
+}
+
+func Test_GetClaimValues_WhenClaimIsNested_ValuesReturned(t *testing.T) {
+	claims := map[string]any{
+		"realm_access": map[string]any{
+			"roles": []any{"admin", "user"},
+		},
+	}
+
+	assert.Equal(t, []string{"admin", "user"}, getClaimValues(claims, "realm_access.roles"))
+	assert.Empty(t, getClaimValues(claims, "groups"))
+}
+
+func Test_ParseRoleMapping_WhenRoleIsInvalid_ErrorReturned(t *testing.T) {
+	roleMapping, err := ParseRoleMapping("admins=ADMIN, ops = OPERATOR")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]user_enums.UserRole{
+		"admins": user_enums.UserRoleAdmin,
+		"ops":    user_enums.UserRoleOperator,
+	}, roleMapping)
+
+	_, err = ParseRoleMapping("admins=OWNER")
+	assert.Error(t, err)
+}
+
+// mockOidcProvider is a local OIDC issuer. Its token endpoint returns
+// ID token with the given claims for any authorization code
+type mockOidcProvider struct {
+	server     *httptest.Server
+	provider   *OidcProvider
+	signingKey *rsa.PrivateKey
+	keyID      string
+	claims     map[string]any
+
+	jwksRequestsCount int
+}
+
+func newMockOidcProvider(t *testing.T, claims map[string]any) *mockOidcProvider {
+	signingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	mockProvider := &mockOidcProvider{
+		signingKey: signingKey,
+		keyID:      testKeyID,
+		claims:     claims,
+	}
+
+	publicKey := signingKey.PublicKey
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{
+			"issuer":                 mockProvider.server.URL,
+			"authorization_endpoint": mockProvider.server.URL + "/authorize",
+			"token_endpoint":         mockProvider.server.URL + "/token",
+			"jwks_uri":               mockProvider.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		mockProvider.jwksRequestsCount++
+
+		writeJSON(w, map[string]any{
+			"keys": []map[string]any{{
+				"kid": mockProvider.keyID,
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+				"e": base64.RawURLEncoding.EncodeToString(
+					big.NewInt(int64(publicKey.E)).Bytes(),
+				),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.PostFormValue("code_verifier") != testCodeVerifier {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": "invalid_grant"})
+			return
+		}
+
+		tokenClaims := jwt.MapClaims{
+			"iss":   mockProvider.server.URL,
+			"sub":   "user-1",
+			"aud":   testClientID,
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"iat":   time.Now().Unix(),
+			"nonce": testNonce,
+		}
+		for key, value := range mockProvider.claims {
+			tokenClaims[key] = value
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, tokenClaims)
+		token.Header["kid"] = mockProvider.keyID
+
+		idToken, err := token.SignedString(mockProvider.signingKey)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, map[string]any{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     idToken,
+		})
+	})
+
+	mockProvider.server = httptest.NewServer(mux)
+	t.Cleanup(mockProvider.server.Close)
+
+	mockProvider.provider = &OidcProvider{
+		config: &OidcConfig{
+			IssuerURL:    mockProvider.server.URL,
+			ClientID:     testClientID,
+			ClientSecret: "secret",
+			RedirectURL:  "http://localhost/auth/oidc/callback",
+			ProviderName: "Mock",
+			RolesClaim:   "groups",
+			RoleMapping: map[string]user_enums.UserRole{
+				"postgresus-admins": user_enums.UserRoleAdmin,
+				"developers":        user_enums.UserRoleOperator,
+			},
+			DefaultRole: user_enums.UserRoleViewer,
+		},
+	}
+
+	return mockProvider
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
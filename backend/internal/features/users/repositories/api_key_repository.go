@@ -0,0 +1,73 @@
+package user_repositories
+
+import (
+	user_models "postgresus-backend/internal/features/users/models"
+	"postgresus-backend/internal/storage"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ApiKeyRepository struct{}
+
+func (r *ApiKeyRepository) Save(apiKey *user_models.ApiKey) error {
+	if apiKey.ID == uuid.Nil {
+		apiKey.ID = uuid.New()
+	}
+
+	return storage.GetDb().Save(apiKey).Error
+}
+
+func (r *ApiKeyRepository) FindByID(id uuid.UUID) (*user_models.ApiKey, error) {
+	var apiKey user_models.ApiKey
+
+	if err := storage.
+		GetDb().
+		Where("id = ?", id).
+		First(&apiKey).Error; err != nil {
+		return nil, err
+	}
+
+	return &apiKey, nil
+}
+
+func (r *ApiKeyRepository) FindByKeyHash(keyHash string) (*user_models.ApiKey, error) {
+	var apiKey user_models.ApiKey
+
+	if err := storage.
+		GetDb().
+		Where("key_hash = ?", keyHash).
+		First(&apiKey).Error; err != nil {
+		return nil, err
+	}
+
+	return &apiKey, nil
+}
+
+func (r *ApiKeyRepository) FindByUserID(userID uuid.UUID) ([]*user_models.ApiKey, error) {
+	var apiKeys []*user_models.ApiKey
+
+	if err := storage.
+		GetDb().
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&apiKeys).Error; err != nil {
+		return nil, err
+	}
+
+	return apiKeys, nil
+}
+
+func (r *ApiKeyRepository) UpdateLastUsedAt(id uuid.UUID, lastUsedAt time.Time) error {
+	return storage.
+		GetDb().
+		Model(&user_models.ApiKey{}).
+		Where("id = ?", id).
+		Update("last_used_at", lastUsedAt).Error
+}
+
+func (r *ApiKeyRepository) DeleteByID(id uuid.UUID) error {
+	return storage.
+		GetDb().
+		Delete(&user_models.ApiKey{}, "id = ?", id).Error
+}
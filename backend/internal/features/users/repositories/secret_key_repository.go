@@ -4,6 +4,7 @@ import (
 	"errors"
 	user_models "postgresus-backend/internal/features/users/models"
 	"postgresus-backend/internal/storage"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -11,26 +12,82 @@ import (
 
 type SecretKeyRepository struct{}
 
-func (r *SecretKeyRepository) GetSecretKey() (string, error) {
+// GetSecretKey returns the current secret key, the key is created if
+// there is no one yet
+func (r *SecretKeyRepository) GetSecretKey() (*user_models.SecretKey, error) {
 	var secretKey user_models.SecretKey
 
 	if err := storage.
 		GetDb().
+		Where("expires_at IS NULL").
+		Order("created_at DESC").
 		First(&secretKey).Error; err != nil {
 		// create a new secret key if not found
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			newSecretKey := user_models.SecretKey{
-				Secret: uuid.New().String() + uuid.New().String(),
-			}
-			if err := storage.GetDb().Create(&newSecretKey).Error; err != nil {
-				return "", errors.New("failed to create new secret key")
+			newSecretKey := newSecretKey()
+			if err := storage.GetDb().Create(newSecretKey).Error; err != nil {
+				return nil, errors.New("failed to create new secret key")
 			}
 
-			return newSecretKey.Secret, nil
+			return newSecretKey, nil
+		}
+
+		return nil, err
+	}
+
+	return &secretKey, nil
+}
+
+// GetValidSecretKeyByID returns the key if it is current or rotated
+// but not expired yet
+func (r *SecretKeyRepository) GetValidSecretKeyByID(id uuid.UUID) (*user_models.SecretKey, error) {
+	var secretKey user_models.SecretKey
+
+	if err := storage.
+		GetDb().
+		Where("id = ?", id).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now().UTC()).
+		First(&secretKey).Error; err != nil {
+		return nil, err
+	}
+
+	return &secretKey, nil
+}
+
+// RotateSecretKey makes a new current key. Previous keys expire at
+// the given time, so tokens signed by them keep working until then
+func (r *SecretKeyRepository) RotateSecretKey(
+	previousKeysExpireAt time.Time,
+) (*user_models.SecretKey, error) {
+	secretKey := newSecretKey()
+
+	err := storage.GetDb().Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Where("expires_at IS NOT NULL AND expires_at <= ?", time.Now().UTC()).
+			Delete(&user_models.SecretKey{}).Error; err != nil {
+			return err
 		}
 
-		return "", err
+		if err := tx.
+			Model(&user_models.SecretKey{}).
+			Where("expires_at IS NULL OR expires_at > ?", previousKeysExpireAt).
+			Update("expires_at", previousKeysExpireAt).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(secretKey).Error
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return secretKey.Secret, nil
+	return secretKey, nil
+}
+
+func newSecretKey() *user_models.SecretKey {
+	return &user_models.SecretKey{
+		ID:        uuid.New(),
+		Secret:    uuid.New().String() + uuid.New().String(),
+		CreatedAt: time.Now().UTC(),
+	}
 }
@@ -0,0 +1,39 @@
+package user_repositories
+
+import (
+	"errors"
+	user_models "postgresus-backend/internal/features/users/models"
+	"postgresus-backend/internal/storage"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SecuritySettingsRepository struct{}
+
+// GetSecuritySettings returns the settings, default ones are created
+// if there are no settings yet
+func (r *SecuritySettingsRepository) GetSecuritySettings() (*user_models.SecuritySettings, error) {
+	var settings user_models.SecuritySettings
+
+	if err := storage.
+		GetDb().
+		First(&settings).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			settings = user_models.SecuritySettings{ID: uuid.New()}
+			if err := storage.GetDb().Create(&settings).Error; err != nil {
+				return nil, err
+			}
+
+			return &settings, nil
+		}
+
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+func (r *SecuritySettingsRepository) Save(settings *user_models.SecuritySettings) error {
+	return storage.GetDb().Save(settings).Error
+}
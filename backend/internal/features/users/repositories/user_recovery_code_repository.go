@@ -0,0 +1,67 @@
+package user_repositories
+
+import (
+	user_models "postgresus-backend/internal/features/users/models"
+	"postgresus-backend/internal/storage"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type UserRecoveryCodeRepository struct{}
+
+// ReplaceByUserID removes previous codes of the user and saves new ones
+func (r *UserRecoveryCodeRepository) ReplaceByUserID(
+	userID uuid.UUID,
+	codeHashes []string,
+) error {
+	return storage.GetDb().Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Where("user_id = ?", userID).
+			Delete(&user_models.UserRecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		recoveryCodes := make([]*user_models.UserRecoveryCode, 0, len(codeHashes))
+		for _, codeHash := range codeHashes {
+			recoveryCodes = append(recoveryCodes, &user_models.UserRecoveryCode{
+				ID:        uuid.New(),
+				UserID:    userID,
+				CodeHash:  codeHash,
+				CreatedAt: time.Now().UTC(),
+			})
+		}
+
+		if len(recoveryCodes) == 0 {
+			return nil
+		}
+
+		return tx.Create(&recoveryCodes).Error
+	})
+}
+
+// UseCode marks unused code of the user as used. False is returned if
+// there is no such code
+func (r *UserRecoveryCodeRepository) UseCode(userID uuid.UUID, codeHash string) (bool, error) {
+	result := storage.
+		GetDb().
+		Model(&user_models.UserRecoveryCode{}).
+		Where("user_id = ?", userID).
+		Where("code_hash = ?", codeHash).
+		Where("used_at IS NULL").
+		Update("used_at", time.Now().UTC())
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *UserRecoveryCodeRepository) DeleteByUserID(userID uuid.UUID) error {
+	return storage.
+		GetDb().
+		Where("user_id = ?", userID).
+		Delete(&user_models.UserRecoveryCode{}).Error
+}
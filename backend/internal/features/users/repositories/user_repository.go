@@ -1,6 +1,7 @@
 package user_repositories
 
 import (
+	user_enums "postgresus-backend/internal/features/users/enums"
 	user_models "postgresus-backend/internal/features/users/models"
 	"postgresus-backend/internal/storage"
 	"time"
@@ -38,6 +39,27 @@ func (r *UserRepository) GetUserByEmail(email string) (*user_models.User, error)
 	return &user, nil
 }
 
+// GetUserByOidcSubject returns nil if no user is linked to the subject
+func (r *UserRepository) GetUserByOidcSubject(subject string) (*user_models.User, error) {
+	var user user_models.User
+
+	if err := storage.GetDb().Where("oidc_subject = ?", subject).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (r *UserRepository) UpdateUserOidcSubject(userID uuid.UUID, subject string) error {
+	return storage.GetDb().Model(&user_models.User{}).
+		Where("id = ?", userID).
+		Update("oidc_subject", subject).Error
+}
+
 func (r *UserRepository) GetUserByID(userID string) (*user_models.User, error) {
 	var user user_models.User
 
@@ -51,7 +73,7 @@ func (r *UserRepository) GetUserByID(userID string) (*user_models.User, error) {
 func (r *UserRepository) GetFirstUser() (*user_models.User, error) {
 	var user user_models.User
 
-	if err := storage.GetDb().First(&user).Error; err != nil {
+	if err := storage.GetDb().Order("created_at ASC").First(&user).Error; err != nil {
 		return nil, err
 	}
 
@@ -66,3 +88,86 @@ func (r *UserRepository) UpdateUserPassword(userID uuid.UUID, hashedPassword str
 			"password_creation_time": time.Now().UTC(),
 		}).Error
 }
+
+func (r *UserRepository) UpdateUserTotp(
+	userID uuid.UUID,
+	totpSecret string,
+	isTotpEnabled bool,
+) error {
+	// struct is used instead of map, so the secret goes through
+	// encrypted serializer
+	return storage.GetDb().Model(&user_models.User{}).
+		Where("id = ?", userID).
+		Select("totp_secret", "is_totp_enabled").
+		Updates(&user_models.User{
+			TotpSecret:    totpSecret,
+			IsTotpEnabled: isTotpEnabled,
+		}).Error
+}
+
+// UseTotpStep stores the period of the accepted code. False is returned
+// if the code of this or later period is already used, the check is
+// done by the same query, so concurrent requests cannot use one code
+func (r *UserRepository) UseTotpStep(userID uuid.UUID, step int64) (bool, error) {
+	result := storage.GetDb().Model(&user_models.User{}).
+		Where("id = ?", userID).
+		Where("totp_last_used_step < ?", step).
+		Update("totp_last_used_step", step)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *UserRepository) GetUsers() ([]*user_models.User, error) {
+	var users []*user_models.User
+
+	if err := storage.
+		GetDb().
+		Order("created_at ASC").
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *UserRepository) CountUsersByRole(role user_enums.UserRole) (int64, error) {
+	var count int64
+
+	if err := storage.
+		GetDb().
+		Model(&user_models.User{}).
+		Where("role = ?", role).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *UserRepository) UpdateUserRole(userID uuid.UUID, role user_enums.UserRole) error {
+	return storage.GetDb().Model(&user_models.User{}).
+		Where("id = ?", userID).
+		Update("role", role).Error
+}
+
+// DeleteUser removes the user and hands over databases, storages and
+// notifiers created by the user to another user. Databases are
+// removed on user removal otherwise
+func (r *UserRepository) DeleteUser(userID uuid.UUID, newOwnerID uuid.UUID) error {
+	return storage.GetDb().Transaction(func(tx *gorm.DB) error {
+		for _, table := range []string{"databases", "storages", "notifiers"} {
+			if err := tx.
+				Table(table).
+				Where("user_id = ?", userID).
+				Update("user_id", newOwnerID).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Delete(&user_models.User{}, "id = ?", userID).Error
+	})
+}
@@ -0,0 +1,92 @@
+package user_repositories
+
+import (
+	user_models "postgresus-backend/internal/features/users/models"
+	"postgresus-backend/internal/storage"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserSessionRepository struct{}
+
+func (r *UserSessionRepository) Save(session *user_models.UserSession) error {
+	if session.ID == uuid.Nil {
+		session.ID = uuid.New()
+	}
+
+	return storage.GetDb().Save(session).Error
+}
+
+func (r *UserSessionRepository) FindByID(id uuid.UUID) (*user_models.UserSession, error) {
+	var session user_models.UserSession
+
+	if err := storage.
+		GetDb().
+		Where("id = ?", id).
+		First(&session).Error; err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (r *UserSessionRepository) FindByRefreshTokenHash(
+	refreshTokenHash string,
+) (*user_models.UserSession, error) {
+	var session user_models.UserSession
+
+	if err := storage.
+		GetDb().
+		Where("refresh_token_hash = ?", refreshTokenHash).
+		First(&session).Error; err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (r *UserSessionRepository) FindActiveByUserID(
+	userID uuid.UUID,
+) ([]*user_models.UserSession, error) {
+	var sessions []*user_models.UserSession
+
+	if err := storage.
+		GetDb().
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Where("expires_at > ?", time.Now().UTC()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (r *UserSessionRepository) RevokeByID(id uuid.UUID) error {
+	return storage.
+		GetDb().
+		Model(&user_models.UserSession{}).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", time.Now().UTC()).Error
+}
+
+func (r *UserSessionRepository) RevokeByUserID(userID uuid.UUID) error {
+	return storage.
+		GetDb().
+		Model(&user_models.UserSession{}).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", time.Now().UTC()).Error
+}
+
+// DeleteInactiveBefore removes sessions revoked or expired before the
+// given time
+func (r *UserSessionRepository) DeleteInactiveBefore(before time.Time) error {
+	return storage.
+		GetDb().
+		Where("revoked_at < ? OR expires_at < ?", before, before).
+		Delete(&user_models.UserSession{}).Error
+}
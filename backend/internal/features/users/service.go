@@ -1,22 +1,86 @@
 package users
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 
+	"postgresus-backend/internal/config"
 	user_enums "postgresus-backend/internal/features/users/enums"
 	user_models "postgresus-backend/internal/features/users/models"
+	users_oidc "postgresus-backend/internal/features/users/oidc"
 	user_repositories "postgresus-backend/internal/features/users/repositories"
+	"postgresus-backend/internal/util/totp"
 )
 
+const (
+	accessTokenLifetime  = 15 * time.Minute
+	refreshTokenLifetime = 30 * 24 * time.Hour
+
+	oidcStateTokenType     = "oidc_state"
+	oidcStateLifetime      = 10 * time.Minute
+	oidcVerifierCookieName = "postgresus_oidc_verifier"
+	oidcVerifierCookiePath = "/api/v1/users/oidc"
+	totpChallengeTokenType = "totp_challenge"
+	totpChallengeLifetime  = 5 * time.Minute
+
+	totpIssuer         = "Postgresus"
+	recoveryCodesCount = 10
+
+	apiKeyPrefix = "psk_"
+	// last usage of API key is updated not more often than once
+	// per interval, so each request of a pipeline does not write
+	apiKeyLastUsedUpdateInterval = time.Minute
+)
+
+// apiKeyScopeRoutes lists requests allowed for scopes in addition to
+// reading ones. Routes are gin path patterns
+var apiKeyScopeRoutes = map[user_enums.ApiKeyScope][]string{
+	user_enums.ApiKeyScopeBackup:  {"POST /api/v1/backups"},
+	user_enums.ApiKeyScopeRestore: {"POST /api/v1/restores/:backupId/restore"},
+}
+
+// apiKeyFullScopeReadingRoutes lists reading requests allowed only for
+// keys with full scope: they return backups data or the audit log
+// export instead of settings and statuses
+var apiKeyFullScopeReadingRoutes = []string{
+	"GET /api/v1/backups/:id/file",
+	"GET /api/v1/audit-logs/export",
+}
+
 type UserService struct {
-	userRepository      *user_repositories.UserRepository
-	secretKeyRepository *user_repositories.SecretKeyRepository
+	userRepository        *user_repositories.UserRepository
+	secretKeyRepository   *user_repositories.SecretKeyRepository
+	userSessionRepository *user_repositories.UserSessionRepository
+	apiKeyRepository      *user_repositories.ApiKeyRepository
+
+	recoveryCodeRepository     *user_repositories.UserRecoveryCodeRepository
+	securitySettingsRepository *user_repositories.SecuritySettingsRepository
+	oidcProvider               *users_oidc.OidcProvider
+	signinThrottler            *SigninThrottler
+
+	auditLogWriter       UserAuditLogWriter
+	accountLockListeners []AccountLockListener
+}
+
+func (s *UserService) SetAuditLogWriter(auditLogWriter UserAuditLogWriter) {
+	s.auditLogWriter = auditLogWriter
+}
+
+func (s *UserService) AddAccountLockListener(listener AccountLockListener) {
+	s.accountLockListeners = append(s.accountLockListeners, listener)
 }
 
 func (s *UserService) IsAnyUserExist() (bool, error) {
@@ -24,6 +88,10 @@ func (s *UserService) IsAnyUserExist() (bool, error) {
 }
 
 func (s *UserService) SignUp(request *SignUpRequest) error {
+	if config.GetEnv().IsPasswordLoginDisabled {
+		return errors.New("password sign up is disabled, sign in via SSO")
+	}
+
 	isAnyUserExists, err := s.userRepository.IsAnyUserExist()
 	if err != nil {
 		return fmt.Errorf("failed to check if any user exists: %w", err)
@@ -59,117 +127,1440 @@ func (s *UserService) SignUp(request *SignUpRequest) error {
 	return nil
 }
 
-func (s *UserService) SignIn(request *SignInRequest) (*SignInResponse, error) {
+func (s *UserService) SignIn(
+	request *SignInRequest,
+	userAgent string,
+	ipAddress string,
+) (*SignInResponse, error) {
+	if config.GetEnv().IsPasswordLoginDisabled {
+		return nil, errors.New("password sign in is disabled, sign in via SSO")
+	}
+
+	if err := s.signinThrottler.CheckLockout(ipAddress, request.Email); err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepository.GetUserByEmail(request.Email)
 	if err != nil {
+		s.registerFailedSignin(nil, request.Email, ipAddress, "unknown email")
 		return nil, errors.New("user with this email does not exist")
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.HashedPassword), []byte(request.Password))
 	if err != nil {
+		s.registerFailedSignin(user, user.Email, ipAddress, "incorrect password")
 		return nil, errors.New("password is incorrect")
 	}
 
-	return s.GenerateAccessToken(user)
+	totpChallenge, err := s.getTotpChallenge(user)
+	if err != nil {
+		return nil, err
+	}
+
+	if totpChallenge != nil {
+		return totpChallenge, nil
+	}
+
+	s.signinThrottler.RegisterSuccess(ipAddress, user.Email)
+
+	return s.CreateSession(user, userAgent, ipAddress)
 }
 
-func (s *UserService) GetUserFromToken(token string) (*user_models.User, error) {
-	secretKey, err := s.secretKeyRepository.GetSecretKey()
+// SignInWithTotp finishes password sign in with the second factor.
+// If enrollment is required, the code confirms the secret set up via
+// StartTotpEnrollment and recovery codes are returned with tokens
+func (s *UserService) SignInWithTotp(
+	request *TotpSignInRequest,
+	userAgent string,
+	ipAddress string,
+) (*SignInResponse, error) {
+	user, err := s.getUserFromTotpChallenge(request.TotpToken)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get secret key: %w", err)
+		return nil, err
+	}
+
+	// codes are short, so their failures lock the account the same
+	// way as failures of passwords
+	if err := s.signinThrottler.CheckLockout(ipAddress, user.Email); err != nil {
+		return nil, err
 	}
 
-	parsedToken, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	if user.IsTotpEnabled {
+		if err := s.verifySecondFactor(user, request.Code); err != nil {
+			s.registerFailedSignin(user, user.Email, ipAddress, "incorrect two-factor code")
+			return nil, err
 		}
-		return []byte(secretKey), nil
-	})
 
+		s.signinThrottler.RegisterSuccess(ipAddress, user.Email)
+
+		return s.CreateSession(user, userAgent, ipAddress)
+	}
+
+	recoveryCodes, err := s.enableTotp(user, request.Code)
 	if err != nil {
-		return nil, fmt.Errorf("invalid token: %w", err)
+		s.registerFailedSignin(user, user.Email, ipAddress, "incorrect two-factor code")
+		return nil, err
 	}
 
-	if claims, ok := parsedToken.Claims.(jwt.MapClaims); ok && parsedToken.Valid {
-		userID, ok := claims["sub"].(string)
-		if !ok {
-			return nil, errors.New("invalid token claims")
-		}
+	s.signinThrottler.RegisterSuccess(ipAddress, user.Email)
+
+	response, err := s.CreateSession(user, userAgent, ipAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	response.RecoveryCodes = recoveryCodes
+
+	return response, nil
+}
+
+// StartTotpEnrollment sets up two-factor authentication during sign
+// in of the user who has to enroll it
+func (s *UserService) StartTotpEnrollment(
+	request *TotpEnrollmentRequest,
+) (*TotpSetupResponse, error) {
+	user, err := s.getUserFromTotpChallenge(request.TotpToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.setupTotp(user)
+}
+
+func (s *UserService) GetOidcConfig() *OidcConfigResponse {
+	response := &OidcConfigResponse{
+		IsOidcEnabled:           s.oidcProvider.IsEnabled(),
+		IsPasswordLoginDisabled: config.GetEnv().IsPasswordLoginDisabled,
+	}
+
+	if response.IsOidcEnabled {
+		response.ProviderName = s.oidcProvider.GetProviderName()
+	}
+
+	return response
+}
+
+// GetOidcAuthorizationURL returns URL of the identity provider login
+// page and the PKCE code verifier. State is signed, so it is checked on
+// sign in without storing. The verifier is kept in the browser cookie
+// and its hash is put into the state, so the sign in can be finished
+// only by the browser which started it
+func (s *UserService) GetOidcAuthorizationURL() (string, string, error) {
+	if !s.oidcProvider.IsEnabled() {
+		return "", "", errors.New("SSO sign in is not configured")
+	}
+
+	nonce, err := generateRandomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	codeVerifier, err := generateRandomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := s.generateSignedToken(
+		oidcStateTokenType,
+		oidcStateLifetime,
+		jwt.MapClaims{"nonce": nonce, "verifierHash": hashToken(codeVerifier)},
+	)
+	if err != nil {
+		return "", "", err
+	}
+
+	authorizationURL, err := s.oidcProvider.GetAuthorizationURL(state, nonce, codeVerifier)
+	if err != nil {
+		return "", "", err
+	}
+
+	return authorizationURL, codeVerifier, nil
+}
+
+// SignInWithOidc signs in the user authenticated by the identity
+// provider. Users are created on first sign in with the role from
+// claims, so admins are granted by role mapping only. Roles of existing
+// users are synced if the claims of the user are mapped to a role.
+// Two-factor authentication is asked the same way as for password
+// sign in, SSO does not bypass it
+func (s *UserService) SignInWithOidc(
+	request *OidcSignInRequest,
+	codeVerifier string,
+	userAgent string,
+	ipAddress string,
+) (*SignInResponse, error) {
+	if !s.oidcProvider.IsEnabled() {
+		return nil, errors.New("SSO sign in is not configured")
+	}
+
+	nonce, err := s.parseOidcState(request.State, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := s.oidcProvider.Exchange(request.Code, nonce, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.getOidcUser(identity)
+	if err != nil {
+		return nil, err
+	}
 
-		user, err := s.userRepository.GetUserByID(userID)
+	if user == nil {
+		user, err = s.createOidcUser(identity)
 		if err != nil {
 			return nil, err
 		}
+	} else if err := s.syncOidcUserRole(user, identity); err != nil {
+		return nil, err
+	}
 
-		if passwordCreationTimeUnix, ok := claims["passwordCreationTime"].(float64); ok {
-			tokenPasswordTime := time.Unix(int64(passwordCreationTimeUnix), 0)
+	totpChallenge, err := s.getTotpChallenge(user)
+	if err != nil {
+		return nil, err
+	}
 
-			tokenTimeSeconds := tokenPasswordTime.Truncate(time.Second)
-			userTimeSeconds := user.PasswordCreationTime.Truncate(time.Second)
+	if totpChallenge != nil {
+		return totpChallenge, nil
+	}
 
-			if !tokenTimeSeconds.Equal(userTimeSeconds) {
-				return nil, errors.New("password has been changed, please sign in again")
-			}
-		} else {
-			return nil, errors.New("invalid token claims: missing password creation time")
-		}
+	return s.CreateSession(user, userAgent, ipAddress)
+}
 
-		return user, nil
+// CreateSession starts a new session of the user and issues access
+// and refresh tokens for it
+func (s *UserService) CreateSession(
+	user *user_models.User,
+	userAgent string,
+	ipAddress string,
+) (*SignInResponse, error) {
+	now := time.Now().UTC()
+
+	if err := s.userSessionRepository.DeleteInactiveBefore(
+		now.Add(-refreshTokenLifetime),
+	); err != nil {
+		return nil, fmt.Errorf("failed to delete inactive sessions: %w", err)
+	}
+
+	refreshToken, err := generateRandomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &user_models.UserSession{
+		UserID:           user.ID,
+		RefreshTokenHash: hashToken(refreshToken),
+		UserAgent:        userAgent,
+		IpAddress:        ipAddress,
+		CreatedAt:        now,
+		LastUsedAt:       now,
+		ExpiresAt:        now.Add(refreshTokenLifetime),
+	}
+
+	if err := s.userSessionRepository.Save(session); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
-	return nil, errors.New("invalid token")
+	return s.generateAccessToken(user, session, refreshToken)
 }
 
-func (s *UserService) ChangePassword(newPassword string) error {
-	exists, err := s.userRepository.IsAnyUserExist()
-	if err != nil || !exists {
-		return errors.New("no users exist to change password")
+// RefreshSession issues new access token for the session of the
+// refresh token. The refresh token is rotated, so the used one
+// cannot be used again
+func (s *UserService) RefreshSession(
+	request *RefreshTokenRequest,
+	userAgent string,
+	ipAddress string,
+) (*SignInResponse, error) {
+	session, err := s.userSessionRepository.FindByRefreshTokenHash(
+		hashToken(request.RefreshToken),
+	)
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	if !session.IsActive() {
+		return nil, errors.New("session is expired or revoked, please sign in again")
 	}
 
-	user, err := s.userRepository.GetFirstUser()
+	user, err := s.userRepository.GetUserByID(session.UserID.String())
 	if err != nil {
-		return fmt.Errorf("failed to get user: %w", err)
+		return nil, err
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	refreshToken, err := generateRandomToken()
 	if err != nil {
-		return fmt.Errorf("failed to hash password: %w", err)
+		return nil, err
 	}
 
-	if err := s.userRepository.UpdateUserPassword(user.ID, string(hashedPassword)); err != nil {
-		return fmt.Errorf("failed to update password: %w", err)
+	now := time.Now().UTC()
+
+	session.RefreshTokenHash = hashToken(refreshToken)
+	session.UserAgent = userAgent
+	session.IpAddress = ipAddress
+	session.LastUsedAt = now
+	session.ExpiresAt = now.Add(refreshTokenLifetime)
+
+	if err := s.userSessionRepository.Save(session); err != nil {
+		return nil, fmt.Errorf("failed to update session: %w", err)
+	}
+
+	return s.generateAccessToken(user, session, refreshToken)
+}
+
+// GetUserFromToken returns the user of access token or API key
+func (s *UserService) GetUserFromToken(token string) (*user_models.User, error) {
+	if IsApiKey(token) {
+		return s.getUserFromApiKey(token)
+	}
+
+	user, _, err := s.parseAccessToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *UserService) GetSessionIDFromToken(token string) (uuid.UUID, error) {
+	_, session, err := s.parseAccessToken(token)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return session.ID, nil
+}
+
+// Logout revokes the session of the token, refresh token of the
+// session stops working immediately and access token is rejected
+func (s *UserService) Logout(token string) error {
+	_, session, err := s.parseAccessToken(token)
+	if err != nil {
+		return err
+	}
+
+	return s.userSessionRepository.RevokeByID(session.ID)
+}
+
+// GetActiveSessions returns not revoked and not expired sessions of
+// the user. The session with the given ID is marked as current
+func (s *UserService) GetActiveSessions(
+	userID uuid.UUID,
+	currentSessionID uuid.UUID,
+) ([]*user_models.UserSession, error) {
+	sessions, err := s.userSessionRepository.FindActiveByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		session.IsCurrent = session.ID == currentSessionID
+	}
+
+	return sessions, nil
+}
+
+func (s *UserService) RevokeOwnSession(user *user_models.User, sessionID uuid.UUID) error {
+	session, err := s.userSessionRepository.FindByID(sessionID)
+	if err != nil {
+		return errors.New("session not found")
+	}
+
+	if session.UserID != user.ID {
+		return errors.New("session not found")
+	}
+
+	return s.userSessionRepository.RevokeByID(session.ID)
+}
+
+// RevokeAllSessions signs the user out on all devices
+func (s *UserService) RevokeAllSessions(admin *user_models.User, userID uuid.UUID) error {
+	user, err := s.userRepository.GetUserByID(userID.String())
+	if err != nil {
+		return err
 	}
 
+	if err := s.userSessionRepository.RevokeByUserID(user.ID); err != nil {
+		return err
+	}
+
+	s.writeAuditLog(
+		admin,
+		"REVOKE_SESSIONS",
+		user,
+		user,
+		fmt.Sprintf("Revoked all sessions of user %s", user.Email),
+	)
+
 	return nil
 }
 
-func (s *UserService) GetFirstUser() (*user_models.User, error) {
-	return s.userRepository.GetFirstUser()
+// RotateSecretKey replaces the key used to sign access tokens. The
+// previous key keeps verifying tokens for the lifetime of an access
+// token, so users are not signed out: their sessions get tokens
+// signed by the new key on the next refresh
+func (s *UserService) RotateSecretKey(admin *user_models.User) error {
+	previousKeysExpireAt := time.Now().UTC().Add(accessTokenLifetime)
+
+	if _, err := s.secretKeyRepository.RotateSecretKey(previousKeysExpireAt); err != nil {
+		return fmt.Errorf("failed to rotate secret key: %w", err)
+	}
+
+	s.writeAuditLog(admin, "ROTATE_SECRET_KEY", admin, admin, "Rotated secret key of tokens")
+
+	return nil
 }
 
-func (s *UserService) GenerateAccessToken(user *user_models.User) (*SignInResponse, error) {
-	secretKey, err := s.secretKeyRepository.GetSecretKey()
+// CheckApiKeyScope checks the request is allowed by scope of the API
+// key. Reading requests are allowed for all scopes, except downloading
+// backups and exporting the audit log
+func (s *UserService) CheckApiKeyScope(token string, method string, route string) error {
+	apiKey, err := s.getApiKey(token)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get secret key: %w", err)
+		return err
 	}
 
-	tenYearsExpiration := time.Now().UTC().Add(time.Hour * 24 * 365 * 10)
+	if apiKey.Scope == user_enums.ApiKeyScopeFull {
+		return nil
+	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub":                  user.ID,
-		"exp":                  tenYearsExpiration.Unix(),
-		"iat":                  time.Now().UTC().Unix(),
-		"role":                 string(user.Role),
-		"passwordCreationTime": user.PasswordCreationTime.Unix(),
-	})
+	request := method + " " + route
+	if method == http.MethodGet && !slices.Contains(apiKeyFullScopeReadingRoutes, request) {
+		return nil
+	}
+
+	if slices.Contains(apiKeyScopeRoutes[apiKey.Scope], request) {
+		return nil
+	}
+
+	return fmt.Errorf("request is not allowed for API key with scope %s", apiKey.Scope)
+}
 
-	tokenString, err := token.SignedString([]byte(secretKey))
+// CreateApiKey creates a key acting on behalf of the user. The key is
+// returned only once, it cannot be read later
+func (s *UserService) CreateApiKey(
+	user *user_models.User,
+	request *CreateApiKeyRequest,
+) (*CreateApiKeyResponse, error) {
+	if err := request.Scope.Validate(); err != nil {
+		return nil, err
+	}
+
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now().UTC()) {
+		return nil, errors.New("expiration time should be in the future")
+	}
+
+	randomPart, err := generateRandomToken()
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
+		return nil, err
 	}
 
-	return &SignInResponse{
-		UserID: user.ID,
-		Token:  tokenString,
+	key := apiKeyPrefix + randomPart
+
+	apiKey := &user_models.ApiKey{
+		UserID:    user.ID,
+		Name:      request.Name,
+		KeyPrefix: key[:12],
+		KeyHash:   hashToken(key),
+		Scope:     request.Scope,
+		ExpiresAt: request.ExpiresAt,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := s.apiKeyRepository.Save(apiKey); err != nil {
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	s.writeAuditLog(
+		user,
+		"CREATE",
+		user,
+		user,
+		fmt.Sprintf("Created API key \"%s\" with scope %s", apiKey.Name, apiKey.Scope),
+	)
+
+	return &CreateApiKeyResponse{
+		ApiKey: apiKey,
+		Key:    key,
 	}, nil
 }
+
+func (s *UserService) GetApiKeys(user *user_models.User) ([]*user_models.ApiKey, error) {
+	return s.apiKeyRepository.FindByUserID(user.ID)
+}
+
+// DeleteApiKey revokes the key. Admins can revoke keys of any user
+func (s *UserService) DeleteApiKey(user *user_models.User, apiKeyID uuid.UUID) error {
+	apiKey, err := s.apiKeyRepository.FindByID(apiKeyID)
+	if err != nil {
+		return errors.New("API key not found")
+	}
+
+	if apiKey.UserID != user.ID && user.Role != user_enums.UserRoleAdmin {
+		return errors.New("API key not found")
+	}
+
+	if err := s.apiKeyRepository.DeleteByID(apiKey.ID); err != nil {
+		return err
+	}
+
+	s.writeAuditLog(
+		user,
+		"DELETE",
+		user,
+		user,
+		fmt.Sprintf("Deleted API key \"%s\"", apiKey.Name),
+	)
+
+	return nil
+}
+
+// SetupTotp generates a new secret for the authenticator app. Two-factor
+// authentication is enabled after the first code is confirmed
+func (s *UserService) SetupTotp(user *user_models.User) (*TotpSetupResponse, error) {
+	return s.setupTotp(user)
+}
+
+func (s *UserService) EnableTotp(
+	user *user_models.User,
+	request *TotpCodeRequest,
+) ([]string, error) {
+	if user.IsTotpEnabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	return s.enableTotp(user, request.Code)
+}
+
+func (s *UserService) DisableTotp(user *user_models.User, request *TotpCodeRequest) error {
+	if !user.IsTotpEnabled {
+		return errors.New("two-factor authentication is not enabled")
+	}
+
+	securitySettings, err := s.securitySettingsRepository.GetSecuritySettings()
+	if err != nil {
+		return err
+	}
+
+	if securitySettings.IsTotpRequired {
+		return errors.New("two-factor authentication is required for all users")
+	}
+
+	if err := s.verifySecondFactor(user, request.Code); err != nil {
+		return err
+	}
+
+	if err := s.clearTotp(user); err != nil {
+		return err
+	}
+
+	oldUser := *user
+	user.IsTotpEnabled = false
+
+	s.writeAuditLog(user, "UPDATE", &oldUser, user, "Disabled two-factor authentication")
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces recovery codes, previous ones stop
+// working. Only the code of authenticator app is accepted
+func (s *UserService) RegenerateRecoveryCodes(
+	user *user_models.User,
+	request *TotpCodeRequest,
+) ([]string, error) {
+	if !user.IsTotpEnabled {
+		return nil, errors.New("two-factor authentication is not enabled")
+	}
+
+	if err := s.useTotpCode(user, request.Code); err != nil {
+		return nil, err
+	}
+
+	return s.generateRecoveryCodes(user)
+}
+
+// ResetUserTotp disables two-factor authentication of the user who
+// lost both authenticator app and recovery codes
+func (s *UserService) ResetUserTotp(admin *user_models.User, userID uuid.UUID) error {
+	user, err := s.userRepository.GetUserByID(userID.String())
+	if err != nil {
+		return err
+	}
+
+	if err := s.clearTotp(user); err != nil {
+		return err
+	}
+
+	oldUser := *user
+	user.IsTotpEnabled = false
+
+	s.writeAuditLog(
+		admin,
+		"UPDATE",
+		&oldUser,
+		user,
+		fmt.Sprintf("Reset two-factor authentication of user %s", user.Email),
+	)
+
+	return nil
+}
+
+func (s *UserService) GetSecuritySettings() (*user_models.SecuritySettings, error) {
+	return s.securitySettingsRepository.GetSecuritySettings()
+}
+
+func (s *UserService) UpdateSecuritySettings(
+	admin *user_models.User,
+	request *user_models.SecuritySettings,
+) (*user_models.SecuritySettings, error) {
+	securitySettings, err := s.securitySettingsRepository.GetSecuritySettings()
+	if err != nil {
+		return nil, err
+	}
+
+	securitySettings.IsTotpRequired = request.IsTotpRequired
+
+	if err := s.securitySettingsRepository.Save(securitySettings); err != nil {
+		return nil, err
+	}
+
+	message := "Made two-factor authentication optional"
+	if securitySettings.IsTotpRequired {
+		message = "Required two-factor authentication for all users"
+	}
+
+	s.writeAuditLog(admin, "UPDATE", admin, admin, message)
+
+	return securitySettings, nil
+}
+
+// ResetPassword sets a new password without knowing the current one,
+// it is used from the command line when the password is lost. Email
+// may be omitted only if there is a single user
+func (s *UserService) ResetPassword(email string, newPassword string) error {
+	users, err := s.userRepository.GetUsers()
+	if err != nil {
+		return fmt.Errorf("failed to get users: %w", err)
+	}
+
+	if len(users) == 0 {
+		return errors.New("no users exist to change password")
+	}
+
+	var user *user_models.User
+	if email == "" {
+		if len(users) > 1 {
+			return errors.New("there are several users, email is required")
+		}
+
+		user = users[0]
+	} else {
+		user, err = s.userRepository.GetUserByEmail(email)
+		if err != nil {
+			return errors.New("user with this email does not exist")
+		}
+	}
+
+	if err := s.updatePassword(user, newPassword); err != nil {
+		return err
+	}
+
+	s.writeAuditLog(nil, "RESET_PASSWORD", user, user, "Reset password via command line")
+
+	return nil
+}
+
+func (s *UserService) ChangePassword(
+	user *user_models.User,
+	request *ChangePasswordRequest,
+) error {
+	err := bcrypt.CompareHashAndPassword(
+		[]byte(user.HashedPassword),
+		[]byte(request.CurrentPassword),
+	)
+	if err != nil {
+		return errors.New("current password is incorrect")
+	}
+
+	if err := s.updatePassword(user, request.NewPassword); err != nil {
+		return err
+	}
+
+	s.writeAuditLog(user, "CHANGE_PASSWORD", user, user, "Changed own password")
+
+	return nil
+}
+
+func (s *UserService) CreateUser(
+	admin *user_models.User,
+	request *CreateUserRequest,
+) (*user_models.User, error) {
+	if err := request.Role.Validate(); err != nil {
+		return nil, err
+	}
+
+	existingUser, err := s.userRepository.GetUserByEmail(request.Email)
+	if err == nil && existingUser != nil {
+		return nil, errors.New("user with this email already exists")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user := &user_models.User{
+		ID:                   uuid.New(),
+		Email:                request.Email,
+		HashedPassword:       string(hashedPassword),
+		PasswordCreationTime: time.Now().UTC(),
+		CreatedAt:            time.Now().UTC(),
+		Role:                 request.Role,
+	}
+
+	if err := s.userRepository.CreateUser(user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	s.writeAuditLog(
+		admin,
+		"CREATE",
+		nil,
+		user,
+		fmt.Sprintf("Created user %s with role %s", user.Email, user.Role),
+	)
+
+	return user, nil
+}
+
+func (s *UserService) GetUsers() ([]*user_models.User, error) {
+	return s.userRepository.GetUsers()
+}
+
+func (s *UserService) ChangeUserRole(
+	admin *user_models.User,
+	userID uuid.UUID,
+	role user_enums.UserRole,
+) (*user_models.User, error) {
+	if err := role.Validate(); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepository.GetUserByID(userID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role == role {
+		return user, nil
+	}
+
+	if user.Role == user_enums.UserRoleAdmin {
+		if err := s.validateNotLastAdmin(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.userRepository.UpdateUserRole(user.ID, role); err != nil {
+		return nil, err
+	}
+
+	oldUser := *user
+	user.Role = role
+
+	s.writeAuditLog(
+		admin,
+		"UPDATE",
+		&oldUser,
+		user,
+		fmt.Sprintf("Changed role of user %s from %s to %s", user.Email, oldUser.Role, role),
+	)
+
+	return user, nil
+}
+
+func (s *UserService) DeleteUser(admin *user_models.User, userID uuid.UUID) error {
+	if admin.ID == userID {
+		return errors.New("you cannot delete yourself")
+	}
+
+	user, err := s.userRepository.GetUserByID(userID.String())
+	if err != nil {
+		return err
+	}
+
+	if user.Role == user_enums.UserRoleAdmin {
+		if err := s.validateNotLastAdmin(); err != nil {
+			return err
+		}
+	}
+
+	if err := s.userRepository.DeleteUser(user.ID, admin.ID); err != nil {
+		return err
+	}
+
+	s.writeAuditLog(admin, "DELETE", user, nil, fmt.Sprintf("Deleted user %s", user.Email))
+
+	return nil
+}
+
+func (s *UserService) GetUserByEmail(email string) (*user_models.User, error) {
+	return s.userRepository.GetUserByEmail(email)
+}
+
+func (s *UserService) GetFirstUser() (*user_models.User, error) {
+	return s.userRepository.GetFirstUser()
+}
+
+func (s *UserService) updatePassword(user *user_models.User, newPassword string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	if err := s.userRepository.UpdateUserPassword(user.ID, string(hashedPassword)); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	if err := s.userSessionRepository.RevokeByUserID(user.ID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return nil
+}
+
+func (s *UserService) generateAccessToken(
+	user *user_models.User,
+	session *user_models.UserSession,
+	refreshToken string,
+) (*SignInResponse, error) {
+	secretKey, err := s.secretKeyRepository.GetSecretKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret key: %w", err)
+	}
+
+	expiresAt := time.Now().UTC().Add(accessTokenLifetime)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":                  user.ID,
+		"sid":                  session.ID,
+		"exp":                  expiresAt.Unix(),
+		"iat":                  time.Now().UTC().Unix(),
+		"role":                 string(user.Role),
+		"passwordCreationTime": user.PasswordCreationTime.Unix(),
+	})
+	token.Header["kid"] = secretKey.ID.String()
+
+	tokenString, err := token.SignedString([]byte(secretKey.Secret))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return &SignInResponse{
+		UserID:       user.ID,
+		Token:        tokenString,
+		RefreshToken: refreshToken,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
+// parseAccessToken validates the token and returns its user and
+// session. Tokens of revoked sessions are rejected even if they are
+// not expired yet
+func (s *UserService) parseAccessToken(
+	token string,
+) (*user_models.User, *user_models.UserSession, error) {
+	parsedToken, err := jwt.Parse(token, s.getSecretKeyForToken)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	claims, ok := parsedToken.Claims.(jwt.MapClaims)
+	if !ok || !parsedToken.Valid {
+		return nil, nil, errors.New("invalid token")
+	}
+
+	userID, ok := claims["sub"].(string)
+	if !ok {
+		return nil, nil, errors.New("invalid token claims")
+	}
+
+	sessionIDClaim, ok := claims["sid"].(string)
+	if !ok {
+		return nil, nil, errors.New("invalid token claims: missing session")
+	}
+
+	sessionID, err := uuid.Parse(sessionIDClaim)
+	if err != nil {
+		return nil, nil, errors.New("invalid token claims: invalid session")
+	}
+
+	user, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	session, err := s.userSessionRepository.FindByID(sessionID)
+	if err != nil {
+		return nil, nil, errors.New("session not found")
+	}
+
+	if session.UserID != user.ID || !session.IsActive() {
+		return nil, nil, errors.New("session is expired or revoked, please sign in again")
+	}
+
+	if passwordCreationTimeUnix, ok := claims["passwordCreationTime"].(float64); ok {
+		tokenPasswordTime := time.Unix(int64(passwordCreationTimeUnix), 0)
+
+		tokenTimeSeconds := tokenPasswordTime.Truncate(time.Second)
+		userTimeSeconds := user.PasswordCreationTime.Truncate(time.Second)
+
+		if !tokenTimeSeconds.Equal(userTimeSeconds) {
+			return nil, nil, errors.New("password has been changed, please sign in again")
+		}
+	} else {
+		return nil, nil, errors.New("invalid token claims: missing password creation time")
+	}
+
+	return user, session, nil
+}
+
+// getOidcUser returns the user linked to the identity or nil if there
+// is no such user. Existing user is linked by email only if the provider
+// verified the email, otherwise anybody who registers the email of
+// admin at the provider would sign in as admin
+func (s *UserService) getOidcUser(identity *users_oidc.OidcIdentity) (*user_models.User, error) {
+	user, err := s.userRepository.GetUserByOidcSubject(identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	if user != nil {
+		return user, nil
+	}
+
+	user, err = s.userRepository.GetUserByEmail(identity.Email)
+	if err != nil {
+		return nil, nil
+	}
+
+	if user.OidcSubject != "" {
+		return nil, errors.New("user with this email is linked to another SSO account")
+	}
+
+	if !identity.IsEmailVerified {
+		return nil, errors.New(
+			"email is not verified by the identity provider, it cannot be linked to existing user",
+		)
+	}
+
+	if err := s.userRepository.UpdateUserOidcSubject(user.ID, identity.Subject); err != nil {
+		return nil, err
+	}
+
+	oldUser := *user
+	user.OidcSubject = identity.Subject
+
+	s.writeAuditLog(
+		nil,
+		"UPDATE",
+		&oldUser,
+		user,
+		fmt.Sprintf("Linked user %s to SSO account on sign in", user.Email),
+	)
+
+	return user, nil
+}
+
+func (s *UserService) createOidcUser(identity *users_oidc.OidcIdentity) (*user_models.User, error) {
+	role := identity.Role
+	if role == "" {
+		return nil, errors.New("you have no role in Postgresus, ask admin to grant access")
+	}
+
+	// users of identity provider sign in via SSO only, so the
+	// password is random and nobody knows it
+	password, err := generateRandomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user := &user_models.User{
+		ID:                   uuid.New(),
+		Email:                identity.Email,
+		HashedPassword:       string(hashedPassword),
+		PasswordCreationTime: time.Now().UTC(),
+		CreatedAt:            time.Now().UTC(),
+		Role:                 role,
+		OidcSubject:          identity.Subject,
+	}
+
+	if err := s.userRepository.CreateUser(user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	s.writeAuditLog(
+		nil,
+		"CREATE",
+		nil,
+		user,
+		fmt.Sprintf("Created user %s with role %s on first SSO sign in", user.Email, user.Role),
+	)
+
+	return user, nil
+}
+
+// syncOidcUserRole applies the role mapped from claims. The default
+// role is not applied, so roles changed by admins are kept. The last
+// admin is never demoted to not lose access to settings
+func (s *UserService) syncOidcUserRole(
+	user *user_models.User,
+	identity *users_oidc.OidcIdentity,
+) error {
+	if !identity.IsRoleMapped || user.Role == identity.Role {
+		return nil
+	}
+
+	if user.Role == user_enums.UserRoleAdmin {
+		adminsCount, err := s.userRepository.CountUsersByRole(user_enums.UserRoleAdmin)
+		if err != nil {
+			return err
+		}
+
+		if adminsCount <= 1 {
+			return nil
+		}
+	}
+
+	if err := s.userRepository.UpdateUserRole(user.ID, identity.Role); err != nil {
+		return err
+	}
+
+	oldUser := *user
+	user.Role = identity.Role
+
+	s.writeAuditLog(
+		nil,
+		"UPDATE",
+		&oldUser,
+		user,
+		fmt.Sprintf(
+			"Changed role of user %s from %s to %s by SSO claims",
+			user.Email,
+			oldUser.Role,
+			user.Role,
+		),
+	)
+
+	return nil
+}
+
+// generateSignedToken signs short-lived tokens of sign in steps, e.g.
+// OIDC state. Type claim does not let to use one token for another
+// step, access tokens are never accepted because they have session
+func (s *UserService) generateSignedToken(
+	tokenType string,
+	lifetime time.Duration,
+	claims jwt.MapClaims,
+) (string, error) {
+	secretKey, err := s.secretKeyRepository.GetSecretKey()
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret key: %w", err)
+	}
+
+	claims["typ"] = tokenType
+	claims["exp"] = time.Now().UTC().Add(lifetime).Unix()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = secretKey.ID.String()
+
+	return token.SignedString([]byte(secretKey.Secret))
+}
+
+func (s *UserService) parseSignedToken(token string, tokenType string) (jwt.MapClaims, error) {
+	parsedToken, err := jwt.Parse(token, s.getSecretKeyForToken)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := parsedToken.Claims.(jwt.MapClaims)
+	if !ok || !parsedToken.Valid || claims["typ"] != tokenType {
+		return nil, errors.New("invalid token type")
+	}
+
+	return claims, nil
+}
+
+// parseOidcState returns the nonce of the state. The state should be
+// issued for the code verifier of the browser, otherwise the attacker
+// could make the user sign in into the account of the attacker
+func (s *UserService) parseOidcState(state string, codeVerifier string) (string, error) {
+	claims, err := s.parseSignedToken(state, oidcStateTokenType)
+	if err != nil {
+		return "", errors.New("SSO sign in is expired, please try again")
+	}
+
+	nonce, ok := claims["nonce"].(string)
+	if !ok {
+		return "", errors.New("invalid SSO state")
+	}
+
+	verifierHash, ok := claims["verifierHash"].(string)
+	if !ok || codeVerifier == "" ||
+		subtle.ConstantTimeCompare([]byte(verifierHash), []byte(hashToken(codeVerifier))) != 1 {
+		return "", errors.New("SSO sign in was started in another browser, please try again")
+	}
+
+	return nonce, nil
+}
+
+// createTotpChallenge is returned by password sign in instead of
+// tokens when the second factor is needed. Its token lets to pass
+// the code or enroll two-factor authentication if it is required
+func (s *UserService) createTotpChallenge(
+	user *user_models.User,
+	isEnrollmentRequired bool,
+) (*SignInResponse, error) {
+	totpToken, err := s.generateSignedToken(
+		totpChallengeTokenType,
+		totpChallengeLifetime,
+		jwt.MapClaims{"sub": user.ID.String()},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate two-factor token: %w", err)
+	}
+
+	return &SignInResponse{
+		UserID:                   user.ID,
+		IsTotpRequired:           !isEnrollmentRequired,
+		IsTotpEnrollmentRequired: isEnrollmentRequired,
+		TotpToken:                totpToken,
+	}, nil
+}
+
+// getTotpChallenge returns the challenge if the user has two-factor
+// authentication or it is required for all users, nil otherwise
+func (s *UserService) getTotpChallenge(user *user_models.User) (*SignInResponse, error) {
+	if user.IsTotpEnabled {
+		return s.createTotpChallenge(user, false)
+	}
+
+	securitySettings, err := s.securitySettingsRepository.GetSecuritySettings()
+	if err != nil {
+		return nil, err
+	}
+
+	if securitySettings.IsTotpRequired {
+		return s.createTotpChallenge(user, true)
+	}
+
+	return nil, nil
+}
+
+func (s *UserService) getUserFromTotpChallenge(totpToken string) (*user_models.User, error) {
+	claims, err := s.parseSignedToken(totpToken, totpChallengeTokenType)
+	if err != nil {
+		return nil, errors.New("two-factor sign in is expired, please sign in again")
+	}
+
+	userID, ok := claims["sub"].(string)
+	if !ok {
+		return nil, errors.New("invalid two-factor token")
+	}
+
+	return s.userRepository.GetUserByID(userID)
+}
+
+func (s *UserService) setupTotp(user *user_models.User) (*TotpSetupResponse, error) {
+	if user.IsTotpEnabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepository.UpdateUserTotp(user.ID, secret, false); err != nil {
+		return nil, err
+	}
+
+	return &TotpSetupResponse{
+		Secret:     secret,
+		OtpauthURL: totp.GetOtpauthURL(totpIssuer, user.Email, secret),
+	}, nil
+}
+
+// enableTotp confirms the secret set up before and issues new
+// recovery codes
+func (s *UserService) enableTotp(user *user_models.User, code string) ([]string, error) {
+	if user.TotpSecret == "" {
+		return nil, errors.New("set up two-factor authentication first")
+	}
+
+	if err := s.useTotpCode(user, code); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepository.UpdateUserTotp(user.ID, user.TotpSecret, true); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := s.generateRecoveryCodes(user)
+	if err != nil {
+		return nil, err
+	}
+
+	oldUser := *user
+	user.IsTotpEnabled = true
+
+	s.writeAuditLog(user, "UPDATE", &oldUser, user, "Enabled two-factor authentication")
+
+	return recoveryCodes, nil
+}
+
+// verifySecondFactor accepts either TOTP code or unused recovery
+// code. Recovery code is marked as used
+func (s *UserService) verifySecondFactor(user *user_models.User, code string) error {
+	if _, isTotpCode := totp.GetCodeStep(user.TotpSecret, code, time.Now().UTC()); isTotpCode {
+		return s.useTotpCode(user, code)
+	}
+
+	isRecoveryCodeUsed, err := s.recoveryCodeRepository.UseCode(
+		user.ID,
+		hashToken(normalizeRecoveryCode(code)),
+	)
+	if err != nil {
+		return err
+	}
+
+	if !isRecoveryCodeUsed {
+		return errors.New("invalid two-factor code")
+	}
+
+	return nil
+}
+
+// useTotpCode accepts the code of authenticator app only once. Codes
+// are valid for about 90 seconds, so anybody who saw the code could
+// use it again otherwise
+func (s *UserService) useTotpCode(user *user_models.User, code string) error {
+	step, isValid := totp.GetCodeStep(user.TotpSecret, code, time.Now().UTC())
+	if !isValid {
+		return errors.New("invalid two-factor code")
+	}
+
+	isStepUsed, err := s.userRepository.UseTotpStep(user.ID, step)
+	if err != nil {
+		return err
+	}
+
+	if !isStepUsed {
+		return errors.New("two-factor code is already used, wait for the next one")
+	}
+
+	user.TotpLastUsedStep = step
+
+	return nil
+}
+
+func (s *UserService) generateRecoveryCodes(user *user_models.User) ([]string, error) {
+	recoveryCodes := make([]string, 0, recoveryCodesCount)
+	codeHashes := make([]string, 0, recoveryCodesCount)
+
+	for range recoveryCodesCount {
+		codeBytes := make([]byte, 5)
+		if _, err := rand.Read(codeBytes); err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+
+		code := hex.EncodeToString(codeBytes)
+		code = code[:5] + "-" + code[5:]
+
+		recoveryCodes = append(recoveryCodes, code)
+		codeHashes = append(codeHashes, hashToken(code))
+	}
+
+	if err := s.recoveryCodeRepository.ReplaceByUserID(user.ID, codeHashes); err != nil {
+		return nil, fmt.Errorf("failed to save recovery codes: %w", err)
+	}
+
+	return recoveryCodes, nil
+}
+
+func (s *UserService) getApiKey(key string) (*user_models.ApiKey, error) {
+	apiKey, err := s.apiKeyRepository.FindByKeyHash(hashToken(key))
+	if err != nil {
+		return nil, errors.New("invalid API key")
+	}
+
+	if apiKey.IsExpired() {
+		return nil, errors.New("API key is expired")
+	}
+
+	return apiKey, nil
+}
+
+func (s *UserService) getUserFromApiKey(key string) (*user_models.User, error) {
+	apiKey, err := s.getApiKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepository.GetUserByID(apiKey.UserID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > apiKeyLastUsedUpdateInterval {
+		if err := s.apiKeyRepository.UpdateLastUsedAt(apiKey.ID, now); err != nil {
+			return nil, fmt.Errorf("failed to update API key usage: %w", err)
+		}
+	}
+
+	return user, nil
+}
+
+// getSecretKeyForToken picks the key which signed the token, so tokens
+// signed before key rotation are still verified
+func (s *UserService) getSecretKeyForToken(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	keyID, ok := token.Header["kid"].(string)
+	if !ok {
+		return nil, errors.New("missing secret key ID")
+	}
+
+	secretKeyID, err := uuid.Parse(keyID)
+	if err != nil {
+		return nil, errors.New("invalid secret key ID")
+	}
+
+	secretKey, err := s.secretKeyRepository.GetValidSecretKeyByID(secretKeyID)
+	if err != nil {
+		return nil, errors.New("secret key is expired or unknown")
+	}
+
+	return []byte(secretKey.Secret), nil
+}
+
+func (s *UserService) clearTotp(user *user_models.User) error {
+	if err := s.userRepository.UpdateUserTotp(user.ID, "", false); err != nil {
+		return err
+	}
+
+	return s.recoveryCodeRepository.DeleteByUserID(user.ID)
+}
+
+func (s *UserService) validateNotLastAdmin() error {
+	adminsCount, err := s.userRepository.CountUsersByRole(user_enums.UserRoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	if adminsCount <= 1 {
+		return errors.New("at least one admin should remain")
+	}
+
+	return nil
+}
+
+func (s *UserService) writeAuditLog(
+	actor *user_models.User,
+	action string,
+	before *user_models.User,
+	after *user_models.User,
+	message string,
+) {
+	if s.auditLogWriter != nil {
+		s.auditLogWriter.WriteUserAuditLog(actor, action, before, after, message)
+	}
+}
+
+// registerFailedSignin counts the failed attempt and records it into
+// audit log. If the attempt locks the account, listeners are notified
+func (s *UserService) registerFailedSignin(
+	user *user_models.User,
+	email string,
+	ipAddress string,
+	reason string,
+) {
+	lockedUntil := s.signinThrottler.RegisterFailure(ipAddress, email)
+
+	if s.auditLogWriter != nil {
+		s.auditLogWriter.WriteSigninAuditLog(
+			"FAILED_SIGNIN",
+			user,
+			email,
+			fmt.Sprintf("Failed sign in from %s: %s", ipAddress, reason),
+		)
+	}
+
+	if lockedUntil == nil {
+		return
+	}
+
+	if s.auditLogWriter != nil {
+		s.auditLogWriter.WriteSigninAuditLog(
+			"LOCK_ACCOUNT",
+			user,
+			email,
+			fmt.Sprintf(
+				"Locked sign in until %s after failed attempts from %s",
+				lockedUntil.Format(time.RFC3339),
+				ipAddress,
+			),
+		)
+	}
+
+	if user == nil {
+		return
+	}
+
+	for _, listener := range s.accountLockListeners {
+		listener.OnAccountLocked(user, ipAddress, *lockedUntil)
+	}
+}
+
+// IsApiKey checks the token of Authorization header is an API key
+// rather than access token
+func IsApiKey(token string) bool {
+	return strings.HasPrefix(token, apiKeyPrefix)
+}
+
+func generateRandomToken() (string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(tokenBytes), nil
+}
+
+// normalizeRecoveryCode lets to type recovery codes in any case and
+// without dash
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	if len(code) != 10 {
+		return code
+	}
+
+	return code[:5] + "-" + code[5:]
+}
+
+// hashToken hashes refresh tokens and API keys before they are
+// stored. They are random, so plain SHA-256 is enough
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
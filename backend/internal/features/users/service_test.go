@@ -0,0 +1,712 @@
+package users
+
+import (
+	"fmt"
+	"net/http"
+	user_enums "postgresus-backend/internal/features/users/enums"
+	user_models "postgresus-backend/internal/features/users/models"
+	"postgresus-backend/internal/storage"
+	"postgresus-backend/internal/util/totp"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testUserPassword = "testtest"
+	testTotpPeriod   = 30 * time.Second
+)
+
+func Test_SignIn_WhenTotpEnabled_SessionCreatedOnlyAfterCode(t *testing.T) {
+	// setup data
+	user := createTestUser(t, user_enums.UserRoleViewer)
+	secret, _ := enableTestTotp(t, user)
+
+	// act
+	challenge, err := userService.SignIn(
+		&SignInRequest{Email: user.Email, Password: testUserPassword},
+		"test",
+		getTestIpAddress(),
+	)
+	require.NoError(t, err)
+
+	// assertions
+	assert.True(t, challenge.IsTotpRequired)
+	assert.NotEmpty(t, challenge.TotpToken)
+	assert.Empty(t, challenge.Token)
+	assert.Empty(t, challenge.RefreshToken)
+
+	_, err = userService.SignInWithTotp(
+		&TotpSignInRequest{TotpToken: challenge.TotpToken, Code: "000000"},
+		"test",
+		getTestIpAddress(),
+	)
+	assert.Error(t, err)
+
+	response, err := userService.SignInWithTotp(
+		&TotpSignInRequest{
+			TotpToken: challenge.TotpToken,
+			Code:      generateTestTotpCode(t, secret, time.Now().Add(testTotpPeriod)),
+		},
+		"test",
+		getTestIpAddress(),
+	)
+	require.NoError(t, err)
+	assert.NotEmpty(t, response.Token)
+	assert.NotEmpty(t, response.RefreshToken)
+
+	// cleanup
+	RemoveTestUser(user.ID)
+}
+
+func Test_SignInWithTotp_WhenCodeIsReplayed_CodeRejected(t *testing.T) {
+	// setup data
+	user := createTestUser(t, user_enums.UserRoleViewer)
+	secret, _ := enableTestTotp(t, user)
+
+	code := generateTestTotpCode(t, secret, time.Now().Add(testTotpPeriod))
+
+	// act
+	firstChallenge := signInTestUserWithPassword(t, user)
+	_, firstErr := userService.SignInWithTotp(
+		&TotpSignInRequest{TotpToken: firstChallenge.TotpToken, Code: code},
+		"test",
+		getTestIpAddress(),
+	)
+
+	secondChallenge := signInTestUserWithPassword(t, user)
+	_, secondErr := userService.SignInWithTotp(
+		&TotpSignInRequest{TotpToken: secondChallenge.TotpToken, Code: code},
+		"test",
+		getTestIpAddress(),
+	)
+
+	// assertions
+	assert.NoError(t, firstErr)
+	assert.ErrorContains(t, secondErr, "already used")
+
+	// cleanup
+	RemoveTestUser(user.ID)
+}
+
+func Test_SignInWithTotp_WhenRecoveryCodeUsed_CodeAcceptedOnce(t *testing.T) {
+	// setup data
+	user := createTestUser(t, user_enums.UserRoleViewer)
+	_, recoveryCodes := enableTestTotp(t, user)
+	require.Len(t, recoveryCodes, recoveryCodesCount)
+
+	// act
+	firstChallenge := signInTestUserWithPassword(t, user)
+	response, firstErr := userService.SignInWithTotp(
+		&TotpSignInRequest{
+			TotpToken: firstChallenge.TotpToken,
+			Code:      recoveryCodes[0],
+		},
+		"test",
+		getTestIpAddress(),
+	)
+
+	secondChallenge := signInTestUserWithPassword(t, user)
+	_, secondErr := userService.SignInWithTotp(
+		&TotpSignInRequest{
+			TotpToken: secondChallenge.TotpToken,
+			Code:      recoveryCodes[0],
+		},
+		"test",
+		getTestIpAddress(),
+	)
+
+	// assertions
+	require.NoError(t, firstErr)
+	assert.NotEmpty(t, response.Token)
+	assert.ErrorContains(t, secondErr, "invalid two-factor code")
+
+	// cleanup
+	RemoveTestUser(user.ID)
+}
+
+func Test_SignIn_WhenTotpRequiredForAllUsers_UserEnrollsOnSignIn(t *testing.T) {
+	// setup data
+	admin := getTestAdmin(t)
+	user := createTestUser(t, user_enums.UserRoleViewer)
+
+	_, err := userService.UpdateSecuritySettings(
+		admin,
+		&user_models.SecuritySettings{IsTotpRequired: true},
+	)
+	require.NoError(t, err)
+	defer func() {
+		_, err := userService.UpdateSecuritySettings(
+			admin,
+			&user_models.SecuritySettings{IsTotpRequired: false},
+		)
+		assert.NoError(t, err)
+	}()
+
+	// act
+	challenge := signInTestUserWithPassword(t, user)
+
+	setup, err := userService.StartTotpEnrollment(
+		&TotpEnrollmentRequest{TotpToken: challenge.TotpToken},
+	)
+	require.NoError(t, err)
+
+	response, err := userService.SignInWithTotp(
+		&TotpSignInRequest{
+			TotpToken: challenge.TotpToken,
+			Code:      generateTestTotpCode(t, setup.Secret, time.Now()),
+		},
+		"test",
+		getTestIpAddress(),
+	)
+	require.NoError(t, err)
+
+	// assertions
+	assert.True(t, challenge.IsTotpEnrollmentRequired)
+	assert.NotEmpty(t, response.Token)
+	assert.Len(t, response.RecoveryCodes, recoveryCodesCount)
+
+	enrolledUser, err := userRepository.GetUserByID(user.ID.String())
+	require.NoError(t, err)
+	assert.True(t, enrolledUser.IsTotpEnabled)
+
+	// cleanup
+	RemoveTestUser(user.ID)
+}
+
+func Test_ResetUserTotp_WhenAdminResetsTotp_UserSignsInWithoutCode(t *testing.T) {
+	// setup data
+	admin := getTestAdmin(t)
+	user := createTestUser(t, user_enums.UserRoleViewer)
+	_, recoveryCodes := enableTestTotp(t, user)
+
+	// act
+	err := userService.ResetUserTotp(admin, user.ID)
+	require.NoError(t, err)
+
+	response := signInTestUserWithPassword(t, user)
+
+	// assertions
+	assert.Empty(t, response.TotpToken)
+	assert.NotEmpty(t, response.Token)
+
+	resetUser, err := userRepository.GetUserByID(user.ID.String())
+	require.NoError(t, err)
+	assert.False(t, resetUser.IsTotpEnabled)
+	assert.Empty(t, resetUser.TotpSecret)
+
+	isRecoveryCodeUsed, err := recoveryCodeRepository.UseCode(
+		user.ID,
+		hashToken(normalizeRecoveryCode(recoveryCodes[0])),
+	)
+	require.NoError(t, err)
+	assert.False(t, isRecoveryCodeUsed)
+
+	// cleanup
+	RemoveTestUser(user.ID)
+}
+
+func Test_CreateApiKey_KeyCreated_OnlyHashOfKeyStored(t *testing.T) {
+	// setup data
+	user := createTestUser(t, user_enums.UserRoleOperator)
+
+	// act
+	response, err := userService.CreateApiKey(user, &CreateApiKeyRequest{
+		Name:  "Test key",
+		Scope: user_enums.ApiKeyScopeReadOnly,
+	})
+	require.NoError(t, err)
+
+	// assertions
+	assert.True(t, IsApiKey(response.Key))
+	assert.Equal(t, response.Key[:12], response.ApiKey.KeyPrefix)
+
+	savedApiKey, err := apiKeyRepository.FindByID(response.ApiKey.ID)
+	require.NoError(t, err)
+	assert.Equal(t, hashToken(response.Key), savedApiKey.KeyHash)
+	assert.NotContains(t, savedApiKey.KeyHash, response.Key)
+
+	keyUser, err := userService.GetUserFromToken(response.Key)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, keyUser.ID)
+
+	// cleanup
+	RemoveTestUser(user.ID)
+}
+
+func Test_CreateApiKey_WhenExpirationTimeInPast_KeyNotCreated(t *testing.T) {
+	// setup data
+	user := createTestUser(t, user_enums.UserRoleOperator)
+	expiresAt := time.Now().UTC().Add(-time.Hour)
+
+	// act
+	_, err := userService.CreateApiKey(user, &CreateApiKeyRequest{
+		Name:      "Test key",
+		Scope:     user_enums.ApiKeyScopeReadOnly,
+		ExpiresAt: &expiresAt,
+	})
+
+	// assertions
+	assert.ErrorContains(t, err, "expiration time should be in the future")
+
+	apiKeys, err := userService.GetApiKeys(user)
+	require.NoError(t, err)
+	assert.Empty(t, apiKeys)
+
+	// cleanup
+	RemoveTestUser(user.ID)
+}
+
+func Test_GetUserFromToken_WhenApiKeyExpired_KeyRejected(t *testing.T) {
+	// setup data
+	user := createTestUser(t, user_enums.UserRoleOperator)
+	key, apiKey := createTestApiKey(t, user, user_enums.ApiKeyScopeFull)
+
+	expiresAt := time.Now().UTC().Add(-time.Minute)
+	apiKey.ExpiresAt = &expiresAt
+	require.NoError(t, apiKeyRepository.Save(apiKey))
+
+	// act
+	_, err := userService.GetUserFromToken(key)
+	scopeErr := userService.CheckApiKeyScope(key, http.MethodGet, "/api/v1/backups")
+
+	// assertions
+	assert.ErrorContains(t, err, "API key is expired")
+	assert.ErrorContains(t, scopeErr, "API key is expired")
+
+	// cleanup
+	RemoveTestUser(user.ID)
+}
+
+func Test_GetUserFromToken_WhenApiKeyUsed_LastUsedTimeUpdatedOncePerInterval(t *testing.T) {
+	// setup data
+	user := createTestUser(t, user_enums.UserRoleOperator)
+	key, apiKey := createTestApiKey(t, user, user_enums.ApiKeyScopeReadOnly)
+	require.Nil(t, apiKey.LastUsedAt)
+
+	// act
+	_, err := userService.GetUserFromToken(key)
+	require.NoError(t, err)
+
+	firstUsedApiKey, err := apiKeyRepository.FindByID(apiKey.ID)
+	require.NoError(t, err)
+
+	_, err = userService.GetUserFromToken(key)
+	require.NoError(t, err)
+
+	secondUsedApiKey, err := apiKeyRepository.FindByID(apiKey.ID)
+	require.NoError(t, err)
+
+	// assertions
+	require.NotNil(t, firstUsedApiKey.LastUsedAt)
+	assert.WithinDuration(t, time.Now().UTC(), *firstUsedApiKey.LastUsedAt, time.Minute)
+	assert.True(t, firstUsedApiKey.LastUsedAt.Equal(*secondUsedApiKey.LastUsedAt))
+
+	// cleanup
+	RemoveTestUser(user.ID)
+}
+
+func Test_CheckApiKeyScope_RequestsCheckedByScope(t *testing.T) {
+	// setup data
+	user := createTestUser(t, user_enums.UserRoleOperator)
+
+	readOnlyKey, _ := createTestApiKey(t, user, user_enums.ApiKeyScopeReadOnly)
+	backupKey, _ := createTestApiKey(t, user, user_enums.ApiKeyScopeBackup)
+	restoreKey, _ := createTestApiKey(t, user, user_enums.ApiKeyScopeRestore)
+	fullKey, _ := createTestApiKey(t, user, user_enums.ApiKeyScopeFull)
+
+	testCases := []struct {
+		name      string
+		key       string
+		method    string
+		route     string
+		isAllowed bool
+	}{
+		{"read only key reads backups", readOnlyKey, http.MethodGet, "/api/v1/backups", true},
+		{"read only key makes backup", readOnlyKey, http.MethodPost, "/api/v1/backups", false},
+		{
+			"read only key downloads backup",
+			readOnlyKey,
+			http.MethodGet,
+			"/api/v1/backups/:id/file",
+			false,
+		},
+		{
+			"read only key exports audit log",
+			readOnlyKey,
+			http.MethodGet,
+			"/api/v1/audit-logs/export",
+			false,
+		},
+		{"backup key makes backup", backupKey, http.MethodPost, "/api/v1/backups", true},
+		{"backup key deletes backup", backupKey, http.MethodDelete, "/api/v1/backups/:id", false},
+		{
+			"backup key downloads backup",
+			backupKey,
+			http.MethodGet,
+			"/api/v1/backups/:id/file",
+			false,
+		},
+		{
+			"restore key restores backup",
+			restoreKey,
+			http.MethodPost,
+			"/api/v1/restores/:backupId/restore",
+			true,
+		},
+		{"restore key makes backup", restoreKey, http.MethodPost, "/api/v1/backups", false},
+		{"full key deletes backup", fullKey, http.MethodDelete, "/api/v1/backups/:id", true},
+		{
+			"full key downloads backup",
+			fullKey,
+			http.MethodGet,
+			"/api/v1/backups/:id/file",
+			true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// act
+			err := userService.CheckApiKeyScope(testCase.key, testCase.method, testCase.route)
+
+			// assertions
+			if testCase.isAllowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, "request is not allowed")
+			}
+		})
+	}
+
+	// cleanup
+	RemoveTestUser(user.ID)
+}
+
+func Test_DeleteApiKey_WhenKeyDeleted_KeyRejected(t *testing.T) {
+	// setup data
+	user := createTestUser(t, user_enums.UserRoleOperator)
+	key, apiKey := createTestApiKey(t, user, user_enums.ApiKeyScopeFull)
+
+	// act
+	err := userService.DeleteApiKey(user, apiKey.ID)
+	require.NoError(t, err)
+
+	_, tokenErr := userService.GetUserFromToken(key)
+
+	// assertions
+	assert.ErrorContains(t, tokenErr, "invalid API key")
+
+	// cleanup
+	RemoveTestUser(user.ID)
+}
+
+func Test_RefreshSession_WhenRefreshTokenUsed_TokenRotated(t *testing.T) {
+	// setup data
+	user := createTestUser(t, user_enums.UserRoleViewer)
+	signInResponse := signInTestUserWithPassword(t, user)
+
+	// act
+	refreshResponse, err := userService.RefreshSession(
+		&RefreshTokenRequest{RefreshToken: signInResponse.RefreshToken},
+		"test",
+		getTestIpAddress(),
+	)
+	require.NoError(t, err)
+
+	// assertions
+	assert.NotEmpty(t, refreshResponse.Token)
+	assert.NotEqual(t, signInResponse.RefreshToken, refreshResponse.RefreshToken)
+
+	initialSessionID, err := userService.GetSessionIDFromToken(signInResponse.Token)
+	require.NoError(t, err)
+	refreshedSessionID, err := userService.GetSessionIDFromToken(refreshResponse.Token)
+	require.NoError(t, err)
+	assert.Equal(t, initialSessionID, refreshedSessionID)
+
+	_, err = userService.RefreshSession(
+		&RefreshTokenRequest{RefreshToken: refreshResponse.RefreshToken},
+		"test",
+		getTestIpAddress(),
+	)
+	assert.NoError(t, err)
+
+	// cleanup
+	RemoveTestUser(user.ID)
+}
+
+func Test_RefreshSession_WhenRotatedRefreshTokenReused_TokenRejected(t *testing.T) {
+	// setup data
+	user := createTestUser(t, user_enums.UserRoleViewer)
+	signInResponse := signInTestUserWithPassword(t, user)
+
+	_, err := userService.RefreshSession(
+		&RefreshTokenRequest{RefreshToken: signInResponse.RefreshToken},
+		"test",
+		getTestIpAddress(),
+	)
+	require.NoError(t, err)
+
+	// act
+	response, err := userService.RefreshSession(
+		&RefreshTokenRequest{RefreshToken: signInResponse.RefreshToken},
+		"test",
+		getTestIpAddress(),
+	)
+
+	// assertions
+	assert.Nil(t, response)
+	assert.ErrorContains(t, err, "invalid refresh token")
+
+	// cleanup
+	RemoveTestUser(user.ID)
+}
+
+func Test_Logout_WhenUserLoggedOut_AccessAndRefreshTokensRejected(t *testing.T) {
+	// setup data
+	user := createTestUser(t, user_enums.UserRoleViewer)
+	signInResponse := signInTestUserWithPassword(t, user)
+
+	// act
+	err := userService.Logout(signInResponse.Token)
+	require.NoError(t, err)
+
+	// assertions
+	_, err = userService.GetUserFromToken(signInResponse.Token)
+	assert.ErrorContains(t, err, "session is expired or revoked")
+
+	_, err = userService.RefreshSession(
+		&RefreshTokenRequest{RefreshToken: signInResponse.RefreshToken},
+		"test",
+		getTestIpAddress(),
+	)
+	assert.ErrorContains(t, err, "session is expired or revoked")
+
+	// cleanup
+	RemoveTestUser(user.ID)
+}
+
+func Test_RevokeAllSessions_WhenAdminRevokesSessions_AllSessionsOfUserRejected(t *testing.T) {
+	// setup data
+	admin := getTestAdmin(t)
+	user := createTestUser(t, user_enums.UserRoleViewer)
+
+	firstResponse := signInTestUserWithPassword(t, user)
+	secondResponse := signInTestUserWithPassword(t, user)
+
+	// act
+	err := userService.RevokeAllSessions(admin, user.ID)
+	require.NoError(t, err)
+
+	// assertions
+	for _, response := range []*SignInResponse{firstResponse, secondResponse} {
+		_, err := userService.GetUserFromToken(response.Token)
+		assert.ErrorContains(t, err, "session is expired or revoked")
+	}
+
+	sessions, err := userService.GetActiveSessions(user.ID, uuid.Nil)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	// cleanup
+	RemoveTestUser(user.ID)
+}
+
+func Test_RotateSecretKey_WhenKeyRotated_PreviousKeyVerifiesTokensUntilExpiration(t *testing.T) {
+	// setup data
+	admin := getTestAdmin(t)
+	user := createTestUser(t, user_enums.UserRoleViewer)
+	signInResponse := signInTestUserWithPassword(t, user)
+
+	previousSecretKey, err := secretKeyRepository.GetSecretKey()
+	require.NoError(t, err)
+
+	// act
+	err = userService.RotateSecretKey(admin)
+	require.NoError(t, err)
+
+	// assertions
+	_, err = userService.GetUserFromToken(signInResponse.Token)
+	assert.NoError(t, err)
+
+	rotatedSecretKey, err := secretKeyRepository.GetValidSecretKeyByID(previousSecretKey.ID)
+	require.NoError(t, err)
+	require.NotNil(t, rotatedSecretKey.ExpiresAt)
+	assert.WithinDuration(
+		t,
+		time.Now().UTC().Add(accessTokenLifetime),
+		*rotatedSecretKey.ExpiresAt,
+		time.Minute,
+	)
+
+	currentSecretKey, err := secretKeyRepository.GetSecretKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, previousSecretKey.ID, currentSecretKey.ID)
+
+	refreshResponse, err := userService.RefreshSession(
+		&RefreshTokenRequest{RefreshToken: signInResponse.RefreshToken},
+		"test",
+		getTestIpAddress(),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, currentSecretKey.ID.String(), getTestTokenKeyID(t, refreshResponse.Token))
+
+	// cleanup
+	RemoveTestUser(user.ID)
+}
+
+func Test_GetUserFromToken_WhenSecretKeyOfTokenExpired_TokenRejected(t *testing.T) {
+	// setup data
+	user := createTestUser(t, user_enums.UserRoleViewer)
+	signInResponse := signInTestUserWithPassword(t, user)
+
+	sessionID, err := userService.GetSessionIDFromToken(signInResponse.Token)
+	require.NoError(t, err)
+
+	// the key is created already expired instead of rotating the
+	// current one, so tokens of other tests are not affected
+	expiresAt := time.Now().UTC().Add(-time.Minute)
+	expiredSecretKey := &user_models.SecretKey{
+		ID:        uuid.New(),
+		Secret:    uuid.New().String() + uuid.New().String(),
+		CreatedAt: time.Now().UTC().Add(-time.Hour),
+		ExpiresAt: &expiresAt,
+	}
+	require.NoError(t, storage.GetDb().Create(expiredSecretKey).Error)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": user.ID,
+		"sid": sessionID,
+		"exp": time.Now().UTC().Add(accessTokenLifetime).Unix(),
+		"iat": time.Now().UTC().Unix(),
+	})
+	token.Header["kid"] = expiredSecretKey.ID.String()
+
+	tokenString, err := token.SignedString([]byte(expiredSecretKey.Secret))
+	require.NoError(t, err)
+
+	// act
+	_, err = userService.GetUserFromToken(tokenString)
+
+	// assertions
+	assert.ErrorContains(t, err, "secret key is expired or unknown")
+
+	// cleanup
+	assert.NoError(t, storage.GetDb().Delete(expiredSecretKey).Error)
+	RemoveTestUser(user.ID)
+}
+
+func Test_ParseOidcState_WhenStateIssuedForAnotherBrowser_StateRejected(t *testing.T) {
+	// setup data
+	GetTestUser()
+
+	codeVerifier := "test-code-verifier-0123456789-0123456789-0123456789"
+	state, err := userService.generateSignedToken(
+		oidcStateTokenType,
+		oidcStateLifetime,
+		jwt.MapClaims{"nonce": "test-nonce", "verifierHash": hashToken(codeVerifier)},
+	)
+	require.NoError(t, err)
+
+	// act
+	nonce, err := userService.parseOidcState(state, codeVerifier)
+	_, anotherBrowserErr := userService.parseOidcState(state, "another-code-verifier")
+	_, noCookieErr := userService.parseOidcState(state, "")
+
+	// assertions
+	require.NoError(t, err)
+	assert.Equal(t, "test-nonce", nonce)
+	assert.ErrorContains(t, anotherBrowserErr, "started in another browser")
+	assert.ErrorContains(t, noCookieErr, "started in another browser")
+}
+
+func createTestUser(t *testing.T, role user_enums.UserRole) *user_models.User {
+	GetTestUser()
+
+	signInResponse := CreateTestUserWithRole(role)
+
+	user, err := userRepository.GetUserByID(signInResponse.UserID.String())
+	require.NoError(t, err)
+
+	return user
+}
+
+func getTestAdmin(t *testing.T) *user_models.User {
+	GetTestUser()
+
+	admin, err := userService.GetFirstUser()
+	require.NoError(t, err)
+
+	return admin
+}
+
+func createTestApiKey(
+	t *testing.T,
+	user *user_models.User,
+	scope user_enums.ApiKeyScope,
+) (string, *user_models.ApiKey) {
+	response, err := userService.CreateApiKey(user, &CreateApiKeyRequest{
+		Name:  "Test key " + uuid.New().String(),
+		Scope: scope,
+	})
+	require.NoError(t, err)
+
+	return response.Key, response.ApiKey
+}
+
+// enableTestTotp enrolls the user into two-factor authentication by
+// the code of the current period and returns the secret with recovery codes
+func enableTestTotp(t *testing.T, user *user_models.User) (string, []string) {
+	setup, err := userService.SetupTotp(user)
+	require.NoError(t, err)
+
+	user, err = userRepository.GetUserByID(user.ID.String())
+	require.NoError(t, err)
+
+	recoveryCodes, err := userService.EnableTotp(
+		user,
+		&TotpCodeRequest{Code: generateTestTotpCode(t, setup.Secret, time.Now())},
+	)
+	require.NoError(t, err)
+
+	return setup.Secret, recoveryCodes
+}
+
+func signInTestUserWithPassword(t *testing.T, user *user_models.User) *SignInResponse {
+	response, err := userService.SignIn(
+		&SignInRequest{Email: user.Email, Password: testUserPassword},
+		"test",
+		getTestIpAddress(),
+	)
+	require.NoError(t, err)
+
+	return response
+}
+
+func generateTestTotpCode(t *testing.T, secret string, at time.Time) string {
+	code, err := totp.GenerateCode(secret, at)
+	require.NoError(t, err)
+
+	return code
+}
+
+func getTestTokenKeyID(t *testing.T, token string) string {
+	parsedToken, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	require.NoError(t, err)
+
+	keyID, ok := parsedToken.Header["kid"].(string)
+	require.True(t, ok)
+
+	return keyID
+}
+
+// getTestIpAddress returns a new address for each sign in, so tests do
+// not reach limits of sign in throttler
+func getTestIpAddress() string {
+	id := uuid.New()
+	return fmt.Sprintf("10.%d.%d.%d", id[0], id[1], id[2])
+}
@@ -0,0 +1,206 @@
+package users
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+var ErrTooManySigninAttempts = errors.New("too many sign in attempts")
+
+const (
+	// sign in requests of an IP address are limited regardless of
+	// their result, so passwords cannot be guessed quickly
+	signinRequestsInterval = 6 * time.Second
+	signinRequestsBurst    = 10
+
+	// all sign in requests are limited as well, so the limit is kept
+	// even if IP addresses are spoofed or rotated
+	signinGlobalRequestsPerSecond = 10
+	signinGlobalRequestsBurst     = 30
+
+	// after several failed attempts in a row the account or the IP
+	// address is locked. Each next lockout is twice as long
+	maxFailedSigninsPerAccount = 5
+	maxFailedSigninsPerIP      = 20
+	signinBaseLockout          = time.Minute
+	signinMaxLockout           = 24 * time.Hour
+
+	// failures and lockouts are forgotten after a quiet period
+	signinAttemptsTTL = 24 * time.Hour
+)
+
+type signinAttempts struct {
+	limiter       *rate.Limiter
+	failuresCount int
+	lockoutsCount int
+	lockedUntil   time.Time
+	lastAttemptAt time.Time
+}
+
+// SigninThrottler limits sign in requests per IP address and locks
+// accounts and IP addresses with exponentially growing lockouts after
+// failed attempts. Accounts are tracked by email, so unknown emails
+// are locked the same way as existing ones
+type SigninThrottler struct {
+	mutex         sync.Mutex
+	globalLimiter *rate.Limiter
+	ips           map[string]*signinAttempts
+	accounts      map[string]*signinAttempts
+	lastCleanupAt time.Time
+}
+
+func NewSigninThrottler() *SigninThrottler {
+	return &SigninThrottler{
+		globalLimiter: rate.NewLimiter(
+			rate.Limit(signinGlobalRequestsPerSecond),
+			signinGlobalRequestsBurst,
+		),
+		ips:           map[string]*signinAttempts{},
+		accounts:      map[string]*signinAttempts{},
+		lastCleanupAt: time.Now().UTC(),
+	}
+}
+
+// AllowRequest checks neither the IP address nor all clients together
+// exceed the rate of sign in requests. Each call is counted as a request
+func (t *SigninThrottler) AllowRequest(ipAddress string) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	// rejected requests do not add IP addresses, so the map of them
+	// grows not faster than the global limit
+	if !t.globalLimiter.Allow() {
+		return false
+	}
+
+	ip := t.getAttempts(t.ips, ipAddress)
+	if ip.limiter == nil {
+		ip.limiter = rate.NewLimiter(rate.Every(signinRequestsInterval), signinRequestsBurst)
+	}
+
+	return ip.limiter.Allow()
+}
+
+// CheckLockout returns ErrTooManySigninAttempts if the IP address or
+// the account is locked
+func (t *SigninThrottler) CheckLockout(ipAddress string, email string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := time.Now().UTC()
+
+	lockedUntil := t.getAttempts(t.ips, ipAddress).lockedUntil
+	accountLockedUntil := t.getAttempts(t.accounts, normalizeEmail(email)).lockedUntil
+	if accountLockedUntil.After(lockedUntil) {
+		lockedUntil = accountLockedUntil
+	}
+
+	if lockedUntil.After(now) {
+		return fmt.Errorf(
+			"%w, try again in %s",
+			ErrTooManySigninAttempts,
+			lockedUntil.Sub(now).Round(time.Second),
+		)
+	}
+
+	return nil
+}
+
+// RegisterFailure counts the failed attempt. If the attempt locks the
+// account, the time until which it is locked is returned
+func (t *SigninThrottler) RegisterFailure(ipAddress string, email string) *time.Time {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := time.Now().UTC()
+
+	t.registerFailure(t.getAttempts(t.ips, ipAddress), maxFailedSigninsPerIP, now)
+
+	account := t.getAttempts(t.accounts, normalizeEmail(email))
+	if !t.registerFailure(account, maxFailedSigninsPerAccount, now) {
+		return nil
+	}
+
+	lockedUntil := account.lockedUntil
+	return &lockedUntil
+}
+
+// RegisterSuccess resets failures of the IP address and the account
+// after the user has passed all factors
+func (t *SigninThrottler) RegisterSuccess(ipAddress string, email string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.accounts, normalizeEmail(email))
+
+	if ip, isFound := t.ips[ipAddress]; isFound {
+		ip.failuresCount = 0
+		ip.lockoutsCount = 0
+	}
+}
+
+// registerFailure returns true if the failure locks the key
+func (t *SigninThrottler) registerFailure(
+	attempts *signinAttempts,
+	maxFailures int,
+	now time.Time,
+) bool {
+	attempts.failuresCount++
+
+	if attempts.failuresCount < maxFailures {
+		return false
+	}
+
+	lockout := signinBaseLockout << min(attempts.lockoutsCount, 16)
+	attempts.lockedUntil = now.Add(min(lockout, signinMaxLockout))
+	attempts.lockoutsCount++
+	attempts.failuresCount = 0
+
+	return true
+}
+
+func (t *SigninThrottler) getAttempts(
+	attemptsByKey map[string]*signinAttempts,
+	key string,
+) *signinAttempts {
+	now := time.Now().UTC()
+	t.cleanUp(now)
+
+	attempts, isFound := attemptsByKey[key]
+	if !isFound {
+		attempts = &signinAttempts{}
+		attemptsByKey[key] = attempts
+	}
+
+	attempts.lastAttemptAt = now
+
+	return attempts
+}
+
+// cleanUp removes keys without attempts for a while, so maps do not
+// grow with each IP address and email ever used
+func (t *SigninThrottler) cleanUp(now time.Time) {
+	if now.Sub(t.lastCleanupAt) < time.Hour {
+		return
+	}
+
+	t.lastCleanupAt = now
+
+	for _, attemptsByKey := range []map[string]*signinAttempts{t.ips, t.accounts} {
+		for key, attempts := range attemptsByKey {
+			if now.Sub(attempts.lastAttemptAt) > signinAttemptsTTL &&
+				now.After(attempts.lockedUntil) {
+				delete(attemptsByKey, key)
+			}
+		}
+	}
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,100 @@
+package users
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RegisterFailure_MaxFailuresReached_AccountLocked(t *testing.T) {
+	throttler := NewSigninThrottler()
+
+	for range maxFailedSigninsPerAccount - 1 {
+		assert.Nil(t, throttler.RegisterFailure("10.0.0.1", "user@example.com"))
+	}
+	require.NoError(t, throttler.CheckLockout("10.0.0.1", "user@example.com"))
+
+	lockedUntil := throttler.RegisterFailure("10.0.0.1", "user@example.com")
+	require.NotNil(t, lockedUntil)
+	assert.WithinDuration(t, time.Now().UTC().Add(signinBaseLockout), *lockedUntil, time.Second)
+
+	err := throttler.CheckLockout("10.0.0.2", "USER@example.com")
+	assert.ErrorIs(t, err, ErrTooManySigninAttempts)
+
+	assert.NoError(t, throttler.CheckLockout("10.0.0.1", "other@example.com"))
+}
+
+func Test_RegisterFailure_AccountLockedAgain_LockoutDoubled(t *testing.T) {
+	throttler := NewSigninThrottler()
+
+	for range maxFailedSigninsPerAccount {
+		throttler.RegisterFailure("10.0.0.1", "user@example.com")
+	}
+
+	// lockout is expired, next failures lock the account again
+	throttler.accounts["user@example.com"].lockedUntil = time.Now().UTC()
+
+	var lockedUntil *time.Time
+	for range maxFailedSigninsPerAccount {
+		lockedUntil = throttler.RegisterFailure("10.0.0.1", "user@example.com")
+	}
+
+	require.NotNil(t, lockedUntil)
+	assert.WithinDuration(
+		t,
+		time.Now().UTC().Add(2*signinBaseLockout),
+		*lockedUntil,
+		time.Second,
+	)
+}
+
+func Test_RegisterFailure_MaxFailuresOfIpReached_IpLocked(t *testing.T) {
+	throttler := NewSigninThrottler()
+
+	for i := range maxFailedSigninsPerIP {
+		throttler.RegisterFailure("10.0.0.1", string(rune('a'+i))+"@example.com")
+	}
+
+	err := throttler.CheckLockout("10.0.0.1", "new@example.com")
+	assert.ErrorIs(t, err, ErrTooManySigninAttempts)
+
+	assert.NoError(t, throttler.CheckLockout("10.0.0.2", "new@example.com"))
+}
+
+func Test_RegisterSuccess_FailuresReset(t *testing.T) {
+	throttler := NewSigninThrottler()
+
+	for range maxFailedSigninsPerAccount - 1 {
+		throttler.RegisterFailure("10.0.0.1", "user@example.com")
+	}
+
+	throttler.RegisterSuccess("10.0.0.1", "user@example.com")
+
+	assert.Nil(t, throttler.RegisterFailure("10.0.0.1", "user@example.com"))
+	assert.NoError(t, throttler.CheckLockout("10.0.0.1", "user@example.com"))
+}
+
+func Test_AllowRequest_BurstExceeded_RequestsOfIpRejected(t *testing.T) {
+	throttler := NewSigninThrottler()
+
+	for range signinRequestsBurst {
+		assert.True(t, throttler.AllowRequest("10.0.0.1"))
+	}
+
+	assert.False(t, throttler.AllowRequest("10.0.0.1"))
+	assert.True(t, throttler.AllowRequest("10.0.0.2"))
+}
+
+func Test_AllowRequest_GlobalBurstExceeded_RequestsOfAnyIpRejected(t *testing.T) {
+	throttler := NewSigninThrottler()
+
+	for i := range signinGlobalRequestsBurst {
+		assert.True(t, throttler.AllowRequest(fmt.Sprintf("10.0.%d.%d", i/256, i%256)))
+	}
+
+	assert.False(t, throttler.AllowRequest("10.1.0.1"))
+	assert.NotContains(t, throttler.ips, "10.1.0.1")
+}
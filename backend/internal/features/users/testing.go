@@ -1,5 +1,11 @@
 package users
 
+import (
+	user_enums "postgresus-backend/internal/features/users/enums"
+
+	"github.com/google/uuid"
+)
+
 func GetTestUser() *SignInResponse {
 	isAnyUserExists, err := userService.IsAnyUserExist()
 	if err != nil {
@@ -22,10 +28,46 @@ func GetTestUser() *SignInResponse {
 		panic(err)
 	}
 
-	signInResponse, err := userService.GenerateAccessToken(user)
+	signInResponse, err := userService.CreateSession(user, "test", "127.0.0.1")
 	if err != nil {
 		panic(err)
 	}
 
 	return signInResponse
 }
+
+// CreateTestUserWithRole creates one more user with the given role,
+// the user should be removed via RemoveTestUser after the test
+func CreateTestUserWithRole(role user_enums.UserRole) *SignInResponse {
+	admin, err := userService.GetFirstUser()
+	if err != nil {
+		panic(err)
+	}
+
+	user, err := userService.CreateUser(admin, &CreateUserRequest{
+		Email:    "test-" + uuid.New().String() + "@test.com",
+		Password: "testtest",
+		Role:     role,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	signInResponse, err := userService.CreateSession(user, "test", "127.0.0.1")
+	if err != nil {
+		panic(err)
+	}
+
+	return signInResponse
+}
+
+func RemoveTestUser(userID uuid.UUID) {
+	admin, err := userService.GetFirstUser()
+	if err != nil {
+		panic(err)
+	}
+
+	if err := userService.DeleteUser(admin, userID); err != nil {
+		panic(err)
+	}
+}
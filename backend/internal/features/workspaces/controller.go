@@ -0,0 +1,342 @@
+package workspaces
+
+import (
+	"net/http"
+	"postgresus-backend/internal/features/users"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WorkspaceController struct {
+	workspaceService *WorkspaceService
+	userService      *users.UserService
+}
+
+func (c *WorkspaceController) RegisterRoutes(router *gin.RouterGroup) {
+	router.POST("/workspaces", c.CreateWorkspace)
+	router.GET("/workspaces", c.GetWorkspaces)
+	router.GET("/workspaces/:id", c.GetWorkspace)
+	router.PUT("/workspaces/:id", c.UpdateWorkspace)
+	router.DELETE("/workspaces/:id", c.DeleteWorkspace)
+
+	router.GET("/workspaces/:id/members", c.GetMembers)
+	router.POST("/workspaces/:id/members", c.SaveMember)
+	router.DELETE("/workspaces/:id/members/:userId", c.RemoveMember)
+}
+
+// CreateWorkspace
+// @Summary Create a workspace
+// @Description Create a workspace. Available for admins only
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param request body SaveWorkspaceRequest true "Workspace data"
+// @Success 200 {object} Workspace
+// @Failure 400
+// @Failure 401
+// @Router /workspaces [post]
+func (c *WorkspaceController) CreateWorkspace(ctx *gin.Context) {
+	var request SaveWorkspaceRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authorizationHeader := ctx.GetHeader("Authorization")
+	if authorizationHeader == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+		return
+	}
+
+	user, err := c.userService.GetUserFromToken(authorizationHeader)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	workspace, err := c.workspaceService.CreateWorkspace(user, &request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, workspace)
+}
+
+// GetWorkspaces
+// @Summary Get workspaces
+// @Description Get workspaces the user is a member of, admins get all workspaces
+// @Tags workspaces
+// @Produce json
+// @Success 200 {array} Workspace
+// @Failure 401
+// @Router /workspaces [get]
+func (c *WorkspaceController) GetWorkspaces(ctx *gin.Context) {
+	authorizationHeader := ctx.GetHeader("Authorization")
+	if authorizationHeader == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+		return
+	}
+
+	user, err := c.userService.GetUserFromToken(authorizationHeader)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	workspaces, err := c.workspaceService.GetWorkspaces(user)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, workspaces)
+}
+
+// GetWorkspace
+// @Summary Get a workspace
+// @Description Get the workspace by ID
+// @Tags workspaces
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Success 200 {object} Workspace
+// @Failure 400
+// @Failure 401
+// @Router /workspaces/{id} [get]
+func (c *WorkspaceController) GetWorkspace(ctx *gin.Context) {
+	workspaceID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid workspace ID"})
+		return
+	}
+
+	authorizationHeader := ctx.GetHeader("Authorization")
+	if authorizationHeader == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+		return
+	}
+
+	user, err := c.userService.GetUserFromToken(authorizationHeader)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	workspace, err := c.workspaceService.GetWorkspace(user, workspaceID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, workspace)
+}
+
+// UpdateWorkspace
+// @Summary Update a workspace
+// @Description Rename the workspace. Available for workspace admins
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Param request body SaveWorkspaceRequest true "Workspace data"
+// @Success 200 {object} Workspace
+// @Failure 400
+// @Failure 401
+// @Router /workspaces/{id} [put]
+func (c *WorkspaceController) UpdateWorkspace(ctx *gin.Context) {
+	workspaceID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid workspace ID"})
+		return
+	}
+
+	var request SaveWorkspaceRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authorizationHeader := ctx.GetHeader("Authorization")
+	if authorizationHeader == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+		return
+	}
+
+	user, err := c.userService.GetUserFromToken(authorizationHeader)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	workspace, err := c.workspaceService.UpdateWorkspace(user, workspaceID, &request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, workspace)
+}
+
+// DeleteWorkspace
+// @Summary Delete a workspace
+// @Description Delete the workspace. Only workspaces without databases, storages and notifiers can be deleted. Available for admins only
+// @Tags workspaces
+// @Param id path string true "Workspace ID"
+// @Success 204
+// @Failure 400
+// @Failure 401
+// @Router /workspaces/{id} [delete]
+func (c *WorkspaceController) DeleteWorkspace(ctx *gin.Context) {
+	workspaceID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid workspace ID"})
+		return
+	}
+
+	authorizationHeader := ctx.GetHeader("Authorization")
+	if authorizationHeader == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+		return
+	}
+
+	user, err := c.userService.GetUserFromToken(authorizationHeader)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	if err := c.workspaceService.DeleteWorkspace(user, workspaceID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetMembers
+// @Summary Get workspace members
+// @Description Get members of the workspace with their roles
+// @Tags workspaces
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Success 200 {array} WorkspaceMember
+// @Failure 400
+// @Failure 401
+// @Router /workspaces/{id}/members [get]
+func (c *WorkspaceController) GetMembers(ctx *gin.Context) {
+	workspaceID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid workspace ID"})
+		return
+	}
+
+	authorizationHeader := ctx.GetHeader("Authorization")
+	if authorizationHeader == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+		return
+	}
+
+	user, err := c.userService.GetUserFromToken(authorizationHeader)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	members, err := c.workspaceService.GetMembers(user, workspaceID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, members)
+}
+
+// SaveMember
+// @Summary Add or update workspace member
+// @Description Add the user with the email to the workspace or change role of the member. Available for workspace admins
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Param request body SaveWorkspaceMemberRequest true "Member data"
+// @Success 200 {object} WorkspaceMember
+// @Failure 400
+// @Failure 401
+// @Router /workspaces/{id}/members [post]
+func (c *WorkspaceController) SaveMember(ctx *gin.Context) {
+	workspaceID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid workspace ID"})
+		return
+	}
+
+	var request SaveWorkspaceMemberRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authorizationHeader := ctx.GetHeader("Authorization")
+	if authorizationHeader == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+		return
+	}
+
+	user, err := c.userService.GetUserFromToken(authorizationHeader)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	member, err := c.workspaceService.SaveMember(user, workspaceID, &request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, member)
+}
+
+// RemoveMember
+// @Summary Remove workspace member
+// @Description Remove the user from the workspace. Available for workspace admins
+// @Tags workspaces
+// @Param id path string true "Workspace ID"
+// @Param userId path string true "User ID"
+// @Success 204
+// @Failure 400
+// @Failure 401
+// @Router /workspaces/{id}/members/{userId} [delete]
+func (c *WorkspaceController) RemoveMember(ctx *gin.Context) {
+	workspaceID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid workspace ID"})
+		return
+	}
+
+	memberUserID, err := uuid.Parse(ctx.Param("userId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	authorizationHeader := ctx.GetHeader("Authorization")
+	if authorizationHeader == "" {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
+		return
+	}
+
+	user, err := c.userService.GetUserFromToken(authorizationHeader)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		return
+	}
+
+	if err := c.workspaceService.RemoveMember(user, workspaceID, memberUserID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,28 @@
+package workspaces
+
+import (
+	"postgresus-backend/internal/features/audit_logs"
+	"postgresus-backend/internal/features/users"
+)
+
+var workspaceRepository = &WorkspaceRepository{}
+var workspaceMemberRepository = &WorkspaceMemberRepository{}
+var workspaceService = &WorkspaceService{
+	workspaceRepository,
+	workspaceMemberRepository,
+	users.GetUserService(),
+	audit_logs.GetAuditLogService(),
+	[]WorkspaceRemoveListener{},
+}
+var workspaceController = &WorkspaceController{
+	workspaceService,
+	users.GetUserService(),
+}
+
+func GetWorkspaceService() *WorkspaceService {
+	return workspaceService
+}
+
+func GetWorkspaceController() *WorkspaceController {
+	return workspaceController
+}
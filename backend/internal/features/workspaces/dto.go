@@ -0,0 +1,12 @@
+package workspaces
+
+import user_enums "postgresus-backend/internal/features/users/enums"
+
+type SaveWorkspaceRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type SaveWorkspaceMemberRequest struct {
+	Email string              `json:"email" binding:"required,email"`
+	Role  user_enums.UserRole `json:"role"  binding:"required"`
+}
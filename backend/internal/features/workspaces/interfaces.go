@@ -0,0 +1,7 @@
+package workspaces
+
+import "github.com/google/uuid"
+
+type WorkspaceRemoveListener interface {
+	OnBeforeWorkspaceRemove(workspaceID uuid.UUID) error
+}
@@ -0,0 +1,35 @@
+package workspaces
+
+import (
+	user_enums "postgresus-backend/internal/features/users/enums"
+	users_models "postgresus-backend/internal/features/users/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Workspace owns databases, storages and notifiers. Users see
+// resources only of workspaces they are members of
+type Workspace struct {
+	ID        uuid.UUID `json:"id"        gorm:"column:id;primaryKey;type:uuid"`
+	Name      string    `json:"name"      gorm:"column:name;type:text;not null"`
+	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at;not null"`
+}
+
+func (w *Workspace) TableName() string {
+	return "workspaces"
+}
+
+type WorkspaceMember struct {
+	ID          uuid.UUID           `json:"id"          gorm:"column:id;primaryKey;type:uuid"`
+	WorkspaceID uuid.UUID           `json:"workspaceId" gorm:"column:workspace_id;type:uuid;not null"`
+	UserID      uuid.UUID           `json:"userId"      gorm:"column:user_id;type:uuid;not null"`
+	Role        user_enums.UserRole `json:"role"        gorm:"column:role;type:text;not null"`
+	CreatedAt   time.Time           `json:"createdAt"   gorm:"column:created_at;not null"`
+
+	User *users_models.User `json:"user" gorm:"foreignKey:UserID"`
+}
+
+func (m *WorkspaceMember) TableName() string {
+	return "workspace_members"
+}
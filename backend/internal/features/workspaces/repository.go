@@ -0,0 +1,128 @@
+package workspaces
+
+import (
+	"postgresus-backend/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+type WorkspaceRepository struct{}
+
+func (r *WorkspaceRepository) Save(workspace *Workspace) error {
+	db := storage.GetDb()
+
+	if workspace.ID == uuid.Nil {
+		workspace.ID = uuid.New()
+		return db.Create(workspace).Error
+	}
+
+	return db.Save(workspace).Error
+}
+
+func (r *WorkspaceRepository) FindByID(id uuid.UUID) (*Workspace, error) {
+	var workspace Workspace
+
+	if err := storage.
+		GetDb().
+		Where("id = ?", id).
+		First(&workspace).Error; err != nil {
+		return nil, err
+	}
+
+	return &workspace, nil
+}
+
+func (r *WorkspaceRepository) FindAll() ([]*Workspace, error) {
+	var workspaces []*Workspace
+
+	if err := storage.
+		GetDb().
+		Order("created_at ASC").
+		Find(&workspaces).Error; err != nil {
+		return nil, err
+	}
+
+	return workspaces, nil
+}
+
+func (r *WorkspaceRepository) FindByMemberUserID(userID uuid.UUID) ([]*Workspace, error) {
+	var workspaces []*Workspace
+
+	if err := storage.
+		GetDb().
+		Joins("JOIN workspace_members ON workspace_members.workspace_id = workspaces.id").
+		Where("workspace_members.user_id = ?", userID).
+		Order("workspaces.created_at ASC").
+		Find(&workspaces).Error; err != nil {
+		return nil, err
+	}
+
+	return workspaces, nil
+}
+
+func (r *WorkspaceRepository) DeleteByID(id uuid.UUID) error {
+	return storage.
+		GetDb().
+		Delete(&Workspace{}, "id = ?", id).Error
+}
+
+type WorkspaceMemberRepository struct{}
+
+func (r *WorkspaceMemberRepository) Save(member *WorkspaceMember) error {
+	db := storage.GetDb()
+
+	// user is preloaded for responses only
+	member.User = nil
+
+	if member.ID == uuid.Nil {
+		member.ID = uuid.New()
+		return db.Create(member).Error
+	}
+
+	return db.Save(member).Error
+}
+
+func (r *WorkspaceMemberRepository) FindByWorkspaceIdAndUserID(
+	workspaceID uuid.UUID,
+	userID uuid.UUID,
+) (*WorkspaceMember, error) {
+	var members []*WorkspaceMember
+
+	if err := storage.
+		GetDb().
+		Preload("User").
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		Limit(1).
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+
+	if len(members) == 0 {
+		return nil, nil
+	}
+
+	return members[0], nil
+}
+
+func (r *WorkspaceMemberRepository) FindByWorkspaceID(
+	workspaceID uuid.UUID,
+) ([]*WorkspaceMember, error) {
+	var members []*WorkspaceMember
+
+	if err := storage.
+		GetDb().
+		Preload("User").
+		Where("workspace_id = ?", workspaceID).
+		Order("created_at ASC").
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (r *WorkspaceMemberRepository) DeleteByID(id uuid.UUID) error {
+	return storage.
+		GetDb().
+		Delete(&WorkspaceMember{}, "id = ?", id).Error
+}
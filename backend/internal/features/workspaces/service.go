@@ -0,0 +1,347 @@
+package workspaces
+
+import (
+	"errors"
+	"fmt"
+	"postgresus-backend/internal/features/audit_logs"
+	"postgresus-backend/internal/features/users"
+	user_enums "postgresus-backend/internal/features/users/enums"
+	users_models "postgresus-backend/internal/features/users/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceService manages workspaces and checks access to them.
+// Installation admins have admin access to every workspace, other
+// users have access with the role of their membership
+type WorkspaceService struct {
+	workspaceRepository       *WorkspaceRepository
+	workspaceMemberRepository *WorkspaceMemberRepository
+	userService               *users.UserService
+	auditLogService           *audit_logs.AuditLogService
+
+	workspaceRemoveListeners []WorkspaceRemoveListener
+}
+
+func (s *WorkspaceService) AddWorkspaceRemoveListener(listener WorkspaceRemoveListener) {
+	s.workspaceRemoveListeners = append(s.workspaceRemoveListeners, listener)
+}
+
+func (s *WorkspaceService) CreateWorkspace(
+	user *users_models.User,
+	request *SaveWorkspaceRequest,
+) (*Workspace, error) {
+	if user.Role != user_enums.UserRoleAdmin {
+		return nil, errors.New("only admins can create workspaces")
+	}
+
+	workspace := &Workspace{
+		Name:      request.Name,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := s.workspaceRepository.Save(workspace); err != nil {
+		return nil, err
+	}
+
+	s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+		Action:     audit_logs.AuditLogActionCreate,
+		TargetType: audit_logs.AuditLogTargetTypeWorkspace,
+		TargetID:   workspace.ID,
+		TargetName: workspace.Name,
+		Message:    fmt.Sprintf("Created workspace \"%s\"", workspace.Name),
+		Diff:       audit_logs.CalculateDiff(nil, workspace),
+	})
+
+	return workspace, nil
+}
+
+func (s *WorkspaceService) UpdateWorkspace(
+	user *users_models.User,
+	workspaceID uuid.UUID,
+	request *SaveWorkspaceRequest,
+) (*Workspace, error) {
+	if err := s.CheckAccess(user, workspaceID, user_enums.UserRoleAdmin); err != nil {
+		return nil, err
+	}
+
+	workspace, err := s.workspaceRepository.FindByID(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	oldWorkspace := *workspace
+	workspace.Name = request.Name
+
+	if err := s.workspaceRepository.Save(workspace); err != nil {
+		return nil, err
+	}
+
+	s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+		Action:     audit_logs.AuditLogActionUpdate,
+		TargetType: audit_logs.AuditLogTargetTypeWorkspace,
+		TargetID:   workspace.ID,
+		TargetName: workspace.Name,
+		Message: fmt.Sprintf(
+			"Renamed workspace \"%s\" to \"%s\"",
+			oldWorkspace.Name,
+			workspace.Name,
+		),
+		Diff: audit_logs.CalculateDiff(&oldWorkspace, workspace),
+	})
+
+	return workspace, nil
+}
+
+func (s *WorkspaceService) DeleteWorkspace(
+	user *users_models.User,
+	workspaceID uuid.UUID,
+) error {
+	if user.Role != user_enums.UserRoleAdmin {
+		return errors.New("only admins can delete workspaces")
+	}
+
+	workspace, err := s.workspaceRepository.FindByID(workspaceID)
+	if err != nil {
+		return err
+	}
+
+	for _, listener := range s.workspaceRemoveListeners {
+		if err := listener.OnBeforeWorkspaceRemove(workspaceID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.workspaceRepository.DeleteByID(workspaceID); err != nil {
+		return err
+	}
+
+	s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+		Action:     audit_logs.AuditLogActionDelete,
+		TargetType: audit_logs.AuditLogTargetTypeWorkspace,
+		TargetID:   workspace.ID,
+		TargetName: workspace.Name,
+		Message:    fmt.Sprintf("Deleted workspace \"%s\"", workspace.Name),
+		Diff:       audit_logs.CalculateDiff(workspace, nil),
+	})
+
+	return nil
+}
+
+func (s *WorkspaceService) GetWorkspace(
+	user *users_models.User,
+	workspaceID uuid.UUID,
+) (*Workspace, error) {
+	if err := s.CheckAccess(user, workspaceID, user_enums.UserRoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.workspaceRepository.FindByID(workspaceID)
+}
+
+// GetWorkspaces returns workspaces available to the user
+func (s *WorkspaceService) GetWorkspaces(user *users_models.User) ([]*Workspace, error) {
+	if user.Role == user_enums.UserRoleAdmin {
+		return s.workspaceRepository.FindAll()
+	}
+
+	return s.workspaceRepository.FindByMemberUserID(user.ID)
+}
+
+func (s *WorkspaceService) GetWorkspaceIDs(user *users_models.User) ([]uuid.UUID, error) {
+	workspaces, err := s.GetWorkspaces(user)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaceIDs := make([]uuid.UUID, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		workspaceIDs = append(workspaceIDs, workspace.ID)
+	}
+
+	return workspaceIDs, nil
+}
+
+// ResolveWorkspaceID returns the given workspace ID or, if it is
+// empty, ID of the first workspace available to the user. It allows
+// to create resources without choosing workspace when there is one
+func (s *WorkspaceService) ResolveWorkspaceID(
+	user *users_models.User,
+	workspaceID uuid.UUID,
+) (uuid.UUID, error) {
+	if workspaceID != uuid.Nil {
+		return workspaceID, nil
+	}
+
+	workspaces, err := s.GetWorkspaces(user)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if len(workspaces) == 0 {
+		return uuid.Nil, errors.New("user is not a member of any workspace")
+	}
+
+	return workspaces[0].ID, nil
+}
+
+func (s *WorkspaceService) GetMembers(
+	user *users_models.User,
+	workspaceID uuid.UUID,
+) ([]*WorkspaceMember, error) {
+	if err := s.CheckAccess(user, workspaceID, user_enums.UserRoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.workspaceMemberRepository.FindByWorkspaceID(workspaceID)
+}
+
+// SaveMember adds the user with the email to the workspace or
+// changes role of the user if the user is already a member
+func (s *WorkspaceService) SaveMember(
+	user *users_models.User,
+	workspaceID uuid.UUID,
+	request *SaveWorkspaceMemberRequest,
+) (*WorkspaceMember, error) {
+	if err := request.Role.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.CheckAccess(user, workspaceID, user_enums.UserRoleAdmin); err != nil {
+		return nil, err
+	}
+
+	workspace, err := s.workspaceRepository.FindByID(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	memberUser, err := s.userService.GetUserByEmail(request.Email)
+	if err != nil {
+		return nil, errors.New("user with this email does not exist")
+	}
+
+	member, err := s.workspaceMemberRepository.FindByWorkspaceIdAndUserID(
+		workspaceID,
+		memberUser.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	action := audit_logs.AuditLogActionUpdate
+	var oldRole any
+
+	if member == nil {
+		action = audit_logs.AuditLogActionCreate
+		member = &WorkspaceMember{
+			WorkspaceID: workspaceID,
+			UserID:      memberUser.ID,
+			CreatedAt:   time.Now().UTC(),
+		}
+	} else {
+		oldRole = member.Role
+	}
+
+	member.Role = request.Role
+
+	if err := s.workspaceMemberRepository.Save(member); err != nil {
+		return nil, err
+	}
+
+	s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+		Action:     action,
+		TargetType: audit_logs.AuditLogTargetTypeWorkspaceMember,
+		TargetID:   member.ID,
+		TargetName: memberUser.Email,
+		Message: fmt.Sprintf(
+			"Set role %s for user %s in workspace \"%s\"",
+			member.Role,
+			memberUser.Email,
+			workspace.Name,
+		),
+		Diff: audit_logs.AuditLogDiff{
+			"role": {Old: oldRole, New: member.Role},
+		},
+	})
+
+	member.User = memberUser
+
+	return member, nil
+}
+
+func (s *WorkspaceService) RemoveMember(
+	user *users_models.User,
+	workspaceID uuid.UUID,
+	memberUserID uuid.UUID,
+) error {
+	if err := s.CheckAccess(user, workspaceID, user_enums.UserRoleAdmin); err != nil {
+		return err
+	}
+
+	workspace, err := s.workspaceRepository.FindByID(workspaceID)
+	if err != nil {
+		return err
+	}
+
+	member, err := s.workspaceMemberRepository.FindByWorkspaceIdAndUserID(
+		workspaceID,
+		memberUserID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if member == nil {
+		return errors.New("user is not a member of the workspace")
+	}
+
+	if err := s.workspaceMemberRepository.DeleteByID(member.ID); err != nil {
+		return err
+	}
+
+	s.auditLogService.WriteAuditLog(user, &audit_logs.AuditLogEntry{
+		Action:     audit_logs.AuditLogActionDelete,
+		TargetType: audit_logs.AuditLogTargetTypeWorkspaceMember,
+		TargetID:   member.ID,
+		TargetName: member.User.Email,
+		Message: fmt.Sprintf(
+			"Removed user %s from workspace \"%s\"",
+			member.User.Email,
+			workspace.Name,
+		),
+		Diff: audit_logs.AuditLogDiff{
+			"role": {Old: member.Role, New: nil},
+		},
+	})
+
+	return nil
+}
+
+// CheckAccess returns error if the user has no access to the
+// workspace or has lower role in it than required
+func (s *WorkspaceService) CheckAccess(
+	user *users_models.User,
+	workspaceID uuid.UUID,
+	role user_enums.UserRole,
+) error {
+	if user.Role == user_enums.UserRoleAdmin {
+		return nil
+	}
+
+	member, err := s.workspaceMemberRepository.FindByWorkspaceIdAndUserID(workspaceID, user.ID)
+	if err != nil {
+		return err
+	}
+
+	if member == nil {
+		return errors.New("user does not have access to this workspace")
+	}
+
+	if !member.Role.IsAtLeast(role) {
+		return errors.New("insufficient permissions in this workspace")
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package workspaces
+
+import (
+	user_enums "postgresus-backend/internal/features/users/enums"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetTestWorkspace returns the first workspace of the installation,
+// test resources are created in it unless another one is needed
+func GetTestWorkspace() *Workspace {
+	workspaces, err := workspaceRepository.FindAll()
+	if err != nil {
+		panic(err)
+	}
+
+	if len(workspaces) > 0 {
+		return workspaces[0]
+	}
+
+	workspace := &Workspace{
+		Name:      "Test Workspace",
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := workspaceRepository.Save(workspace); err != nil {
+		panic(err)
+	}
+
+	return workspace
+}
+
+func AddTestWorkspaceMember(
+	workspaceID uuid.UUID,
+	userID uuid.UUID,
+	role user_enums.UserRole,
+) *WorkspaceMember {
+	member := &WorkspaceMember{
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		Role:        role,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	if err := workspaceMemberRepository.Save(member); err != nil {
+		panic(err)
+	}
+
+	return member
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"os"
 	"postgresus-backend/internal/config"
+	"postgresus-backend/internal/util/encryption"
 	"postgresus-backend/internal/util/logger"
 	"sync"
 
@@ -51,5 +52,14 @@ func LoadMainDb() {
 
 	db = database
 
+	// secret fields of models are encrypted on write and decrypted on
+	// read via data keys of this database. Keys are loaded at once, so
+	// wrong master key is reported on start
+	encryption.Setup(db)
+	if err := encryption.LoadKeys(); err != nil {
+		log.Error("error loading encryption keys", "error", err)
+		os.Exit(1)
+	}
+
 	log.Info("Main database connected successfully!")
 }
@@ -0,0 +1,124 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const keySize = 32
+
+// passphrases are derived by Argon2id with parameters recommended by
+// RFC 9106 for memory constrained environments. Salt is fixed because
+// the key should be derived the same way on each start
+const (
+	passphraseSalt    = "postgresus-encryption-master-key"
+	passphraseTime    = 3
+	passphraseMemory  = 64 * 1024
+	passphraseThreads = 4
+)
+
+// parseMasterKey accepts base64 encoded 32 bytes key. Any other value
+// is treated as a passphrase and derived to the key by Argon2id, so
+// weak passphrases are expensive to brute force
+func parseMasterKey(value string) []byte {
+	value = strings.TrimSpace(value)
+
+	if key, ok := decodeMasterKey(value); ok {
+		return key
+	}
+
+	return argon2.IDKey(
+		[]byte(value),
+		[]byte(passphraseSalt),
+		passphraseTime,
+		passphraseMemory,
+		passphraseThreads,
+		keySize,
+	)
+}
+
+// parseLegacyMasterKey returns the key derived from the passphrase by
+// SHA-256 as it was done before Argon2id. Data keys wrapped by it are
+// unwrapped once and wrapped again by the new key. Returns nil for
+// base64 encoded keys, they are used as is
+func parseLegacyMasterKey(value string) []byte {
+	value = strings.TrimSpace(value)
+
+	if _, ok := decodeMasterKey(value); ok {
+		return nil
+	}
+
+	key := sha256.Sum256([]byte(value))
+	return key[:]
+}
+
+func decodeMasterKey(value string) ([]byte, bool) {
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(key) != keySize {
+		return nil, false
+	}
+
+	return key, true
+}
+
+func generateKey() ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate encryption key: %w", err)
+	}
+
+	return key, nil
+}
+
+// seal encrypts the data with AES-256-GCM. Random nonce is prepended
+// to the result
+func seal(key []byte, plaintext []byte, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key []byte, ciphertext []byte, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+
+	nonce, ciphertext := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, errors.New("failed to decrypt, the key is wrong or data is corrupted")
+	}
+
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
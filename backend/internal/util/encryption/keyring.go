@@ -0,0 +1,525 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"postgresus-backend/internal/config"
+	"postgresus-backend/internal/util/logger"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// encryptedValuePrefix marks encrypted values, values without it are
+// plain text written before encryption was introduced
+const encryptedValuePrefix = "enc:v1:"
+
+// keysReloadInterval limits how long the instance keeps encrypting by
+// the data key which was replaced by rotation in another process
+const keysReloadInterval = time.Minute
+
+// DataKey encrypts values of secret fields. Data keys are stored
+// encrypted by the master key, so the master key never gets into the
+// database and can be changed without re-encryption of all values
+type DataKey struct {
+	ID           uuid.UUID `gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()"`
+	EncryptedKey string    `gorm:"column:encrypted_key;not null"`
+	IsActive     bool      `gorm:"column:is_active;not null"`
+	CreatedAt    time.Time `gorm:"column:created_at;not null"`
+}
+
+func (DataKey) TableName() string {
+	return "encryption_keys"
+}
+
+// keyring keeps decrypted data keys in memory. New values are
+// encrypted by the active key, old values are decrypted by the key
+// which ID is stored inside the value
+type keyring struct {
+	db *gorm.DB
+
+	mutex       sync.RWMutex
+	loadedAt    *time.Time
+	masterKeys  [][]byte
+	dataKeys    map[uuid.UUID][]byte
+	activeKeyID uuid.UUID
+}
+
+var defaultKeyring *keyring
+
+// Setup makes secret fields of models encrypted via the given
+// database. Fields are marked by `gorm:"serializer:encrypted"` tag
+func Setup(db *gorm.DB) {
+	defaultKeyring = &keyring{db: db}
+	registerSerializer()
+}
+
+// LoadKeys loads data keys, so wrong master key is reported at once
+// and keys encrypted by the previous master key are re-encrypted by
+// the current one
+func LoadKeys() error {
+	k, err := getKeyring()
+	if err != nil {
+		return err
+	}
+
+	return k.ensureLoaded()
+}
+
+// Encrypt encrypts the value by the active data key. Empty values are
+// kept empty, so "not set" is still visible without decryption
+func Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	k, err := getKeyring()
+	if err != nil {
+		return "", err
+	}
+
+	return k.encrypt(plaintext)
+}
+
+// Decrypt returns the value encrypted by Encrypt. Plain text values
+// are returned as is
+func Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	k, err := getKeyring()
+	if err != nil {
+		return "", err
+	}
+
+	return k.decrypt(value)
+}
+
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedValuePrefix)
+}
+
+// IsEncryptedWithActiveKey reports whether the value does not need
+// re-encryption after the data key is rotated
+func IsEncryptedWithActiveKey(value string) (bool, error) {
+	if !IsEncrypted(value) {
+		return false, nil
+	}
+
+	k, err := getKeyring()
+	if err != nil {
+		return false, err
+	}
+
+	keyID, _, err := parseEncryptedValue(value)
+	if err != nil {
+		return false, err
+	}
+
+	activeKeyID, err := k.getActiveKeyID()
+	if err != nil {
+		return false, err
+	}
+
+	return keyID == activeKeyID, nil
+}
+
+// RotateDataKey creates a new active data key. Previous keys are
+// kept to decrypt existing values until they are re-encrypted
+func RotateDataKey() error {
+	k, err := getKeyring()
+	if err != nil {
+		return err
+	}
+
+	return k.rotate()
+}
+
+// DeleteInactiveDataKeys removes keys replaced by rotation. It should
+// be called only after all values are re-encrypted by the active key
+func DeleteInactiveDataKeys() error {
+	k, err := getKeyring()
+	if err != nil {
+		return err
+	}
+
+	return k.deleteInactiveKeys()
+}
+
+func getKeyring() (*keyring, error) {
+	if defaultKeyring == nil {
+		return nil, errors.New("encryption is not set up")
+	}
+
+	return defaultKeyring, nil
+}
+
+func (k *keyring) encrypt(plaintext string) (string, error) {
+	keyID, err := k.getActiveKeyID()
+	if err != nil {
+		return "", err
+	}
+
+	key, err := k.getDataKey(keyID)
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := seal(key, []byte(plaintext), []byte(keyID.String()))
+	if err != nil {
+		return "", err
+	}
+
+	return encryptedValuePrefix + keyID.String() + ":" +
+		base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+func (k *keyring) decrypt(value string) (string, error) {
+	keyID, ciphertext, err := parseEncryptedValue(value)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := k.getDataKey(keyID)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := open(key, ciphertext, []byte(keyID.String()))
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+func (k *keyring) getActiveKeyID() (uuid.UUID, error) {
+	if err := k.ensureLoaded(); err != nil {
+		return uuid.Nil, err
+	}
+
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+
+	return k.activeKeyID, nil
+}
+
+// getDataKey returns the key by ID. Keys are reloaded once if the key
+// is unknown, because another instance may have rotated them
+func (k *keyring) getDataKey(keyID uuid.UUID) ([]byte, error) {
+	if err := k.ensureLoaded(); err != nil {
+		return nil, err
+	}
+
+	k.mutex.RLock()
+	key, ok := k.dataKeys[keyID]
+	k.mutex.RUnlock()
+
+	if ok {
+		return key, nil
+	}
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if err := k.load(); err != nil {
+		return nil, err
+	}
+
+	key, ok = k.dataKeys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("encryption key %s is not found", keyID)
+	}
+
+	return key, nil
+}
+
+func (k *keyring) ensureLoaded() error {
+	k.mutex.RLock()
+	isFresh := k.isFresh()
+	k.mutex.RUnlock()
+
+	if isFresh {
+		return nil
+	}
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if k.isFresh() {
+		return nil
+	}
+
+	return k.load()
+}
+
+func (k *keyring) isFresh() bool {
+	return k.loadedAt != nil && time.Since(*k.loadedAt) < keysReloadInterval
+}
+
+// load decrypts data keys by the master key. Keys encrypted by the
+// previous master key are re-encrypted by the current one, so the
+// previous master key is needed only once
+func (k *keyring) load() error {
+	if k.masterKeys == nil {
+		masterKeys, err := loadMasterKeys()
+		if err != nil {
+			return err
+		}
+
+		k.masterKeys = masterKeys
+	}
+
+	var storedKeys []*DataKey
+	if err := k.db.Order("created_at ASC").Find(&storedKeys).Error; err != nil {
+		return fmt.Errorf("failed to load encryption keys: %w", err)
+	}
+
+	dataKeys := make(map[uuid.UUID][]byte, len(storedKeys))
+	activeKeyID := uuid.Nil
+
+	for _, storedKey := range storedKeys {
+		key, masterKeyIndex, err := k.unwrapKey(storedKey)
+		if err != nil {
+			return err
+		}
+
+		if masterKeyIndex > 0 {
+			if err := k.rewrapKey(storedKey, key); err != nil {
+				return err
+			}
+		}
+
+		dataKeys[storedKey.ID] = key
+		if storedKey.IsActive {
+			activeKeyID = storedKey.ID
+		}
+	}
+
+	k.dataKeys = dataKeys
+	k.activeKeyID = activeKeyID
+
+	if k.activeKeyID == uuid.Nil {
+		if err := k.createActiveKey(); err != nil {
+			return err
+		}
+	}
+
+	loadedAt := time.Now().UTC()
+	k.loadedAt = &loadedAt
+
+	return nil
+}
+
+func (k *keyring) rotate() error {
+	if err := k.ensureLoaded(); err != nil {
+		return err
+	}
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	return k.createActiveKey()
+}
+
+func (k *keyring) deleteInactiveKeys() error {
+	if err := k.ensureLoaded(); err != nil {
+		return err
+	}
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if err := k.db.Where("is_active = ?", false).Delete(&DataKey{}).Error; err != nil {
+		return err
+	}
+
+	for keyID := range k.dataKeys {
+		if keyID != k.activeKeyID {
+			delete(k.dataKeys, keyID)
+		}
+	}
+
+	return nil
+}
+
+// createActiveKey makes a new data key active. Should be called under
+// write lock
+func (k *keyring) createActiveKey() error {
+	key, err := generateKey()
+	if err != nil {
+		return err
+	}
+
+	dataKey := &DataKey{
+		ID:        uuid.New(),
+		IsActive:  true,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	wrappedKey, err := seal(k.masterKeys[0], key, []byte(dataKey.ID.String()))
+	if err != nil {
+		return err
+	}
+	dataKey.EncryptedKey = base64.StdEncoding.EncodeToString(wrappedKey)
+
+	err = k.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&DataKey{}).
+			Where("is_active = ?", true).
+			Update("is_active", false).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(dataKey).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save encryption key: %w", err)
+	}
+
+	k.dataKeys[dataKey.ID] = key
+	k.activeKeyID = dataKey.ID
+
+	return nil
+}
+
+// unwrapKey decrypts the data key and returns the index of the master
+// key which decrypted it
+func (k *keyring) unwrapKey(storedKey *DataKey) ([]byte, int, error) {
+	wrappedKey, err := base64.StdEncoding.DecodeString(storedKey.EncryptedKey)
+	if err != nil {
+		return nil, 0, fmt.Errorf("encryption key %s is corrupted: %w", storedKey.ID, err)
+	}
+
+	for index, masterKey := range k.masterKeys {
+		key, err := open(masterKey, wrappedKey, []byte(storedKey.ID.String()))
+		if err == nil {
+			return key, index, nil
+		}
+	}
+
+	return nil, 0, fmt.Errorf(
+		"encryption key %s cannot be decrypted, check ENCRYPTION_MASTER_KEY",
+		storedKey.ID,
+	)
+}
+
+func (k *keyring) rewrapKey(storedKey *DataKey, key []byte) error {
+	wrappedKey, err := seal(k.masterKeys[0], key, []byte(storedKey.ID.String()))
+	if err != nil {
+		return err
+	}
+
+	storedKey.EncryptedKey = base64.StdEncoding.EncodeToString(wrappedKey)
+
+	if err := k.db.Save(storedKey).Error; err != nil {
+		return fmt.Errorf("failed to re-encrypt encryption key: %w", err)
+	}
+
+	logger.GetLogger().Info("Encryption key re-encrypted by new master key", "keyId", storedKey.ID)
+
+	return nil
+}
+
+func parseEncryptedValue(value string) (uuid.UUID, []byte, error) {
+	keyIDAndCiphertext := strings.SplitN(strings.TrimPrefix(value, encryptedValuePrefix), ":", 2)
+	if len(keyIDAndCiphertext) != 2 {
+		return uuid.Nil, nil, errors.New("invalid encrypted value")
+	}
+
+	keyID, err := uuid.Parse(keyIDAndCiphertext[0])
+	if err != nil {
+		return uuid.Nil, nil, errors.New("invalid key ID of encrypted value")
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(keyIDAndCiphertext[1])
+	if err != nil {
+		return uuid.Nil, nil, errors.New("invalid encrypted value")
+	}
+
+	return keyID, ciphertext, nil
+}
+
+// loadMasterKeys returns the current master key and, if set, the
+// previous one. If master key is not configured, it is generated once
+// and kept in the data folder, which is convenient but weaker than the
+// key kept apart from the data
+func loadMasterKeys() ([][]byte, error) {
+	env := config.GetEnv()
+
+	masterKey := env.EncryptionMasterKey
+	if masterKey == "" {
+		fileMasterKey, err := loadOrCreateMasterKeyFile(env.EncryptionMasterKeyPath)
+		if err != nil {
+			return nil, err
+		}
+
+		masterKey = fileMasterKey
+	}
+
+	masterKeyValues := []string{masterKey}
+	if env.EncryptionPreviousMasterKey != "" {
+		masterKeyValues = append(masterKeyValues, env.EncryptionPreviousMasterKey)
+	}
+
+	masterKeys := [][]byte{}
+	for _, value := range masterKeyValues {
+		masterKeys = append(masterKeys, parseMasterKey(value))
+	}
+
+	// passphrases were hashed by SHA-256 before, data keys wrapped by
+	// such keys are wrapped again by the current key on load
+	for _, value := range masterKeyValues {
+		if legacyKey := parseLegacyMasterKey(value); legacyKey != nil {
+			masterKeys = append(masterKeys, legacyKey)
+		}
+	}
+
+	return masterKeys, nil
+}
+
+func loadOrCreateMasterKeyFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err == nil {
+		return strings.TrimSpace(string(content)), nil
+	}
+
+	if !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read master key file: %w", err)
+	}
+
+	key, err := generateKey()
+	if err != nil {
+		return "", err
+	}
+
+	masterKey := base64.StdEncoding.EncodeToString(key)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", fmt.Errorf("failed to create master key folder: %w", err)
+	}
+
+	if err := os.WriteFile(path, []byte(masterKey), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write master key file: %w", err)
+	}
+
+	// the key lies next to the encrypted credentials, so anyone with
+	// a copy of the data volume can decrypt them
+	logger.GetLogger().Warn(
+		"!!! ENCRYPTION_MASTER_KEY is not set, generated master key is saved to the data "+
+			"folder. Anyone who gets a copy of the data folder (e.g. its backup) can "+
+			"decrypt all passwords and tokens. Move the key out of the data folder: set "+
+			"its content to ENCRYPTION_MASTER_KEY and delete the file. Until then, keep "+
+			"the file backed up separately, credentials cannot be decrypted without it",
+		"path",
+		path,
+	)
+
+	return masterKey, nil
+}
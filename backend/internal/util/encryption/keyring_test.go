@@ -0,0 +1,145 @@
+package encryption
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EncryptAndDecrypt_OriginalValueReturned(t *testing.T) {
+	setUpTestKeyring(t)
+
+	encryptedValue, err := Encrypt("my-password")
+	require.NoError(t, err)
+
+	assert.True(t, IsEncrypted(encryptedValue))
+	assert.NotContains(t, encryptedValue, "my-password")
+
+	decryptedValue, err := Decrypt(encryptedValue)
+	require.NoError(t, err)
+	assert.Equal(t, "my-password", decryptedValue)
+}
+
+func Test_EncryptSameValueTwice_DifferentValuesReturned(t *testing.T) {
+	setUpTestKeyring(t)
+
+	firstValue, err := Encrypt("my-password")
+	require.NoError(t, err)
+
+	secondValue, err := Encrypt("my-password")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, firstValue, secondValue)
+}
+
+func Test_EncryptEmptyValue_EmptyValueReturned(t *testing.T) {
+	setUpTestKeyring(t)
+
+	encryptedValue, err := Encrypt("")
+	require.NoError(t, err)
+	assert.Empty(t, encryptedValue)
+}
+
+func Test_DecryptPlainValue_ValueReturnedAsIs(t *testing.T) {
+	setUpTestKeyring(t)
+
+	decryptedValue, err := Decrypt("plain-password")
+	require.NoError(t, err)
+	assert.Equal(t, "plain-password", decryptedValue)
+}
+
+func Test_DecryptModifiedValue_ErrorReturned(t *testing.T) {
+	setUpTestKeyring(t)
+
+	encryptedValue, err := Encrypt("my-password")
+	require.NoError(t, err)
+
+	keyIDAndCiphertext := strings.SplitN(
+		strings.TrimPrefix(encryptedValue, encryptedValuePrefix),
+		":",
+		2,
+	)
+	ciphertext, err := base64.StdEncoding.DecodeString(keyIDAndCiphertext[1])
+	require.NoError(t, err)
+	ciphertext[len(ciphertext)-1] ^= 0xFF
+
+	modifiedValue := encryptedValuePrefix + keyIDAndCiphertext[0] + ":" +
+		base64.StdEncoding.EncodeToString(ciphertext)
+
+	_, err = Decrypt(modifiedValue)
+	assert.Error(t, err)
+}
+
+func Test_UnwrapKeyEncryptedByPreviousMasterKey_KeyAndIndexReturned(t *testing.T) {
+	previousMasterKey := parseMasterKey("previous-master-key")
+	currentMasterKey := parseMasterKey("current-master-key")
+
+	dataKey, err := generateKey()
+	require.NoError(t, err)
+
+	keyID := uuid.New()
+	wrappedKey, err := seal(previousMasterKey, dataKey, []byte(keyID.String()))
+	require.NoError(t, err)
+
+	k := &keyring{masterKeys: [][]byte{currentMasterKey, previousMasterKey}}
+
+	unwrappedKey, masterKeyIndex, err := k.unwrapKey(&DataKey{
+		ID:           keyID,
+		EncryptedKey: base64.StdEncoding.EncodeToString(wrappedKey),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, dataKey, unwrappedKey)
+	assert.Equal(t, 1, masterKeyIndex)
+
+	k.masterKeys = [][]byte{currentMasterKey}
+	_, _, err = k.unwrapKey(&DataKey{
+		ID:           keyID,
+		EncryptedKey: base64.StdEncoding.EncodeToString(wrappedKey),
+	})
+	assert.ErrorContains(t, err, "ENCRYPTION_MASTER_KEY")
+}
+
+func Test_ParseMasterKey_Base64KeyUsedAsIsAndPassphraseDerived(t *testing.T) {
+	key, err := generateKey()
+	require.NoError(t, err)
+
+	assert.Equal(t, key, parseMasterKey(base64.StdEncoding.EncodeToString(key)))
+	assert.Nil(t, parseLegacyMasterKey(base64.StdEncoding.EncodeToString(key)))
+
+	passphraseKey := parseMasterKey("short passphrase")
+	legacyPassphraseKey := sha256.Sum256([]byte("short passphrase"))
+
+	assert.Len(t, passphraseKey, keySize)
+	assert.Equal(t, passphraseKey, parseMasterKey(" short passphrase "))
+	assert.NotEqual(t, legacyPassphraseKey[:], passphraseKey)
+	assert.Equal(t, legacyPassphraseKey[:], parseLegacyMasterKey("short passphrase"))
+}
+
+func setUpTestKeyring(t *testing.T) {
+	dataKey, err := generateKey()
+	require.NoError(t, err)
+
+	keyID := uuid.New()
+	// keys are "loaded" in the future, so they are never reloaded from
+	// database, which is absent in this test
+	loadedAt := time.Now().UTC().Add(time.Hour)
+
+	previousKeyring := defaultKeyring
+	defaultKeyring = &keyring{
+		loadedAt:    &loadedAt,
+		masterKeys:  [][]byte{parseMasterKey("test-master-key")},
+		dataKeys:    map[uuid.UUID][]byte{keyID: dataKey},
+		activeKeyID: keyID,
+	}
+
+	t.Cleanup(func() {
+		defaultKeyring = previousKeyring
+	})
+}
@@ -0,0 +1,59 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName is used in `gorm:"serializer:encrypted"` tag of
+// string fields which should be stored encrypted
+const SerializerName = "encrypted"
+
+type encryptedSerializer struct{}
+
+func registerSerializer() {
+	schema.RegisterSerializer(SerializerName, encryptedSerializer{})
+}
+
+func (encryptedSerializer) Scan(
+	ctx context.Context,
+	field *schema.Field,
+	dst reflect.Value,
+	dbValue any,
+) error {
+	var value string
+	switch typedValue := dbValue.(type) {
+	case nil:
+		return field.Set(ctx, dst, "")
+	case string:
+		value = typedValue
+	case []byte:
+		value = string(typedValue)
+	default:
+		return fmt.Errorf("unsupported value type %T of encrypted field %s", dbValue, field.Name)
+	}
+
+	plaintext, err := Decrypt(value)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt field %s: %w", field.Name, err)
+	}
+
+	return field.Set(ctx, dst, plaintext)
+}
+
+func (encryptedSerializer) Value(
+	_ context.Context,
+	field *schema.Field,
+	_ reflect.Value,
+	fieldValue any,
+) (any, error) {
+	plaintext, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("encrypted field %s should be a string", field.Name)
+	}
+
+	return Encrypt(plaintext)
+}
@@ -0,0 +1,166 @@
+package secret_refs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"postgresus-backend/internal/config"
+	"strings"
+)
+
+// Credentials can be saved as references to secrets kept outside of
+// Postgresus. References are resolved each time the secret is used,
+// so rotated secrets are picked up without editing Postgresus
+const (
+	vaultPrefix = "vault://"
+	envPrefix   = "env://"
+	filePrefix  = "file://"
+
+	// only variables with this prefix can be referenced, otherwise
+	// anybody who can edit a database would be able to read any
+	// variable of the server (including ENCRYPTION_MASTER_KEY)
+	allowedEnvPrefix = "POSTGRESUS_SECRET_"
+)
+
+// IsReference checks whether the value is a reference to an external
+// secret rather than the secret itself
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, vaultPrefix) ||
+		strings.HasPrefix(value, envPrefix) ||
+		strings.HasPrefix(value, filePrefix)
+}
+
+// Validate checks the format of the reference without resolving it,
+// so credentials can be saved before the secret is created
+func Validate(value string) error {
+	switch {
+	case strings.HasPrefix(value, vaultPrefix):
+		_, err := parseVaultReference(strings.TrimPrefix(value, vaultPrefix))
+		return err
+	case strings.HasPrefix(value, envPrefix):
+		return validateEnvName(strings.TrimPrefix(value, envPrefix))
+	case strings.HasPrefix(value, filePrefix):
+		_, err := getSecretFilePath(
+			config.GetEnv().SecretFilesDir,
+			strings.TrimPrefix(value, filePrefix),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// Resolve returns the secret referenced by the value. Values which are
+// not references are returned as is
+func Resolve(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, vaultPrefix):
+		client, err := newVaultClient()
+		if err != nil {
+			return "", err
+		}
+
+		return client.readSecret(strings.TrimPrefix(value, vaultPrefix))
+	case strings.HasPrefix(value, envPrefix):
+		return resolveEnvSecret(strings.TrimPrefix(value, envPrefix))
+	case strings.HasPrefix(value, filePrefix):
+		return resolveFileSecret(
+			config.GetEnv().SecretFilesDir,
+			strings.TrimPrefix(value, filePrefix),
+		)
+	}
+
+	return value, nil
+}
+
+func resolveEnvSecret(name string) (string, error) {
+	if err := validateEnvName(name); err != nil {
+		return "", err
+	}
+
+	value := os.Getenv(name)
+	if value == "" {
+		return "", fmt.Errorf("env variable %s is not set", name)
+	}
+
+	return value, nil
+}
+
+func validateEnvName(name string) error {
+	if !strings.HasPrefix(name, allowedEnvPrefix) || name == allowedEnvPrefix {
+		return fmt.Errorf("only env variables with %s prefix can be referenced", allowedEnvPrefix)
+	}
+
+	return nil
+}
+
+func resolveFileSecret(secretsDir, path string) (string, error) {
+	secretPath, err := getSecretFilePath(secretsDir, path)
+	if err != nil {
+		return "", err
+	}
+
+	// symlinks are resolved to be sure they do not lead out of the
+	// secrets folder. Mounted secrets of Kubernetes are symlinks too
+	realSecretsDir, err := filepath.EvalSymlinks(secretsDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secrets folder: %w", err)
+	}
+
+	realSecretPath, err := filepath.EvalSymlinks(secretPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file %s: %w", path, err)
+	}
+
+	if !isInsideDir(realSecretsDir, realSecretPath) {
+		return "", fmt.Errorf("secret file %s is outside of %s", path, secretsDir)
+	}
+
+	content, err := os.ReadFile(realSecretPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file %s: %w", path, err)
+	}
+
+	value := strings.TrimRight(string(content), "\r\n")
+	if value == "" {
+		return "", fmt.Errorf("secret file %s is empty", path)
+	}
+
+	return value, nil
+}
+
+// getSecretFilePath returns the path of the secret file. Relative paths
+// are relative to the secrets folder, absolute ones must be inside it
+func getSecretFilePath(secretsDir, path string) (string, error) {
+	if path == "" {
+		return "", errors.New("secret file path is required")
+	}
+
+	if secretsDir == "" {
+		return "", errors.New("SECRET_FILES_DIR is not set, secret files cannot be referenced")
+	}
+
+	secretPath := path
+	if !filepath.IsAbs(secretPath) {
+		secretPath = filepath.Join(secretsDir, secretPath)
+	}
+	secretPath = filepath.Clean(secretPath)
+
+	if !isInsideDir(secretsDir, secretPath) {
+		return "", fmt.Errorf("secret file %s is outside of %s", path, secretsDir)
+	}
+
+	return secretPath, nil
+}
+
+func isInsideDir(dir, path string) bool {
+	relativePath, err := filepath.Rel(filepath.Clean(dir), path)
+	if err != nil {
+		return false
+	}
+
+	return relativePath != "." &&
+		relativePath != ".." &&
+		!strings.HasPrefix(relativePath, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,130 @@
+package secret_refs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_IsReference_ReferencesDetected(t *testing.T) {
+	assert.True(t, IsReference("vault://secret/postgres#password"))
+	assert.True(t, IsReference("env://POSTGRESUS_SECRET_PG_PASSWORD"))
+	assert.True(t, IsReference("file://pg_password"))
+	assert.False(t, IsReference("my-password"))
+	assert.False(t, IsReference(""))
+}
+
+func Test_ResolveEnvSecret_VariableWithPrefix_ValueReturned(t *testing.T) {
+	t.Setenv("POSTGRESUS_SECRET_PG_PASSWORD", "my-password")
+
+	value, err := resolveEnvSecret("POSTGRESUS_SECRET_PG_PASSWORD")
+	require.NoError(t, err)
+	assert.Equal(t, "my-password", value)
+}
+
+func Test_ResolveEnvSecret_VariableWithoutPrefix_ErrorReturned(t *testing.T) {
+	t.Setenv("ENCRYPTION_MASTER_KEY", "master-key")
+
+	_, err := resolveEnvSecret("ENCRYPTION_MASTER_KEY")
+	assert.Error(t, err)
+
+	_, err = resolveEnvSecret("POSTGRESUS_SECRET_NOT_SET")
+	assert.Error(t, err)
+}
+
+func Test_ResolveFileSecret_FileInsideDir_ValueWithoutNewLineReturned(t *testing.T) {
+	secretsDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(secretsDir, "pg_password"), []byte("my-password\n"), 0600)
+	require.NoError(t, err)
+
+	value, err := resolveFileSecret(secretsDir, "pg_password")
+	require.NoError(t, err)
+	assert.Equal(t, "my-password", value)
+
+	value, err = resolveFileSecret(secretsDir, filepath.Join(secretsDir, "pg_password"))
+	require.NoError(t, err)
+	assert.Equal(t, "my-password", value)
+}
+
+func Test_ResolveFileSecret_FileOutsideDir_ErrorReturned(t *testing.T) {
+	rootDir := t.TempDir()
+	secretsDir := filepath.Join(rootDir, "secrets")
+	require.NoError(t, os.Mkdir(secretsDir, 0700))
+
+	outsideFile := filepath.Join(rootDir, "outside")
+	require.NoError(t, os.WriteFile(outsideFile, []byte("outside-secret"), 0600))
+	require.NoError(t, os.Symlink(outsideFile, filepath.Join(secretsDir, "link")))
+
+	_, err := resolveFileSecret(secretsDir, "../outside")
+	assert.Error(t, err)
+
+	_, err = resolveFileSecret(secretsDir, outsideFile)
+	assert.Error(t, err)
+
+	_, err = resolveFileSecret(secretsDir, "link")
+	assert.Error(t, err)
+}
+
+func Test_ReadVaultSecret_KvV2Secret_KeyValueReturned(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "test-token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+
+		if r.URL.Path != "/v1/secret/data/postgres/main" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{
+				"data":     map[string]any{"password": "vault-password"},
+				"metadata": map[string]any{"version": 1},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := &vaultClient{addr: server.URL, token: "test-token", httpClient: server.Client()}
+
+	value, err := client.readSecret("secret/postgres/main#password")
+	require.NoError(t, err)
+	assert.Equal(t, "vault-password", value)
+
+	_, err = client.readSecret("secret/postgres/main#username")
+	assert.Error(t, err)
+
+	_, err = client.readSecret("secret/postgres/other#password")
+	assert.Error(t, err)
+
+	client.token = "wrong-token"
+	_, err = client.readSecret("secret/postgres/main#password")
+	assert.ErrorContains(t, err, "permission denied")
+}
+
+func Test_ParseVaultReference_InvalidReference_ErrorReturned(t *testing.T) {
+	ref, err := parseVaultReference("secret/postgres#password")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", ref.Mount)
+	assert.Equal(t, "postgres", ref.Path)
+	assert.Equal(t, "password", ref.Key)
+
+	for _, reference := range []string{
+		"secret/postgres",
+		"secret#password",
+		"secret/postgres#",
+		"secret/../sys#password",
+	} {
+		_, err := parseVaultReference(reference)
+		assert.Error(t, err, reference)
+	}
+}
@@ -0,0 +1,147 @@
+package secret_refs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"postgresus-backend/internal/config"
+	"strings"
+	"time"
+)
+
+const vaultRequestTimeout = 15 * time.Second
+
+type vaultReference struct {
+	Mount string
+	Path  string
+	Key   string
+}
+
+type vaultClient struct {
+	addr       string
+	token      string
+	namespace  string
+	httpClient *http.Client
+}
+
+type vaultSecretResponse struct {
+	Data struct {
+		Data map[string]any `json:"data"`
+	} `json:"data"`
+}
+
+type vaultErrorResponse struct {
+	Errors []string `json:"errors"`
+}
+
+func newVaultClient() (*vaultClient, error) {
+	env := config.GetEnv()
+
+	if env.VaultAddr == "" || env.VaultToken == "" {
+		return nil, errors.New("VAULT_ADDR and VAULT_TOKEN are required to resolve vault secrets")
+	}
+
+	return &vaultClient{
+		addr:       env.VaultAddr,
+		token:      env.VaultToken,
+		namespace:  env.VaultNamespace,
+		httpClient: &http.Client{Timeout: vaultRequestTimeout},
+	}, nil
+}
+
+// readSecret reads the key of KV v2 secret, reference has
+// "mount/path/to/secret#key" format
+func (c *vaultClient) readSecret(reference string) (string, error) {
+	ref, err := parseVaultReference(reference)
+	if err != nil {
+		return "", err
+	}
+
+	secretURL, err := url.JoinPath(c.addr, "v1", ref.Mount, "data", ref.Path)
+	if err != nil {
+		return "", fmt.Errorf("invalid VAULT_ADDR: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), vaultRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, secretURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create vault request: %w", err)
+	}
+
+	req.Header.Set("X-Vault-Token", c.token)
+	if c.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request vault: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read vault response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResponse vaultErrorResponse
+		_ = json.Unmarshal(body, &errorResponse)
+
+		return "", fmt.Errorf(
+			"vault returned status %d for secret %s/%s: %s",
+			resp.StatusCode,
+			ref.Mount,
+			ref.Path,
+			strings.Join(errorResponse.Errors, "; "),
+		)
+	}
+
+	var secretResponse vaultSecretResponse
+	if err := json.Unmarshal(body, &secretResponse); err != nil {
+		return "", fmt.Errorf("failed to parse vault response: %w", err)
+	}
+
+	value, ok := secretResponse.Data.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in vault secret %s/%s", ref.Key, ref.Mount, ref.Path)
+	}
+
+	stringValue, ok := value.(string)
+	if !ok || stringValue == "" {
+		return "", fmt.Errorf(
+			"key %s of vault secret %s/%s is empty or not a string",
+			ref.Key,
+			ref.Mount,
+			ref.Path,
+		)
+	}
+
+	return stringValue, nil
+}
+
+func parseVaultReference(reference string) (*vaultReference, error) {
+	secretPath, key, isKeyFound := strings.Cut(reference, "#")
+	if !isKeyFound || key == "" {
+		return nil, errors.New("vault reference must have \"mount/path#key\" format")
+	}
+
+	mount, path, isPathFound := strings.Cut(strings.Trim(secretPath, "/"), "/")
+	if !isPathFound || mount == "" || path == "" {
+		return nil, errors.New("vault reference must have \"mount/path#key\" format")
+	}
+
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return nil, fmt.Errorf("invalid vault secret path %s", path)
+		}
+	}
+
+	return &vaultReference{Mount: mount, Path: path, Key: key}, nil
+}
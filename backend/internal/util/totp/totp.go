@@ -0,0 +1,127 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Codes follow RFC 6238 with the defaults of authenticator apps:
+// HMAC-SHA1, 6 digits and 30 seconds period
+const (
+	period = 30 * time.Second
+	digits = 6
+	// allowedSkewSteps is the number of periods before and after the
+	// current one accepted to tolerate clock drift of devices
+	allowedSkewSteps = 1
+)
+
+var base32Encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns random base32 encoded secret of 160 bits
+func GenerateSecret() (string, error) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate TOTP secret: %w", err)
+	}
+
+	return base32Encoding.EncodeToString(secret), nil
+}
+
+// GetOtpauthURL returns URL for QR code scanned by authenticator apps
+func GetOtpauthURL(issuer string, accountName string, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("digits", fmt.Sprintf("%d", digits))
+	query.Set("period", fmt.Sprintf("%d", int(period.Seconds())))
+
+	return (&url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + accountName,
+		RawQuery: query.Encode(),
+	}).String()
+}
+
+// GenerateCode returns the code of the secret at the given time
+func GenerateCode(secret string, at time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return generateCode(key, uint64(at.Unix())/uint64(period.Seconds())), nil
+}
+
+// ValidateCode checks the code against the current period and the
+// periods around it
+func ValidateCode(secret string, code string, at time.Time) bool {
+	_, isValid := GetCodeStep(secret, code, at)
+	return isValid
+}
+
+// GetCodeStep returns the period of the valid code. Callers keep the
+// last used period to reject the same code and codes issued before it
+func GetCodeStep(secret string, code string, at time.Time) (int64, bool) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != digits {
+		return 0, false
+	}
+
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return 0, false
+	}
+
+	currentStep := int64(at.Unix()) / int64(period.Seconds())
+	for skew := -allowedSkewSteps; skew <= allowedSkewSteps; skew++ {
+		step := currentStep + int64(skew)
+
+		expectedCode := generateCode(key, uint64(step))
+		if subtle.ConstantTimeCompare([]byte(expectedCode), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+func generateCode(key []byte, step uint64) string {
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, step)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message)
+	hash := mac.Sum(nil)
+
+	// dynamic truncation from RFC 4226
+	offset := hash[len(hash)-1] & 0x0f
+	value := binary.BigEndian.Uint32(hash[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for range digits {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%modulo)
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	normalizedSecret := strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	normalizedSecret = strings.TrimRight(normalizedSecret, "=")
+
+	key, err := base32Encoding.DecodeString(normalizedSecret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TOTP secret: %w", err)
+	}
+
+	return key, nil
+}
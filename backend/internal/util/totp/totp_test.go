@@ -0,0 +1,73 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// secret and codes are from RFC 6238 test vectors for SHA1, codes
+// are truncated to 6 digits
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func Test_GenerateCode_WhenRfcTestVectorsUsed_SameCodesReturned(t *testing.T) {
+	testCases := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unixTime, expectedCode := range testCases {
+		code, err := GenerateCode(rfcSecret, time.Unix(unixTime, 0))
+		require.NoError(t, err)
+		assert.Equal(t, expectedCode, code, "time %d", unixTime)
+	}
+}
+
+func Test_GetCodeStep_WhenCodeIsFromPreviousPeriod_PreviousStepReturned(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+
+	previousCode, err := GenerateCode(rfcSecret, now.Add(-period))
+	require.NoError(t, err)
+
+	step, isValid := GetCodeStep(rfcSecret, previousCode, now)
+	assert.True(t, isValid)
+	assert.Equal(t, int64(1234567890/30-1), step)
+}
+
+func Test_ValidateCode_WhenCodeIsFromNeighbourPeriod_CodeAccepted(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+
+	previousCode, err := GenerateCode(rfcSecret, now.Add(-period))
+	require.NoError(t, err)
+
+	assert.True(t, ValidateCode(rfcSecret, previousCode, now))
+}
+
+func Test_ValidateCode_WhenCodeIsOutdated_CodeRejected(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+
+	outdatedCode, err := GenerateCode(rfcSecret, now.Add(-3*period))
+	require.NoError(t, err)
+
+	assert.False(t, ValidateCode(rfcSecret, outdatedCode, now))
+	assert.False(t, ValidateCode(rfcSecret, "12345", now))
+}
+
+func Test_GenerateSecret_SecretIsUsableForCodes(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	code, err := GenerateCode(secret, now)
+	require.NoError(t, err)
+
+	assert.True(t, ValidateCode(secret, code, now))
+	assert.Contains(t, GetOtpauthURL("Postgresus", "john@example.com", secret), "secret="+secret)
+}
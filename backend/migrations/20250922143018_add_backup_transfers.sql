@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE backup_transfers (
+    id                        UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    database_id               UUID NOT NULL,
+    backup_id                 UUID,
+    source_storage_id         UUID NOT NULL,
+    target_storage_id         UUID NOT NULL,
+    is_delete_from_source     BOOLEAN NOT NULL DEFAULT FALSE,
+    status                    TEXT NOT NULL,
+    fail_message              TEXT,
+    total_backups_count       INT NOT NULL DEFAULT 0,
+    transferred_backups_count INT NOT NULL DEFAULT 0,
+    created_at                TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at               TIMESTAMPTZ
+);
+
+ALTER TABLE backup_transfers
+    ADD CONSTRAINT fk_backup_transfers_database_id
+    FOREIGN KEY (database_id)
+    REFERENCES databases (id)
+    ON DELETE CASCADE;
+
+ALTER TABLE backup_transfers
+    ADD CONSTRAINT fk_backup_transfers_source_storage_id
+    FOREIGN KEY (source_storage_id)
+    REFERENCES storages (id)
+    ON DELETE CASCADE;
+
+ALTER TABLE backup_transfers
+    ADD CONSTRAINT fk_backup_transfers_target_storage_id
+    FOREIGN KEY (target_storage_id)
+    REFERENCES storages (id)
+    ON DELETE CASCADE;
+
+CREATE INDEX idx_backup_transfers_database_id_created_at ON backup_transfers (database_id, created_at DESC);
+CREATE INDEX idx_backup_transfers_status ON backup_transfers (status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_backup_transfers_status;
+DROP INDEX IF EXISTS idx_backup_transfers_database_id_created_at;
+DROP TABLE IF EXISTS backup_transfers;
+-- +goose StatementEnd
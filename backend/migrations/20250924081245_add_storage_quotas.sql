@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE storages
+    ADD COLUMN quota_mb            DOUBLE PRECISION,
+    ADD COLUMN quota_exceed_action TEXT NOT NULL DEFAULT 'WARN';
+
+CREATE TABLE storage_usage_snapshots (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    storage_id UUID NOT NULL,
+    used_mb    DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE storage_usage_snapshots
+    ADD CONSTRAINT fk_storage_usage_snapshots_storage_id
+    FOREIGN KEY (storage_id)
+    REFERENCES storages (id)
+    ON DELETE CASCADE;
+
+CREATE INDEX idx_storage_usage_snapshots_storage_id_created_at ON storage_usage_snapshots (storage_id, created_at);
+
+UPDATE backup_configs
+SET send_notifications_on = send_notifications_on || ',STORAGE_QUOTA_WARNING'
+WHERE send_notifications_on LIKE '%BACKUP_FAILED%';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE backup_configs
+SET send_notifications_on = REPLACE(send_notifications_on, ',STORAGE_QUOTA_WARNING', '');
+
+DROP INDEX IF EXISTS idx_storage_usage_snapshots_storage_id_created_at;
+DROP TABLE IF EXISTS storage_usage_snapshots;
+
+ALTER TABLE storages
+    DROP COLUMN quota_mb,
+    DROP COLUMN quota_exceed_action;
+-- +goose StatementEnd
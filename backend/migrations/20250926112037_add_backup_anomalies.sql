@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE backups
+    ADD COLUMN is_anomaly      BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN anomaly_message TEXT;
+
+ALTER TABLE backup_configs
+    ADD COLUMN size_anomaly_threshold_percent     INT NOT NULL DEFAULT 50,
+    ADD COLUMN duration_anomaly_threshold_percent INT NOT NULL DEFAULT 100;
+
+UPDATE backup_configs
+SET send_notifications_on = send_notifications_on || ',BACKUP_ANOMALY'
+WHERE send_notifications_on LIKE '%BACKUP_FAILED%';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE backup_configs
+SET send_notifications_on = REPLACE(send_notifications_on, ',BACKUP_ANOMALY', '');
+
+ALTER TABLE backup_configs
+    DROP COLUMN size_anomaly_threshold_percent,
+    DROP COLUMN duration_anomaly_threshold_percent;
+
+ALTER TABLE backups
+    DROP COLUMN is_anomaly,
+    DROP COLUMN anomaly_message;
+-- +goose StatementEnd
@@ -0,0 +1,93 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE workspaces (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name       TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE workspace_members (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL,
+    user_id      UUID NOT NULL,
+    role         TEXT NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE workspace_members
+    ADD CONSTRAINT fk_workspace_members_workspace_id
+    FOREIGN KEY (workspace_id)
+    REFERENCES workspaces (id)
+    ON DELETE CASCADE;
+
+ALTER TABLE workspace_members
+    ADD CONSTRAINT fk_workspace_members_user_id
+    FOREIGN KEY (user_id)
+    REFERENCES users (id)
+    ON DELETE CASCADE;
+
+ALTER TABLE workspace_members
+    ADD CONSTRAINT uk_workspace_members_workspace_id_user_id
+    UNIQUE (workspace_id, user_id);
+
+CREATE INDEX idx_workspace_members_user_id ON workspace_members (user_id);
+
+-- existing databases, storages and notifiers
+-- are moved into the default workspace
+INSERT INTO workspaces (name)
+VALUES ('Default');
+
+INSERT INTO workspace_members (workspace_id, user_id, role)
+SELECT (SELECT id FROM workspaces LIMIT 1), id, role
+FROM users;
+
+ALTER TABLE databases ADD COLUMN workspace_id UUID;
+ALTER TABLE storages ADD COLUMN workspace_id UUID;
+ALTER TABLE notifiers ADD COLUMN workspace_id UUID;
+
+UPDATE databases SET workspace_id = (SELECT id FROM workspaces LIMIT 1);
+UPDATE storages SET workspace_id = (SELECT id FROM workspaces LIMIT 1);
+UPDATE notifiers SET workspace_id = (SELECT id FROM workspaces LIMIT 1);
+
+ALTER TABLE databases ALTER COLUMN workspace_id SET NOT NULL;
+ALTER TABLE storages ALTER COLUMN workspace_id SET NOT NULL;
+ALTER TABLE notifiers ALTER COLUMN workspace_id SET NOT NULL;
+
+ALTER TABLE databases
+    ADD CONSTRAINT fk_databases_workspace_id
+    FOREIGN KEY (workspace_id)
+    REFERENCES workspaces (id);
+
+ALTER TABLE storages
+    ADD CONSTRAINT fk_storages_workspace_id
+    FOREIGN KEY (workspace_id)
+    REFERENCES workspaces (id);
+
+ALTER TABLE notifiers
+    ADD CONSTRAINT fk_notifiers_workspace_id
+    FOREIGN KEY (workspace_id)
+    REFERENCES workspaces (id);
+
+CREATE INDEX idx_databases_workspace_id ON databases (workspace_id);
+CREATE INDEX idx_storages_workspace_id ON storages (workspace_id);
+CREATE INDEX idx_notifiers_workspace_id ON notifiers (workspace_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_notifiers_workspace_id;
+DROP INDEX IF EXISTS idx_storages_workspace_id;
+DROP INDEX IF EXISTS idx_databases_workspace_id;
+
+ALTER TABLE notifiers DROP CONSTRAINT IF EXISTS fk_notifiers_workspace_id;
+ALTER TABLE storages DROP CONSTRAINT IF EXISTS fk_storages_workspace_id;
+ALTER TABLE databases DROP CONSTRAINT IF EXISTS fk_databases_workspace_id;
+
+ALTER TABLE notifiers DROP COLUMN workspace_id;
+ALTER TABLE storages DROP COLUMN workspace_id;
+ALTER TABLE databases DROP COLUMN workspace_id;
+
+DROP INDEX IF EXISTS idx_workspace_members_user_id;
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
+-- +goose StatementEnd
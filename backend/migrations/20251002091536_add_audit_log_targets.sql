@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE audit_logs
+    ADD COLUMN actor_type  TEXT,
+    ADD COLUMN action      TEXT,
+    ADD COLUMN target_type TEXT,
+    ADD COLUMN target_id   UUID,
+    ADD COLUMN target_name TEXT,
+    ADD COLUMN diff        JSONB;
+
+UPDATE audit_logs
+SET actor_type  = 'USER',
+    action      = 'UNKNOWN',
+    target_type = 'UNKNOWN',
+    target_name = '';
+
+ALTER TABLE audit_logs
+    ALTER COLUMN actor_type SET NOT NULL,
+    ALTER COLUMN action SET NOT NULL,
+    ALTER COLUMN target_type SET NOT NULL,
+    ALTER COLUMN target_name SET NOT NULL;
+
+CREATE INDEX idx_audit_logs_action_created_at ON audit_logs (action, created_at DESC);
+CREATE INDEX idx_audit_logs_target_created_at ON audit_logs (target_type, target_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_audit_logs_target_created_at;
+DROP INDEX IF EXISTS idx_audit_logs_action_created_at;
+
+ALTER TABLE audit_logs
+    DROP COLUMN diff,
+    DROP COLUMN target_name,
+    DROP COLUMN target_id,
+    DROP COLUMN target_type,
+    DROP COLUMN action,
+    DROP COLUMN actor_type;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE user_sessions (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id            UUID NOT NULL,
+    refresh_token_hash TEXT NOT NULL,
+    user_agent         TEXT NOT NULL,
+    ip_address         TEXT NOT NULL,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at         TIMESTAMPTZ NOT NULL,
+    revoked_at         TIMESTAMPTZ
+);
+
+ALTER TABLE user_sessions
+    ADD CONSTRAINT fk_user_sessions_user_id
+    FOREIGN KEY (user_id)
+    REFERENCES users (id)
+    ON DELETE CASCADE;
+
+ALTER TABLE user_sessions
+    ADD CONSTRAINT uk_user_sessions_refresh_token_hash
+    UNIQUE (refresh_token_hash);
+
+CREATE INDEX idx_user_sessions_user_id ON user_sessions (user_id);
+
+-- existing key becomes the current one, tokens issued before
+-- sessions are rejected because they do not have session ID
+ALTER TABLE secret_keys
+    ADD COLUMN id         UUID NOT NULL DEFAULT gen_random_uuid(),
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN expires_at TIMESTAMPTZ;
+
+ALTER TABLE secret_keys ADD PRIMARY KEY (id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM secret_keys WHERE expires_at IS NOT NULL;
+
+ALTER TABLE secret_keys DROP CONSTRAINT IF EXISTS secret_keys_pkey;
+
+ALTER TABLE secret_keys
+    DROP COLUMN expires_at,
+    DROP COLUMN created_at,
+    DROP COLUMN id;
+
+DROP INDEX IF EXISTS idx_user_sessions_user_id;
+DROP TABLE IF EXISTS user_sessions;
+-- +goose StatementEnd
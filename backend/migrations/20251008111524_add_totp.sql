@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN totp_secret     TEXT NOT NULL DEFAULT '',
+    ADD COLUMN is_totp_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE user_recovery_codes (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id    UUID NOT NULL,
+    code_hash  TEXT NOT NULL,
+    used_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE user_recovery_codes
+    ADD CONSTRAINT fk_user_recovery_codes_user_id
+    FOREIGN KEY (user_id)
+    REFERENCES users (id)
+    ON DELETE CASCADE;
+
+CREATE INDEX idx_user_recovery_codes_user_id ON user_recovery_codes (user_id);
+
+CREATE TABLE security_settings (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    is_totp_required BOOLEAN NOT NULL DEFAULT FALSE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS security_settings;
+
+DROP INDEX IF EXISTS idx_user_recovery_codes_user_id;
+DROP TABLE IF EXISTS user_recovery_codes;
+
+ALTER TABLE users
+    DROP COLUMN is_totp_enabled,
+    DROP COLUMN totp_secret;
+-- +goose StatementEnd
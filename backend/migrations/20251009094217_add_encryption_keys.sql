@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE encryption_keys (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    encrypted_key TEXT NOT NULL,
+    is_active     BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- only one data key encrypts new values
+CREATE UNIQUE INDEX uk_encryption_keys_active ON encryption_keys (is_active) WHERE is_active;
+
+-- encrypted value is longer than the original one
+ALTER TABLE email_notifiers
+    ALTER COLUMN smtp_password TYPE TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS uk_encryption_keys_active;
+DROP TABLE IF EXISTS encryption_keys;
+-- +goose StatementEnd
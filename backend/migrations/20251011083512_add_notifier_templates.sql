@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE notifier_templates (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    notifier_id    UUID NOT NULL,
+    event_type     VARCHAR(50) NOT NULL,
+    title_template TEXT NOT NULL DEFAULT '',
+    body_template  TEXT NOT NULL DEFAULT ''
+);
+
+ALTER TABLE notifier_templates
+    ADD CONSTRAINT fk_notifier_templates_notifier
+    FOREIGN KEY (notifier_id)
+    REFERENCES notifiers (id)
+    ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED;
+
+CREATE UNIQUE INDEX uk_notifier_templates_notifier_event_type
+    ON notifier_templates (notifier_id, event_type);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS notifier_templates;
+
+-- +goose StatementEnd
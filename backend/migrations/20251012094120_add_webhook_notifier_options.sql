@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE webhook_notifiers
+    ADD COLUMN headers         TEXT NOT NULL DEFAULT '',
+    ADD COLUMN body_template   TEXT NOT NULL DEFAULT '',
+    ADD COLUMN timeout_seconds INT NOT NULL DEFAULT 10,
+    ADD COLUMN max_retries     INT NOT NULL DEFAULT 0,
+    ADD COLUMN signing_secret  TEXT NOT NULL DEFAULT '';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE webhook_notifiers
+    DROP COLUMN IF EXISTS headers,
+    DROP COLUMN IF EXISTS body_template,
+    DROP COLUMN IF EXISTS timeout_seconds,
+    DROP COLUMN IF EXISTS max_retries,
+    DROP COLUMN IF EXISTS signing_secret;
+
+-- +goose StatementEnd
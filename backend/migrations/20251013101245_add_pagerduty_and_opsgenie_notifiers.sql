@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE pagerduty_notifiers (
+    notifier_id UUID PRIMARY KEY,
+    routing_key TEXT NOT NULL
+);
+
+ALTER TABLE pagerduty_notifiers
+    ADD CONSTRAINT fk_pagerduty_notifiers_notifier
+    FOREIGN KEY (notifier_id)
+    REFERENCES notifiers (id)
+    ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED;
+
+CREATE TABLE opsgenie_notifiers (
+    notifier_id UUID PRIMARY KEY,
+    api_key     TEXT NOT NULL,
+    region      VARCHAR(10) NOT NULL DEFAULT 'US'
+);
+
+ALTER TABLE opsgenie_notifiers
+    ADD CONSTRAINT fk_opsgenie_notifiers_notifier
+    FOREIGN KEY (notifier_id)
+    REFERENCES notifiers (id)
+    ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS opsgenie_notifiers;
+DROP TABLE IF EXISTS pagerduty_notifiers;
+
+-- +goose StatementEnd
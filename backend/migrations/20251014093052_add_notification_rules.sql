@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE notification_rules (
+    id                     UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id           UUID NOT NULL,
+    name                   TEXT NOT NULL,
+    event_types            TEXT NOT NULL DEFAULT '',
+    min_severity           VARCHAR(20) NOT NULL DEFAULT '',
+    database_ids           TEXT NOT NULL DEFAULT '',
+    notifier_ids           TEXT NOT NULL,
+    is_quiet_hours_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    quiet_hours_from       VARCHAR(5) NOT NULL DEFAULT '',
+    quiet_hours_to         VARCHAR(5) NOT NULL DEFAULT '',
+    created_at             TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE notification_rules
+    ADD CONSTRAINT fk_notification_rules_workspace_id
+    FOREIGN KEY (workspace_id)
+    REFERENCES workspaces (id)
+    ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED;
+
+CREATE INDEX idx_notification_rules_workspace_id ON notification_rules (workspace_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS notification_rules;
+
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE notification_deliveries (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    notifier_id     UUID NOT NULL,
+    event_type      VARCHAR(50) NOT NULL,
+    status          VARCHAR(20) NOT NULL,
+    event           TEXT NOT NULL,
+    attempts        TEXT NOT NULL DEFAULT '[]',
+    attempts_count  INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at    TIMESTAMPTZ
+);
+
+ALTER TABLE notification_deliveries
+    ADD CONSTRAINT fk_notification_deliveries_notifier
+    FOREIGN KEY (notifier_id)
+    REFERENCES notifiers (id)
+    ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED;
+
+CREATE INDEX idx_notification_deliveries_status_next_attempt_at
+    ON notification_deliveries (status, next_attempt_at);
+
+CREATE INDEX idx_notification_deliveries_notifier_id_created_at
+    ON notification_deliveries (notifier_id, created_at DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS notification_deliveries;
+
+-- +goose StatementEnd
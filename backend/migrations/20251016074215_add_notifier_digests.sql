@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE notifiers
+    ADD COLUMN digest_frequency   VARCHAR(20) NOT NULL DEFAULT 'NONE',
+    ADD COLUMN digest_time_of_day VARCHAR(5) NOT NULL DEFAULT '',
+    ADD COLUMN digest_weekday     INT NOT NULL DEFAULT 1,
+    ADD COLUMN last_digest_at     TIMESTAMPTZ;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE notifiers
+    DROP COLUMN IF EXISTS digest_frequency,
+    DROP COLUMN IF EXISTS digest_time_of_day,
+    DROP COLUMN IF EXISTS digest_weekday,
+    DROP COLUMN IF EXISTS last_digest_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE backup_configs
+    ADD COLUMN backup_overdue_grace_minutes INT NOT NULL DEFAULT 60,
+    ADD COLUMN heartbeat_url                TEXT NOT NULL DEFAULT '';
+
+UPDATE backup_configs
+SET send_notifications_on = send_notifications_on || ',BACKUP_OVERDUE'
+WHERE send_notifications_on LIKE '%BACKUP_FAILED%';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+UPDATE backup_configs
+SET send_notifications_on = REPLACE(send_notifications_on, ',BACKUP_OVERDUE', '');
+
+ALTER TABLE backup_configs
+    DROP COLUMN IF EXISTS backup_overdue_grace_minutes,
+    DROP COLUMN IF EXISTS heartbeat_url;
+
+-- +goose StatementEnd
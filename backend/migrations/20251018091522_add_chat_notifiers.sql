@@ -0,0 +1,76 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE mattermost_notifiers (
+    notifier_id UUID PRIMARY KEY,
+    webhook_url TEXT NOT NULL,
+    channel     VARCHAR(255),
+    username    VARCHAR(255)
+);
+
+ALTER TABLE mattermost_notifiers
+    ADD CONSTRAINT fk_mattermost_notifiers_notifier
+    FOREIGN KEY (notifier_id)
+    REFERENCES notifiers (id)
+    ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED;
+
+CREATE TABLE rocket_chat_notifiers (
+    notifier_id UUID PRIMARY KEY,
+    webhook_url TEXT NOT NULL
+);
+
+ALTER TABLE rocket_chat_notifiers
+    ADD CONSTRAINT fk_rocket_chat_notifiers_notifier
+    FOREIGN KEY (notifier_id)
+    REFERENCES notifiers (id)
+    ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED;
+
+CREATE TABLE google_chat_notifiers (
+    notifier_id UUID PRIMARY KEY,
+    webhook_url TEXT NOT NULL
+);
+
+ALTER TABLE google_chat_notifiers
+    ADD CONSTRAINT fk_google_chat_notifiers_notifier
+    FOREIGN KEY (notifier_id)
+    REFERENCES notifiers (id)
+    ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED;
+
+CREATE TABLE matrix_notifiers (
+    notifier_id    UUID PRIMARY KEY,
+    homeserver_url TEXT NOT NULL,
+    access_token   TEXT NOT NULL,
+    room_id        VARCHAR(255) NOT NULL
+);
+
+ALTER TABLE matrix_notifiers
+    ADD CONSTRAINT fk_matrix_notifiers_notifier
+    FOREIGN KEY (notifier_id)
+    REFERENCES notifiers (id)
+    ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED;
+
+CREATE TABLE ntfy_notifiers (
+    notifier_id  UUID PRIMARY KEY,
+    server_url   TEXT NOT NULL,
+    topic        VARCHAR(64) NOT NULL,
+    access_token TEXT
+);
+
+ALTER TABLE ntfy_notifiers
+    ADD CONSTRAINT fk_ntfy_notifiers_notifier
+    FOREIGN KEY (notifier_id)
+    REFERENCES notifiers (id)
+    ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS ntfy_notifiers;
+DROP TABLE IF EXISTS matrix_notifiers;
+DROP TABLE IF EXISTS google_chat_notifiers;
+DROP TABLE IF EXISTS rocket_chat_notifiers;
+DROP TABLE IF EXISTS mattermost_notifiers;
+
+-- +goose StatementEnd
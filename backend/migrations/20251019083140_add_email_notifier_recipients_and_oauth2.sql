@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE email_notifiers
+    ALTER COLUMN target_email TYPE TEXT;
+
+ALTER TABLE email_notifiers
+    ADD COLUMN cc_emails              TEXT,
+    ADD COLUMN smtp_security          VARCHAR(20) NOT NULL DEFAULT 'AUTO',
+    ADD COLUMN smtp_auth_type         VARCHAR(20) NOT NULL DEFAULT 'PASSWORD',
+    ADD COLUMN oauth2_provider        VARCHAR(20),
+    ADD COLUMN oauth2_tenant_id       VARCHAR(255),
+    ADD COLUMN oauth2_client_id       TEXT,
+    ADD COLUMN oauth2_client_secret   TEXT,
+    ADD COLUMN oauth2_refresh_token   TEXT,
+    ADD COLUMN is_backup_log_attached BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE email_notifiers
+    DROP COLUMN IF EXISTS is_backup_log_attached,
+    DROP COLUMN IF EXISTS oauth2_refresh_token,
+    DROP COLUMN IF EXISTS oauth2_client_secret,
+    DROP COLUMN IF EXISTS oauth2_client_id,
+    DROP COLUMN IF EXISTS oauth2_tenant_id,
+    DROP COLUMN IF EXISTS oauth2_provider,
+    DROP COLUMN IF EXISTS smtp_auth_type,
+    DROP COLUMN IF EXISTS smtp_security,
+    DROP COLUMN IF EXISTS cc_emails;
+
+ALTER TABLE email_notifiers
+    ALTER COLUMN target_email TYPE VARCHAR(255);
+
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN oidc_subject TEXT NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX idx_users_oidc_subject ON users (oidc_subject) WHERE oidc_subject <> '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_users_oidc_subject;
+
+ALTER TABLE users
+    DROP COLUMN oidc_subject;
+-- +goose StatementEnd
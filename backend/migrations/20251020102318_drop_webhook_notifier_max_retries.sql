@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE webhook_notifiers
+    DROP COLUMN IF EXISTS max_retries;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE webhook_notifiers
+    ADD COLUMN max_retries INT NOT NULL DEFAULT 0;
+
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE notification_deliveries
+    ADD COLUMN incident_key TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_notification_deliveries_notifier_id_incident_key
+    ON notification_deliveries (notifier_id, incident_key, created_at DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_notification_deliveries_notifier_id_incident_key;
+
+ALTER TABLE notification_deliveries
+    DROP COLUMN IF EXISTS incident_key;
+
+-- +goose StatementEnd
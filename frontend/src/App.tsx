@@ -4,6 +4,7 @@ import { Routes } from 'react-router';
 
 import { userApi } from './entity/users';
 import { OauthStorageComponent } from './features/storages/OauthStorageComponent';
+import { OidcCallbackComponent } from './features/users';
 import { AuthPageComponent } from './pages/AuthPageComponent';
 import { MainScreenComponent } from './widgets/main/MainScreenComponent';
 
@@ -27,6 +28,7 @@ function App() {
           path="/storages/google-oauth"
           element={!isAuthorized ? <AuthPageComponent /> : <OauthStorageComponent />}
         />
+        <Route path="/auth/oidc/callback" element={<OidcCallbackComponent />} />
       </Routes>
     </BrowserRouter>
   );
@@ -14,4 +14,6 @@ export interface BackupConfig {
   cpuCount: number;
   isRetryIfFailed: boolean;
   maxFailedTriesCount: number;
+  backupOverdueGraceMinutes: number;
+  heartbeatUrl: string;
 }
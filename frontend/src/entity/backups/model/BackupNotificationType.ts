@@ -1,4 +1,5 @@
 export enum BackupNotificationType {
   BackupFailed = 'BACKUP_FAILED',
   BackupSuccess = 'BACKUP_SUCCESS',
+  BackupOverdue = 'BACKUP_OVERDUE',
 }
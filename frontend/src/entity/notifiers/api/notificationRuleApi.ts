@@ -0,0 +1,32 @@
+import { getApplicationServer } from '../../../constants';
+import RequestOptions from '../../../shared/api/RequestOptions';
+import { apiHelper } from '../../../shared/api/apiHelper';
+import type { NotificationRule } from '../models/rule/NotificationRule';
+
+export const notificationRuleApi = {
+  async saveNotificationRule(rule: NotificationRule) {
+    const requestOptions: RequestOptions = new RequestOptions();
+    requestOptions.setBody(JSON.stringify(rule));
+    return apiHelper.fetchPostJson<NotificationRule>(
+      `${getApplicationServer()}/api/v1/notification-rules`,
+      requestOptions,
+    );
+  },
+
+  async getNotificationRules() {
+    const requestOptions: RequestOptions = new RequestOptions();
+    return apiHelper.fetchGetJson<NotificationRule[]>(
+      `${getApplicationServer()}/api/v1/notification-rules`,
+      requestOptions,
+      true,
+    );
+  },
+
+  async deleteNotificationRule(id: string) {
+    const requestOptions: RequestOptions = new RequestOptions();
+    return apiHelper.fetchDeleteJson(
+      `${getApplicationServer()}/api/v1/notification-rules/${id}`,
+      requestOptions,
+    );
+  },
+};
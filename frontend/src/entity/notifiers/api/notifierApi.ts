@@ -2,6 +2,9 @@ import { getApplicationServer } from '../../../constants';
 import RequestOptions from '../../../shared/api/RequestOptions';
 import { apiHelper } from '../../../shared/api/apiHelper';
 import type { Notifier } from '../models/Notifier';
+import type { GetNotificationDeliveriesResponse } from '../models/delivery/GetNotificationDeliveriesResponse';
+import type { NotifierTemplate } from '../models/template/NotifierTemplate';
+import type { TemplatePreview } from '../models/template/TemplatePreview';
 
 export const notifierApi = {
   async saveNotifier(notifier: Notifier) {
@@ -31,6 +34,15 @@ export const notifierApi = {
     );
   },
 
+  async getDeliveries(id: string, limit: number, offset: number) {
+    const requestOptions: RequestOptions = new RequestOptions();
+    return apiHelper.fetchGetJson<GetNotificationDeliveriesResponse>(
+      `${getApplicationServer()}/api/v1/notifiers/${id}/deliveries?limit=${limit}&offset=${offset}`,
+      requestOptions,
+      true,
+    );
+  },
+
   async deleteNotifier(id: string) {
     const requestOptions: RequestOptions = new RequestOptions();
     return apiHelper.fetchDeleteJson(
@@ -55,4 +67,13 @@ export const notifierApi = {
       requestOptions,
     );
   },
+
+  async previewTemplate(template: NotifierTemplate) {
+    const requestOptions: RequestOptions = new RequestOptions();
+    requestOptions.setBody(JSON.stringify(template));
+    return apiHelper.fetchPostJson<TemplatePreview>(
+      `${getApplicationServer()}/api/v1/notifiers/templates/preview`,
+      requestOptions,
+    );
+  },
 };
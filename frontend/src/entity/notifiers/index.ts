@@ -1,9 +1,13 @@
 export { notifierApi } from './api/notifierApi';
+export { notificationRuleApi } from './api/notificationRuleApi';
 export type { Notifier } from './models/Notifier';
 export { NotifierType } from './models/NotifierType';
 
 export type { EmailNotifier } from './models/email/EmailNotifier';
 export { validateEmailNotifier } from './models/email/validateEmailNotifier';
+export { EmailSMTPSecurity } from './models/email/EmailSMTPSecurity';
+export { EmailAuthType } from './models/email/EmailAuthType';
+export { EmailOAuth2Provider } from './models/email/EmailOAuth2Provider';
 
 export type { TelegramNotifier } from './models/telegram/TelegramNotifier';
 export { validateTelegramNotifier } from './models/telegram/validateTelegramNotifier';
@@ -11,6 +15,7 @@ export { validateTelegramNotifier } from './models/telegram/validateTelegramNoti
 export type { WebhookNotifier } from './models/webhook/WebhookNotifier';
 export { validateWebhookNotifier } from './models/webhook/validateWebhookNotifier';
 export { WebhookMethod } from './models/webhook/WebhookMethod';
+export type { WebhookHeader } from './models/webhook/WebhookHeader';
 
 export type { SlackNotifier } from './models/slack/SlackNotifier';
 export { validateSlackNotifier } from './models/slack/validateSlackNotifier';
@@ -20,3 +25,42 @@ export { validateDiscordNotifier } from './models/discord/validateDiscordNotifie
 
 export type { TeamsNotifier } from './models/teams/TeamsNotifier';
 export { validateTeamsNotifier } from './models/teams/validateTeamsNotifier';
+
+export type { PagerDutyNotifier } from './models/pagerduty/PagerDutyNotifier';
+export { validatePagerDutyNotifier } from './models/pagerduty/validatePagerDutyNotifier';
+
+export type { OpsgenieNotifier } from './models/opsgenie/OpsgenieNotifier';
+export { validateOpsgenieNotifier } from './models/opsgenie/validateOpsgenieNotifier';
+export { OpsgenieRegion } from './models/opsgenie/OpsgenieRegion';
+
+export type { MattermostNotifier } from './models/mattermost/MattermostNotifier';
+export { validateMattermostNotifier } from './models/mattermost/validateMattermostNotifier';
+
+export type { RocketChatNotifier } from './models/rocketchat/RocketChatNotifier';
+export { validateRocketChatNotifier } from './models/rocketchat/validateRocketChatNotifier';
+
+export type { GoogleChatNotifier } from './models/googlechat/GoogleChatNotifier';
+export { validateGoogleChatNotifier } from './models/googlechat/validateGoogleChatNotifier';
+
+export type { MatrixNotifier } from './models/matrix/MatrixNotifier';
+export { validateMatrixNotifier } from './models/matrix/validateMatrixNotifier';
+
+export type { NtfyNotifier } from './models/ntfy/NtfyNotifier';
+export { validateNtfyNotifier } from './models/ntfy/validateNtfyNotifier';
+
+export type { NotifierTemplate } from './models/template/NotifierTemplate';
+export type { TemplatePreview } from './models/template/TemplatePreview';
+export { NotificationEventType } from './models/template/NotificationEventType';
+export { getNotificationEventTypeName } from './models/template/getNotificationEventTypeName';
+
+export type { NotificationRule } from './models/rule/NotificationRule';
+export { NotificationSeverity } from './models/rule/NotificationSeverity';
+
+export type {
+  NotificationDelivery,
+  NotificationDeliveryAttempt,
+} from './models/delivery/NotificationDelivery';
+export { NotificationDeliveryStatus } from './models/delivery/NotificationDeliveryStatus';
+export type { GetNotificationDeliveriesResponse } from './models/delivery/GetNotificationDeliveriesResponse';
+
+export { DigestFrequency } from './models/digest/DigestFrequency';
@@ -1,9 +1,18 @@
 import type { NotifierType } from './NotifierType';
+import type { DigestFrequency } from './digest/DigestFrequency';
 import type { DiscordNotifier } from './discord/DiscordNotifier';
 import type { EmailNotifier } from './email/EmailNotifier';
+import type { GoogleChatNotifier } from './googlechat/GoogleChatNotifier';
+import type { MatrixNotifier } from './matrix/MatrixNotifier';
+import type { MattermostNotifier } from './mattermost/MattermostNotifier';
+import type { NtfyNotifier } from './ntfy/NtfyNotifier';
+import type { OpsgenieNotifier } from './opsgenie/OpsgenieNotifier';
+import type { PagerDutyNotifier } from './pagerduty/PagerDutyNotifier';
+import type { RocketChatNotifier } from './rocketchat/RocketChatNotifier';
 import type { SlackNotifier } from './slack/SlackNotifier';
 import type { TeamsNotifier } from './teams/TeamsNotifier';
 import type { TelegramNotifier } from './telegram/TelegramNotifier';
+import type { NotifierTemplate } from './template/NotifierTemplate';
 import type { WebhookNotifier } from './webhook/WebhookNotifier';
 
 export interface Notifier {
@@ -19,4 +28,20 @@ export interface Notifier {
   slackNotifier?: SlackNotifier;
   discordNotifier?: DiscordNotifier;
   teamsNotifier?: TeamsNotifier;
+  pagerDutyNotifier?: PagerDutyNotifier;
+  opsgenieNotifier?: OpsgenieNotifier;
+  mattermostNotifier?: MattermostNotifier;
+  rocketChatNotifier?: RocketChatNotifier;
+  googleChatNotifier?: GoogleChatNotifier;
+  matrixNotifier?: MatrixNotifier;
+  ntfyNotifier?: NtfyNotifier;
+
+  // overrides of title and body per event type
+  templates?: NotifierTemplate[];
+
+  // summary of backups, time is in UTC, weekday is 1 (Monday) - 7
+  digestFrequency?: DigestFrequency;
+  digestTimeOfDay?: string;
+  digestWeekday?: number;
+  lastDigestAt?: string;
 }
@@ -5,4 +5,11 @@ export enum NotifierType {
   SLACK = 'SLACK',
   DISCORD = 'DISCORD',
   TEAMS = 'TEAMS',
+  PAGERDUTY = 'PAGERDUTY',
+  OPSGENIE = 'OPSGENIE',
+  MATTERMOST = 'MATTERMOST',
+  ROCKET_CHAT = 'ROCKET_CHAT',
+  GOOGLE_CHAT = 'GOOGLE_CHAT',
+  MATRIX = 'MATRIX',
+  NTFY = 'NTFY',
 }
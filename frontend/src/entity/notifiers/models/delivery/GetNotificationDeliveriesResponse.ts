@@ -0,0 +1,8 @@
+import type { NotificationDelivery } from './NotificationDelivery';
+
+export interface GetNotificationDeliveriesResponse {
+  deliveries: NotificationDelivery[];
+  total: number;
+  limit: number;
+  offset: number;
+}
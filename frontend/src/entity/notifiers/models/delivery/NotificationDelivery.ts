@@ -0,0 +1,30 @@
+import type { NotificationEventType } from '../template/NotificationEventType';
+import type { NotificationDeliveryStatus } from './NotificationDeliveryStatus';
+
+export interface NotificationDeliveryAttempt {
+  attemptedAt: string;
+  durationMs: number;
+  error?: string;
+}
+
+export interface NotificationDelivery {
+  id: string;
+  notifierId: string;
+  eventType: NotificationEventType;
+  status: NotificationDeliveryStatus;
+
+  event: {
+    type: NotificationEventType;
+    severity: string;
+    title: string;
+    message: string;
+    [key: string]: unknown;
+  };
+
+  attempts: NotificationDeliveryAttempt[];
+  attemptsCount: number;
+
+  nextAttemptAt: string;
+  createdAt: string;
+  deliveredAt?: string;
+}
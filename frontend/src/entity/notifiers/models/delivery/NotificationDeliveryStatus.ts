@@ -0,0 +1,6 @@
+export enum NotificationDeliveryStatus {
+  PENDING = 'PENDING',
+  SENDING = 'SENDING',
+  DELIVERED = 'DELIVERED',
+  FAILED = 'FAILED',
+}
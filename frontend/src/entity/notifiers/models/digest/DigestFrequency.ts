@@ -0,0 +1,5 @@
+export enum DigestFrequency {
+  NONE = 'NONE',
+  DAILY = 'DAILY',
+  WEEKLY = 'WEEKLY',
+}
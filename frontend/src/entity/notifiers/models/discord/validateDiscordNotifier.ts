@@ -1,7 +1,11 @@
 import type { DiscordNotifier } from './DiscordNotifier';
 
-export const validateDiscordNotifier = (notifier: DiscordNotifier): boolean => {
-  if (!notifier.channelWebhookUrl) {
+export const validateDiscordNotifier = (
+  notifier: DiscordNotifier,
+  isExisting: boolean,
+): boolean => {
+  // saved webhook is not returned by the API, empty means keep the saved one
+  if (!notifier.channelWebhookUrl && !isExisting) {
     return false;
   }
 
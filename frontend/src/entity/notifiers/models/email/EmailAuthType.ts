@@ -0,0 +1,4 @@
+export enum EmailAuthType {
+  PASSWORD = 'PASSWORD',
+  OAUTH2 = 'OAUTH2',
+}
@@ -1,7 +1,25 @@
+import type { EmailAuthType } from './EmailAuthType';
+import type { EmailOAuth2Provider } from './EmailOAuth2Provider';
+import type { EmailSMTPSecurity } from './EmailSMTPSecurity';
+
 export interface EmailNotifier {
+  // emails separated by commas
   targetEmail: string;
+  ccEmails?: string;
+
   smtpHost: string;
   smtpPort: number;
+  smtpSecurity?: EmailSMTPSecurity;
   smtpUser: string;
   smtpPassword: string;
+
+  // OAuth2 replaces the password for Gmail and Office 365
+  smtpAuthType?: EmailAuthType;
+  oauth2Provider?: EmailOAuth2Provider;
+  oauth2TenantId?: string;
+  oauth2ClientId?: string;
+  oauth2ClientSecret?: string;
+  oauth2RefreshToken?: string;
+
+  isBackupLogAttached?: boolean;
 }
@@ -0,0 +1,4 @@
+export enum EmailOAuth2Provider {
+  GOOGLE = 'GOOGLE',
+  MICROSOFT = 'MICROSOFT',
+}
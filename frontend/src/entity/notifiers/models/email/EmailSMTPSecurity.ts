@@ -0,0 +1,6 @@
+export enum EmailSMTPSecurity {
+  AUTO = 'AUTO',
+  NONE = 'NONE',
+  STARTTLS = 'STARTTLS',
+  TLS = 'TLS',
+}
@@ -1,6 +1,7 @@
+import { EmailAuthType } from './EmailAuthType';
 import type { EmailNotifier } from './EmailNotifier';
 
-export const validateEmailNotifier = (notifier: EmailNotifier): boolean => {
+export const validateEmailNotifier = (notifier: EmailNotifier, isExisting: boolean): boolean => {
   if (!notifier.targetEmail) {
     return false;
   }
@@ -13,5 +14,16 @@ export const validateEmailNotifier = (notifier: EmailNotifier): boolean => {
     return false;
   }
 
+  if (notifier.smtpAuthType === EmailAuthType.OAUTH2) {
+    if (!notifier.oauth2Provider || !notifier.oauth2ClientId || !notifier.smtpUser) {
+      return false;
+    }
+
+    // saved secrets are not returned by the API, empty means keep the saved ones
+    if (!notifier.oauth2ClientSecret || !notifier.oauth2RefreshToken) {
+      return isExisting;
+    }
+  }
+
   return true;
 };
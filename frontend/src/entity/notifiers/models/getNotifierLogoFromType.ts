@@ -14,6 +14,20 @@ export const getNotifierLogoFromType = (type: NotifierType) => {
       return '/icons/notifiers/discord.svg';
     case NotifierType.TEAMS:
       return '/icons/notifiers/teams.svg';
+    case NotifierType.PAGERDUTY:
+      return '/icons/notifiers/pagerduty.svg';
+    case NotifierType.OPSGENIE:
+      return '/icons/notifiers/opsgenie.svg';
+    case NotifierType.MATTERMOST:
+      return '/icons/notifiers/mattermost.svg';
+    case NotifierType.ROCKET_CHAT:
+      return '/icons/notifiers/rocketchat.svg';
+    case NotifierType.GOOGLE_CHAT:
+      return '/icons/notifiers/googlechat.svg';
+    case NotifierType.MATRIX:
+      return '/icons/notifiers/matrix.svg';
+    case NotifierType.NTFY:
+      return '/icons/notifiers/ntfy.svg';
     default:
       return '';
   }
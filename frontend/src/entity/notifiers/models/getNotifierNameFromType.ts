@@ -14,6 +14,20 @@ export const getNotifierNameFromType = (type: NotifierType) => {
       return 'Discord';
     case NotifierType.TEAMS:
       return 'Teams';
+    case NotifierType.PAGERDUTY:
+      return 'PagerDuty';
+    case NotifierType.OPSGENIE:
+      return 'Opsgenie';
+    case NotifierType.MATTERMOST:
+      return 'Mattermost';
+    case NotifierType.ROCKET_CHAT:
+      return 'Rocket.Chat';
+    case NotifierType.GOOGLE_CHAT:
+      return 'Google Chat';
+    case NotifierType.MATRIX:
+      return 'Matrix';
+    case NotifierType.NTFY:
+      return 'ntfy';
     default:
       return '';
   }
@@ -0,0 +1,3 @@
+export interface GoogleChatNotifier {
+  webhookUrl: string;
+}
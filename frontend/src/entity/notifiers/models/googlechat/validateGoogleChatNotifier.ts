@@ -0,0 +1,13 @@
+import type { GoogleChatNotifier } from './GoogleChatNotifier';
+
+export const validateGoogleChatNotifier = (
+  notifier: GoogleChatNotifier,
+  isExisting: boolean,
+): boolean => {
+  // saved webhook is not returned by the API, empty means keep the saved one
+  if (!notifier.webhookUrl && !isExisting) {
+    return false;
+  }
+
+  return true;
+};
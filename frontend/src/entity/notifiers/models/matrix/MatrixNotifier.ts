@@ -0,0 +1,7 @@
+export interface MatrixNotifier {
+  homeserverUrl: string;
+  accessToken: string;
+
+  // ID like !roomid:server.org, aliases are not supported
+  roomId: string;
+}
@@ -0,0 +1,18 @@
+import type { MatrixNotifier } from './MatrixNotifier';
+
+export const validateMatrixNotifier = (notifier: MatrixNotifier, isExisting: boolean): boolean => {
+  if (!notifier.homeserverUrl) {
+    return false;
+  }
+
+  if (!notifier.roomId.startsWith('!') || !notifier.roomId.includes(':')) {
+    return false;
+  }
+
+  // saved token is not returned by the API, empty means keep the saved one
+  if (!notifier.accessToken) {
+    return isExisting;
+  }
+
+  return true;
+};
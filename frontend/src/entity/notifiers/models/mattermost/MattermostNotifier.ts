@@ -0,0 +1,7 @@
+export interface MattermostNotifier {
+  webhookUrl: string;
+
+  // optional overrides, webhook defaults are used when empty
+  channel?: string;
+  username?: string;
+}
@@ -0,0 +1,7 @@
+export interface NtfyNotifier {
+  serverUrl: string;
+  topic: string;
+
+  // required only by servers with access control
+  accessToken?: string;
+}
@@ -0,0 +1,9 @@
+import type { NtfyNotifier } from './NtfyNotifier';
+
+export const validateNtfyNotifier = (notifier: NtfyNotifier): boolean => {
+  if (!notifier.serverUrl) {
+    return false;
+  }
+
+  return /^[-_A-Za-z0-9]{1,64}$/.test(notifier.topic);
+};
@@ -0,0 +1,7 @@
+import type { OpsgenieRegion } from './OpsgenieRegion';
+
+export interface OpsgenieNotifier {
+  // key of API integration of the team
+  apiKey: string;
+  region: OpsgenieRegion;
+}
@@ -0,0 +1,4 @@
+export enum OpsgenieRegion {
+  US = 'US',
+  EU = 'EU',
+}
@@ -0,0 +1,17 @@
+import type { OpsgenieNotifier } from './OpsgenieNotifier';
+
+export const validateOpsgenieNotifier = (
+  notifier: OpsgenieNotifier,
+  isExisting: boolean,
+): boolean => {
+  if (!notifier.region) {
+    return false;
+  }
+
+  // saved key is not returned by the API, empty means keep the saved one
+  if (!notifier.apiKey) {
+    return isExisting;
+  }
+
+  return true;
+};
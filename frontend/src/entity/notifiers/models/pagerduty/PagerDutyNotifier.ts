@@ -0,0 +1,4 @@
+export interface PagerDutyNotifier {
+  // integration key of Events API v2 service integration
+  routingKey: string;
+}
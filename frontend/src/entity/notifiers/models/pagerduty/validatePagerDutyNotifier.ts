@@ -0,0 +1,13 @@
+import type { PagerDutyNotifier } from './PagerDutyNotifier';
+
+export const validatePagerDutyNotifier = (
+  notifier: PagerDutyNotifier,
+  isExisting: boolean,
+): boolean => {
+  // saved key is not returned by the API, empty means keep the saved one
+  if (!notifier.routingKey) {
+    return isExisting;
+  }
+
+  return true;
+};
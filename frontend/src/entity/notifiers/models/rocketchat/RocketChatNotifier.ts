@@ -0,0 +1,3 @@
+export interface RocketChatNotifier {
+  webhookUrl: string;
+}
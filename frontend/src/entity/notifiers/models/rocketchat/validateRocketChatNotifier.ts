@@ -0,0 +1,13 @@
+import type { RocketChatNotifier } from './RocketChatNotifier';
+
+export const validateRocketChatNotifier = (
+  notifier: RocketChatNotifier,
+  isExisting: boolean,
+): boolean => {
+  // saved webhook is not returned by the API, empty means keep the saved one
+  if (!notifier.webhookUrl && !isExisting) {
+    return false;
+  }
+
+  return true;
+};
@@ -0,0 +1,21 @@
+import type { NotificationEventType } from '../template/NotificationEventType';
+import type { NotificationSeverity } from './NotificationSeverity';
+
+export interface NotificationRule {
+  id: string;
+  workspaceId?: string;
+  name: string;
+
+  // empty event types, severity or databases match any value
+  eventTypes: NotificationEventType[];
+  minSeverity: NotificationSeverity | '';
+  databaseIds: string[];
+  notifierIds: string[];
+
+  // HH:MM in UTC, only critical events are sent in quiet hours
+  isQuietHoursEnabled: boolean;
+  quietHoursFrom: string;
+  quietHoursTo: string;
+
+  createdAt?: string;
+}
@@ -1,7 +1,11 @@
 import type { SlackNotifier } from './SlackNotifier';
 
-export const validateSlackNotifier = (notifier: SlackNotifier): boolean => {
-  if (!notifier.botToken) {
+export const validateSlackNotifier = (
+  notifier: SlackNotifier,
+  isExisting: boolean,
+): boolean => {
+  // saved tokens are not returned by the API, empty means keep the saved one
+  if (!notifier.botToken && !isExisting) {
     return false;
   }
 
@@ -1,8 +1,9 @@
 import type { TeamsNotifier } from './TeamsNotifier';
 
-export const validateTeamsNotifier = (notifier: TeamsNotifier): boolean => {
+export const validateTeamsNotifier = (notifier: TeamsNotifier, isExisting: boolean): boolean => {
+  // saved URL is not returned by the API, empty means keep the saved one
   if (!notifier?.powerAutomateUrl) {
-    return false;
+    return isExisting;
   }
 
   try {
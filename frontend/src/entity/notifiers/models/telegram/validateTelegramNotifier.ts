@@ -1,7 +1,11 @@
 import type { TelegramNotifier } from './TelegramNotifier';
 
-export const validateTelegramNotifier = (notifier: TelegramNotifier): boolean => {
-  if (!notifier.botToken) {
+export const validateTelegramNotifier = (
+  notifier: TelegramNotifier,
+  isExisting: boolean,
+): boolean => {
+  // saved tokens are not returned by the API, empty means keep the saved one
+  if (!notifier.botToken && !isExisting) {
     return false;
   }
 
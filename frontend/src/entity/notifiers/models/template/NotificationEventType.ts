@@ -0,0 +1,12 @@
+export enum NotificationEventType {
+  BACKUP_SUCCESS = 'BACKUP_SUCCESS',
+  BACKUP_FAILED = 'BACKUP_FAILED',
+  BACKUP_ANOMALY = 'BACKUP_ANOMALY',
+  BACKUP_OVERDUE = 'BACKUP_OVERDUE',
+  STORAGE_QUOTA_WARNING = 'STORAGE_QUOTA_WARNING',
+  DATABASE_UNAVAILABLE = 'DATABASE_UNAVAILABLE',
+  DATABASE_AVAILABLE = 'DATABASE_AVAILABLE',
+  ACCOUNT_LOCKED = 'ACCOUNT_LOCKED',
+  BACKUP_DIGEST = 'BACKUP_DIGEST',
+  TEST = 'TEST',
+}
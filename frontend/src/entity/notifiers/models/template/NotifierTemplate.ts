@@ -0,0 +1,8 @@
+import type { NotificationEventType } from './NotificationEventType';
+
+export interface NotifierTemplate {
+  id?: string;
+  eventType: NotificationEventType;
+  titleTemplate: string;
+  bodyTemplate: string;
+}
@@ -0,0 +1,5 @@
+export interface TemplatePreview {
+  title: string;
+  body: string;
+  variables: Record<string, unknown>;
+}
@@ -0,0 +1,26 @@
+import { NotificationEventType } from './NotificationEventType';
+
+export const getNotificationEventTypeName = (eventType: NotificationEventType) => {
+  switch (eventType) {
+    case NotificationEventType.BACKUP_SUCCESS:
+      return 'Backup success';
+    case NotificationEventType.BACKUP_FAILED:
+      return 'Backup failed';
+    case NotificationEventType.BACKUP_ANOMALY:
+      return 'Backup anomaly';
+    case NotificationEventType.BACKUP_OVERDUE:
+      return 'Backup overdue';
+    case NotificationEventType.STORAGE_QUOTA_WARNING:
+      return 'Storage quota warning';
+    case NotificationEventType.DATABASE_UNAVAILABLE:
+      return 'Database unavailable';
+    case NotificationEventType.DATABASE_AVAILABLE:
+      return 'Database available';
+    case NotificationEventType.ACCOUNT_LOCKED:
+      return 'Account locked';
+    case NotificationEventType.BACKUP_DIGEST:
+      return 'Backup digest';
+    case NotificationEventType.TEST:
+      return 'Test notification';
+  }
+};
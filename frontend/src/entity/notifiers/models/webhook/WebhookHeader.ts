@@ -0,0 +1,4 @@
+export interface WebhookHeader {
+  name: string;
+  value: string;
+}
@@ -1,6 +1,13 @@
+import type { WebhookHeader } from './WebhookHeader';
 import type { WebhookMethod } from './WebhookMethod';
 
 export interface WebhookNotifier {
   webhookUrl: string;
   webhookMethod: WebhookMethod;
+
+  // values of headers and the signing secret are not returned by API
+  headers?: WebhookHeader[];
+  bodyTemplate?: string;
+  timeoutSeconds?: number;
+  signingSecret?: string;
 }
@@ -5,5 +5,9 @@ export const validateWebhookNotifier = (notifier: WebhookNotifier): boolean => {
     return false;
   }
 
+  if (notifier.headers?.some((header) => !header.name)) {
+    return false;
+  }
+
   return true;
 };
@@ -2,14 +2,17 @@ import { getApplicationServer } from '../../../constants';
 import RequestOptions from '../../../shared/api/RequestOptions';
 import { accessTokenHelper } from '../../../shared/api/accessTokenHelper';
 import { apiHelper } from '../../../shared/api/apiHelper';
+import type { OidcConfig } from '../model/OidcConfig';
 import type { SignInRequest } from '../model/SignInRequest';
 import type { SignInResponse } from '../model/SignInResponse';
 import type { SignUpRequest } from '../model/SignUpRequest';
+import type { TotpSetup } from '../model/TotpSetup';
 
 const listeners: (() => void)[] = [];
 
-const saveAuthorizedData = (accessToken: string, userId: string) => {
+const saveAuthorizedData = (accessToken: string, refreshToken: string, userId: string) => {
   accessTokenHelper.saveAccessToken(accessToken);
+  accessTokenHelper.saveRefreshToken(refreshToken);
   accessTokenHelper.saveUserId(userId);
 };
 
@@ -34,7 +37,92 @@ export const userApi = {
       .fetchPostJson(`${getApplicationServer()}/api/v1/users/signin`, requestOptions)
       .then((response: unknown): SignInResponse => {
         const typedResponse = response as SignInResponse;
-        saveAuthorizedData(typedResponse.token, typedResponse.userId);
+
+        // second factor is required, user is authorized after the code
+        if (typedResponse.totpToken) {
+          return typedResponse;
+        }
+
+        saveAuthorizedData(
+          typedResponse.token,
+          typedResponse.refreshToken,
+          typedResponse.userId,
+        );
+        notifyAuthListeners();
+        return typedResponse;
+      });
+  },
+
+  // listeners are not notified, so recovery codes of a new enrollment can
+  // be shown before the user leaves the sign in page
+  async signInWithTotp(totpToken: string, code: string): Promise<SignInResponse> {
+    const requestOptions: RequestOptions = new RequestOptions();
+    requestOptions.setBody(JSON.stringify({ totpToken, code }));
+
+    return apiHelper
+      .fetchPostJson(`${getApplicationServer()}/api/v1/users/signin/totp`, requestOptions)
+      .then((response: unknown): SignInResponse => {
+        const typedResponse = response as SignInResponse;
+        saveAuthorizedData(
+          typedResponse.token,
+          typedResponse.refreshToken,
+          typedResponse.userId,
+        );
+        return typedResponse;
+      });
+  },
+
+  async startTotpEnrollment(totpToken: string): Promise<TotpSetup> {
+    const requestOptions: RequestOptions = new RequestOptions();
+    requestOptions.setBody(JSON.stringify({ totpToken }));
+
+    return apiHelper.fetchPostJson<TotpSetup>(
+      `${getApplicationServer()}/api/v1/users/signin/totp/enroll`,
+      requestOptions,
+    );
+  },
+
+  async getOidcConfig(): Promise<OidcConfig> {
+    return apiHelper.fetchGetJson<OidcConfig>(
+      `${getApplicationServer()}/api/v1/users/oidc/config`,
+      undefined,
+      true,
+    );
+  },
+
+  async getOidcAuthorizationUrl(): Promise<string> {
+    // the response sets the cookie which binds the sign in to the browser
+    const requestOptions: RequestOptions = new RequestOptions();
+    requestOptions.setCredentials('include');
+
+    const response = await apiHelper.fetchGetJson<{ url: string }>(
+      `${getApplicationServer()}/api/v1/users/oidc/authorization-url`,
+      requestOptions,
+    );
+
+    return response.url;
+  },
+
+  async signInWithOidc(code: string, state: string): Promise<SignInResponse> {
+    const requestOptions: RequestOptions = new RequestOptions();
+    requestOptions.setBody(JSON.stringify({ code, state }));
+    requestOptions.setCredentials('include');
+
+    return apiHelper
+      .fetchPostJson(`${getApplicationServer()}/api/v1/users/oidc/signin`, requestOptions)
+      .then((response: unknown): SignInResponse => {
+        const typedResponse = response as SignInResponse;
+
+        // SSO does not bypass the second factor
+        if (typedResponse.totpToken) {
+          return typedResponse;
+        }
+
+        saveAuthorizedData(
+          typedResponse.token,
+          typedResponse.refreshToken,
+          typedResponse.userId,
+        );
         notifyAuthListeners();
         return typedResponse;
       });
@@ -56,8 +144,13 @@ export const userApi = {
 
   isAuthorized: (): boolean => !!accessTokenHelper.getAccessToken(),
 
-  logout: () => {
-    accessTokenHelper.cleanAccessToken();
+  async logout() {
+    try {
+      await apiHelper.fetchPostRaw(`${getApplicationServer()}/api/v1/users/logout`);
+    } finally {
+      accessTokenHelper.cleanAccessToken();
+      notifyAuthListeners();
+    }
   },
 
   // listeners
@@ -1 +1,4 @@
 export { userApi } from './api/userApi';
+export { type OidcConfig } from './model/OidcConfig';
+export { type SignInResponse } from './model/SignInResponse';
+export { type TotpSetup } from './model/TotpSetup';
@@ -0,0 +1,5 @@
+export interface OidcConfig {
+  isOidcEnabled: boolean;
+  providerName: string;
+  isPasswordLoginDisabled: boolean;
+}
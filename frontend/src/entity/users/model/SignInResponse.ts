@@ -1,4 +1,11 @@
 export interface SignInResponse {
   userId: string;
   token: string;
+  refreshToken: string;
+  expiresAt: string;
+
+  isTotpRequired: boolean;
+  isTotpEnrollmentRequired: boolean;
+  totpToken?: string;
+  recoveryCodes?: string[];
 }
@@ -0,0 +1,4 @@
+export interface TotpSetup {
+  secret: string;
+  otpauthUrl: string;
+}
@@ -2,6 +2,7 @@ import { InfoCircleOutlined } from '@ant-design/icons';
 import {
   Button,
   Checkbox,
+  Input,
   InputNumber,
   Modal,
   Select,
@@ -154,6 +155,8 @@ export const EditBackupConfigComponent = ({
         sendNotificationsOn: [],
         isRetryIfFailed: true,
         maxFailedTriesCount: 3,
+        backupOverdueGraceMinutes: 60,
+        heartbeatUrl: '',
       });
     }
     loadStorages();
@@ -458,7 +461,64 @@ export const EditBackupConfigComponent = ({
               >
                 Backup failed
               </Checkbox>
+
+              <Checkbox
+                checked={backupConfig.sendNotificationsOn.includes(
+                  BackupNotificationType.BackupOverdue,
+                )}
+                onChange={(e) => {
+                  const notifications = [...backupConfig.sendNotificationsOn];
+                  const index = notifications.indexOf(BackupNotificationType.BackupOverdue);
+                  if (e.target.checked && index === -1) {
+                    notifications.push(BackupNotificationType.BackupOverdue);
+                  } else if (!e.target.checked && index > -1) {
+                    notifications.splice(index, 1);
+                  }
+                  updateBackupConfig({ sendNotificationsOn: notifications });
+                }}
+              >
+                Backup overdue
+              </Checkbox>
+            </div>
+          </div>
+
+          {backupConfig.sendNotificationsOn.includes(BackupNotificationType.BackupOverdue) && (
+            <div className="mb-1 flex w-full items-center">
+              <div className="min-w-[150px]">Overdue grace period</div>
+              <InputNumber
+                min={0}
+                value={backupConfig.backupOverdueGraceMinutes}
+                onChange={(value) => updateBackupConfig({ backupOverdueGraceMinutes: value || 0 })}
+                size="small"
+                className="max-w-[200px] grow"
+                addonAfter="min"
+              />
+
+              <Tooltip
+                className="cursor-pointer"
+                title="Notify when the last successful backup is older than the backup interval plus this period, e.g. when schedule silently stopped."
+              >
+                <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
+              </Tooltip>
             </div>
+          )}
+
+          <div className="mb-1 flex w-full items-center">
+            <div className="min-w-[150px]">Heartbeat URL</div>
+            <Input
+              value={backupConfig.heartbeatUrl}
+              onChange={(e) => updateBackupConfig({ heartbeatUrl: e.target.value.trim() })}
+              size="small"
+              className="max-w-[200px] grow"
+              placeholder="https://hc-ping.com/..."
+            />
+
+            <Tooltip
+              className="cursor-pointer"
+              title="URL requested after each successful backup, e.g. healthchecks.io check. The external service alerts you when backups stop."
+            >
+              <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
+            </Tooltip>
           </div>
         </>
       )}
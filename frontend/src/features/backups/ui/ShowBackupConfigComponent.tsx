@@ -48,6 +48,7 @@ const periodLabels = {
 const notificationLabels = {
   [BackupNotificationType.BackupFailed]: 'Backup failed',
   [BackupNotificationType.BackupSuccess]: 'Backup success',
+  [BackupNotificationType.BackupOverdue]: 'Backup overdue',
 };
 
 export const ShowBackupConfigComponent = ({ database }: Props) => {
@@ -175,6 +176,20 @@ export const ShowBackupConfigComponent = ({ database }: Props) => {
                 : 'None'}
             </div>
           </div>
+
+          {backupConfig.sendNotificationsOn.includes(BackupNotificationType.BackupOverdue) && (
+            <div className="mb-1 flex w-full items-center">
+              <div className="min-w-[150px]">Overdue grace period</div>
+              <div>{backupConfig.backupOverdueGraceMinutes} min</div>
+            </div>
+          )}
+
+          {backupConfig.heartbeatUrl && (
+            <div className="mb-1 flex w-full items-center">
+              <div className="min-w-[150px]">Heartbeat URL</div>
+              <div className="max-w-[250px] truncate">{backupConfig.heartbeatUrl}</div>
+            </div>
+          )}
         </>
       ) : (
         <div />
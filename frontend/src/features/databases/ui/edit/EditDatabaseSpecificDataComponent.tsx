@@ -101,7 +101,8 @@ export const EditDatabaseSpecificDataComponent = ({
   if (!editingDatabase.postgresql?.host) isAllFieldsFilled = false;
   if (!editingDatabase.postgresql?.port) isAllFieldsFilled = false;
   if (!editingDatabase.postgresql?.username) isAllFieldsFilled = false;
-  if (!editingDatabase.postgresql?.password) isAllFieldsFilled = false;
+  // saved password is not returned by the API, empty means keep the saved one
+  if (!editingDatabase.postgresql?.password && !editingDatabase.id) isAllFieldsFilled = false;
   if (!editingDatabase.postgresql?.database) isAllFieldsFilled = false;
 
   return (
@@ -243,7 +244,7 @@ export const EditDatabaseSpecificDataComponent = ({
               }}
               size="small"
               className="max-w-[200px] grow"
-              placeholder="Enter PG password"
+              placeholder={editingDatabase.id ? 'Leave empty to keep saved' : 'Enter PG password'}
             />
           </div>
 
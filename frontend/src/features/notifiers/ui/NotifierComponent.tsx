@@ -8,6 +8,7 @@ import { notifierApi } from '../../../entity/notifiers';
 import type { Notifier } from '../../../entity/notifiers';
 import { ToastHelper } from '../../../shared/toast';
 import { ConfirmationComponent } from '../../../shared/ui';
+import { NotifierDeliveriesComponent } from './NotifierDeliveriesComponent';
 import { EditNotifierComponent } from './edit/EditNotifierComponent';
 import { ShowNotifierComponent } from './show/ShowNotifierComponent';
 
@@ -197,6 +198,7 @@ export const NotifierComponent = ({ notifierId, onNotifierChanged, onNotifierDel
                       - send test notification via button below (even if you updated settings);
                     </li>
                     <li>- wait until the next notification is sent without errors;</li>
+                    <li>- failed notifications are retried for about an hour;</li>
                   </ul>
                 </div>
               </div>
@@ -257,6 +259,8 @@ export const NotifierComponent = ({ notifierId, onNotifierChanged, onNotifierDel
                 </Button>
               </div>
             )}
+
+            {!isEditSettings && <NotifierDeliveriesComponent notifierId={notifier.id} />}
           </div>
         )}
 
@@ -0,0 +1,174 @@
+import {
+  CheckCircleOutlined,
+  ClockCircleOutlined,
+  ExclamationCircleOutlined,
+  SyncOutlined,
+} from '@ant-design/icons';
+import { Button, Modal, Table } from 'antd';
+import type { ColumnsType } from 'antd/es/table';
+import dayjs from 'dayjs';
+import { useEffect, useState } from 'react';
+
+import {
+  type NotificationDelivery,
+  NotificationDeliveryStatus,
+  getNotificationEventTypeName,
+  notifierApi,
+} from '../../../entity/notifiers';
+import { getUserTimeFormat } from '../../../shared/time';
+
+interface Props {
+  notifierId: string;
+}
+
+const PAGE_SIZE = 10;
+
+export const NotifierDeliveriesComponent = ({ notifierId }: Props) => {
+  const [isLoading, setIsLoading] = useState(true);
+  const [deliveries, setDeliveries] = useState<NotificationDelivery[]>([]);
+  const [total, setTotal] = useState(0);
+  const [page, setPage] = useState(1);
+
+  const [showingDelivery, setShowingDelivery] = useState<NotificationDelivery | undefined>();
+
+  const loadDeliveries = (page: number) => {
+    setIsLoading(true);
+
+    notifierApi
+      .getDeliveries(notifierId, PAGE_SIZE, (page - 1) * PAGE_SIZE)
+      .then((response) => {
+        setDeliveries(response.deliveries);
+        setTotal(response.total);
+      })
+      .catch((e) => alert(e.message))
+      .finally(() => setIsLoading(false));
+  };
+
+  useEffect(() => {
+    setPage(1);
+    loadDeliveries(1);
+  }, [notifierId]);
+
+  const formatTime = (time: string) =>
+    dayjs.utc(time).local().format(getUserTimeFormat().format);
+
+  const columns: ColumnsType<NotificationDelivery> = [
+    {
+      title: 'Created at',
+      dataIndex: 'createdAt',
+      key: 'createdAt',
+      render: (createdAt: string) => formatTime(createdAt),
+    },
+    {
+      title: 'Event',
+      dataIndex: 'eventType',
+      key: 'eventType',
+      render: (_, record) => (
+        <div className="cursor-pointer underline" onClick={() => setShowingDelivery(record)}>
+          {getNotificationEventTypeName(record.eventType) ?? record.eventType}
+        </div>
+      ),
+    },
+    {
+      title: 'Status',
+      dataIndex: 'status',
+      key: 'status',
+      render: (status: NotificationDeliveryStatus, record) => {
+        if (status === NotificationDeliveryStatus.DELIVERED) {
+          return (
+            <div className="flex items-center text-green-600">
+              <CheckCircleOutlined className="mr-2" />
+              Delivered
+            </div>
+          );
+        }
+
+        if (status === NotificationDeliveryStatus.FAILED) {
+          return (
+            <div className="flex items-center text-red-600">
+              <ExclamationCircleOutlined className="mr-2" />
+              Failed
+            </div>
+          );
+        }
+
+        if (status === NotificationDeliveryStatus.SENDING) {
+          return (
+            <div className="flex items-center text-blue-600">
+              <SyncOutlined spin className="mr-2" />
+              Sending
+            </div>
+          );
+        }
+
+        return (
+          <div className="flex items-center text-gray-600">
+            <ClockCircleOutlined className="mr-2" />
+            {record.attemptsCount > 0
+              ? `Retry at ${dayjs.utc(record.nextAttemptAt).local().format('HH:mm:ss')}`
+              : 'Pending'}
+          </div>
+        );
+      },
+    },
+    {
+      title: 'Attempts',
+      dataIndex: 'attemptsCount',
+      key: 'attemptsCount',
+    },
+  ];
+
+  return (
+    <div className="mt-5">
+      <div className="mb-1 font-bold">Delivery history</div>
+
+      <Table
+        size="small"
+        rowKey="id"
+        loading={isLoading}
+        columns={columns}
+        dataSource={deliveries}
+        className="max-w-[700px]"
+        pagination={{
+          current: page,
+          pageSize: PAGE_SIZE,
+          total,
+          showSizeChanger: false,
+          onChange: (page) => {
+            setPage(page);
+            loadDeliveries(page);
+          },
+        }}
+      />
+
+      {showingDelivery && (
+        <Modal
+          title={showingDelivery.event.title}
+          open
+          onCancel={() => setShowingDelivery(undefined)}
+          footer={<Button onClick={() => setShowingDelivery(undefined)}>Close</Button>}
+        >
+          <div className="mb-1 font-bold">Attempts</div>
+          {showingDelivery.attempts.length === 0 && (
+            <div className="text-sm text-gray-500">Not sent yet</div>
+          )}
+          {showingDelivery.attempts.map((attempt, index) => (
+            <div key={index} className="mb-1 text-sm">
+              {formatTime(attempt.attemptedAt)} ({attempt.durationMs} ms):{' '}
+              {attempt.error ? (
+                <span className="text-red-600">{attempt.error}</span>
+              ) : (
+                <span className="text-green-600">delivered</span>
+              )}
+            </div>
+          ))}
+
+          <div className="mt-3 mb-1 font-bold">Payload</div>
+          <pre className="max-h-[300px] overflow-auto rounded bg-gray-100 p-2 text-xs">
+            {JSON.stringify(showingDelivery.event, null, 2)}
+          </pre>
+        </Modal>
+      )}
+    </div>
+  );
+};
@@ -6,6 +6,7 @@ import type { Notifier } from '../../../entity/notifiers';
 import { NotifierCardComponent } from './NotifierCardComponent';
 import { NotifierComponent } from './NotifierComponent';
 import { EditNotifierComponent } from './edit/EditNotifierComponent';
+import { NotificationRulesComponent } from './rules/NotificationRulesComponent';
 
 interface Props {
   contentHeight: number;
@@ -15,6 +16,7 @@ export const NotifiersComponent = ({ contentHeight }: Props) => {
   const [notifiers, setNotifiers] = useState<Notifier[]>([]);
 
   const [isShowAddNotifier, setIsShowAddNotifier] = useState(false);
+  const [isShowRules, setIsShowRules] = useState(false);
   const [selectedNotifierId, setSelectedNotifierId] = useState<string | undefined>(undefined);
   const loadNotifiers = () => {
     setIsLoading(true);
@@ -72,6 +74,12 @@ export const NotifiersComponent = ({ contentHeight }: Props) => {
           <div className="mx-3 text-center text-xs text-gray-500">
             Notifier - is a place where notifications will be sent (email, Slack, Telegram, etc.)
           </div>
+
+          {notifiers.length > 0 && (
+            <Button className="mt-3 w-full" onClick={() => setIsShowRules(true)}>
+              Routing rules
+            </Button>
+          )}
         </div>
 
         {selectedNotifierId && (
@@ -112,6 +120,17 @@ export const NotifiersComponent = ({ contentHeight }: Props) => {
           />
         </Modal>
       )}
+
+      {isShowRules && (
+        <Modal
+          title="Routing rules"
+          footer={<div />}
+          open={isShowRules}
+          onCancel={() => setIsShowRules(false)}
+        >
+          <NotificationRulesComponent notifiers={notifiers} />
+        </Modal>
+      )}
     </>
   );
 };
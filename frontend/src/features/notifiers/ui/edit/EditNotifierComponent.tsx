@@ -2,12 +2,23 @@ import { Button, Input, Select } from 'antd';
 import { useEffect, useState } from 'react';
 
 import {
+  DigestFrequency,
+  EmailAuthType,
+  EmailSMTPSecurity,
   type Notifier,
   NotifierType,
+  OpsgenieRegion,
   WebhookMethod,
   notifierApi,
   validateDiscordNotifier,
   validateEmailNotifier,
+  validateGoogleChatNotifier,
+  validateMatrixNotifier,
+  validateMattermostNotifier,
+  validateNtfyNotifier,
+  validateOpsgenieNotifier,
+  validatePagerDutyNotifier,
+  validateRocketChatNotifier,
   validateSlackNotifier,
   validateTeamsNotifier,
   validateTelegramNotifier,
@@ -15,8 +26,17 @@ import {
 } from '../../../../entity/notifiers';
 import { getNotifierLogoFromType } from '../../../../entity/notifiers/models/getNotifierLogoFromType';
 import { ToastHelper } from '../../../../shared/toast';
+import { EditNotifierDigestComponent } from './EditNotifierDigestComponent';
+import { EditNotifierTemplatesComponent } from './EditNotifierTemplatesComponent';
 import { EditDiscordNotifierComponent } from './notifiers/EditDiscordNotifierComponent';
 import { EditEmailNotifierComponent } from './notifiers/EditEmailNotifierComponent';
+import { EditGoogleChatNotifierComponent } from './notifiers/EditGoogleChatNotifierComponent';
+import { EditMatrixNotifierComponent } from './notifiers/EditMatrixNotifierComponent';
+import { EditMattermostNotifierComponent } from './notifiers/EditMattermostNotifierComponent';
+import { EditNtfyNotifierComponent } from './notifiers/EditNtfyNotifierComponent';
+import { EditOpsgenieNotifierComponent } from './notifiers/EditOpsgenieNotifierComponent';
+import { EditPagerDutyNotifierComponent } from './notifiers/EditPagerDutyNotifierComponent';
+import { EditRocketChatNotifierComponent } from './notifiers/EditRocketChatNotifierComponent';
 import { EditSlackNotifierComponent } from './notifiers/EditSlackNotifierComponent';
 import { EditTeamsNotifierComponent } from './notifiers/EditTeamsNotifierComponent';
 import { EditTelegramNotifierComponent } from './notifiers/EditTelegramNotifierComponent';
@@ -93,6 +113,18 @@ export function EditNotifierComponent({
     notifier.emailNotifier = undefined;
     notifier.telegramNotifier = undefined;
     notifier.teamsNotifier = undefined;
+    notifier.pagerDutyNotifier = undefined;
+    notifier.opsgenieNotifier = undefined;
+    notifier.mattermostNotifier = undefined;
+    notifier.rocketChatNotifier = undefined;
+    notifier.googleChatNotifier = undefined;
+    notifier.matrixNotifier = undefined;
+    notifier.ntfyNotifier = undefined;
+
+    // incidents are not opened for digests
+    if (type === NotifierType.PAGERDUTY || type === NotifierType.OPSGENIE) {
+      notifier.digestFrequency = DigestFrequency.NONE;
+    }
 
     if (type === NotifierType.TELEGRAM) {
       notifier.telegramNotifier = {
@@ -106,8 +138,10 @@ export function EditNotifierComponent({
         targetEmail: '',
         smtpHost: '',
         smtpPort: 0,
+        smtpSecurity: EmailSMTPSecurity.AUTO,
         smtpUser: '',
         smtpPassword: '',
+        smtpAuthType: EmailAuthType.PASSWORD,
       };
     }
 
@@ -135,6 +169,38 @@ export function EditNotifierComponent({
       notifier.teamsNotifier = { powerAutomateUrl: '' };
     }
 
+    if (type === NotifierType.PAGERDUTY) {
+      notifier.pagerDutyNotifier = { routingKey: '' };
+    }
+
+    if (type === NotifierType.OPSGENIE) {
+      notifier.opsgenieNotifier = { apiKey: '', region: OpsgenieRegion.US };
+    }
+
+    if (type === NotifierType.MATTERMOST) {
+      notifier.mattermostNotifier = { webhookUrl: '' };
+    }
+
+    if (type === NotifierType.ROCKET_CHAT) {
+      notifier.rocketChatNotifier = { webhookUrl: '' };
+    }
+
+    if (type === NotifierType.GOOGLE_CHAT) {
+      notifier.googleChatNotifier = { webhookUrl: '' };
+    }
+
+    if (type === NotifierType.MATRIX) {
+      notifier.matrixNotifier = {
+        homeserverUrl: 'https://matrix.org',
+        accessToken: '',
+        roomId: '',
+      };
+    }
+
+    if (type === NotifierType.NTFY) {
+      notifier.ntfyNotifier = { serverUrl: 'https://ntfy.sh', topic: '' };
+    }
+
     setNotifier(
       JSON.parse(
         JSON.stringify({
@@ -171,11 +237,11 @@ export function EditNotifierComponent({
     if (!notifier.name) return false;
 
     if (notifier.notifierType === NotifierType.TELEGRAM && notifier.telegramNotifier) {
-      return validateTelegramNotifier(notifier.telegramNotifier);
+      return validateTelegramNotifier(notifier.telegramNotifier, !!notifier.id);
     }
 
     if (notifier.notifierType === NotifierType.EMAIL && notifier.emailNotifier) {
-      return validateEmailNotifier(notifier.emailNotifier);
+      return validateEmailNotifier(notifier.emailNotifier, !!notifier.id);
     }
 
     if (notifier.notifierType === NotifierType.WEBHOOK && notifier.webhookNotifier) {
@@ -183,15 +249,43 @@ export function EditNotifierComponent({
     }
 
     if (notifier.notifierType === NotifierType.SLACK && notifier.slackNotifier) {
-      return validateSlackNotifier(notifier.slackNotifier);
+      return validateSlackNotifier(notifier.slackNotifier, !!notifier.id);
     }
 
     if (notifier.notifierType === NotifierType.DISCORD && notifier.discordNotifier) {
-      return validateDiscordNotifier(notifier.discordNotifier);
+      return validateDiscordNotifier(notifier.discordNotifier, !!notifier.id);
     }
 
     if (notifier.notifierType === NotifierType.TEAMS && notifier.teamsNotifier) {
-      return validateTeamsNotifier(notifier.teamsNotifier);
+      return validateTeamsNotifier(notifier.teamsNotifier, !!notifier.id);
+    }
+
+    if (notifier.notifierType === NotifierType.PAGERDUTY && notifier.pagerDutyNotifier) {
+      return validatePagerDutyNotifier(notifier.pagerDutyNotifier, !!notifier.id);
+    }
+
+    if (notifier.notifierType === NotifierType.OPSGENIE && notifier.opsgenieNotifier) {
+      return validateOpsgenieNotifier(notifier.opsgenieNotifier, !!notifier.id);
+    }
+
+    if (notifier.notifierType === NotifierType.MATTERMOST && notifier.mattermostNotifier) {
+      return validateMattermostNotifier(notifier.mattermostNotifier, !!notifier.id);
+    }
+
+    if (notifier.notifierType === NotifierType.ROCKET_CHAT && notifier.rocketChatNotifier) {
+      return validateRocketChatNotifier(notifier.rocketChatNotifier, !!notifier.id);
+    }
+
+    if (notifier.notifierType === NotifierType.GOOGLE_CHAT && notifier.googleChatNotifier) {
+      return validateGoogleChatNotifier(notifier.googleChatNotifier, !!notifier.id);
+    }
+
+    if (notifier.notifierType === NotifierType.MATRIX && notifier.matrixNotifier) {
+      return validateMatrixNotifier(notifier.matrixNotifier, !!notifier.id);
+    }
+
+    if (notifier.notifierType === NotifierType.NTFY && notifier.ntfyNotifier) {
+      return validateNtfyNotifier(notifier.ntfyNotifier);
     }
 
     return false;
@@ -230,6 +324,13 @@ export function EditNotifierComponent({
             { label: 'Slack', value: NotifierType.SLACK },
             { label: 'Discord', value: NotifierType.DISCORD },
             { label: 'Teams', value: NotifierType.TEAMS },
+            { label: 'PagerDuty', value: NotifierType.PAGERDUTY },
+            { label: 'Opsgenie', value: NotifierType.OPSGENIE },
+            { label: 'Mattermost', value: NotifierType.MATTERMOST },
+            { label: 'Rocket.Chat', value: NotifierType.ROCKET_CHAT },
+            { label: 'Google Chat', value: NotifierType.GOOGLE_CHAT },
+            { label: 'Matrix', value: NotifierType.MATRIX },
+            { label: 'ntfy', value: NotifierType.NTFY },
           ]}
           onChange={(value) => {
             setNotifierType(value);
@@ -291,8 +392,79 @@ export function EditNotifierComponent({
             setIsUnsaved={setIsUnsaved}
           />
         )}
+
+        {notifier?.notifierType === NotifierType.PAGERDUTY && (
+          <EditPagerDutyNotifierComponent
+            notifier={notifier}
+            setNotifier={setNotifier}
+            setIsUnsaved={setIsUnsaved}
+          />
+        )}
+
+        {notifier?.notifierType === NotifierType.OPSGENIE && (
+          <EditOpsgenieNotifierComponent
+            notifier={notifier}
+            setNotifier={setNotifier}
+            setIsUnsaved={setIsUnsaved}
+          />
+        )}
+
+        {notifier?.notifierType === NotifierType.MATTERMOST && (
+          <EditMattermostNotifierComponent
+            notifier={notifier}
+            setNotifier={setNotifier}
+            setIsUnsaved={setIsUnsaved}
+          />
+        )}
+
+        {notifier?.notifierType === NotifierType.ROCKET_CHAT && (
+          <EditRocketChatNotifierComponent
+            notifier={notifier}
+            setNotifier={setNotifier}
+            setIsUnsaved={setIsUnsaved}
+          />
+        )}
+
+        {notifier?.notifierType === NotifierType.GOOGLE_CHAT && (
+          <EditGoogleChatNotifierComponent
+            notifier={notifier}
+            setNotifier={setNotifier}
+            setIsUnsaved={setIsUnsaved}
+          />
+        )}
+
+        {notifier?.notifierType === NotifierType.MATRIX && (
+          <EditMatrixNotifierComponent
+            notifier={notifier}
+            setNotifier={setNotifier}
+            setIsUnsaved={setIsUnsaved}
+          />
+        )}
+
+        {notifier?.notifierType === NotifierType.NTFY && (
+          <EditNtfyNotifierComponent
+            notifier={notifier}
+            setNotifier={setNotifier}
+            setIsUnsaved={setIsUnsaved}
+          />
+        )}
       </div>
 
+      {notifier.notifierType !== NotifierType.PAGERDUTY &&
+        notifier.notifierType !== NotifierType.OPSGENIE && (
+          <EditNotifierDigestComponent
+            notifier={notifier}
+            setNotifier={setNotifier}
+            setIsUnsaved={setIsUnsaved}
+          />
+        )}
+
+      <EditNotifierTemplatesComponent
+        notifier={notifier}
+        setNotifier={setNotifier}
+        setIsUnsaved={setIsUnsaved}
+      />
+
       <div className="mt-3 flex">
         {isUnsaved && !isTestNotificationSuccess ? (
           <Button
@@ -0,0 +1,110 @@
+import { InfoCircleOutlined } from '@ant-design/icons';
+import { Select, TimePicker, Tooltip } from 'antd';
+import dayjs from 'dayjs';
+import { useMemo } from 'react';
+
+import { DigestFrequency, type Notifier } from '../../../../entity/notifiers';
+import { getLocalWeekday, getUserTimeFormat, getUtcWeekday } from '../../../../shared/time/utils';
+
+interface Props {
+  notifier: Notifier;
+  setNotifier: (notifier: Notifier) => void;
+  setIsUnsaved: (isUnsaved: boolean) => void;
+}
+
+const weekdayOptions = [
+  { value: 1, label: 'Mon' },
+  { value: 2, label: 'Tue' },
+  { value: 3, label: 'Wed' },
+  { value: 4, label: 'Thu' },
+  { value: 5, label: 'Fri' },
+  { value: 6, label: 'Sat' },
+  { value: 7, label: 'Sun' },
+];
+
+export function EditNotifierDigestComponent({ notifier, setNotifier, setIsUnsaved }: Props) {
+  const timeFormat = useMemo(() => {
+    const is12 = getUserTimeFormat();
+    return { use12Hours: is12, format: is12 ? 'h:mm A' : 'HH:mm' };
+  }, []);
+
+  const frequency = notifier.digestFrequency ?? DigestFrequency.NONE;
+  const timeOfDay = notifier.digestTimeOfDay || '09:00';
+  const weekday = notifier.digestWeekday || 1;
+
+  const localTime = dayjs.utc(timeOfDay, 'HH:mm').local();
+  const displayedWeekday = getLocalWeekday(weekday, timeOfDay);
+
+  const updateNotifier = (patch: Partial<Notifier>) => {
+    setNotifier({
+      ...notifier,
+      digestTimeOfDay: timeOfDay,
+      digestWeekday: weekday,
+      ...patch,
+    });
+    setIsUnsaved(true);
+  };
+
+  return (
+    <div className="mt-3">
+      <div className="mb-1 flex items-center">
+        <div className="w-[130px] min-w-[130px]">Backup digest</div>
+        <Select
+          value={frequency}
+          onChange={(digestFrequency) => updateNotifier({ digestFrequency })}
+          options={[
+            { label: 'Disabled', value: DigestFrequency.NONE },
+            { label: 'Daily', value: DigestFrequency.DAILY },
+            { label: 'Weekly', value: DigestFrequency.WEEKLY },
+          ]}
+          size="small"
+          className="w-full max-w-[250px]"
+        />
+
+        <Tooltip
+          className="cursor-pointer"
+          title="Summary of backups, missed schedules and uptime of databases using the notifier"
+        >
+          <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
+        </Tooltip>
+      </div>
+
+      {frequency === DigestFrequency.WEEKLY && (
+        <div className="mb-1 flex items-center">
+          <div className="w-[130px] min-w-[130px]">Digest weekday</div>
+          <Select
+            value={displayedWeekday}
+            onChange={(localWeekday) =>
+              updateNotifier({ digestWeekday: getUtcWeekday(localWeekday, localTime) })
+            }
+            options={weekdayOptions}
+            size="small"
+            className="w-full max-w-[250px]"
+          />
+        </div>
+      )}
+
+      {frequency !== DigestFrequency.NONE && (
+        <div className="mb-1 flex items-center">
+          <div className="w-[130px] min-w-[130px]">Digest time</div>
+          <TimePicker
+            value={localTime}
+            format={timeFormat.format}
+            use12Hours={timeFormat.use12Hours}
+            allowClear={false}
+            size="small"
+            className="w-full max-w-[250px]"
+            onChange={(t) => {
+              if (!t) return;
+
+              updateNotifier({
+                digestTimeOfDay: t.utc().format('HH:mm'),
+                digestWeekday: getUtcWeekday(displayedWeekday, t),
+              });
+            }}
+          />
+        </div>
+      )}
+    </div>
+  );
+}
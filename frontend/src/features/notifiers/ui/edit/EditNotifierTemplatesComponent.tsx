@@ -0,0 +1,163 @@
+import { InfoCircleOutlined } from '@ant-design/icons';
+import { Button, Input, Select, Tooltip } from 'antd';
+import { useState } from 'react';
+
+import {
+  NotificationEventType,
+  type Notifier,
+  type NotifierTemplate,
+  type TemplatePreview,
+  getNotificationEventTypeName,
+  notifierApi,
+} from '../../../../entity/notifiers';
+
+interface Props {
+  notifier: Notifier;
+  setNotifier: (notifier: Notifier) => void;
+  setIsUnsaved: (isUnsaved: boolean) => void;
+}
+
+const TEMPLATE_VARIABLES =
+  '{{.EventType}}, {{.Severity}}, {{.Title}}, {{.Message}}, {{.DatabaseID}}, ' +
+  '{{.DatabaseName}}, {{.BackupID}}, {{.RestoreID}}, {{.Size}}, {{.SizeMb}}, ' +
+  '{{.Duration}}, {{.DurationMs}}, {{.Error}}, {{.Link}}, {{.CreatedAt}}';
+
+export function EditNotifierTemplatesComponent({ notifier, setNotifier, setIsUnsaved }: Props) {
+  const [previews, setPreviews] = useState<Record<number, TemplatePreview>>({});
+  const [previewingIndex, setPreviewingIndex] = useState<number | undefined>();
+
+  const templates = notifier.templates || [];
+
+  const setTemplates = (newTemplates: NotifierTemplate[]) => {
+    setNotifier({ ...notifier, templates: newTemplates });
+    setPreviews({});
+    setIsUnsaved(true);
+  };
+
+  const updateTemplate = (index: number, template: NotifierTemplate) => {
+    setTemplates(templates.map((t, i) => (i === index ? template : t)));
+  };
+
+  const addTemplate = () => {
+    const unusedEventType = Object.values(NotificationEventType).find(
+      (eventType) => !templates.some((t) => t.eventType === eventType),
+    );
+    if (!unusedEventType) return;
+
+    setTemplates([
+      ...templates,
+      { eventType: unusedEventType, titleTemplate: '', bodyTemplate: '' },
+    ]);
+  };
+
+  const previewTemplate = async (index: number) => {
+    setPreviewingIndex(index);
+
+    try {
+      const preview = await notifierApi.previewTemplate(templates[index]);
+      setPreviews({ ...previews, [index]: preview });
+    } catch (e) {
+      alert((e as Error).message);
+    }
+
+    setPreviewingIndex(undefined);
+  };
+
+  return (
+    <div className="mt-5">
+      <div className="mb-1 flex items-center">
+        <div className="font-bold">Message templates</div>
+
+        <Tooltip
+          className="cursor-pointer"
+          title={`Go templates override title and body of notifications. Available variables: ${TEMPLATE_VARIABLES}`}
+        >
+          <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
+        </Tooltip>
+      </div>
+
+      {templates.map((template, index) => (
+        <div key={index} className="mb-3 rounded border border-gray-200 p-2">
+          <div className="mb-1 flex items-center">
+            <div className="w-[120px] min-w-[120px]">Event</div>
+
+            <Select
+              value={template.eventType}
+              options={Object.values(NotificationEventType).map((eventType) => ({
+                label: getNotificationEventTypeName(eventType),
+                value: eventType,
+                disabled: templates.some((t, i) => i !== index && t.eventType === eventType),
+              }))}
+              onChange={(value) => updateTemplate(index, { ...template, eventType: value })}
+              size="small"
+              className="w-full max-w-[250px]"
+            />
+          </div>
+
+          <div className="mb-1 flex items-center">
+            <div className="w-[120px] min-w-[120px]">Title</div>
+
+            <Input
+              value={template.titleTemplate}
+              onChange={(e) =>
+                updateTemplate(index, { ...template, titleTemplate: e.target.value })
+              }
+              size="small"
+              className="w-full"
+              placeholder="Default title if empty"
+            />
+          </div>
+
+          <div className="mb-1 flex">
+            <div className="w-[120px] min-w-[120px]">Body</div>
+
+            <Input.TextArea
+              value={template.bodyTemplate}
+              onChange={(e) =>
+                updateTemplate(index, { ...template, bodyTemplate: e.target.value })
+              }
+              size="small"
+              className="w-full"
+              autoSize={{ minRows: 2, maxRows: 8 }}
+              placeholder="Backup of {{.DatabaseName}} took {{.Duration}}, size is {{.Size}}"
+            />
+          </div>
+
+          {previews[index] && (
+            <div className="mb-1 rounded bg-gray-100 p-2 px-3 text-sm whitespace-pre-line">
+              <div className="font-bold">{previews[index].title}</div>
+              <div>{previews[index].body}</div>
+            </div>
+          )}
+
+          <div className="flex">
+            <Button
+              className="mr-1"
+              size="small"
+              loading={previewingIndex === index}
+              disabled={!template.titleTemplate && !template.bodyTemplate}
+              onClick={() => previewTemplate(index)}
+            >
+              Preview
+            </Button>
+
+            <Button
+              size="small"
+              danger
+              ghost
+              onClick={() => setTemplates(templates.filter((_, i) => i !== index))}
+            >
+              Remove
+            </Button>
+          </div>
+        </div>
+      ))}
+
+      {templates.length < Object.values(NotificationEventType).length && (
+        <Button size="small" onClick={addTemplate}>
+          Add template
+        </Button>
+      )}
+    </div>
+  );
+}
@@ -1,7 +1,13 @@
 import { InfoCircleOutlined } from '@ant-design/icons';
-import { Input, Tooltip } from 'antd';
+import { Checkbox, Input, Select, Tooltip } from 'antd';
 
-import type { Notifier } from '../../../../../entity/notifiers';
+import {
+  EmailAuthType,
+  type EmailNotifier,
+  EmailOAuth2Provider,
+  EmailSMTPSecurity,
+  type Notifier,
+} from '../../../../../entity/notifiers';
 
 interface Props {
   notifier: Notifier;
@@ -10,50 +16,58 @@ interface Props {
 }
 
 export function EditEmailNotifierComponent({ notifier, setNotifier, setIsUnsaved }: Props) {
+  const emailNotifier = notifier?.emailNotifier;
+  const isOAuth2 = emailNotifier?.smtpAuthType === EmailAuthType.OAUTH2;
+
+  const updateEmailNotifier = (fields: Partial<EmailNotifier>) => {
+    if (!notifier?.emailNotifier) return;
+
+    setNotifier({
+      ...notifier,
+      emailNotifier: {
+        ...notifier.emailNotifier,
+        ...fields,
+      },
+    });
+    setIsUnsaved(true);
+  };
+
   return (
     <>
       <div className="mb-1 flex items-center">
-        <div className="w-[130px] min-w-[130px]">Target email</div>
+        <div className="w-[130px] min-w-[130px]">Target emails</div>
         <Input
-          value={notifier?.emailNotifier?.targetEmail || ''}
-          onChange={(e) => {
-            if (!notifier?.emailNotifier) return;
-
-            setNotifier({
-              ...notifier,
-              emailNotifier: {
-                ...notifier.emailNotifier,
-                targetEmail: e.target.value.trim(),
-              },
-            });
-            setIsUnsaved(true);
-          }}
+          value={emailNotifier?.targetEmail || ''}
+          onChange={(e) => updateEmailNotifier({ targetEmail: e.target.value })}
           size="small"
           className="w-full max-w-[250px]"
-          placeholder="example@gmail.com"
+          placeholder="admin@gmail.com, dba@gmail.com"
         />
 
-        <Tooltip className="cursor-pointer" title="The email where you want to receive the message">
+        <Tooltip
+          className="cursor-pointer"
+          title="Emails where you want to receive the message, separated by commas"
+        >
           <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
         </Tooltip>
       </div>
 
+      <div className="mb-1 flex items-center">
+        <div className="w-[130px] min-w-[130px]">CC emails</div>
+        <Input
+          value={emailNotifier?.ccEmails || ''}
+          onChange={(e) => updateEmailNotifier({ ccEmails: e.target.value })}
+          size="small"
+          className="w-full max-w-[250px]"
+          placeholder="team@gmail.com (optional)"
+        />
+      </div>
+
       <div className="mb-1 flex items-center">
         <div className="w-[130px] min-w-[130px]">SMTP host</div>
         <Input
-          value={notifier?.emailNotifier?.smtpHost || ''}
-          onChange={(e) => {
-            if (!notifier?.emailNotifier) return;
-
-            setNotifier({
-              ...notifier,
-              emailNotifier: {
-                ...notifier.emailNotifier,
-                smtpHost: e.target.value.trim(),
-              },
-            });
-            setIsUnsaved(true);
-          }}
+          value={emailNotifier?.smtpHost || ''}
+          onChange={(e) => updateEmailNotifier({ smtpHost: e.target.value.trim() })}
           size="small"
           className="w-full max-w-[250px]"
           placeholder="smtp.gmail.com"
@@ -64,19 +78,8 @@ export function EditEmailNotifierComponent({ notifier, setNotifier, setIsUnsaved
         <div className="w-[130px] min-w-[130px]">SMTP port</div>
         <Input
           type="number"
-          value={notifier?.emailNotifier?.smtpPort || ''}
-          onChange={(e) => {
-            if (!notifier?.emailNotifier) return;
-
-            setNotifier({
-              ...notifier,
-              emailNotifier: {
-                ...notifier.emailNotifier,
-                smtpPort: Number(e.target.value),
-              },
-            });
-            setIsUnsaved(true);
-          }}
+          value={emailNotifier?.smtpPort || ''}
+          onChange={(e) => updateEmailNotifier({ smtpPort: Number(e.target.value) })}
           size="small"
           className="w-full max-w-[250px]"
           placeholder="25"
@@ -84,48 +87,166 @@ export function EditEmailNotifierComponent({ notifier, setNotifier, setIsUnsaved
       </div>
 
       <div className="mb-1 flex items-center">
-        <div className="w-[130px] min-w-[130px]">SMTP user</div>
-        <Input
-          value={notifier?.emailNotifier?.smtpUser || ''}
-          onChange={(e) => {
-            if (!notifier?.emailNotifier) return;
-
-            setNotifier({
-              ...notifier,
-              emailNotifier: {
-                ...notifier.emailNotifier,
-                smtpUser: e.target.value.trim(),
-              },
-            });
-            setIsUnsaved(true);
-          }}
+        <div className="w-[130px] min-w-[130px]">Security</div>
+        <Select
+          value={emailNotifier?.smtpSecurity || EmailSMTPSecurity.AUTO}
+          onChange={(value) => updateEmailNotifier({ smtpSecurity: value })}
           size="small"
           className="w-full max-w-[250px]"
-          placeholder="user@gmail.com"
+          options={[
+            { value: EmailSMTPSecurity.AUTO, label: 'Auto' },
+            { value: EmailSMTPSecurity.STARTTLS, label: 'STARTTLS' },
+            { value: EmailSMTPSecurity.TLS, label: 'Implicit TLS' },
+            { value: EmailSMTPSecurity.NONE, label: 'None' },
+          ]}
         />
+
+        <Tooltip
+          className="cursor-pointer"
+          title="Auto uses implicit TLS on port 465 and STARTTLS on other ports if the server supports it. STARTTLS fails if the server does not support it"
+        >
+          <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
+        </Tooltip>
       </div>
 
       <div className="mb-1 flex items-center">
-        <div className="w-[130px] min-w-[130px]">SMTP password</div>
+        <div className="w-[130px] min-w-[130px]">Authentication</div>
+        <Select
+          value={emailNotifier?.smtpAuthType || EmailAuthType.PASSWORD}
+          onChange={(value) =>
+            updateEmailNotifier({
+              smtpAuthType: value,
+              oauth2Provider:
+                value === EmailAuthType.OAUTH2
+                  ? emailNotifier?.oauth2Provider || EmailOAuth2Provider.GOOGLE
+                  : emailNotifier?.oauth2Provider,
+            })
+          }
+          size="small"
+          className="w-full max-w-[250px]"
+          options={[
+            { value: EmailAuthType.PASSWORD, label: 'Password' },
+            { value: EmailAuthType.OAUTH2, label: 'OAuth2 (Gmail, Office 365)' },
+          ]}
+        />
+      </div>
+
+      <div className="mb-1 flex items-center">
+        <div className="w-[130px] min-w-[130px]">SMTP user</div>
         <Input
-          value={notifier?.emailNotifier?.smtpPassword || ''}
-          onChange={(e) => {
-            if (!notifier?.emailNotifier) return;
-
-            setNotifier({
-              ...notifier,
-              emailNotifier: {
-                ...notifier.emailNotifier,
-                smtpPassword: e.target.value.trim(),
-              },
-            });
-            setIsUnsaved(true);
-          }}
+          value={emailNotifier?.smtpUser || ''}
+          onChange={(e) => updateEmailNotifier({ smtpUser: e.target.value.trim() })}
           size="small"
           className="w-full max-w-[250px]"
-          placeholder="password"
+          placeholder="user@gmail.com"
         />
       </div>
+
+      {!isOAuth2 && (
+        <div className="mb-1 flex items-center">
+          <div className="w-[130px] min-w-[130px]">SMTP password</div>
+          <Input
+            value={emailNotifier?.smtpPassword || ''}
+            onChange={(e) => updateEmailNotifier({ smtpPassword: e.target.value.trim() })}
+            size="small"
+            className="w-full max-w-[250px]"
+            placeholder={notifier.id ? 'Leave empty to keep saved' : 'password'}
+          />
+        </div>
+      )}
+
+      {isOAuth2 && (
+        <>
+          <div className="mb-1 flex items-center">
+            <div className="w-[130px] min-w-[130px]">Provider</div>
+            <Select
+              value={emailNotifier?.oauth2Provider || EmailOAuth2Provider.GOOGLE}
+              onChange={(value) => updateEmailNotifier({ oauth2Provider: value })}
+              size="small"
+              className="w-full max-w-[250px]"
+              options={[
+                { value: EmailOAuth2Provider.GOOGLE, label: 'Google (Gmail)' },
+                { value: EmailOAuth2Provider.MICROSOFT, label: 'Microsoft (Office 365)' },
+              ]}
+            />
+          </div>
+
+          {emailNotifier?.oauth2Provider === EmailOAuth2Provider.MICROSOFT && (
+            <div className="mb-1 flex items-center">
+              <div className="w-[130px] min-w-[130px]">Tenant ID</div>
+              <Input
+                value={emailNotifier?.oauth2TenantId || ''}
+                onChange={(e) => updateEmailNotifier({ oauth2TenantId: e.target.value.trim() })}
+                size="small"
+                className="w-full max-w-[250px]"
+                placeholder="common"
+              />
+
+              <Tooltip
+                className="cursor-pointer"
+                title="Directory (tenant) ID of the app registration, empty means common"
+              >
+                <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
+              </Tooltip>
+            </div>
+          )}
+
+          <div className="mb-1 flex items-center">
+            <div className="w-[130px] min-w-[130px]">Client ID</div>
+            <Input
+              value={emailNotifier?.oauth2ClientId || ''}
+              onChange={(e) => updateEmailNotifier({ oauth2ClientId: e.target.value.trim() })}
+              size="small"
+              className="w-full max-w-[250px]"
+              placeholder="OAuth2 client ID"
+            />
+          </div>
+
+          <div className="mb-1 flex items-center">
+            <div className="w-[130px] min-w-[130px]">Client secret</div>
+            <Input.Password
+              value={emailNotifier?.oauth2ClientSecret || ''}
+              onChange={(e) => updateEmailNotifier({ oauth2ClientSecret: e.target.value.trim() })}
+              size="small"
+              className="w-full max-w-[250px]"
+              placeholder={notifier.id ? 'Leave empty to keep saved' : 'OAuth2 client secret'}
+            />
+          </div>
+
+          <div className="mb-1 flex items-center">
+            <div className="w-[130px] min-w-[130px]">Refresh token</div>
+            <Input.Password
+              value={emailNotifier?.oauth2RefreshToken || ''}
+              onChange={(e) => updateEmailNotifier({ oauth2RefreshToken: e.target.value.trim() })}
+              size="small"
+              className="w-full max-w-[250px]"
+              placeholder={notifier.id ? 'Leave empty to keep saved' : 'OAuth2 refresh token'}
+            />
+
+            <Tooltip
+              className="cursor-pointer"
+              title="Refresh token of the SMTP user with scope https://mail.google.com/ for Google or SMTP.Send and offline_access for Microsoft"
+            >
+              <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
+            </Tooltip>
+          </div>
+        </>
+      )}
+
+      <div className="mb-1 flex items-center">
+        <div className="w-[130px] min-w-[130px]">Attach backup log</div>
+        <Checkbox
+          checked={!!emailNotifier?.isBackupLogAttached}
+          onChange={(e) => updateEmailNotifier({ isBackupLogAttached: e.target.checked })}
+        />
+
+        <Tooltip
+          className="cursor-pointer"
+          title="Attach full output of pg_dump to emails about failed backups, the email itself shows only the beginning of the error"
+        >
+          <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
+        </Tooltip>
+      </div>
     </>
   );
 }
@@ -0,0 +1,45 @@
+import { InfoCircleOutlined } from '@ant-design/icons';
+import { Input, Tooltip } from 'antd';
+
+import type { Notifier } from '../../../../../entity/notifiers';
+
+interface Props {
+  notifier: Notifier;
+  setNotifier: (notifier: Notifier) => void;
+  setIsUnsaved: (isUnsaved: boolean) => void;
+}
+
+export function EditGoogleChatNotifierComponent({ notifier, setNotifier, setIsUnsaved }: Props) {
+  return (
+    <div className="flex items-center">
+      <div className="w-[130px] min-w-[130px]">Webhook URL</div>
+
+      <div className="w-[250px]">
+        <Input
+          value={notifier?.googleChatNotifier?.webhookUrl || ''}
+          onChange={(e) => {
+            setNotifier({
+              ...notifier,
+              googleChatNotifier: { webhookUrl: e.target.value.trim() },
+            });
+            setIsUnsaved(true);
+          }}
+          size="small"
+          className="w-full"
+          placeholder={
+            notifier.id
+              ? 'Leave empty to keep saved'
+              : 'https://chat.googleapis.com/v1/spaces/.../messages?key=...'
+          }
+        />
+      </div>
+
+      <Tooltip
+        className="cursor-pointer"
+        title="Space settings → Apps & integrations → Webhooks → Add webhook in Google Chat"
+      >
+        <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
+      </Tooltip>
+    </div>
+  );
+}
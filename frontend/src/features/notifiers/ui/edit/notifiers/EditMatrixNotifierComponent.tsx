@@ -0,0 +1,83 @@
+import { InfoCircleOutlined } from '@ant-design/icons';
+import { Input, Tooltip } from 'antd';
+
+import type { MatrixNotifier, Notifier } from '../../../../../entity/notifiers';
+
+interface Props {
+  notifier: Notifier;
+  setNotifier: (notifier: Notifier) => void;
+  setIsUnsaved: (isUnsaved: boolean) => void;
+}
+
+export function EditMatrixNotifierComponent({ notifier, setNotifier, setIsUnsaved }: Props) {
+  const updateMatrixNotifier = (fields: Partial<MatrixNotifier>) => {
+    setNotifier({
+      ...notifier,
+      matrixNotifier: {
+        ...(notifier.matrixNotifier || { homeserverUrl: '', accessToken: '', roomId: '' }),
+        ...fields,
+      },
+    });
+    setIsUnsaved(true);
+  };
+
+  return (
+    <>
+      <div className="flex items-center">
+        <div className="w-[130px] min-w-[130px]">Homeserver URL</div>
+
+        <div className="w-[250px]">
+          <Input
+            value={notifier?.matrixNotifier?.homeserverUrl || ''}
+            onChange={(e) => updateMatrixNotifier({ homeserverUrl: e.target.value.trim() })}
+            size="small"
+            className="w-full"
+            placeholder="https://matrix.org"
+          />
+        </div>
+      </div>
+
+      <div className="mt-1 flex items-center">
+        <div className="w-[130px] min-w-[130px]">Access token</div>
+
+        <div className="w-[250px]">
+          <Input.Password
+            value={notifier?.matrixNotifier?.accessToken || ''}
+            onChange={(e) => updateMatrixNotifier({ accessToken: e.target.value.trim() })}
+            size="small"
+            className="w-full"
+            placeholder={notifier.id ? 'Leave empty to keep saved' : 'syt_...'}
+          />
+        </div>
+
+        <Tooltip
+          className="cursor-pointer"
+          title="Access token of the bot account, e.g. from Settings → Help & About of Element. The bot must be joined to the room"
+        >
+          <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
+        </Tooltip>
+      </div>
+
+      <div className="mt-1 flex items-center">
+        <div className="w-[130px] min-w-[130px]">Room ID</div>
+
+        <div className="w-[250px]">
+          <Input
+            value={notifier?.matrixNotifier?.roomId || ''}
+            onChange={(e) => updateMatrixNotifier({ roomId: e.target.value.trim() })}
+            size="small"
+            className="w-full"
+            placeholder="!roomid:matrix.org"
+          />
+        </div>
+
+        <Tooltip
+          className="cursor-pointer"
+          title="Internal room ID from room settings → Advanced, aliases like #room:matrix.org are not supported"
+        >
+          <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
+        </Tooltip>
+      </div>
+    </>
+  );
+}
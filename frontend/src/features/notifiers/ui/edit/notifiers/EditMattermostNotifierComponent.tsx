@@ -0,0 +1,92 @@
+import { InfoCircleOutlined } from '@ant-design/icons';
+import { Input, Tooltip } from 'antd';
+
+import type { MattermostNotifier, Notifier } from '../../../../../entity/notifiers';
+
+interface Props {
+  notifier: Notifier;
+  setNotifier: (notifier: Notifier) => void;
+  setIsUnsaved: (isUnsaved: boolean) => void;
+}
+
+export function EditMattermostNotifierComponent({ notifier, setNotifier, setIsUnsaved }: Props) {
+  const updateMattermostNotifier = (fields: Partial<MattermostNotifier>) => {
+    setNotifier({
+      ...notifier,
+      mattermostNotifier: {
+        ...(notifier.mattermostNotifier || { webhookUrl: '' }),
+        ...fields,
+      },
+    });
+    setIsUnsaved(true);
+  };
+
+  return (
+    <>
+      <div className="flex items-center">
+        <div className="w-[130px] min-w-[130px]">Webhook URL</div>
+
+        <div className="w-[250px]">
+          <Input
+            value={notifier?.mattermostNotifier?.webhookUrl || ''}
+            onChange={(e) => updateMattermostNotifier({ webhookUrl: e.target.value.trim() })}
+            size="small"
+            className="w-full"
+            placeholder={
+              notifier.id ? 'Leave empty to keep saved' : 'https://mattermost.example.com/hooks/xxx'
+            }
+          />
+        </div>
+
+        <Tooltip
+          className="cursor-pointer"
+          title="Integrations → Incoming Webhooks → Add Incoming Webhook in Mattermost"
+        >
+          <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
+        </Tooltip>
+      </div>
+
+      <div className="mt-1 flex items-center">
+        <div className="w-[130px] min-w-[130px]">Channel</div>
+
+        <div className="w-[250px]">
+          <Input
+            value={notifier?.mattermostNotifier?.channel || ''}
+            onChange={(e) => updateMattermostNotifier({ channel: e.target.value.trim() })}
+            size="small"
+            className="w-full"
+            placeholder="Channel of webhook"
+          />
+        </div>
+
+        <Tooltip
+          className="cursor-pointer"
+          title="Optional. Name of the channel like town-square, webhook should not be locked to its channel"
+        >
+          <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
+        </Tooltip>
+      </div>
+
+      <div className="mt-1 flex items-center">
+        <div className="w-[130px] min-w-[130px]">Username</div>
+
+        <div className="w-[250px]">
+          <Input
+            value={notifier?.mattermostNotifier?.username || ''}
+            onChange={(e) => updateMattermostNotifier({ username: e.target.value.trim() })}
+            size="small"
+            className="w-full"
+            placeholder="Username of webhook"
+          />
+        </div>
+
+        <Tooltip
+          className="cursor-pointer"
+          title="Optional. Requires enabled overriding of usernames in Mattermost settings"
+        >
+          <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
+        </Tooltip>
+      </div>
+    </>
+  );
+}
@@ -0,0 +1,83 @@
+import { InfoCircleOutlined } from '@ant-design/icons';
+import { Input, Tooltip } from 'antd';
+
+import type { Notifier, NtfyNotifier } from '../../../../../entity/notifiers';
+
+interface Props {
+  notifier: Notifier;
+  setNotifier: (notifier: Notifier) => void;
+  setIsUnsaved: (isUnsaved: boolean) => void;
+}
+
+export function EditNtfyNotifierComponent({ notifier, setNotifier, setIsUnsaved }: Props) {
+  const updateNtfyNotifier = (fields: Partial<NtfyNotifier>) => {
+    setNotifier({
+      ...notifier,
+      ntfyNotifier: {
+        ...(notifier.ntfyNotifier || { serverUrl: '', topic: '' }),
+        ...fields,
+      },
+    });
+    setIsUnsaved(true);
+  };
+
+  return (
+    <>
+      <div className="flex items-center">
+        <div className="w-[130px] min-w-[130px]">Server URL</div>
+
+        <div className="w-[250px]">
+          <Input
+            value={notifier?.ntfyNotifier?.serverUrl || ''}
+            onChange={(e) => updateNtfyNotifier({ serverUrl: e.target.value.trim() })}
+            size="small"
+            className="w-full"
+            placeholder="https://ntfy.sh"
+          />
+        </div>
+      </div>
+
+      <div className="mt-1 flex items-center">
+        <div className="w-[130px] min-w-[130px]">Topic</div>
+
+        <div className="w-[250px]">
+          <Input
+            value={notifier?.ntfyNotifier?.topic || ''}
+            onChange={(e) => updateNtfyNotifier({ topic: e.target.value.trim() })}
+            size="small"
+            className="w-full"
+            placeholder="postgresus-backups"
+          />
+        </div>
+
+        <Tooltip
+          className="cursor-pointer"
+          title="Letters, digits, - and _. Topics of public servers are readable by anyone who knows the name, so use a hard to guess one"
+        >
+          <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
+        </Tooltip>
+      </div>
+
+      <div className="mt-1 flex items-center">
+        <div className="w-[130px] min-w-[130px]">Access token</div>
+
+        <div className="w-[250px]">
+          <Input.Password
+            value={notifier?.ntfyNotifier?.accessToken || ''}
+            onChange={(e) => updateNtfyNotifier({ accessToken: e.target.value.trim() })}
+            size="small"
+            className="w-full"
+            placeholder={notifier.id ? 'Leave empty to keep saved' : 'tk_... (optional)'}
+          />
+        </div>
+
+        <Tooltip
+          className="cursor-pointer"
+          title="Optional. Required only if the topic is protected by access control of the server"
+        >
+          <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
+        </Tooltip>
+      </div>
+    </>
+  );
+}
@@ -0,0 +1,72 @@
+import { InfoCircleOutlined } from '@ant-design/icons';
+import { Input, Select, Tooltip } from 'antd';
+
+import { type Notifier, OpsgenieRegion } from '../../../../../entity/notifiers';
+
+interface Props {
+  notifier: Notifier;
+  setNotifier: (notifier: Notifier) => void;
+  setIsUnsaved: (isUnsaved: boolean) => void;
+}
+
+export function EditOpsgenieNotifierComponent({ notifier, setNotifier, setIsUnsaved }: Props) {
+  return (
+    <>
+      <div className="flex items-center">
+        <div className="w-[130px] min-w-[130px]">API key</div>
+
+        <div className="w-[250px]">
+          <Input.Password
+            value={notifier?.opsgenieNotifier?.apiKey || ''}
+            onChange={(e) => {
+              setNotifier({
+                ...notifier,
+                opsgenieNotifier: {
+                  ...(notifier.opsgenieNotifier || { region: OpsgenieRegion.US }),
+                  apiKey: e.target.value.trim(),
+                },
+              });
+              setIsUnsaved(true);
+            }}
+            size="small"
+            className="w-full"
+            placeholder={notifier.id ? 'Leave empty to keep saved' : 'API integration key'}
+          />
+        </div>
+
+        <Tooltip
+          className="cursor-pointer"
+          title="Key of API integration of Opsgenie team. Unavailable database creates an alert, available again database closes it"
+        >
+          <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
+        </Tooltip>
+      </div>
+
+      <div className="mt-1 flex items-center">
+        <div className="w-[130px] min-w-[130px]">Region</div>
+
+        <div className="w-[250px]">
+          <Select
+            value={notifier?.opsgenieNotifier?.region || OpsgenieRegion.US}
+            onChange={(value) => {
+              setNotifier({
+                ...notifier,
+                opsgenieNotifier: {
+                  ...(notifier.opsgenieNotifier || { apiKey: '' }),
+                  region: value,
+                },
+              });
+              setIsUnsaved(true);
+            }}
+            size="small"
+            className="w-full"
+            options={[
+              { value: OpsgenieRegion.US, label: 'US (api.opsgenie.com)' },
+              { value: OpsgenieRegion.EU, label: 'EU (api.eu.opsgenie.com)' },
+            ]}
+          />
+        </div>
+      </div>
+    </>
+  );
+}
@@ -0,0 +1,46 @@
+import { InfoCircleOutlined } from '@ant-design/icons';
+import { Input, Tooltip } from 'antd';
+
+import type { Notifier } from '../../../../../entity/notifiers';
+
+interface Props {
+  notifier: Notifier;
+  setNotifier: (notifier: Notifier) => void;
+  setIsUnsaved: (isUnsaved: boolean) => void;
+}
+
+export function EditPagerDutyNotifierComponent({ notifier, setNotifier, setIsUnsaved }: Props) {
+  return (
+    <div className="flex items-center">
+      <div className="w-[130px] min-w-[130px]">Integration key</div>
+
+      <div className="w-[250px]">
+        <Input.Password
+          value={notifier?.pagerDutyNotifier?.routingKey || ''}
+          onChange={(e) => {
+            setNotifier({
+              ...notifier,
+              pagerDutyNotifier: {
+                ...(notifier.pagerDutyNotifier || {}),
+                routingKey: e.target.value.trim(),
+              },
+            });
+            setIsUnsaved(true);
+          }}
+          size="small"
+          className="w-full"
+          placeholder={
+            notifier.id ? 'Leave empty to keep saved' : 'R0123456789ABCDEF0123456789ABCDE'
+          }
+        />
+      </div>
+
+      <Tooltip
+        className="cursor-pointer"
+        title="Integration key of Events API v2 integration of PagerDuty service. Unavailable database triggers an incident, available again database resolves it"
+      >
+        <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
+      </Tooltip>
+    </div>
+  );
+}
@@ -0,0 +1,43 @@
+import { InfoCircleOutlined } from '@ant-design/icons';
+import { Input, Tooltip } from 'antd';
+
+import type { Notifier } from '../../../../../entity/notifiers';
+
+interface Props {
+  notifier: Notifier;
+  setNotifier: (notifier: Notifier) => void;
+  setIsUnsaved: (isUnsaved: boolean) => void;
+}
+
+export function EditRocketChatNotifierComponent({ notifier, setNotifier, setIsUnsaved }: Props) {
+  return (
+    <div className="flex items-center">
+      <div className="w-[130px] min-w-[130px]">Webhook URL</div>
+
+      <div className="w-[250px]">
+        <Input
+          value={notifier?.rocketChatNotifier?.webhookUrl || ''}
+          onChange={(e) => {
+            setNotifier({
+              ...notifier,
+              rocketChatNotifier: { webhookUrl: e.target.value.trim() },
+            });
+            setIsUnsaved(true);
+          }}
+          size="small"
+          className="w-full"
+          placeholder={
+            notifier.id ? 'Leave empty to keep saved' : 'https://chat.example.com/hooks/xxx/yyy'
+          }
+        />
+      </div>
+
+      <Tooltip
+        className="cursor-pointer"
+        title="Administration → Workspace → Integrations → New → Incoming in Rocket.Chat. Enable the integration and choose the channel to post to"
+      >
+        <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
+      </Tooltip>
+    </div>
+  );
+}
@@ -1,7 +1,7 @@
 import { InfoCircleOutlined } from '@ant-design/icons';
-import { Input, Select, Tooltip } from 'antd';
+import { Button, Input, InputNumber, Select, Tooltip } from 'antd';
 
-import type { Notifier } from '../../../../../entity/notifiers';
+import type { Notifier, WebhookNotifier } from '../../../../../entity/notifiers';
 import { WebhookMethod } from '../../../../../entity/notifiers/models/webhook/WebhookMethod';
 
 interface Props {
@@ -11,6 +11,19 @@ interface Props {
 }
 
 export function EditWebhookNotifierComponent({ notifier, setNotifier, setIsUnsaved }: Props) {
+  const headers = notifier?.webhookNotifier?.headers || [];
+
+  const updateWebhookNotifier = (changes: Partial<WebhookNotifier>) => {
+    setNotifier({
+      ...notifier,
+      webhookNotifier: {
+        ...(notifier.webhookNotifier || { webhookUrl: '', webhookMethod: WebhookMethod.POST }),
+        ...changes,
+      },
+    });
+    setIsUnsaved(true);
+  };
+
   return (
     <>
       <div className="flex items-center">
@@ -48,6 +61,8 @@ export function EditWebhookNotifierComponent({ notifier, setNotifier, setIsUnsav
                 webhookNotifier: {
                   ...(notifier.webhookNotifier || { webhookUrl: '' }),
                   webhookMethod: value,
+                  // only POST requests are signed
+                  ...(value === WebhookMethod.GET ? { signingSecret: '' } : {}),
                 },
               });
               setIsUnsaved(true);
@@ -69,6 +84,124 @@ export function EditWebhookNotifierComponent({ notifier, setNotifier, setIsUnsav
         </Tooltip>
       </div>
 
+      <div className="mt-1 flex">
+        <div className="w-[130px] min-w-[130px]">Headers</div>
+
+        <div className="w-[250px]">
+          {headers.map((header, index) => (
+            <div key={index} className="mb-1 flex items-center">
+              <Input
+                value={header.name}
+                onChange={(e) =>
+                  updateWebhookNotifier({
+                    headers: headers.map((h, i) =>
+                      i === index ? { ...h, name: e.target.value.trim() } : h,
+                    ),
+                  })
+                }
+                size="small"
+                className="mr-1"
+                placeholder="Authorization"
+              />
+
+              <Input.Password
+                value={header.value}
+                onChange={(e) =>
+                  updateWebhookNotifier({
+                    headers: headers.map((h, i) =>
+                      i === index ? { ...h, value: e.target.value } : h,
+                    ),
+                  })
+                }
+                size="small"
+                className="mr-1"
+                placeholder={notifier.id ? 'Keep saved' : 'Bearer token'}
+              />
+
+              <Button
+                size="small"
+                danger
+                type="text"
+                onClick={() =>
+                  updateWebhookNotifier({ headers: headers.filter((_, i) => i !== index) })
+                }
+              >
+                ✕
+              </Button>
+            </div>
+          ))}
+
+          <Button
+            size="small"
+            onClick={() =>
+              updateWebhookNotifier({ headers: [...headers, { name: '', value: '' }] })
+            }
+          >
+            Add header
+          </Button>
+        </div>
+      </div>
+
+      {notifier?.webhookNotifier?.webhookMethod !== WebhookMethod.GET && (
+        <div className="mt-1 flex">
+          <div className="w-[130px] min-w-[130px]">Body template</div>
+
+          <div className="w-[250px]">
+            <Input.TextArea
+              value={notifier?.webhookNotifier?.bodyTemplate || ''}
+              onChange={(e) => updateWebhookNotifier({ bodyTemplate: e.target.value })}
+              size="small"
+              className="w-full"
+              autoSize={{ minRows: 2, maxRows: 8 }}
+              placeholder='{"text": {{json .Title}}}'
+            />
+          </div>
+
+          <Tooltip
+            className="cursor-pointer"
+            title="Go template of the request body, the default JSON is sent if empty. Use {{json .Message}} to quote values. Variables are the same as in message templates"
+          >
+            <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
+          </Tooltip>
+        </div>
+      )}
+
+      <div className="mt-1 flex items-center">
+        <div className="w-[130px] min-w-[130px]">Timeout (sec)</div>
+
+        <InputNumber
+          value={notifier?.webhookNotifier?.timeoutSeconds || 10}
+          onChange={(value) => updateWebhookNotifier({ timeoutSeconds: value || 10 })}
+          min={1}
+          max={60}
+          size="small"
+          className="w-[80px]"
+        />
+      </div>
+
+      {notifier?.webhookNotifier?.webhookMethod !== WebhookMethod.GET && (
+        <div className="mt-1 flex items-center">
+          <div className="w-[130px] min-w-[130px]">Signing secret</div>
+
+          <div className="w-[250px]">
+            <Input.Password
+              value={notifier?.webhookNotifier?.signingSecret || ''}
+              onChange={(e) => updateWebhookNotifier({ signingSecret: e.target.value })}
+              size="small"
+              className="w-full"
+              placeholder={notifier.id ? 'Leave empty to keep saved' : 'Optional'}
+            />
+          </div>
+
+          <Tooltip
+            className="cursor-pointer"
+            title="If set, requests have X-Postgresus-Timestamp header and X-Postgresus-Signature header with sha256=HMAC-SHA256 of timestamp, dot and body"
+          >
+            <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
+          </Tooltip>
+        </div>
+      )}
+
       {notifier?.webhookNotifier?.webhookUrl && (
         <div className="mt-3">
           <div className="mb-1">Example request</div>
@@ -76,8 +209,8 @@ export function EditWebhookNotifierComponent({ notifier, setNotifier, setIsUnsav
           {notifier?.webhookNotifier?.webhookMethod === WebhookMethod.GET && (
             <div className="rounded bg-gray-100 p-2 px-3 text-sm break-all">
               GET {notifier?.webhookNotifier?.webhookUrl}?heading=✅ Backup completed for
-              database&message=Backup completed successfully in 2m 17s.\nCompressed backup size:
-              1.7GB
+              database&message=Backup completed successfully\n\nDatabase: main\nSize: 1.70
+              GB\nDuration: 2m 17s&type=BACKUP_SUCCESS&severity=SUCCESS
             </div>
           )}
 
@@ -87,8 +220,20 @@ export function EditWebhookNotifierComponent({ notifier, setNotifier, setIsUnsav
 Content-Type: application/json
 
 {
-  "heading": "✅ Backup completed for database",
-  "message": "Backup completed successfully in 2m 17s.\\nCompressed backup size: 1.7GB"
+  "heading": "✅ Backup completed for database \\"main\\"",
+  "message": "Backup completed successfully\\n\\nDatabase: main\\nSize: 1.70 GB\\nDuration: 2m 17s",
+  "event": {
+    "type": "BACKUP_SUCCESS",
+    "severity": "SUCCESS",
+    "title": "✅ Backup completed for database \\"main\\"",
+    "message": "Backup completed successfully",
+    "databaseId": "4f5e8a0c-...",
+    "databaseName": "main",
+    "backupId": "9b1d2c3e-...",
+    "sizeMb": 1740.8,
+    "durationMs": 137000,
+    "createdAt": "2025-01-01T00:00:00Z"
+  }
 }
 `}
             </div>
@@ -0,0 +1,206 @@
+import { InfoCircleOutlined } from '@ant-design/icons';
+import { Button, Input, Select, Switch, TimePicker, Tooltip } from 'antd';
+import dayjs from 'dayjs';
+import { useMemo, useState } from 'react';
+
+import type { Database } from '../../../../entity/databases';
+import {
+  NotificationEventType,
+  type NotificationRule,
+  NotificationSeverity,
+  type Notifier,
+  getNotificationEventTypeName,
+  notificationRuleApi,
+} from '../../../../entity/notifiers';
+import { getUserTimeFormat } from '../../../../shared/time/utils';
+
+interface Props {
+  rule?: NotificationRule;
+  notifiers: Notifier[];
+  databases: Database[];
+
+  onSaved: () => void;
+  onCancel: () => void;
+}
+
+export function EditNotificationRuleComponent({
+  rule,
+  notifiers,
+  databases,
+  onSaved,
+  onCancel,
+}: Props) {
+  const [editingRule, setEditingRule] = useState<NotificationRule>(
+    rule ?? {
+      id: undefined as unknown as string,
+      name: '',
+      eventTypes: [],
+      minSeverity: '',
+      databaseIds: [],
+      notifierIds: [],
+      isQuietHoursEnabled: false,
+      quietHoursFrom: '22:00',
+      quietHoursTo: '07:00',
+    },
+  );
+  const [isSaving, setIsSaving] = useState(false);
+
+  const timeFormat = useMemo(() => {
+    const is12 = getUserTimeFormat();
+    return { use12Hours: is12, format: is12 ? 'h:mm A' : 'HH:mm' };
+  }, []);
+
+  const updateRule = (patch: Partial<NotificationRule>) => {
+    setEditingRule({ ...editingRule, ...patch });
+  };
+
+  const saveRule = async () => {
+    setIsSaving(true);
+
+    try {
+      await notificationRuleApi.saveNotificationRule(editingRule);
+      onSaved();
+    } catch (e) {
+      alert((e as Error).message);
+    }
+
+    setIsSaving(false);
+  };
+
+  const isAllDataFilled = editingRule.name && editingRule.notifierIds.length > 0;
+
+  return (
+    <div>
+      <div className="mb-1 flex w-full items-center">
+        <div className="min-w-[150px]">Name</div>
+        <Input
+          value={editingRule.name}
+          onChange={(e) => updateRule({ name: e.target.value })}
+          size="small"
+          className="max-w-[250px] grow"
+          placeholder="Backup failures to on-call"
+        />
+      </div>
+
+      <div className="mb-1 flex w-full items-center">
+        <div className="min-w-[150px]">Notifiers</div>
+        <Select
+          mode="multiple"
+          value={editingRule.notifierIds}
+          onChange={(notifierIds) => updateRule({ notifierIds })}
+          options={notifiers.map((n) => ({ label: n.name, value: n.id }))}
+          size="small"
+          className="max-w-[250px] grow"
+          placeholder="Select notifiers"
+        />
+      </div>
+
+      <div className="mb-1 flex w-full items-center">
+        <div className="min-w-[150px]">Events</div>
+        <Select
+          mode="multiple"
+          value={editingRule.eventTypes}
+          onChange={(eventTypes) => updateRule({ eventTypes })}
+          options={Object.values(NotificationEventType)
+            .filter(
+              (eventType) =>
+                eventType !== NotificationEventType.TEST &&
+                eventType !== NotificationEventType.BACKUP_DIGEST,
+            )
+            .map((eventType) => ({
+              label: getNotificationEventTypeName(eventType),
+              value: eventType,
+            }))}
+          size="small"
+          className="max-w-[250px] grow"
+          placeholder="Any event"
+        />
+      </div>
+
+      <div className="mb-1 flex w-full items-center">
+        <div className="min-w-[150px]">Min. severity</div>
+        <Select
+          value={editingRule.minSeverity}
+          onChange={(minSeverity) => updateRule({ minSeverity })}
+          options={[
+            { label: 'Any severity', value: '' },
+            { label: 'Warning', value: NotificationSeverity.WARNING },
+            { label: 'Error', value: NotificationSeverity.ERROR },
+            { label: 'Critical', value: NotificationSeverity.CRITICAL },
+          ]}
+          size="small"
+          className="max-w-[250px] grow"
+        />
+      </div>
+
+      <div className="mb-1 flex w-full items-center">
+        <div className="min-w-[150px]">Databases</div>
+        <Select
+          mode="multiple"
+          value={editingRule.databaseIds}
+          onChange={(databaseIds) => updateRule({ databaseIds })}
+          options={databases.map((d) => ({ label: d.name, value: d.id }))}
+          size="small"
+          className="max-w-[250px] grow"
+          placeholder="All databases"
+        />
+      </div>
+
+      <div className="mb-1 flex w-full items-center">
+        <div className="min-w-[150px]">Quiet hours</div>
+        <Switch
+          checked={editingRule.isQuietHoursEnabled}
+          onChange={(isQuietHoursEnabled) => updateRule({ isQuietHoursEnabled })}
+          size="small"
+        />
+
+        <Tooltip
+          className="cursor-pointer"
+          title="Only critical events, e.g. unavailable database, are sent in quiet hours"
+        >
+          <InfoCircleOutlined className="ml-2" style={{ color: 'gray' }} />
+        </Tooltip>
+      </div>
+
+      {editingRule.isQuietHoursEnabled && (
+        <div className="mb-1 flex w-full items-center">
+          <div className="min-w-[150px]" />
+          <TimePicker
+            value={dayjs.utc(editingRule.quietHoursFrom, 'HH:mm').local()}
+            format={timeFormat.format}
+            use12Hours={timeFormat.use12Hours}
+            allowClear={false}
+            size="small"
+            className="max-w-[120px]"
+            onChange={(t) => t && updateRule({ quietHoursFrom: t.utc().format('HH:mm') })}
+          />
+          <div className="mx-2">to</div>
+          <TimePicker
+            value={dayjs.utc(editingRule.quietHoursTo, 'HH:mm').local()}
+            format={timeFormat.format}
+            use12Hours={timeFormat.use12Hours}
+            allowClear={false}
+            size="small"
+            className="max-w-[120px]"
+            onChange={(t) => t && updateRule({ quietHoursTo: t.utc().format('HH:mm') })}
+          />
+        </div>
+      )}
+
+      <div className="mt-5 flex">
+        <Button className="mr-1" danger ghost onClick={() => onCancel()}>
+          Cancel
+        </Button>
+
+        <Button
+          type="primary"
+          loading={isSaving}
+          disabled={!isAllDataFilled}
+          onClick={() => saveRule()}
+        >
+          Save
+        </Button>
+      </div>
+    </div>
+  );
+}
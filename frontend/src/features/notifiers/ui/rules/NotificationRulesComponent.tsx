@@ -0,0 +1,145 @@
+import { Button, Spin } from 'antd';
+import dayjs from 'dayjs';
+import { useEffect, useState } from 'react';
+
+import { type Database, databaseApi } from '../../../../entity/databases';
+import {
+  type NotificationRule,
+  type Notifier,
+  getNotificationEventTypeName,
+  notificationRuleApi,
+} from '../../../../entity/notifiers';
+import { ConfirmationComponent } from '../../../../shared/ui';
+import { EditNotificationRuleComponent } from './EditNotificationRuleComponent';
+
+interface Props {
+  notifiers: Notifier[];
+}
+
+export function NotificationRulesComponent({ notifiers }: Props) {
+  const [isLoading, setIsLoading] = useState(true);
+  const [rules, setRules] = useState<NotificationRule[]>([]);
+  const [databases, setDatabases] = useState<Database[]>([]);
+
+  const [editingRule, setEditingRule] = useState<NotificationRule | undefined>();
+  const [isAddingRule, setIsAddingRule] = useState(false);
+  const [removingRule, setRemovingRule] = useState<NotificationRule | undefined>();
+
+  const loadRules = () => {
+    setIsLoading(true);
+
+    Promise.all([notificationRuleApi.getNotificationRules(), databaseApi.getDatabases()])
+      .then(([rules, databases]) => {
+        setRules(rules);
+        setDatabases(databases);
+      })
+      .catch((e) => alert(e.message))
+      .finally(() => setIsLoading(false));
+  };
+
+  const removeRule = async (rule: NotificationRule) => {
+    setRemovingRule(undefined);
+
+    try {
+      await notificationRuleApi.deleteNotificationRule(rule.id);
+      loadRules();
+    } catch (e) {
+      alert((e as Error).message);
+    }
+  };
+
+  useEffect(() => {
+    loadRules();
+  }, []);
+
+  if (isLoading) {
+    return (
+      <div className="my-3 flex justify-center">
+        <Spin />
+      </div>
+    );
+  }
+
+  if (isAddingRule || editingRule) {
+    return (
+      <EditNotificationRuleComponent
+        rule={editingRule}
+        notifiers={notifiers}
+        databases={databases}
+        onSaved={() => {
+          setIsAddingRule(false);
+          setEditingRule(undefined);
+          loadRules();
+        }}
+        onCancel={() => {
+          setIsAddingRule(false);
+          setEditingRule(undefined);
+        }}
+      />
+    );
+  }
+
+  const getNames = <T extends { id: string; name: string }>(items: T[], ids: string[]) =>
+    items
+      .filter((item) => ids.includes(item.id))
+      .map((item) => item.name)
+      .join(', ');
+
+  return (
+    <div>
+      <div className="mb-3 text-gray-500">
+        Rules decide which notifiers receive which events. Notifiers without rules receive all
+        events of their databases.
+      </div>
+
+      {rules.map((rule) => (
+        <div key={rule.id} className="mb-2 rounded border border-gray-200 p-2 text-sm">
+          <div className="font-bold">{rule.name}</div>
+
+          <div>To: {getNames(notifiers, rule.notifierIds)}</div>
+          <div>
+            Events:{' '}
+            {rule.eventTypes.length > 0
+              ? rule.eventTypes.map((t) => getNotificationEventTypeName(t)).join(', ')
+              : 'any'}
+            {rule.minSeverity && `, ${rule.minSeverity.toLowerCase()} and above`}
+          </div>
+          <div>
+            Databases:{' '}
+            {rule.databaseIds.length > 0 ? getNames(databases, rule.databaseIds) : 'all'}
+          </div>
+          {rule.isQuietHoursEnabled && (
+            <div>
+              Quiet hours: {dayjs.utc(rule.quietHoursFrom, 'HH:mm').local().format('HH:mm')} -{' '}
+              {dayjs.utc(rule.quietHoursTo, 'HH:mm').local().format('HH:mm')}
+            </div>
+          )}
+
+          <div className="mt-1 flex">
+            <Button className="mr-1" size="small" onClick={() => setEditingRule(rule)}>
+              Edit
+            </Button>
+
+            <Button size="small" danger ghost onClick={() => setRemovingRule(rule)}>
+              Remove
+            </Button>
+          </div>
+        </div>
+      ))}
+
+      <Button type="primary" onClick={() => setIsAddingRule(true)}>
+        Add rule
+      </Button>
+
+      {removingRule && (
+        <ConfirmationComponent
+          onConfirm={() => removeRule(removingRule)}
+          onDecline={() => setRemovingRule(undefined)}
+          description={`Are you sure you want to remove rule "${removingRule.name}"?`}
+          actionText="Remove"
+          actionButtonColor="red"
+        />
+      )}
+    </div>
+  );
+}
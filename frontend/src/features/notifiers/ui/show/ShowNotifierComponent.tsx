@@ -1,8 +1,18 @@
-import { type Notifier, NotifierType } from '../../../../entity/notifiers';
+import dayjs from 'dayjs';
+
+import { DigestFrequency, type Notifier, NotifierType } from '../../../../entity/notifiers';
 import { getNotifierLogoFromType } from '../../../../entity/notifiers/models/getNotifierLogoFromType';
 import { getNotifierNameFromType } from '../../../../entity/notifiers/models/getNotifierNameFromType';
+import { getLocalWeekday } from '../../../../shared/time/utils';
 import { ShowDiscordNotifierComponent } from './notifier/ShowDiscordNotifierComponent';
 import { ShowEmailNotifierComponent } from './notifier/ShowEmailNotifierComponent';
+import { ShowGoogleChatNotifierComponent } from './notifier/ShowGoogleChatNotifierComponent';
+import { ShowMatrixNotifierComponent } from './notifier/ShowMatrixNotifierComponent';
+import { ShowMattermostNotifierComponent } from './notifier/ShowMattermostNotifierComponent';
+import { ShowNtfyNotifierComponent } from './notifier/ShowNtfyNotifierComponent';
+import { ShowOpsgenieNotifierComponent } from './notifier/ShowOpsgenieNotifierComponent';
+import { ShowPagerDutyNotifierComponent } from './notifier/ShowPagerDutyNotifierComponent';
+import { ShowRocketChatNotifierComponent } from './notifier/ShowRocketChatNotifierComponent';
 import { ShowSlackNotifierComponent } from './notifier/ShowSlackNotifierComponent';
 import { ShowTeamsNotifierComponent } from './notifier/ShowTeamsNotifierComponent';
 import { ShowTelegramNotifierComponent } from './notifier/ShowTelegramNotifierComponent';
@@ -12,7 +22,21 @@ interface Props {
   notifier: Notifier;
 }
 
+const weekdayNames = ['Mon', 'Tue', 'Wed', 'Thu', 'Fri', 'Sat', 'Sun'];
+
 export function ShowNotifierComponent({ notifier }: Props) {
+  const getDigestText = () => {
+    const timeOfDay = notifier.digestTimeOfDay || '00:00';
+    const localTime = dayjs.utc(timeOfDay, 'HH:mm').local().format('HH:mm');
+
+    if (notifier.digestFrequency === DigestFrequency.DAILY) {
+      return `Daily at ${localTime}`;
+    }
+
+    const weekday = getLocalWeekday(notifier.digestWeekday || 1, timeOfDay);
+    return `Weekly on ${weekdayNames[weekday - 1]} at ${localTime}`;
+  };
+
   return (
     <div>
       <div className="mb-1 flex items-center">
@@ -46,7 +70,36 @@ export function ShowNotifierComponent({ notifier }: Props) {
         {notifier?.notifierType === NotifierType.TEAMS && (
           <ShowTeamsNotifierComponent notifier={notifier} />
         )}
+
+        {notifier?.notifierType === NotifierType.PAGERDUTY && <ShowPagerDutyNotifierComponent />}
+
+        {notifier?.notifierType === NotifierType.OPSGENIE && (
+          <ShowOpsgenieNotifierComponent notifier={notifier} />
+        )}
+
+        {notifier?.notifierType === NotifierType.MATTERMOST && (
+          <ShowMattermostNotifierComponent notifier={notifier} />
+        )}
+
+        {notifier?.notifierType === NotifierType.ROCKET_CHAT && <ShowRocketChatNotifierComponent />}
+
+        {notifier?.notifierType === NotifierType.GOOGLE_CHAT && <ShowGoogleChatNotifierComponent />}
+
+        {notifier?.notifierType === NotifierType.MATRIX && (
+          <ShowMatrixNotifierComponent notifier={notifier} />
+        )}
+
+        {notifier?.notifierType === NotifierType.NTFY && (
+          <ShowNtfyNotifierComponent notifier={notifier} />
+        )}
       </div>
+
+      {notifier.digestFrequency && notifier.digestFrequency !== DigestFrequency.NONE && (
+        <div className="mb-1 flex items-center">
+          <div className="min-w-[110px]">Backup digest</div>
+          {getDigestText()}
+        </div>
+      )}
     </div>
   );
 }
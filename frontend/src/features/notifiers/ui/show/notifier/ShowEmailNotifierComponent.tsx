@@ -1,17 +1,33 @@
-import type { Notifier } from '../../../../../entity/notifiers';
+import { EmailAuthType, EmailSMTPSecurity, type Notifier } from '../../../../../entity/notifiers';
 
 interface Props {
   notifier: Notifier;
 }
 
+const securityNames: Record<EmailSMTPSecurity, string> = {
+  [EmailSMTPSecurity.AUTO]: 'Auto',
+  [EmailSMTPSecurity.STARTTLS]: 'STARTTLS',
+  [EmailSMTPSecurity.TLS]: 'Implicit TLS',
+  [EmailSMTPSecurity.NONE]: 'None',
+};
+
 export function ShowEmailNotifierComponent({ notifier }: Props) {
+  const isOAuth2 = notifier?.emailNotifier?.smtpAuthType === EmailAuthType.OAUTH2;
+
   return (
     <>
       <div className="mb-1 flex items-center">
-        <div className="min-w-[110px]">Target email</div>
+        <div className="min-w-[110px]">Target emails</div>
         {notifier?.emailNotifier?.targetEmail}
       </div>
 
+      {notifier?.emailNotifier?.ccEmails && (
+        <div className="mb-1 flex items-center">
+          <div className="min-w-[110px]">CC emails</div>
+          {notifier.emailNotifier.ccEmails}
+        </div>
+      )}
+
       <div className="mb-1 flex items-center">
         <div className="min-w-[110px]">SMTP host</div>
         {notifier?.emailNotifier?.smtpHost}
@@ -23,14 +39,33 @@ export function ShowEmailNotifierComponent({ notifier }: Props) {
       </div>
 
       <div className="mb-1 flex items-center">
-        <div className="min-w-[110px]">SMTP user</div>
-        {notifier?.emailNotifier?.smtpUser}
+        <div className="min-w-[110px]">Security</div>
+        {securityNames[notifier?.emailNotifier?.smtpSecurity || EmailSMTPSecurity.AUTO]}
       </div>
 
       <div className="mb-1 flex items-center">
-        <div className="min-w-[110px]">SMTP password</div>
-        {notifier?.emailNotifier?.smtpPassword ? '*********' : ''}
+        <div className="min-w-[110px]">SMTP user</div>
+        {notifier?.emailNotifier?.smtpUser}
       </div>
+
+      {isOAuth2 ? (
+        <div className="mb-1 flex items-center">
+          <div className="min-w-[110px]">Authentication</div>
+          OAuth2 ({notifier?.emailNotifier?.oauth2Provider?.toLowerCase()})
+        </div>
+      ) : (
+        <div className="mb-1 flex items-center">
+          <div className="min-w-[110px]">SMTP password</div>
+          {notifier?.emailNotifier?.smtpPassword ? '*********' : ''}
+        </div>
+      )}
+
+      {notifier?.emailNotifier?.isBackupLogAttached && (
+        <div className="mb-1 flex items-center">
+          <div className="min-w-[110px]">Backup log</div>
+          Attached to failed backups
+        </div>
+      )}
     </>
   );
 }
@@ -0,0 +1,27 @@
+import type { Notifier } from '../../../../../entity/notifiers';
+
+interface Props {
+  notifier: Notifier;
+}
+
+export function ShowMatrixNotifierComponent({ notifier }: Props) {
+  return (
+    <>
+      <div className="flex items-center">
+        <div className="min-w-[110px]">Homeserver URL</div>
+        <div>{notifier?.matrixNotifier?.homeserverUrl || '-'}</div>
+      </div>
+
+      <div className="mt-1 flex items-center">
+        <div className="min-w-[110px]">Access token</div>
+
+        <div className="w-[250px]">*********</div>
+      </div>
+
+      <div className="mt-1 mb-1 flex items-center">
+        <div className="min-w-[110px]">Room ID</div>
+        <div>{notifier?.matrixNotifier?.roomId || '-'}</div>
+      </div>
+    </>
+  );
+}
@@ -0,0 +1,27 @@
+import type { Notifier } from '../../../../../entity/notifiers';
+
+interface Props {
+  notifier: Notifier;
+}
+
+export function ShowMattermostNotifierComponent({ notifier }: Props) {
+  return (
+    <>
+      <div className="flex items-center">
+        <div className="min-w-[110px]">Webhook URL</div>
+
+        <div className="w-[250px]">*********</div>
+      </div>
+
+      <div className="mt-1 flex items-center">
+        <div className="min-w-[110px]">Channel</div>
+        <div>{notifier?.mattermostNotifier?.channel || 'Channel of webhook'}</div>
+      </div>
+
+      <div className="mt-1 mb-1 flex items-center">
+        <div className="min-w-[110px]">Username</div>
+        <div>{notifier?.mattermostNotifier?.username || 'Username of webhook'}</div>
+      </div>
+    </>
+  );
+}
@@ -0,0 +1,21 @@
+import type { Notifier } from '../../../../../entity/notifiers';
+
+interface Props {
+  notifier: Notifier;
+}
+
+export function ShowNtfyNotifierComponent({ notifier }: Props) {
+  return (
+    <>
+      <div className="flex items-center">
+        <div className="min-w-[110px]">Server URL</div>
+        <div>{notifier?.ntfyNotifier?.serverUrl || '-'}</div>
+      </div>
+
+      <div className="mt-1 mb-1 flex items-center">
+        <div className="min-w-[110px]">Topic</div>
+        <div>{notifier?.ntfyNotifier?.topic || '-'}</div>
+      </div>
+    </>
+  );
+}
@@ -0,0 +1,22 @@
+import type { Notifier } from '../../../../../entity/notifiers';
+
+interface Props {
+  notifier: Notifier;
+}
+
+export function ShowOpsgenieNotifierComponent({ notifier }: Props) {
+  return (
+    <>
+      <div className="flex items-center">
+        <div className="min-w-[110px]">API key</div>
+
+        <div className="w-[250px]">*********</div>
+      </div>
+
+      <div className="mt-1 mb-1 flex items-center">
+        <div className="min-w-[110px]">Region</div>
+        <div>{notifier?.opsgenieNotifier?.region || '-'}</div>
+      </div>
+    </>
+  );
+}
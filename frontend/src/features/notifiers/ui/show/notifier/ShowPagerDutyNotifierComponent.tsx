@@ -0,0 +1,9 @@
+export function ShowPagerDutyNotifierComponent() {
+  return (
+    <div className="flex items-center">
+      <div className="min-w-[110px]">Integration key</div>
+
+      <div className="w-[250px]">*********</div>
+    </div>
+  );
+}
@@ -0,0 +1,9 @@
+export function ShowRocketChatNotifierComponent() {
+  return (
+    <div className="flex items-center">
+      <div className="min-w-[110px]">Webhook URL</div>
+
+      <div className="w-[250px]">*********</div>
+    </div>
+  );
+}
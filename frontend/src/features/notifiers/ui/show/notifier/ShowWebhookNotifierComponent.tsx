@@ -17,6 +17,13 @@ export function ShowWebhookNotifierComponent({ notifier }: Props) {
         <div className="min-w-[110px]">Method</div>
         <div>{notifier?.webhookNotifier?.webhookMethod || '-'}</div>
       </div>
+
+      {!!notifier?.webhookNotifier?.headers?.length && (
+        <div className="mb-1 flex items-center">
+          <div className="min-w-[110px]">Headers</div>
+          <div>{notifier.webhookNotifier.headers.map((header) => header.name).join(', ')}</div>
+        </div>
+      )}
     </>
   );
 }
@@ -146,6 +146,9 @@ export function EditStorageComponent({
       return false;
     }
 
+    // secrets of saved storages are not returned by the API, empty means keep the saved one
+    const isExisting = !!storage.id;
+
     if (storage.type === StorageType.LOCAL) {
       return true; // No additional settings required for local storage
     }
@@ -154,15 +157,15 @@ export function EditStorageComponent({
       return (
         storage.s3Storage?.s3Bucket &&
         storage.s3Storage?.s3AccessKey &&
-        storage.s3Storage?.s3SecretKey
+        (storage.s3Storage?.s3SecretKey || isExisting)
       );
     }
 
     if (storage.type === StorageType.GOOGLE_DRIVE) {
       return (
         storage.googleDriveStorage?.clientId &&
-        storage.googleDriveStorage?.clientSecret &&
-        storage.googleDriveStorage?.tokenJson
+        (storage.googleDriveStorage?.clientSecret || isExisting) &&
+        (storage.googleDriveStorage?.tokenJson || isExisting)
       );
     }
 
@@ -172,7 +175,7 @@ export function EditStorageComponent({
         storage.nasStorage?.port &&
         storage.nasStorage?.share &&
         storage.nasStorage?.username &&
-        storage.nasStorage?.password
+        (storage.nasStorage?.password || isExisting)
       );
     }
 
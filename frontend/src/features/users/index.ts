@@ -1,3 +1,4 @@
 export { AuthNavbarComponent } from './ui/AuthNavbarComponent';
+export { OidcCallbackComponent } from './ui/OidcCallbackComponent';
 export { SignInComponent } from './ui/SignInComponent';
 export { SignUpComponent } from './ui/SignUpComponent';
@@ -0,0 +1,68 @@
+import { Button, Spin } from 'antd';
+import { useEffect, useState } from 'react';
+
+import { type SignInResponse, userApi } from '../../../entity/users';
+import { TotpSignInComponent } from './TotpSignInComponent';
+
+export function OidcCallbackComponent() {
+  const [signInError, setSignInError] = useState('');
+  const [totpSignInResponse, setTotpSignInResponse] = useState<SignInResponse | undefined>();
+
+  useEffect(() => {
+    const searchParams = new URLSearchParams(window.location.search);
+    const code = searchParams.get('code');
+    const state = searchParams.get('state');
+
+    if (!code || !state) {
+      setSignInError(searchParams.get('error_description') || 'SSO sign in was cancelled');
+      return;
+    }
+
+    // the second factor notifies listeners when it is passed
+    const onAuthorized = () => window.location.replace('/');
+    userApi.addAuthListener(onAuthorized);
+
+    userApi
+      .signInWithOidc(code, state)
+      .then((response) => {
+        if (response.totpToken) {
+          setTotpSignInResponse(response);
+          return;
+        }
+
+        window.location.replace('/');
+      })
+      .catch((e: Error) => {
+        setSignInError(e.message);
+      });
+
+    return () => userApi.removeAuthListener(onAuthorized);
+  }, []);
+
+  if (totpSignInResponse) {
+    return (
+      <div className="flex h-screen w-screen flex-col items-center justify-center">
+        <TotpSignInComponent
+          signInResponse={totpSignInResponse}
+          onCancel={() => window.location.replace('/')}
+        />
+      </div>
+    );
+  }
+
+  return (
+    <div className="flex h-screen w-screen flex-col items-center justify-center">
+      {signInError ? (
+        <>
+          <div className="mb-3 text-center text-sm text-red-600">{signInError}</div>
+
+          <Button type="primary" onClick={() => window.location.replace('/')}>
+            Back to sign in
+          </Button>
+        </>
+      ) : (
+        <Spin />
+      )}
+    </div>
+  );
+}
@@ -2,10 +2,15 @@ import { EyeInvisibleOutlined, EyeTwoTone } from '@ant-design/icons';
 import { Button, Input } from 'antd';
 import { type JSX, useState } from 'react';
 
-import { userApi } from '../../../entity/users';
+import { type OidcConfig, type SignInResponse, userApi } from '../../../entity/users';
 import { FormValidator } from '../../../shared/lib/FormValidator';
+import { TotpSignInComponent } from './TotpSignInComponent';
 
-export function SignInComponent(): JSX.Element {
+interface Props {
+  oidcConfig?: OidcConfig;
+}
+
+export function SignInComponent({ oidcConfig }: Props): JSX.Element {
   const [email, setEmail] = useState('');
   const [password, setPassword] = useState('');
   const [passwordVisible, setPasswordVisible] = useState(false);
@@ -16,6 +21,7 @@ export function SignInComponent(): JSX.Element {
   const [passwordError, setPasswordError] = useState(false);
 
   const [signInError, setSignInError] = useState('');
+  const [totpSignInResponse, setTotpSignInResponse] = useState<SignInResponse | undefined>();
 
   const validateFieldsForSignIn = (): boolean => {
     if (!email) {
@@ -37,6 +43,18 @@ export function SignInComponent(): JSX.Element {
     return true;
   };
 
+  const onSignInWithOidc = async () => {
+    setSignInError('');
+    setLoading(true);
+
+    try {
+      window.location.href = await userApi.getOidcAuthorizationUrl();
+    } catch (e) {
+      setSignInError((e as Error).message);
+      setLoading(false);
+    }
+  };
+
   const onSignIn = async () => {
     setSignInError('');
 
@@ -44,10 +62,14 @@ export function SignInComponent(): JSX.Element {
       setLoading(true);
 
       try {
-        await userApi.signIn({
+        const response = await userApi.signIn({
           email,
           password,
         });
+
+        if (response.totpToken) {
+          setTotpSignInResponse(response);
+        }
       } catch (e) {
         setSignInError((e as Error).message);
       }
@@ -56,48 +78,75 @@ export function SignInComponent(): JSX.Element {
     }
   };
 
+  if (totpSignInResponse) {
+    return (
+      <TotpSignInComponent
+        signInResponse={totpSignInResponse}
+        onCancel={() => setTotpSignInResponse(undefined)}
+      />
+    );
+  }
+
   return (
     <div className="w-full max-w-[300px]">
       <div className="mb-5 text-center text-2xl font-bold">Sign in</div>
 
-      <div className="my-1 text-xs font-semibold">Your email</div>
-      <Input
-        placeholder="your@email.com"
-        value={email}
-        onChange={(e) => {
-          setEmailError(false);
-          setEmail(e.currentTarget.value.trim().toLowerCase());
-        }}
-        status={isEmailError ? 'error' : undefined}
-        type="email"
-      />
-
-      <div className="my-1 text-xs font-semibold">Password</div>
-      <Input.Password
-        placeholder="********"
-        value={password}
-        onChange={(e) => {
-          setPasswordError(false);
-          setPassword(e.currentTarget.value);
-        }}
-        status={passwordError ? 'error' : undefined}
-        iconRender={(visible) => (visible ? <EyeTwoTone /> : <EyeInvisibleOutlined />)}
-        visibilityToggle={{ visible: passwordVisible, onVisibleChange: setPasswordVisible }}
-      />
+      {!oidcConfig?.isPasswordLoginDisabled && (
+        <>
+          <div className="my-1 text-xs font-semibold">Your email</div>
+          <Input
+            placeholder="your@email.com"
+            value={email}
+            onChange={(e) => {
+              setEmailError(false);
+              setEmail(e.currentTarget.value.trim().toLowerCase());
+            }}
+            status={isEmailError ? 'error' : undefined}
+            type="email"
+          />
+
+          <div className="my-1 text-xs font-semibold">Password</div>
+          <Input.Password
+            placeholder="********"
+            value={password}
+            onChange={(e) => {
+              setPasswordError(false);
+              setPassword(e.currentTarget.value);
+            }}
+            status={passwordError ? 'error' : undefined}
+            iconRender={(visible) => (visible ? <EyeTwoTone /> : <EyeInvisibleOutlined />)}
+            visibilityToggle={{ visible: passwordVisible, onVisibleChange: setPasswordVisible }}
+          />
+
+          <div className="mt-3" />
+
+          <Button
+            disabled={isLoading}
+            loading={isLoading}
+            className="w-full"
+            onClick={() => {
+              onSignIn();
+            }}
+            type="primary"
+          >
+            Sign in
+          </Button>
+        </>
+      )}
 
-      <div className="mt-3" />
-
-      <Button
-        disabled={isLoading}
-        loading={isLoading}
-        className="w-full"
-        onClick={() => {
-          onSignIn();
-        }}
-        type="primary"
-      >
-        Sign in
-      </Button>
+      {oidcConfig?.isOidcEnabled && (
+        <Button
+          disabled={isLoading}
+          loading={isLoading}
+          className={oidcConfig.isPasswordLoginDisabled ? 'w-full' : 'mt-3 w-full'}
+          onClick={() => {
+            onSignInWithOidc();
+          }}
+          type={oidcConfig.isPasswordLoginDisabled ? 'primary' : 'default'}
+        >
+          Sign in with {oidcConfig.providerName}
+        </Button>
+      )}
 
       {signInError && (
         <div className="mt-3 flex justify-center text-center text-sm text-red-600">
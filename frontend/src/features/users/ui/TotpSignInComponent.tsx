@@ -0,0 +1,126 @@
+import { Button, Input } from 'antd';
+import { type JSX, useEffect, useState } from 'react';
+
+import { type SignInResponse, type TotpSetup, userApi } from '../../../entity/users';
+
+interface Props {
+  signInResponse: SignInResponse;
+  onCancel: () => void;
+}
+
+export function TotpSignInComponent({ signInResponse, onCancel }: Props): JSX.Element {
+  const [code, setCode] = useState('');
+  const [totpSetup, setTotpSetup] = useState<TotpSetup | undefined>();
+  const [recoveryCodes, setRecoveryCodes] = useState<string[]>([]);
+
+  const [isLoading, setLoading] = useState(false);
+  const [error, setError] = useState('');
+
+  useEffect(() => {
+    if (!signInResponse.isTotpEnrollmentRequired || !signInResponse.totpToken) {
+      return;
+    }
+
+    userApi
+      .startTotpEnrollment(signInResponse.totpToken)
+      .then(setTotpSetup)
+      .catch((e) => setError((e as Error).message));
+  }, [signInResponse]);
+
+  const onConfirm = async () => {
+    if (!code || !signInResponse.totpToken) {
+      return;
+    }
+
+    setError('');
+    setLoading(true);
+
+    try {
+      const response = await userApi.signInWithTotp(signInResponse.totpToken, code);
+
+      if (response.recoveryCodes?.length) {
+        setRecoveryCodes(response.recoveryCodes);
+      } else {
+        userApi.notifyAuthListeners();
+      }
+    } catch (e) {
+      setError((e as Error).message);
+    }
+
+    setLoading(false);
+  };
+
+  if (recoveryCodes.length > 0) {
+    return (
+      <div className="w-full max-w-[300px]">
+        <div className="mb-3 text-center text-2xl font-bold">Recovery codes</div>
+
+        <div className="mb-3 text-sm text-gray-500">
+          Save these codes in a safe place. Each code can be used once to sign in if you lose
+          access to your authenticator app. They will not be shown again
+        </div>
+
+        <div className="mb-3 grid grid-cols-2 gap-1 rounded bg-gray-100 p-3 font-mono text-sm">
+          {recoveryCodes.map((recoveryCode) => (
+            <div key={recoveryCode}>{recoveryCode}</div>
+          ))}
+        </div>
+
+        <Button className="w-full" type="primary" onClick={() => userApi.notifyAuthListeners()}>
+          I have saved the codes
+        </Button>
+      </div>
+    );
+  }
+
+  return (
+    <div className="w-full max-w-[300px]">
+      <div className="mb-5 text-center text-2xl font-bold">Two-factor authentication</div>
+
+      {signInResponse.isTotpEnrollmentRequired && (
+        <div className="mb-3 text-sm text-gray-500">
+          Two-factor authentication is required. Add the key below to your authenticator app
+          (Google Authenticator, 1Password, etc.) and enter the code from it
+          {totpSetup && (
+            <div className="mt-2 rounded bg-gray-100 p-2 font-mono text-xs break-all text-black">
+              {totpSetup.secret}
+            </div>
+          )}
+        </div>
+      )}
+
+      <div className="my-1 text-xs font-semibold">
+        {signInResponse.isTotpEnrollmentRequired
+          ? 'Code from authenticator app'
+          : 'Code from authenticator app or recovery code'}
+      </div>
+      <Input
+        placeholder="123456"
+        value={code}
+        onChange={(e) => setCode(e.currentTarget.value.trim())}
+        onPressEnter={() => onConfirm()}
+        autoFocus
+      />
+
+      <div className="mt-3" />
+
+      <Button
+        disabled={isLoading || !code}
+        loading={isLoading}
+        className="w-full"
+        onClick={() => onConfirm()}
+        type="primary"
+      >
+        Confirm
+      </Button>
+
+      <Button className="mt-2 w-full" type="text" onClick={onCancel}>
+        Back
+      </Button>
+
+      {error && (
+        <div className="mt-3 flex justify-center text-center text-sm text-red-600">{error}</div>
+      )}
+    </div>
+  );
+}
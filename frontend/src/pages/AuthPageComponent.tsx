@@ -1,22 +1,23 @@
 import { Spin } from 'antd';
 import { useEffect, useState } from 'react';
 
-import { userApi } from '../entity/users';
+import { type OidcConfig, userApi } from '../entity/users';
 import { SignInComponent } from '../features/users';
 import { SignUpComponent } from '../features/users';
 import { AuthNavbarComponent } from '../features/users';
 
 export function AuthPageComponent() {
   const [isAnyUserExists, setIsAnyUserExists] = useState(false);
+  const [oidcConfig, setOidcConfig] = useState<OidcConfig | undefined>();
   const [isLoading, setLoading] = useState(false);
 
   useEffect(() => {
     setLoading(true);
 
-    userApi
-      .isAnyUserExists()
-      .then((isAnyUserExists) => {
+    Promise.all([userApi.isAnyUserExists(), userApi.getOidcConfig()])
+      .then(([isAnyUserExists, oidcConfig]) => {
         setIsAnyUserExists(isAnyUserExists);
+        setOidcConfig(oidcConfig);
       })
       .finally(() => {
         setLoading(false);
@@ -35,7 +36,11 @@ export function AuthPageComponent() {
             <AuthNavbarComponent />
 
             <div className="mt-[20vh] flex justify-center">
-              {isAnyUserExists ? <SignInComponent /> : <SignUpComponent />}
+              {isAnyUserExists || oidcConfig?.isPasswordLoginDisabled ? (
+                <SignInComponent oidcConfig={oidcConfig} />
+              ) : (
+                <SignUpComponent />
+              )}
             </div>
           </div>
         </div>
@@ -32,6 +32,11 @@ export default class RequestOptions {
     return this;
   }
 
+  setHeader(headerName: string, headerValue?: string): RequestOptions {
+    this.headers = this.headers.filter(([name]) => name !== headerName);
+    return this.addHeader(headerName, headerValue);
+  }
+
   toRequestInit(): RequestInit {
     // Example:
     //
@@ -1,4 +1,5 @@
 const AUTHORIED_USER_TOKEN_KEY = 'postgresus_user_token';
+const AUTHORIED_USER_REFRESH_TOKEN_KEY = 'postgresus_user_refresh_token';
 const AUTHORIED_USER_ID_KEY = 'postgresus_user_id';
 
 export const accessTokenHelper = {
@@ -24,6 +25,23 @@ export const accessTokenHelper = {
     }
 
     localStorage.removeItem(AUTHORIED_USER_TOKEN_KEY);
+    localStorage.removeItem(AUTHORIED_USER_REFRESH_TOKEN_KEY);
+  },
+
+  saveRefreshToken: (refreshToken: string) => {
+    if (typeof localStorage === 'undefined') {
+      return;
+    }
+
+    localStorage.setItem(AUTHORIED_USER_REFRESH_TOKEN_KEY, refreshToken);
+  },
+
+  getRefreshToken: (): string | undefined => {
+    if (typeof localStorage === 'undefined') {
+      return;
+    }
+
+    return localStorage.getItem(AUTHORIED_USER_REFRESH_TOKEN_KEY) || undefined;
   },
 
   saveUserId: (id: string) => {
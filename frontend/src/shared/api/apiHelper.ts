@@ -1,9 +1,51 @@
 import { accessTokenHelper } from '.';
+import { getApplicationServer } from '../../constants';
 import RequestOptions from './RequestOptions';
 
 const REPEAT_TRIES_COUNT = 10;
 const REPEAT_INTERVAL_MS = 3_000;
 
+// access tokens are short-lived, so several requests may get 401 at
+// the same time. They wait for the single refresh request
+let tokenRefreshPromise: Promise<boolean> | undefined;
+
+const refreshAccessToken = async (): Promise<boolean> => {
+  const refreshToken = accessTokenHelper.getRefreshToken();
+  if (!refreshToken) {
+    return false;
+  }
+
+  try {
+    const response = await fetch(`${getApplicationServer()}/api/v1/users/refresh`, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({ refreshToken }),
+    });
+
+    if (!response.ok) {
+      return false;
+    }
+
+    const json = (await response.json()) as { token: string; refreshToken: string };
+    accessTokenHelper.saveAccessToken(json.token);
+    accessTokenHelper.saveRefreshToken(json.refreshToken);
+
+    return true;
+  } catch {
+    return false;
+  }
+};
+
+const refreshAccessTokenOnce = (): Promise<boolean> => {
+  if (!tokenRefreshPromise) {
+    tokenRefreshPromise = refreshAccessToken().finally(() => {
+      tokenRefreshPromise = undefined;
+    });
+  }
+
+  return tokenRefreshPromise;
+};
+
 const handleOrThrowMessageIfResponseError = async (
   url: string,
   response: Response,
@@ -40,9 +82,16 @@ const makeRequest = async (
   url: string,
   optionsWrapper: RequestOptions,
   currentTry = 0,
+  isTokenRefreshed = false,
 ): Promise<Response> => {
   try {
     const response = await fetch(url, optionsWrapper.toRequestInit());
+
+    if (response.status === 401 && !isTokenRefreshed && (await refreshAccessTokenOnce())) {
+      optionsWrapper.setHeader('Authorization', accessTokenHelper.getAccessToken());
+      return makeRequest(url, optionsWrapper, currentTry, true);
+    }
+
     await handleOrThrowMessageIfResponseError(url, response);
     return response;
   } catch (e) {